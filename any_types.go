@@ -0,0 +1,53 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// anyTypesDirective lists the types the payload of a google.protobuf.Any field is expected to be, by full name, e.g.
+// `@any-types com.example.Car, com.example.Truck`. It may be repeated.
+const anyTypesDirective = "@any-types"
+
+// anyFullType is the full name of google.protobuf.Any, the type of the fields @any-types directives apply to.
+const anyFullType = "google.protobuf.Any"
+
+// extractAnyTypes removes the @any-types directives from the description of an Any field, returning the remaining
+// description and the listed types in order, without duplicates.
+func extractAnyTypes(description string) (string, []string) {
+	if !strings.Contains(description, anyTypesDirective) {
+		return description, nil
+	}
+
+	var types []string
+	seen := make(map[string]bool)
+	lines := make([]string, 0)
+	for _, line := range strings.Split(description, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != anyTypesDirective {
+			lines = append(lines, line)
+			continue
+		}
+
+		for _, name := range strings.FieldsFunc(strings.Join(fields[1:], " "), isAnyTypesSeparator) {
+			name = strings.TrimPrefix(name, ".")
+			if !seen[name] {
+				seen[name] = true
+				types = append(types, name)
+			}
+		}
+	}
+
+	// drop the paragraphs that only held directives
+	paragraphs := make([]string, 0)
+	for _, paragraph := range strings.Split(strings.Join(lines, "\n"), "\n\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			paragraphs = append(paragraphs, strings.Trim(paragraph, "\n"))
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n"), types
+}
+
+func isAnyTypesSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t'
+}
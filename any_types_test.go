@@ -0,0 +1,83 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newAnyTypesRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"shop.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("shop.proto"),
+			Package: proto.String("shop"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Order"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{
+							Name: proto.String("item"), Number: proto.Int32(1),
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+							TypeName: proto.String(".google.protobuf.Any"),
+						},
+						{
+							Name: proto.String("note"), Number: proto.Int32(2),
+							Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+						},
+					},
+				},
+				{Name: proto.String("Car")},
+				{Name: proto.String("Truck")},
+			},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{
+					Path: []int32{4, 0, 2, 0},
+					Span: []int32{1, 0, 1},
+					LeadingComments: proto.String(
+						" The ordered item.\n @any-types .shop.Car, shop.Truck\n @any-types shop.Car other.Bike\n"),
+				},
+				{
+					Path:            []int32{4, 0, 2, 1},
+					Span:            []int32{2, 0, 1},
+					LeadingComments: proto.String(" @any-types only applies to Any fields.\n"),
+				},
+			}},
+		}},
+	}
+}
+
+func TestAnyTypes(t *testing.T) {
+	req := newAnyTypesRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	order := findMessage("Order", NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0])
+
+	field := findField("item", order)
+	require.Equal(t, "The ordered item.", field.Description)
+	require.Equal(t, []string{"shop.Car", "shop.Truck", "other.Bike"}, field.AnyTypes)
+
+	field = findField("note", order)
+	require.Equal(t, "@any-types only applies to Any fields.", field.Description)
+	require.Empty(t, field.AnyTypes)
+}
+
+func TestAnyTypesOutput(t *testing.T) {
+	resp, err := new(Plugin).Generate(newAnyTypesRequest("html,index.html"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), `The ordered item. <br>Allowed types: `+
+		`<a href="#shop.Car">shop.Car</a>, <a href="#shop.Truck">shop.Truck</a>, other.Bike<br>`)
+
+	resp, err = new(Plugin).Generate(newAnyTypesRequest("markdown,README.md"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "| The ordered item.<br>Allowed types: "+
+		"[shop.Car](#shop-Car), [shop.Truck](#shop-Truck), other.Bike<br>")
+}
@@ -0,0 +1,439 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// eventDirective designates a message as an event published to a channel, e.g. `@event books.created`, or to the
+// channel named after the message with `@event`.
+const eventDirective = "@event"
+
+// AsyncAPIVersion is the version of the AsyncAPI specification the documents of the asyncapi format follow.
+const AsyncAPIVersion = "2.6.0"
+
+// asyncapiScalars maps scalar value types to the JSON schemas of their proto3 JSON mapping.
+var asyncapiScalars = map[string]exampleObject{
+	"double":   {{"type", "number"}, {"format", "double"}},
+	"float":    {{"type", "number"}, {"format", "float"}},
+	"int32":    {{"type", "integer"}, {"format", "int32"}},
+	"int64":    {{"type", "string"}, {"format", "int64"}},
+	"uint32":   {{"type", "integer"}, {"format", "uint32"}},
+	"uint64":   {{"type", "string"}, {"format", "uint64"}},
+	"sint32":   {{"type", "integer"}, {"format", "int32"}},
+	"sint64":   {{"type", "string"}, {"format", "int64"}},
+	"fixed32":  {{"type", "integer"}, {"format", "uint32"}},
+	"fixed64":  {{"type", "string"}, {"format", "uint64"}},
+	"sfixed32": {{"type", "integer"}, {"format", "int32"}},
+	"sfixed64": {{"type", "string"}, {"format", "int64"}},
+	"bool":     {{"type", "boolean"}},
+	"string":   {{"type", "string"}},
+	"bytes":    {{"type", "string"}, {"format", "byte"}},
+}
+
+// asyncapiWellKnownTypes maps the well-known types with a special JSON mapping to JSON schemas.
+var asyncapiWellKnownTypes = map[string]exampleObject{
+	"google.protobuf.Duration":    {{"type", "string"}},
+	"google.protobuf.Empty":       {{"type", "object"}},
+	"google.protobuf.FieldMask":   {{"type", "string"}},
+	"google.protobuf.Struct":      {{"type", "object"}},
+	"google.protobuf.Timestamp":   {{"type", "string"}, {"format", "date-time"}},
+	"google.protobuf.BoolValue":   {{"type", []string{"boolean", "null"}}},
+	"google.protobuf.StringValue": {{"type", []string{"string", "null"}}},
+	"google.protobuf.Int32Value":  {{"type", []string{"integer", "null"}}},
+	"google.protobuf.DoubleValue": {{"type", []string{"number", "null"}}},
+}
+
+// extractEvent removes the @event directive from a message description, returning the remaining description and the
+// channel the message is published to. The channel is fullName when the directive doesn't name one, and empty when
+// there's no directive.
+func extractEvent(description, fullName string) (string, string) {
+	if !strings.Contains(description, eventDirective) {
+		return description, ""
+	}
+
+	channel := ""
+	lines := make([]string, 0)
+	for _, line := range strings.Split(description, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && len(fields) <= 2 && fields[0] == eventDirective {
+			channel = fullName
+			if len(fields) == 2 {
+				channel = fields[1]
+			}
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), channel
+}
+
+// eventChannelFromOption returns the channel opts publish a message to with the event_option option: the option's
+// value if it's a string, or fullName if it's true.
+func eventChannelFromOption(opts proto.Message, fullName string, pluginOptions *PluginOptions) string {
+	if pluginOptions.EventOption == "" {
+		return ""
+	}
+
+	switch value := decodeOptions(opts, pluginOptions)[pluginOptions.EventOption].(type) {
+	case string:
+		return value
+	case bool:
+		if value {
+			return fullName
+		}
+	}
+
+	return ""
+}
+
+// asyncapiRenderer writes an AsyncAPI document describing the streaming methods of the services and the event
+// messages. Each streaming method is a channel named after the method (e.g. `com.example.BookingService/Watch`), that
+// the client publishes the request messages to and subscribes to the response messages of. Event messages are
+// published by the application to the channel they're designated with.
+type asyncapiRenderer struct{}
+
+func (r *asyncapiRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *asyncapiRenderer) ApplyTo(w io.Writer, template *Template) error {
+	doc := newAsyncapiDocument(template)
+
+	for _, file := range template.Files {
+		for _, service := range file.Services {
+			for _, method := range service.Methods {
+				if !method.RequestStreaming && !method.ResponseStreaming {
+					continue
+				}
+
+				doc.addMethod(service, method)
+			}
+		}
+	}
+
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			if message.Channel != "" && !message.Redacted {
+				doc.addEvent(message)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(doc.object(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// asyncapiChannel is a channel of an AsyncAPI document, with the messages published and subscribed to.
+type asyncapiChannel struct {
+	name        string
+	description string
+	publish     *asyncapiOperation
+	subscribe   *asyncapiOperation
+}
+
+type asyncapiOperation struct {
+	operationID string
+	summary     string
+	messages    []string
+}
+
+// asyncapiDocument collects the channels of an AsyncAPI document, and the messages and schemas they use.
+type asyncapiDocument struct {
+	template *Template
+	messages map[string]*Message
+	enums    map[string]*Enum
+
+	channels []*asyncapiChannel
+	byName   map[string]*asyncapiChannel
+
+	// The full names of the message payloads and the schemas they use, in the order they're first used.
+	payloads    []string
+	hasPayload  map[string]bool
+	schemas     []string
+	hasSchema   map[string]bool
+	definitions map[string]exampleObject
+}
+
+func newAsyncapiDocument(template *Template) *asyncapiDocument {
+	doc := &asyncapiDocument{
+		template:    template,
+		messages:    messagesByName(template.Files),
+		enums:       make(map[string]*Enum),
+		byName:      make(map[string]*asyncapiChannel),
+		hasPayload:  make(map[string]bool),
+		hasSchema:   make(map[string]bool),
+		definitions: make(map[string]exampleObject),
+	}
+
+	for _, file := range template.Files {
+		for _, enum := range file.AllEnums() {
+			doc.enums[enum.FullName] = enum
+		}
+	}
+
+	return doc
+}
+
+// channel returns the named channel, adding it if needed.
+func (d *asyncapiDocument) channel(name string) *asyncapiChannel {
+	if channel, ok := d.byName[name]; ok {
+		return channel
+	}
+
+	channel := &asyncapiChannel{name: name}
+	d.channels = append(d.channels, channel)
+	d.byName[name] = channel
+	return channel
+}
+
+// addMethod adds the channel of a streaming method.
+func (d *asyncapiDocument) addMethod(service *Service, method *ServiceMethod) {
+	channel := d.channel(service.FullName + "/" + method.Name)
+	channel.description = method.Description
+
+	channel.publish = &asyncapiOperation{
+		operationID: service.LongName + "." + method.Name + "Request",
+		summary:     streamingSummary("Request messages of", method.RequestStreaming, method.Name),
+		messages:    []string{d.payload(method.RequestFullType)},
+	}
+	channel.subscribe = &asyncapiOperation{
+		operationID: service.LongName + "." + method.Name + "Response",
+		summary:     streamingSummary("Response messages of", method.ResponseStreaming, method.Name),
+		messages:    []string{d.payload(method.ResponseFullType)},
+	}
+}
+
+// streamingSummary returns the summary of an operation of a method, e.g. `Request messages of Watch (streamed).`.
+func streamingSummary(prefix string, streaming bool, method string) string {
+	if streaming {
+		return fmt.Sprintf("%s %s (streamed).", prefix, method)
+	}
+
+	return fmt.Sprintf("%s %s.", prefix, method)
+}
+
+// addEvent adds an event message to the subscribe operation of its channel.
+func (d *asyncapiDocument) addEvent(message *Message) {
+	channel := d.channel(message.Channel)
+	if channel.subscribe == nil {
+		channel.subscribe = new(asyncapiOperation)
+	}
+
+	channel.subscribe.messages = append(channel.subscribe.messages, d.payload(message.FullName))
+}
+
+// payload adds the message fullName to the components and returns its name.
+func (d *asyncapiDocument) payload(fullName string) string {
+	if !d.hasPayload[fullName] {
+		d.hasPayload[fullName] = true
+		d.payloads = append(d.payloads, fullName)
+		d.schema(fullName)
+	}
+
+	return fullName
+}
+
+// schema returns the JSON schema of the type fullName: a reference for documented messages and enums, which are added
+// to the components along with the types they use, and an inline schema otherwise. Types that are neither documented,
+// scalar nor well-known accept any value.
+func (d *asyncapiDocument) schema(fullName string) exampleObject {
+	if scalar, ok := asyncapiScalars[fullName]; ok {
+		return scalar
+	}
+	if wkt, ok := asyncapiWellKnownTypes[fullName]; ok {
+		return wkt
+	}
+
+	message, isMessage := d.messages[fullName]
+	enum, isEnum := d.enums[fullName]
+	if !isMessage && !isEnum {
+		return exampleObject{}
+	}
+
+	if !d.hasSchema[fullName] {
+		d.hasSchema[fullName] = true
+		d.schemas = append(d.schemas, fullName)
+		if isMessage {
+			d.definitions[fullName] = d.messageSchema(message)
+		} else {
+			d.definitions[fullName] = enumSchema(enum)
+		}
+	}
+
+	return exampleObject{{"$ref", "#/components/schemas/" + fullName}}
+}
+
+// messageSchema returns the JSON schema of message, whose properties are the JSON names of its fields.
+func (d *asyncapiDocument) messageSchema(message *Message) exampleObject {
+	properties := make(exampleObject, 0, len(message.Fields))
+	required := make([]string, 0)
+	for _, field := range message.Fields {
+		if field.Redacted {
+			continue
+		}
+
+		name := field.JSONName
+		if name == "" {
+			name = lowerCamelCase(field.Name)
+		}
+		if field.Label == "required" {
+			required = append(required, name)
+		}
+
+		properties = append(properties, exampleMember{name, d.fieldSchema(field)})
+	}
+
+	schema := exampleObject{{"type", "object"}}
+	if message.Description != "" {
+		schema = append(schema, exampleMember{"description", message.Description})
+	}
+	schema = append(schema, exampleMember{"properties", properties})
+	if len(required) > 0 {
+		schema = append(schema, exampleMember{"required", required})
+	}
+
+	return schema
+}
+
+// fieldSchema returns the JSON schema of field, with its description. Repeated fields are arrays and maps are objects.
+func (d *asyncapiDocument) fieldSchema(field *MessageField) exampleObject {
+	var schema exampleObject
+	switch {
+	case field.IsMap:
+		value := exampleObject{}
+		if entry, ok := d.messages[field.FullType]; ok {
+			for _, f := range entry.Fields {
+				if f.Name == "value" {
+					value = d.schema(f.FullType)
+				}
+			}
+		}
+		schema = exampleObject{{"type", "object"}, {"additionalProperties", value}}
+	case field.Label == "repeated":
+		schema = exampleObject{{"type", "array"}, {"items", d.schema(field.FullType)}}
+	default:
+		schema = d.schema(field.FullType)
+	}
+
+	if field.Description == "" {
+		return schema
+	}
+
+	if len(schema) == 1 && schema[0].name == "$ref" {
+		// siblings of $ref are ignored, so the reference is wrapped
+		schema = exampleObject{{"allOf", []exampleObject{schema}}}
+	}
+	return append(schema[:len(schema):len(schema)], exampleMember{"description", field.Description})
+}
+
+// enumSchema returns the JSON schema of enum, whose values are given by name.
+func enumSchema(enum *Enum) exampleObject {
+	values := make([]string, 0, len(enum.Values))
+	for _, value := range enum.Values {
+		if !value.Redacted {
+			values = append(values, value.Name)
+		}
+	}
+
+	schema := exampleObject{{"type", "string"}}
+	if enum.Description != "" {
+		schema = append(schema, exampleMember{"description", enum.Description})
+	}
+	return append(schema, exampleMember{"enum", values})
+}
+
+// object returns the AsyncAPI document as a JSON object.
+func (d *asyncapiDocument) object() exampleObject {
+	meta := d.template.Meta
+	title := meta.Title
+	if title == "" {
+		title = Translate(d.template.Locale, "Protocol Documentation")
+	}
+	version := meta.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+
+	info := exampleObject{{"title", title}, {"version", version}}
+	if meta.Description != "" {
+		info = append(info, exampleMember{"description", meta.Description})
+	}
+
+	channels := make(exampleObject, 0, len(d.channels))
+	for _, channel := range d.channels {
+		item := exampleObject{}
+		if channel.description != "" {
+			item = append(item, exampleMember{"description", channel.description})
+		}
+		if channel.publish != nil {
+			item = append(item, exampleMember{"publish", channel.publish.object()})
+		}
+		if channel.subscribe != nil {
+			item = append(item, exampleMember{"subscribe", channel.subscribe.object()})
+		}
+		channels = append(channels, exampleMember{channel.name, item})
+	}
+
+	messages := make(exampleObject, 0, len(d.payloads))
+	for _, fullName := range d.payloads {
+		messages = append(messages, exampleMember{fullName, d.messageObject(fullName)})
+	}
+
+	schemas := make(exampleObject, 0, len(d.schemas))
+	for _, fullName := range d.schemas {
+		schemas = append(schemas, exampleMember{fullName, d.definitions[fullName]})
+	}
+
+	return exampleObject{
+		{"asyncapi", AsyncAPIVersion},
+		{"info", info},
+		{"defaultContentType", "application/json"},
+		{"channels", channels},
+		{"components", exampleObject{{"messages", messages}, {"schemas", schemas}}},
+	}
+}
+
+// object returns the operation as a JSON object. Operations with several messages accept any of them.
+func (o *asyncapiOperation) object() exampleObject {
+	refs := make([]exampleObject, 0, len(o.messages))
+	for _, name := range o.messages {
+		refs = append(refs, exampleObject{{"$ref", "#/components/messages/" + name}})
+	}
+
+	operation := exampleObject{}
+	if o.operationID != "" {
+		operation = append(operation, exampleMember{"operationId", o.operationID})
+	}
+	if o.summary != "" {
+		operation = append(operation, exampleMember{"summary", o.summary})
+	}
+	if len(refs) == 1 {
+		return append(operation, exampleMember{"message", refs[0]})
+	}
+	return append(operation, exampleMember{"message", exampleObject{{"oneOf", refs}}})
+}
+
+// messageObject returns the message object of the message fullName.
+func (d *asyncapiDocument) messageObject(fullName string) exampleObject {
+	object := exampleObject{{"name", fullName}}
+	if message, ok := d.messages[fullName]; ok {
+		object = append(object, exampleMember{"title", message.Name})
+		if message.Description != "" {
+			object = append(object, exampleMember{"description", message.Description})
+		}
+	}
+
+	return append(object, exampleMember{"payload", d.schema(fullName)})
+}
@@ -0,0 +1,176 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// asyncapiPath returns the value at the given keys of a decoded JSON document.
+func asyncapiPath(t *testing.T, doc interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		object, ok := doc.(map[string]interface{})
+		require.True(t, ok, "not an object at %v: %v", key, doc)
+		doc = object[key]
+	}
+
+	return doc
+}
+
+func TestRenderAsyncAPI(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	output, err := RenderTemplate(RenderTypeAsyncAPI, template, "")
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &doc))
+	require.Equal(t, AsyncAPIVersion, doc["asyncapi"])
+	require.Equal(t, map[string]interface{}{"title": "Protocol Documentation", "version": "0.0.0"}, doc["info"])
+
+	// only streaming methods are channels
+	channels := doc["channels"].(map[string]interface{})
+	require.Len(t, channels, 2)
+	require.Equal(t, map[string]interface{}{
+		"description": "creates models",
+		"publish": map[string]interface{}{
+			"operationId": "VehicleService.AddModelsRequest",
+			"summary":     "Request messages of AddModels (streamed).",
+			"message":     map[string]interface{}{"$ref": "#/components/messages/com.example.Model"},
+		},
+		"subscribe": map[string]interface{}{
+			"operationId": "VehicleService.AddModelsResponse",
+			"summary":     "Response messages of AddModels (streamed).",
+			"message":     map[string]interface{}{"$ref": "#/components/messages/com.example.Model"},
+		},
+	}, channels["com.example.VehicleService/AddModels"])
+	require.Equal(t, "Request messages of GetModels.",
+		asyncapiPath(t, channels, "com.example.VehicleService/GetModels", "publish", "summary"))
+
+	require.Equal(t, map[string]interface{}{
+		"name":        "com.example.Model",
+		"title":       "Model",
+		"description": "Represents a vehicle model.",
+		"payload":     map[string]interface{}{"$ref": "#/components/schemas/com.example.Model"},
+	}, asyncapiPath(t, doc, "components", "messages", "com.example.Model"))
+
+	// schemas use the JSON names of fields, and enums are referred to
+	model := asyncapiPath(t, doc, "components", "schemas", "com.example.Model", "properties")
+	require.Equal(t, map[string]interface{}{"type": "string", "description": "The car model code, e.g. \"PZ003\"."},
+		asyncapiPath(t, model, "modelCode"))
+	require.Equal(t, []interface{}{map[string]interface{}{"$ref": "#/components/schemas/com.example.Type"}},
+		asyncapiPath(t, model, "type", "allOf"))
+	require.Equal(t, []interface{}{"COUPE", "SEDAN"},
+		asyncapiPath(t, doc, "components", "schemas", "com.example.Type", "enum"))
+
+	// messages that aren't used aren't included
+	require.Nil(t, asyncapiPath(t, doc, "components", "schemas", "com.example.Vehicle"))
+}
+
+func TestRenderAsyncAPIEvents(t *testing.T) {
+	created := &Message{Name: "BookCreated", LongName: "BookCreated", FullName: "library.BookCreated",
+		Description: "A book was created.", Channel: "books", Fields: []*MessageField{
+			{Name: "isbn", FullType: "string", Label: "required"},
+			{Name: "tags", FullType: "string", Label: "repeated"},
+			{Name: "labels", FullType: "library.BookCreated.LabelsEntry", IsMap: true, Label: "repeated"},
+			{Name: "created_at", JSONName: "createdAt", FullType: "google.protobuf.Timestamp"},
+		}}
+	labels := &Message{Name: "LabelsEntry", LongName: "BookCreated.LabelsEntry",
+		FullName: "library.BookCreated.LabelsEntry", Fields: []*MessageField{
+			{Name: "key", FullType: "string"},
+			{Name: "value", FullType: "int64"},
+		}}
+	deleted := &Message{Name: "BookDeleted", LongName: "BookDeleted", FullName: "library.BookDeleted",
+		Channel: "books"}
+	template := &Template{
+		Meta:  Meta{Title: "Library Events", Version: "2.1"},
+		Files: []*File{{Name: "events.proto", Messages: []*Message{created, labels, deleted}}},
+	}
+
+	output, err := RenderTemplate(RenderTypeAsyncAPI, template, "")
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &doc))
+	require.Equal(t, map[string]interface{}{"title": "Library Events", "version": "2.1"}, doc["info"])
+
+	// events of the same channel are alternatives of its subscribe operation
+	require.Equal(t, map[string]interface{}{
+		"books": map[string]interface{}{
+			"subscribe": map[string]interface{}{
+				"message": map[string]interface{}{"oneOf": []interface{}{
+					map[string]interface{}{"$ref": "#/components/messages/library.BookCreated"},
+					map[string]interface{}{"$ref": "#/components/messages/library.BookDeleted"},
+				}},
+			},
+		},
+	}, doc["channels"])
+
+	require.Equal(t, map[string]interface{}{
+		"type":        "object",
+		"description": "A book was created.",
+		"properties": map[string]interface{}{
+			"isbn": map[string]interface{}{"type": "string"},
+			"tags": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"labels": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string", "format": "int64"},
+			},
+			"createdAt": map[string]interface{}{"type": "string", "format": "date-time"},
+		},
+		"required": []interface{}{"isbn"},
+	}, asyncapiPath(t, doc, "components", "schemas", "library.BookCreated"))
+}
+
+func TestEventChannels(t *testing.T) {
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"events.proto"},
+		Parameter:      proto.String("asyncapi,asyncapi.json:event_option=deprecated"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("events.proto"),
+			Package: proto.String("library"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("BookCreated")},
+				{Name: proto.String("BookDeleted")},
+				{
+					Name:    proto.String("BookArchived"),
+					Options: &descriptorpb.MessageOptions{Deprecated: proto.Bool(true)},
+				},
+				{Name: proto.String("Book")},
+			},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, Span: []int32{1, 0, 1}, LeadingComments: proto.String(
+					" A book was created.\n\n @event books.created\n")},
+				{Path: []int32{4, 1}, Span: []int32{2, 0, 1}, LeadingComments: proto.String(" @event\n")},
+			}},
+		}},
+	}
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), options)
+	file := template.Files[0]
+
+	message := findMessage("BookCreated", file)
+	require.Equal(t, "A book was created.", message.Description)
+	require.Equal(t, "books.created", message.Channel)
+
+	message = findMessage("BookDeleted", file)
+	require.Equal(t, "", message.Description)
+	require.Equal(t, "library.BookDeleted", message.Channel)
+
+	require.Equal(t, "library.BookArchived", findMessage("BookArchived", file).Channel)
+	require.Equal(t, "", findMessage("Book", file).Channel)
+}
@@ -0,0 +1,338 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// AvroSchemaExtension is the extension of the schema files written for every message with the avro format.
+const AvroSchemaExtension = ".avsc"
+
+// avroPrimitives maps scalar value types to Avro types. Unsigned 64-bit integers don't fit a long, so they're decimals
+// without a fractional part.
+var avroPrimitives = map[string]interface{}{
+	"double":   "double",
+	"float":    "float",
+	"int32":    "int",
+	"int64":    "long",
+	"uint32":   "long",
+	"uint64":   avroUnsignedLong,
+	"sint32":   "int",
+	"sint64":   "long",
+	"fixed32":  "long",
+	"fixed64":  avroUnsignedLong,
+	"sfixed32": "int",
+	"sfixed64": "long",
+	"bool":     "boolean",
+	"string":   "string",
+	"bytes":    "bytes",
+}
+
+var avroUnsignedLong = map[string]interface{}{"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 0}
+
+// avroDefaults are the Avro default values of the proto3 default values of scalar value types.
+var avroDefaults = map[string]json.RawMessage{
+	"double":   json.RawMessage("0"),
+	"float":    json.RawMessage("0"),
+	"int32":    json.RawMessage("0"),
+	"int64":    json.RawMessage("0"),
+	"uint32":   json.RawMessage("0"),
+	"uint64":   json.RawMessage(`"\u0000"`),
+	"sint32":   json.RawMessage("0"),
+	"sint64":   json.RawMessage("0"),
+	"fixed32":  json.RawMessage("0"),
+	"fixed64":  json.RawMessage(`"\u0000"`),
+	"sfixed32": json.RawMessage("0"),
+	"sfixed64": json.RawMessage("0"),
+	"bool":     json.RawMessage("false"),
+	"string":   json.RawMessage(`""`),
+	"bytes":    json.RawMessage(`""`),
+}
+
+type avroRecord struct {
+	Type      string       `json:"type"`
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Doc       string       `json:"doc,omitempty"`
+	Fields    []*avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Doc     string          `json:"doc,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroArray struct {
+	Type  string      `json:"type"`
+	Items interface{} `json:"items"`
+}
+
+type avroMap struct {
+	Type   string      `json:"type"`
+	Values interface{} `json:"values"`
+}
+
+type avroEnum struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Doc       string   `json:"doc,omitempty"`
+	Symbols   []string `json:"symbols"`
+	Default   string   `json:"default,omitempty"`
+}
+
+// avroSchemas converts the messages of a template to Avro records. Each named type is defined once, where it's first
+// used, and referred to by its full name after that, so a schema can only be parsed along with the ones before it.
+type avroSchemas struct {
+	messages map[string]*Message
+	enums    map[string]*Enum
+	defined  map[string]bool
+}
+
+func newAvroSchemas(template *Template) *avroSchemas {
+	s := &avroSchemas{
+		messages: make(map[string]*Message),
+		enums:    make(map[string]*Enum),
+		defined:  make(map[string]bool),
+	}
+
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			if !message.Redacted {
+				s.messages[message.FullName] = message
+			}
+		}
+		for _, enum := range file.AllEnums() {
+			s.enums[enum.FullName] = enum
+		}
+	}
+
+	return s
+}
+
+// record returns the schema of message, or its full name if it's already defined.
+func (s *avroSchemas) record(message *Message) interface{} {
+	if s.defined[message.FullName] {
+		return message.FullName
+	}
+	s.defined[message.FullName] = true
+
+	record := &avroRecord{
+		Type:      "record",
+		Name:      message.Name,
+		Namespace: avroNamespace(message.FullName, message.Name),
+		Doc:       message.Description,
+		Fields:    make([]*avroField, 0, len(message.Fields)),
+	}
+
+	for _, field := range message.Fields {
+		if !field.Redacted {
+			record.Fields = append(record.Fields, s.field(field))
+		}
+	}
+
+	return record
+}
+
+// field returns the Avro field of a message field. Repeated fields are arrays and maps are maps. Proto3 scalar and
+// enum fields default to their zero value, whereas messages, oneof members, and proto2 and proto3 optional fields are
+// nullable.
+func (s *avroSchemas) field(field *MessageField) *avroField {
+	avro := &avroField{Name: field.Name, Doc: field.Description}
+
+	if field.IsMap {
+		// map keys are always strings in Avro
+		entry := s.messages[field.FullType]
+		var value interface{} = "string"
+		if entry != nil {
+			for _, f := range entry.Fields {
+				if f.Name == "value" {
+					value = s.fieldType(f)
+				}
+			}
+		}
+		avro.Type = &avroMap{Type: "map", Values: value}
+		avro.Default = json.RawMessage("{}")
+		return avro
+	}
+
+	fieldType := s.fieldType(field)
+	_, isMessage := s.messages[field.FullType]
+	_, isScalar := avroPrimitives[field.FullType]
+	// proto3 fields without presence have no label
+	nullable := isMessage || !isScalar && s.enums[field.FullType] == nil || field.IsOneof || field.Label == "optional"
+
+	switch {
+	case field.Label == "repeated":
+		avro.Type = &avroArray{Type: "array", Items: fieldType}
+		avro.Default = json.RawMessage("[]")
+	case field.Label == "required":
+		avro.Type = fieldType
+	case nullable:
+		avro.Type = []interface{}{"null", fieldType}
+		avro.Default = json.RawMessage("null")
+	default:
+		avro.Type = fieldType
+		avro.Default = s.zeroValue(field)
+	}
+
+	return avro
+}
+
+// fieldType returns the Avro type of a single value of field. Types that aren't documented, such as well-known types,
+// are strings holding their proto3 JSON mapping.
+func (s *avroSchemas) fieldType(field *MessageField) interface{} {
+	if primitive, ok := avroPrimitives[field.FullType]; ok {
+		return primitive
+	}
+	if message, ok := s.messages[field.FullType]; ok {
+		return s.record(message)
+	}
+	if enum, ok := s.enums[field.FullType]; ok {
+		return s.enum(enum)
+	}
+
+	return "string"
+}
+
+// enum returns the schema of enum, or its full name if it's already defined.
+func (s *avroSchemas) enum(enum *Enum) interface{} {
+	if s.defined[enum.FullName] {
+		return enum.FullName
+	}
+	s.defined[enum.FullName] = true
+
+	avro := &avroEnum{
+		Type:      "enum",
+		Name:      enum.Name,
+		Namespace: avroNamespace(enum.FullName, enum.Name),
+		Doc:       enum.Description,
+		Symbols:   make([]string, 0, len(enum.Values)),
+	}
+	for _, value := range enum.Values {
+		if !value.Redacted {
+			avro.Symbols = append(avro.Symbols, value.Name)
+		}
+	}
+	if len(avro.Symbols) > 0 {
+		avro.Default = avro.Symbols[0]
+	}
+
+	return avro
+}
+
+// zeroValue returns the Avro default of a non-nullable scalar or enum field.
+func (s *avroSchemas) zeroValue(field *MessageField) json.RawMessage {
+	if value, ok := avroDefaults[field.FullType]; ok {
+		return value
+	}
+
+	if enum, ok := s.enums[field.FullType]; ok {
+		for _, value := range enum.Values {
+			if !value.Redacted {
+				data, _ := json.Marshal(value.Name)
+				return data
+			}
+		}
+	}
+
+	return nil
+}
+
+// avroNamespace returns the namespace of the type fullName named name, e.g. `com.example.Vehicle` for the nested
+// `com.example.Vehicle.Category`.
+func avroNamespace(fullName, name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(fullName, name), ".")
+}
+
+// hasAvro returns whether a schema file is written for every message.
+func hasAvro(pluginOptions *PluginOptions) bool {
+	return pluginOptions.Type == RenderTypeAvro
+}
+
+// AvroSchema returns the Avro schema of the message fullName in template, with the types it uses defined inline.
+func AvroSchema(template *Template, fullName string) ([]byte, error) {
+	schemas := newAvroSchemas(template)
+	message, ok := schemas.messages[fullName]
+	if !ok {
+		return nil, fmt.Errorf("Unknown message: %v", fullName)
+	}
+
+	return json.MarshalIndent(schemas.record(message), "", "  ")
+}
+
+// avroSchemaFiles returns the schema file of every message in template, written to dir and named after the message's
+// full name followed by AvroSchemaExtension.
+func avroSchemaFiles(template *Template, dir string) ([]OutputFile, error) {
+	files := make([]OutputFile, 0)
+	mapEntries := mapEntryTypes(template)
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			if message.Redacted || mapEntries[message.FullName] {
+				continue
+			}
+
+			schema, err := AvroSchema(template, message.FullName)
+			if err != nil {
+				return nil, err
+			}
+
+			name := filepath.Join(dir, message.FullName+AvroSchemaExtension)
+			files = append(files, OutputFile{Name: name, Content: string(schema) + "\n"})
+		}
+	}
+
+	return files, nil
+}
+
+// mapEntryTypes returns the full names of the entry types of the map fields in template.
+func mapEntryTypes(template *Template) map[string]bool {
+	entries := make(map[string]bool)
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			for _, field := range message.Fields {
+				if field.IsMap {
+					entries[field.FullType] = true
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// avroRenderer writes the schemas of all messages as a single Avro union, in which each named type is defined once.
+// The plugin also writes a schema file per message (see avroSchemaFiles).
+type avroRenderer struct{}
+
+func (r *avroRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *avroRenderer) ApplyTo(w io.Writer, template *Template) error {
+	schemas := newAvroSchemas(template)
+	mapEntries := mapEntryTypes(template)
+	union := make([]interface{}, 0)
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			if message.Redacted || mapEntries[message.FullName] {
+				continue
+			}
+
+			union = append(union, schemas.record(message))
+		}
+	}
+
+	data, err := json.MarshalIndent(union, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
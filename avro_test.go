@@ -0,0 +1,135 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// avroFields returns the fields of an Avro record schema, keyed by name.
+func avroFields(t *testing.T, schema interface{}) map[string]map[string]interface{} {
+	record, ok := schema.(map[string]interface{})
+	require.True(t, ok, "not a record: %v", schema)
+
+	fields := make(map[string]map[string]interface{})
+	for _, field := range record["fields"].([]interface{}) {
+		f := field.(map[string]interface{})
+		fields[f["name"].(string)] = f
+	}
+
+	return fields
+}
+
+func TestAvroSchema(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+
+	data, err := AvroSchema(template, "com.example.Vehicle")
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &schema))
+	require.Equal(t, "record", schema["type"])
+	require.Equal(t, "Vehicle", schema["name"])
+	require.Equal(t, "com.example", schema["namespace"])
+	require.Equal(t, "Represents a vehicle that can be hired.", schema["doc"])
+
+	fields := avroFields(t, schema)
+	require.Equal(t, "int", fields["id"]["type"])
+	require.Equal(t, 0.0, fields["id"]["default"])
+	require.Equal(t, map[string]interface{}{"type": "array", "items": "int"}, fields["rates"]["type"])
+	require.Equal(t, map[string]interface{}{"type": "map", "values": "string"}, fields["properties"]["type"])
+	require.Equal(t, []interface{}{"null", "long"}, fields["lightyears"]["type"])
+	require.Nil(t, fields["lightyears"]["default"])
+	require.Contains(t, fields["lightyears"], "default")
+
+	// messages are nullable records, defined where they're first used
+	model := fields["model"]["type"].([]interface{})
+	require.Equal(t, "null", model[0])
+	modelFields := avroFields(t, model[1])
+	require.Equal(t, "COUPE", modelFields["type"]["default"])
+	require.Equal(t, []interface{}{"COUPE", "SEDAN"}, modelFields["type"]["type"].(map[string]interface{})["symbols"])
+
+	category := fields["category"]["type"].([]interface{})[1].(map[string]interface{})
+	require.Equal(t, "Category", category["name"])
+	require.Equal(t, "com.example.Vehicle", category["namespace"])
+
+	// proto2 required fields have no default, optional ones are nullable
+	data, err = AvroSchema(template, "com.example.Booking")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	fields = avroFields(t, schema)
+	require.Equal(t, "int", fields["vehicle_id"]["type"])
+	require.NotContains(t, fields["vehicle_id"], "default")
+	require.Equal(t, []interface{}{"null", "boolean"}, fields["payment_received"]["type"])
+
+	_, err = AvroSchema(template, "com.example.Unknown")
+	require.EqualError(t, err, "Unknown message: com.example.Unknown")
+}
+
+func TestAvroSchemaReusesDefinedTypes(t *testing.T) {
+	message := &Message{Name: "Node", LongName: "Node", FullName: "org.Node", Fields: []*MessageField{
+		{Name: "id", FullType: "uint64"},
+		{Name: "parent", FullType: "org.Node", Label: "optional"},
+		{Name: "children", FullType: "org.Node", Label: "repeated"},
+		{Name: "created_at", FullType: "google.protobuf.Timestamp"},
+	}}
+	template := &Template{Files: []*File{{Name: "tree.proto", Messages: []*Message{message}}}}
+
+	data, err := AvroSchema(template, "org.Node")
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	fields := avroFields(t, schema)
+	require.Equal(t, map[string]interface{}{"type": "bytes", "logicalType": "decimal", "precision": 20.0, "scale": 0.0},
+		fields["id"]["type"])
+	require.Equal(t, []interface{}{"null", "org.Node"}, fields["parent"]["type"])
+	require.Equal(t, map[string]interface{}{"type": "array", "items": "org.Node"}, fields["children"]["type"])
+	require.Equal(t, []interface{}{"null", "string"}, fields["created_at"]["type"])
+}
+
+func TestGenerateAvro(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Vehicle.proto")
+	req.Parameter = proto.String("avro,schemas.avsc")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(resp.File))
+	for _, file := range resp.File {
+		names = append(names, file.GetName())
+	}
+	require.Equal(t, []string{
+		"schemas.avsc",
+		"com.example.EmptyMessage.avsc",
+		"com.example.ExcludedMessage.avsc",
+		"com.example.FindVehicleById.avsc",
+		"com.example.Manufacturer.avsc",
+		"com.example.Model.avsc",
+		"com.example.Vehicle.avsc",
+		"com.example.Vehicle.Category.avsc",
+		"com.example.Vehicle.Engine.avsc",
+		"com.example.Vehicle.Engine.Stats.avsc",
+	}, names)
+
+	// the union defines every record once, and refers to the ones already defined by name
+	var union []interface{}
+	require.NoError(t, json.Unmarshal([]byte(resp.File[0].GetContent()), &union))
+	require.Len(t, union, 9)
+	require.Equal(t, "Model", union[4].(map[string]interface{})["name"])
+	require.Equal(t, "com.example.Vehicle.Category", union[6])
+}
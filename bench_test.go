@@ -1,12 +1,113 @@
 package gendoc_test
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
 	"github.com/pseudomuto/protokit/utils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
+// newSyntheticRequest returns a request documenting the given number of files, each with the given number of
+// top-level messages nested depth levels deep, along with an enum and a service. Every entity has a comment.
+func newSyntheticRequest(files, messages, depth int, parameter string) *pluginpb.CodeGeneratorRequest {
+	req := &pluginpb.CodeGeneratorRequest{Parameter: proto.String(parameter)}
+
+	for f := 0; f < files; f++ {
+		pkg := fmt.Sprintf("synthetic.v%d", f)
+		fd := &descriptorpb.FileDescriptorProto{
+			Name:           proto.String(fmt.Sprintf("synthetic/file%d.proto", f)),
+			Package:        proto.String(pkg),
+			Syntax:         proto.String("proto3"),
+			SourceCodeInfo: new(descriptorpb.SourceCodeInfo),
+		}
+		comment := func(text string, path ...int32) {
+			fd.SourceCodeInfo.Location = append(fd.SourceCodeInfo.Location, &descriptorpb.SourceCodeInfo_Location{
+				Path: path, Span: []int32{1, 0, 1}, LeadingComments: proto.String(" " + text + "\n"),
+			})
+		}
+
+		fd.EnumType = []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Kind"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("KIND_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("KIND_SYNTHETIC"), Number: proto.Int32(1)},
+			},
+		}}
+		comment("The kind of a message.", 5, 0)
+
+		for m := 0; m < messages; m++ {
+			path := []int32{4, int32(m)}
+			typeName := "." + pkg
+			msg := &descriptorpb.DescriptorProto{Name: proto.String(fmt.Sprintf("Message%d", m))}
+			fd.MessageType = append(fd.MessageType, msg)
+
+			for d := 0; d <= depth; d++ {
+				typeName += "." + msg.GetName()
+				comment(fmt.Sprintf("%s is a synthetic message.", msg.GetName()), path...)
+
+				msg.Field = []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:     proto.String("id"),
+						JsonName: proto.String("id"),
+						Number:   proto.Int32(1),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					},
+					{
+						Name:     proto.String("kind"),
+						JsonName: proto.String("kind"),
+						Number:   proto.Int32(2),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+						TypeName: proto.String("." + pkg + ".Kind"),
+					},
+				}
+				comment("The identifier.", append(path, 2, 0)...)
+				comment("The kind.", append(path, 2, 1)...)
+
+				if d == depth {
+					break
+				}
+
+				nested := &descriptorpb.DescriptorProto{Name: proto.String(fmt.Sprintf("Level%d", d+1))}
+				msg.NestedType = []*descriptorpb.DescriptorProto{nested}
+				msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+					Name:     proto.String("child"),
+					JsonName: proto.String("child"),
+					Number:   proto.Int32(3),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(typeName + "." + nested.GetName()),
+				})
+				msg = nested
+				path = append(append([]int32{}, path...), 3, 0)
+			}
+		}
+
+		service := &descriptorpb.ServiceDescriptorProto{Name: proto.String("SyntheticService")}
+		for m := 0; m < messages; m++ {
+			service.Method = append(service.Method, &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(fmt.Sprintf("Get%d", m)),
+				InputType:  proto.String(fmt.Sprintf(".%s.Message%d", pkg, m)),
+				OutputType: proto.String(fmt.Sprintf(".%s.Message%d", pkg, m)),
+			})
+			comment("Gets a synthetic message.", 6, 0, 2, int32(m))
+		}
+		fd.Service = []*descriptorpb.ServiceDescriptorProto{service}
+		comment("A synthetic service.", 6, 0)
+
+		req.ProtoFile = append(req.ProtoFile, fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.GetName())
+	}
+
+	return req
+}
+
 func BenchmarkParseCodeRequest(b *testing.B) {
 	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
 	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
@@ -16,3 +117,43 @@ func BenchmarkParseCodeRequest(b *testing.B) {
 		plugin.Generate(req)
 	}
 }
+
+func BenchmarkNewTemplate(b *testing.B) {
+	fds := protokit.ParseCodeGenRequest(newSyntheticRequest(20, 10, 3, ""))
+	options := new(PluginOptions)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		NewTemplate(fds, options)
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	for _, parameter := range []string{"html,index.html", "markdown,index.md", "json,index.json", "docbook,index.xml"} {
+		req := newSyntheticRequest(20, 10, 3, parameter+":reproducible=true")
+		b.Run(parameter, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := new(Plugin).Generate(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGenerateDeepNesting shows how the time taken grows with the nesting depth of messages.
+func BenchmarkGenerateDeepNesting(b *testing.B) {
+	for _, depth := range []int{1, 8, 32} {
+		req := newSyntheticRequest(5, 10, depth, "html,index.html:reproducible=true")
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := new(Plugin).Generate(req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package gendoc_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomTemplateReusesBlocks(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeHTML, newBookingTemplate(t),
+		`{{range .Files}}{{range .Messages}}<h3>{{.LongName}}</h3>{{template "gendoc/default/field-table" .}}{{end}}{{end}}`)
+	require.NoError(t, err)
+	require.Contains(t, string(output), `<h3>BookingStatus</h3>
+            <table class="field-table">`)
+	require.Contains(t, string(output), `<td><a href="#int32">int32</a></td>`)
+
+	output, err = RenderTemplate(RenderTypeHTML, newBookingTemplate(t),
+		`{{template "gendoc/default/scalar-table" .Scalars}}`)
+	require.NoError(t, err)
+	require.Contains(t, string(output), `<tr id="double">`)
+}
+
+func TestCustomTemplateOverridesBlocks(t *testing.T) {
+	template := newBookingTemplate(t)
+	template.Meta.Title = "Bookings"
+
+	output, err := RenderTemplate(RenderTypeHTML, template,
+		`{{define "gendoc/default/title"}}{{.Meta.Title}} API{{end}}<h1>{{template "gendoc/default/title" .}}</h1>`)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Bookings API</h1>", string(output))
+}
+
+func TestBuiltinTemplateUsesBlocks(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeHTML, newBookingTemplate(t), "")
+	require.NoError(t, err)
+	require.Contains(t, string(output), `<table class="field-table">`)
+	require.Contains(t, string(output), `<table class="scalar-value-types-table">`)
+}
+
+func TestErrorInBlock(t *testing.T) {
+	_, err := RenderTemplate(RenderTypeHTML, newBookingTemplate(t),
+		"{{range .Files}}\n{{template \"gendoc/default/enum-table\" .}}\n{{end}}")
+	require.Error(t, err)
+
+	var templateErr *TemplateError
+	require.True(t, errors.As(err, &templateErr))
+	require.Equal(t, "resources/blocks.tmpl", templateErr.Name)
+	require.Equal(t, ".Values", templateErr.Action)
+}
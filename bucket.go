@@ -0,0 +1,242 @@
+package gendoc
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// The environment variables configuring uploads to S3 buckets (see the publish_bucket option). They're the ones of the
+// AWS CLI and SDKs. AWSEndpointURLEnv points to an S3 compatible service instead, e.g. MinIO or Cloudflare R2.
+const (
+	AWSAccessKeyIDEnv     = "AWS_ACCESS_KEY_ID"
+	AWSSecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+	AWSSessionTokenEnv    = "AWS_SESSION_TOKEN"
+	AWSRegionEnv          = "AWS_REGION"
+	AWSEndpointURLEnv     = "AWS_ENDPOINT_URL"
+)
+
+// The environment variables configuring uploads to Google Cloud Storage buckets (see the publish_bucket option), e.g.
+// `GOOGLE_OAUTH_ACCESS_TOKEN=$(gcloud auth print-access-token)`. StorageEmulatorHostEnv points to an emulator instead.
+const (
+	GoogleOAuthAccessTokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
+	StorageEmulatorHostEnv    = "STORAGE_EMULATOR_HOST"
+)
+
+// defaultAWSRegion is the region of S3 buckets when $AWSRegionEnv isn't set.
+const defaultAWSRegion = "us-east-1"
+
+// isBucketURL returns whether value is an s3:// or gs:// URL with a bucket name.
+func isBucketURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "s3" || u.Scheme == "gs") && u.Host != ""
+}
+
+// bucketPrefix returns the path prefix of the objects uploaded to the bucket of the publish_bucket option, replacing
+// its {version} placeholder with the version option, or the version stamp.
+func bucketPrefix(bucketURL *url.URL, options *PluginOptions) (string, error) {
+	prefix := strings.Trim(bucketURL.Path, "/")
+	if !strings.Contains(prefix, "{version}") {
+		return prefix, nil
+	}
+
+	version := options.Version
+	if version == "" {
+		version = options.VersionStamp
+	}
+	if version == "" {
+		return "", errors.New(
+			"The {version} placeholder of publish_bucket requires the version or version_stamp option")
+	}
+
+	return strings.ReplaceAll(prefix, "{version}", version), nil
+}
+
+// publishToBucket uploads the output files to the bucket of options.PublishBucket, as objects named after their paths
+// below its prefix.
+func publishToBucket(files []OutputFile, options *PluginOptions, log *logger) error {
+	bucketURL, err := url.Parse(options.PublishBucket)
+	if err != nil {
+		return err
+	}
+
+	prefix, err := bucketPrefix(bucketURL, options)
+	if err != nil {
+		return err
+	}
+
+	newRequest := newS3Request
+	if bucketURL.Scheme == "gs" {
+		newRequest = newGCSRequest
+	}
+	if err := checkBucketCredentials(bucketURL.Scheme); err != nil {
+		return err
+	}
+
+	err = forEachParallel(len(files), options.Parallelism, func(i int) error {
+		key := path.Join(prefix, files[i].Name)
+		content := []byte(files[i].Content)
+		contentType := mime.TypeByExtension(path.Ext(key))
+		if contentType == "" {
+			contentType = "text/plain; charset=utf-8"
+		}
+
+		return sendWithRetries(func() (*http.Request, error) {
+			return newRequest(bucketURL.Host, key, contentType, content)
+		}, options, log)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.info("published docs", "bucket", bucketURL.Scheme+"://"+path.Join(bucketURL.Host, prefix), "files", len(files))
+	return nil
+}
+
+// checkBucketCredentials returns an error when the environment variables needed to upload to a bucket of the given
+// kind (s3 or gs) aren't set.
+func checkBucketCredentials(scheme string) error {
+	switch {
+	case scheme == "s3" && (os.Getenv(AWSAccessKeyIDEnv) == "" || os.Getenv(AWSSecretAccessKeyEnv) == ""):
+		return fmt.Errorf("Publishing to S3 requires the %s and %s environment variables", AWSAccessKeyIDEnv,
+			AWSSecretAccessKeyEnv)
+	case scheme == "gs" && os.Getenv(GoogleOAuthAccessTokenEnv) == "" && os.Getenv(StorageEmulatorHostEnv) == "":
+		return fmt.Errorf("Publishing to Google Cloud Storage requires the %s environment variable",
+			GoogleOAuthAccessTokenEnv)
+	}
+
+	return nil
+}
+
+// newS3Request returns a request putting an object into an S3 bucket, signed with AWS Signature Version 4.
+func newS3Request(bucket, key, contentType string, content []byte) (*http.Request, error) {
+	region := os.Getenv(AWSRegionEnv)
+	if region == "" {
+		region = defaultAWSRegion
+	}
+
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	objectPath := "/" + key
+	if custom := os.Getenv(AWSEndpointURLEnv); custom != "" {
+		// S3 compatible services are addressed with path-style URLs
+		endpoint = custom
+		objectPath = "/" + bucket + "/" + key
+	}
+
+	req, err := newObjectRequest(endpoint, objectPath, contentType, content)
+	if err != nil {
+		return nil, err
+	}
+
+	signS3Request(req, content, region, time.Now().UTC())
+	return req, nil
+}
+
+// newGCSRequest returns a request putting an object into a Google Cloud Storage bucket through its XML API.
+func newGCSRequest(bucket, key, contentType string, content []byte) (*http.Request, error) {
+	endpoint := "https://storage.googleapis.com"
+	if emulator := os.Getenv(StorageEmulatorHostEnv); emulator != "" {
+		endpoint = emulator
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+	}
+
+	req, err := newObjectRequest(endpoint, "/"+bucket+"/"+key, contentType, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := os.Getenv(GoogleOAuthAccessTokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// newObjectRequest returns a PUT request for the object at objectPath of the endpoint.
+func newObjectRequest(endpoint, objectPath, contentType string, content []byte) (*http.Request, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + objectPath
+	u.RawPath = escapeObjectPath(u.Path)
+
+	req, err := http.NewRequest(http.MethodPut, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	return req, nil
+}
+
+// escapeObjectPath percent-encodes every byte of p but the unreserved characters and slashes, as object storage
+// signatures expect.
+func escapeObjectPath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// signS3Request signs the request with AWS Signature Version 4, with the credentials of the environment.
+func signS3Request(req *http.Request, content []byte, region string, now time.Time) {
+	payloadHash := sha256Hex(string(content))
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if token := os.Getenv(AWSSessionTokenEnv); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+		headers = append(headers, "x-amz-security-token")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headers {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.TrimSpace(value))
+	}
+
+	signedHeaders := strings.Join(headers, ";")
+	canonicalRequest := strings.Join([]string{req.Method, req.URL.EscapedPath(), req.URL.RawQuery,
+		canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+
+	scope := now.Format("20060102") + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+	key := []byte("AWS4" + os.Getenv(AWSSecretAccessKeyEnv))
+	for _, part := range []string{now.Format("20060102"), region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%x",
+		os.Getenv(AWSAccessKeyIDEnv), scope, signedHeaders, hmacSHA256(key, stringToSign)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
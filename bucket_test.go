@@ -0,0 +1,109 @@
+package gendoc_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// newBucketServer returns a server storing the objects put into it, keyed by path.
+func newBucketServer(t *testing.T, check func(r *http.Request)) (*httptest.Server, map[string]string) {
+	var mu sync.Mutex
+	objects := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		check(r)
+
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		objects[r.URL.Path] = string(content)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, objects
+}
+
+func TestParseOptionsForPublishBucket(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:publish_bucket=s3://docs/api/{version}"))
+	require.NoError(t, err)
+	require.Equal(t, "s3://docs/api/{version}", options.PublishBucket)
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:publish_bucket=gs://docs"))
+	require.NoError(t, err)
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:publish_bucket=docs/api"))
+	require.EqualError(t, err, "Invalid publish_bucket value: docs/api")
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:publish_bucket=https://docs/api"))
+	require.EqualError(t, err, "Invalid publish_bucket value: https://docs/api")
+}
+
+func TestPublishToS3(t *testing.T) {
+	server, objects := newBucketServer(t, func(r *http.Request) {
+		require.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), r.Header.Get("Authorization"))
+		require.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request, SignedHeaders=")
+		require.NotEmpty(t, r.Header.Get("X-Amz-Date"))
+		require.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+	})
+
+	t.Setenv(AWSAccessKeyIDEnv, "AKIDEXAMPLE")
+	t.Setenv(AWSSecretAccessKeyEnv, "secret")
+	t.Setenv(AWSRegionEnv, "eu-west-1")
+	t.Setenv(AWSEndpointURLEnv, server.URL)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t,
+		"html,index.html:publish_bucket=s3://docs/api/{version},version=1.2.0,manifest=manifest.json"))
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	require.Equal(t, resp.File[0].GetContent(), objects["/docs/api/1.2.0/index.html"])
+	require.Equal(t, resp.File[1].GetContent(), objects["/docs/api/1.2.0/manifest.json"])
+
+	// the version is required by the prefix
+	_, err = new(Plugin).Generate(newBookingRequest(t, "html,index.html:publish_bucket=s3://docs/api/{version}"))
+	require.EqualError(t, err,
+		"The {version} placeholder of publish_bucket requires the version or version_stamp option")
+
+	t.Setenv(AWSSecretAccessKeyEnv, "")
+	_, err = new(Plugin).Generate(newBookingRequest(t, "html,index.html:publish_bucket=s3://docs"))
+	require.EqualError(t, err,
+		"Publishing to S3 requires the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables")
+}
+
+func TestPublishToGCS(t *testing.T) {
+	server, objects := newBucketServer(t, func(r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, "text/html; charset=utf-8", r.Header.Get("Content-Type"))
+	})
+
+	t.Setenv(StorageEmulatorHostEnv, strings.TrimPrefix(server.URL, "http://"))
+	t.Setenv(GoogleOAuthAccessTokenEnv, "token")
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:publish_bucket=gs://docs"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"/docs/index.html": resp.File[0].GetContent()}, objects)
+}
+
+func TestGenerateDoesNotPublishToBucket(t *testing.T) {
+	server, objects := newBucketServer(t, func(r *http.Request) {})
+	t.Setenv(StorageEmulatorHostEnv, strings.TrimPrefix(server.URL, "http://"))
+	t.Setenv(GoogleOAuthAccessTokenEnv, "token")
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	files, err := Generate(set, Options{Parameter: "html,index.html:publish_bucket=gs://docs"})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Empty(t, objects)
+}
@@ -0,0 +1,101 @@
+package gendoc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// outputCache stores rendered output files on disk (see the cache_dir option). Entries are keyed by a hash of
+// everything that affects the output: the plugin version, the options, the template, the renderer and the file
+// descriptors of the request. Entries are never invalidated, they simply stop being looked up once any of their inputs change.
+type outputCache struct {
+	dir string
+}
+
+// outputKey computes the key for rendering fds into dir with the given inputs (options, template contents, etc.), used
+// by the cache and manifests.
+func outputKey(dir string, fds []*protokit.FileDescriptor, inputs ...string) (string, error) {
+	hash := sha256.New()
+	writeHashString(hash, VERSION)
+	writeHashString(hash, dir)
+
+	for _, input := range inputs {
+		writeHashString(hash, input)
+	}
+
+	for _, fd := range fds {
+		data, err := proto.Marshal(fd.FileDescriptorProto)
+		if err != nil {
+			return "", err
+		}
+
+		writeHashString(hash, string(data))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// protoFilesKey returns a hash of all the files of a request, including the imported ones. Templates can read any of
+// them, e.g. the options of an imported file through the protoreflect descriptors, so every output depends on them.
+func protoFilesKey(files []*descriptorpb.FileDescriptorProto) (string, error) {
+	hash := sha256.New()
+	for _, f := range files {
+		data, err := proto.Marshal(f)
+		if err != nil {
+			return "", err
+		}
+
+		writeHashString(hash, string(data))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// get returns the cached output for key, if there is one.
+func (c *outputCache) get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// put stores the output for key. The entry is written to a temporary file first so that concurrent builds never read a
+// partially written entry.
+func (c *outputCache) put(key string, data []byte) error {
+	if err := os.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, key))
+}
+
+// writeHashString writes a length-prefixed string so that different sequences of inputs never hash the same.
+func writeHashString(w io.Writer, s string) {
+	binary.Write(w, binary.LittleEndian, uint64(len(s)))
+	io.WriteString(w, s)
+}
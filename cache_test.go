@@ -0,0 +1,118 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func generateWithCache(t *testing.T, cacheDir, parameter string) *pluginpb.CodeGeneratorResponse {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String(parameter + ",cache_dir=" + cacheDir)
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestRunPluginWithCacheDir(t *testing.T) {
+	cacheDir, err := os.MkdirTemp("", "protoc-gen-doc-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	first := generateWithCache(t, cacheDir, "markdown,index.md,source_relative:camel_case_fields=false")
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// a second run with the same inputs is served from the cache
+	for _, entry := range entries {
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, entry.Name()), []byte("cached"), 0644))
+	}
+
+	second := generateWithCache(t, cacheDir, "markdown,index.md,source_relative:camel_case_fields=false")
+	require.Len(t, second.File, len(first.File))
+	for _, f := range second.File {
+		require.Equal(t, "cached", f.GetContent())
+	}
+
+	// changing the options changes the cache key
+	third := generateWithCache(t, cacheDir, "markdown,index.md,source_relative:camel_case_fields=true")
+	for _, f := range third.File {
+		require.NotEqual(t, "cached", f.GetContent())
+	}
+
+	entries, err = os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+}
+
+func TestRunPluginWithCacheDirAndMetaFile(t *testing.T) {
+	cacheDir := t.TempDir()
+	meta := filepath.Join(t.TempDir(), "meta.json")
+	parameter := "markdown,index.md:meta_file=" + meta
+
+	require.NoError(t, os.WriteFile(meta, []byte(`{"title": "Bookings", "version": "1.0.0"}`), 0644))
+	first := generateWithCache(t, cacheDir, parameter)
+	require.Contains(t, first.File[0].GetContent(), "1.0.0")
+
+	// editing the meta_file changes the cache key, although the parameter stays the same
+	require.NoError(t, os.WriteFile(meta, []byte(`{"title": "Bookings", "version": "1.1.0"}`), 0644))
+	second := generateWithCache(t, cacheDir, parameter)
+	require.Contains(t, second.File[0].GetContent(), "1.1.0")
+	require.NotContains(t, second.File[0].GetContent(), "1.0.0")
+}
+
+func TestRunPluginWithCacheDirAndExecRenderer(t *testing.T) {
+	cacheDir := t.TempDir()
+	renderer := writeRenderer(t, "echo v1")
+	parameter := "exec:" + renderer + ",docs.txt:"
+
+	require.Equal(t, "v1\n", generateWithCache(t, cacheDir, parameter).File[0].GetContent())
+
+	// rebuilding the renderer at the same path changes the cache key
+	require.NoError(t, os.WriteFile(renderer, []byte("#!/bin/sh\necho v2\n"), 0755))
+	require.Equal(t, "v2\n", generateWithCache(t, cacheDir, parameter).File[0].GetContent())
+}
+
+func TestRunPluginWithCacheDirAndImportedFiles(t *testing.T) {
+	cacheDir := t.TempDir()
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	generate := func() {
+		req := utils.CreateGenRequest(set, "Booking.proto")
+		req.Parameter = proto.String("markdown,index.md:cache_dir=" + cacheDir)
+
+		_, err := new(Plugin).Generate(req)
+		require.NoError(t, err)
+	}
+
+	generate()
+	generate()
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// templates can read any file of the request, so changing one that isn't documented changes the cache key too
+	for _, fd := range set.GetFile() {
+		if fd.GetName() == "Vehicle.proto" {
+			fd.Options = &descriptorpb.FileOptions{JavaPackage: proto.String("com.example.vehicles")}
+		}
+	}
+	generate()
+	entries, err = os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"strings"
 
 	gendoc "github.com/daotl/protoc-gen-doc"
 )
@@ -24,6 +25,19 @@ protoc --doc_out=. --doc_opt=custom.tmpl,docs.txt protos/*.proto
 EXAMPLE: Generate docs relative to source protos
 protoc --doc_out=. --doc_opt=html,index.html,source_relative protos/*.proto
 
+EXAMPLE: Generate HTML docs for a running gRPC server via server reflection
+protoc-gen-doc -reflect=localhost:50051 -plaintext -doc_out=. -doc_opt=html,index.html
+
+EXAMPLE: Preview HTML docs on http://localhost:8080, regenerating them whenever the protos change
+protoc-gen-doc -serve=localhost:8080 -proto_path=protos protos/*.proto
+
+EXAMPLE: Check a doc_opt value (e.g. in CI) without running protoc
+protoc-gen-doc -validate -doc_opt=custom.tmpl,docs.txt:exclude_patterns=google/*
+
+EXAMPLE: List the built-in templates, and write one out as the starting point of a custom template
+protoc-gen-doc -list_templates
+protoc-gen-doc -dump_template=html/minimal -doc_out=templates
+
 See https://github.com/daotl/protoc-gen-doc for more details.
 `
 
@@ -39,9 +53,31 @@ type Flags struct {
 	err         error
 	showHelp    bool
 	showVersion bool
+	reflect     string
+	plaintext   bool
+	docOpt      string
+	docOut      string
+	serve       string
+	descriptors string
+	protoPaths  stringList
+	validate    bool
+	listTmpls   bool
+	dumpTmpl    string
 	writer      io.Writer
 }
 
+// stringList is a flag that can be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 // Code returns the status code to exit with after handling the supplied flags
 func (f *Flags) Code() int {
 	if f.err != nil {
@@ -67,6 +103,63 @@ func (f *Flags) ShowVersion() bool {
 	return f.showVersion
 }
 
+// Reflect returns the address of the gRPC server to document via server reflection, or an empty string when running
+// as a protoc plugin.
+func (f *Flags) Reflect() string {
+	return f.reflect
+}
+
+// Plaintext determines whether to connect to the reflection server without TLS
+func (f *Flags) Plaintext() bool {
+	return f.plaintext
+}
+
+// DocOpt returns the value to use in place of protoc's `--doc_opt` in reflection and serve mode
+func (f *Flags) DocOpt() string {
+	return f.docOpt
+}
+
+// DocOut returns the directory to write documentation to in reflection mode
+func (f *Flags) DocOut() string {
+	return f.docOut
+}
+
+// Serve returns the address to serve a live preview of the docs on, or an empty string when not serving
+func (f *Flags) Serve() string {
+	return f.serve
+}
+
+// DescriptorSet returns the descriptor set file to watch in serve mode, if any
+func (f *Flags) DescriptorSet() string {
+	return f.descriptors
+}
+
+// ProtoPaths returns the import paths to pass to protoc in serve mode
+func (f *Flags) ProtoPaths() []string {
+	return f.protoPaths
+}
+
+// Validate determines whether to check the `-doc_opt` flag instead of generating docs
+func (f *Flags) Validate() bool {
+	return f.validate
+}
+
+// ListTemplates determines whether to list the built-in templates instead of generating docs
+func (f *Flags) ListTemplates() bool {
+	return f.listTmpls
+}
+
+// DumpTemplate returns the name of the built-in template to write to the `-doc_out` directory, or an empty string
+// when not dumping a template
+func (f *Flags) DumpTemplate() string {
+	return f.dumpTmpl
+}
+
+// Args returns the proto files to compile in serve mode
+func (f *Flags) Args() []string {
+	return f.flagSet.Args()
+}
+
 // PrintHelp prints the usage string including all flags to the `io.Writer` that was supplied to the `Flags` object.
 func (f *Flags) PrintHelp() {
 	fmt.Fprintf(f.writer, "Usage of %s:\n", f.appName)
@@ -92,6 +185,22 @@ func ParseFlags(w io.Writer, args []string) *Flags {
 	f.flagSet.BoolVar(&f.showHelp, "help", false, "Show this help message")
 	f.flagSet.BoolVar(&f.showVersion, "version", false,
 		fmt.Sprintf("Print the current version (%v)", Version()))
+	f.flagSet.StringVar(&f.reflect, "reflect", "",
+		"Generate docs for the gRPC server at this address using server reflection instead of running as a protoc plugin")
+	f.flagSet.BoolVar(&f.plaintext, "plaintext", false, "Connect to the reflection server without TLS")
+	f.flagSet.StringVar(&f.docOpt, "doc_opt", "html,index.html",
+		"The doc_opt value to use with -reflect, -serve or -validate")
+	f.flagSet.StringVar(&f.docOut, "doc_out", ".", "The output directory to use with -reflect")
+	f.flagSet.StringVar(&f.serve, "serve", "",
+		"Serve a live preview of the docs on this address, regenerating them whenever the proto files change")
+	f.flagSet.StringVar(&f.descriptors, "descriptor_set", "",
+		"Watch this descriptor set (written by protoc --descriptor_set_out) with -serve instead of compiling proto files")
+	f.flagSet.Var(&f.protoPaths, "proto_path", "An import path passed to protoc with -serve (can be repeated)")
+	f.flagSet.BoolVar(&f.validate, "validate", false,
+		"Check the -doc_opt value (options, exclude patterns and custom template) without generating docs")
+	f.flagSet.BoolVar(&f.listTmpls, "list_templates", false, "List the built-in templates that -dump_template can write")
+	f.flagSet.StringVar(&f.dumpTmpl, "dump_template", "",
+		"Write the built-in template with this name (e.g. html/slate) to the -doc_out directory to customize it")
 	f.flagSet.SetOutput(w)
 
 	// prevent showing help on parse error
@@ -58,6 +58,7 @@ func TestPrintHelp(t *testing.T) {
 	require.Contains(t, result, "FLAGS\n")
 	require.Contains(t, result, "-help")
 	require.Contains(t, result, "-version")
+	require.Contains(t, result, "-reflect")
 }
 
 func TestPrintVersion(t *testing.T) {
@@ -79,3 +80,60 @@ func TestInvalidFlags(t *testing.T) {
 	require.True(t, f.HasMatch())
 	require.True(t, f.ShowHelp())
 }
+
+func TestReflectionFlags(t *testing.T) {
+	f := ParseFlags(nil, []string{"app"})
+	require.Empty(t, f.Reflect())
+	require.False(t, f.Plaintext())
+	require.Equal(t, "html,index.html", f.DocOpt())
+	require.Equal(t, ".", f.DocOut())
+
+	f = ParseFlags(nil, []string{"app", "-reflect=localhost:50051", "-plaintext", "-doc_opt=markdown,docs.md", "-doc_out=out"})
+	require.False(t, f.HasMatch())
+	require.Equal(t, "localhost:50051", f.Reflect())
+	require.True(t, f.Plaintext())
+	require.Equal(t, "markdown,docs.md", f.DocOpt())
+	require.Equal(t, "out", f.DocOut())
+}
+
+func TestServeFlags(t *testing.T) {
+	f := ParseFlags(nil, []string{"app"})
+	require.Empty(t, f.Serve())
+	require.Empty(t, f.DescriptorSet())
+	require.Empty(t, f.ProtoPaths())
+	require.Empty(t, f.Args())
+
+	f = ParseFlags(nil, []string{"app", "-serve=:8080", "-proto_path=protos", "-proto_path=third_party", "protos/a.proto"})
+	require.False(t, f.HasMatch())
+	require.Equal(t, ":8080", f.Serve())
+	require.Equal(t, []string{"protos", "third_party"}, f.ProtoPaths())
+	require.Equal(t, []string{"protos/a.proto"}, f.Args())
+
+	f = ParseFlags(nil, []string{"app", "-serve=:8080", "-descriptor_set=docs.pb"})
+	require.Equal(t, "docs.pb", f.DescriptorSet())
+}
+
+func TestValidateFlags(t *testing.T) {
+	f := ParseFlags(nil, []string{"app"})
+	require.False(t, f.Validate())
+
+	f = ParseFlags(nil, []string{"app", "-validate", "-doc_opt=markdown,docs.md"})
+	require.False(t, f.HasMatch())
+	require.True(t, f.Validate())
+	require.Equal(t, "markdown,docs.md", f.DocOpt())
+}
+
+func TestTemplateFlags(t *testing.T) {
+	f := ParseFlags(nil, []string{"app"})
+	require.False(t, f.ListTemplates())
+	require.Empty(t, f.DumpTemplate())
+
+	f = ParseFlags(nil, []string{"app", "-list_templates"})
+	require.False(t, f.HasMatch())
+	require.True(t, f.ListTemplates())
+
+	f = ParseFlags(nil, []string{"app", "-dump_template=html/slate", "-doc_out=templates"})
+	require.False(t, f.HasMatch())
+	require.Equal(t, "html/slate", f.DumpTemplate())
+	require.Equal(t, "templates", f.DocOut())
+}
@@ -10,10 +10,27 @@
 //
 //	protoc --doc_out=. --doc_opt=custom.tmpl,docs.txt protos/*.proto
 //
+// Example: document a running gRPC server via server reflection
+//
+//	protoc-gen-doc -reflect=localhost:50051 -plaintext -doc_out=. -doc_opt=html,index.html
+//
+// Example: preview HTML documentation, regenerating it whenever the protos change
+//
+//	protoc-gen-doc -serve=localhost:8080 -proto_path=protos protos/*.proto
+//
+// Example: check a doc_opt value without running protoc
+//
+//	protoc-gen-doc -validate -doc_opt=custom.tmpl,docs.txt:exclude_patterns=google/*
+//
+// Example: write a built-in template out as the starting point of a custom template
+//
+//	protoc-gen-doc -dump_template=html/minimal -doc_out=templates
+//
 // For more details, check out the README at https://github.com/daotl/protoc-gen-doc
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -26,8 +43,32 @@ import (
 )
 
 func main() {
-	if flags := ParseFlags(os.Stdout, os.Args); HandleFlags(flags) {
+	flags := ParseFlags(os.Stdout, os.Args)
+	switch {
+	case HandleFlags(flags):
 		os.Exit(flags.Code())
+	case flags.Reflect() != "":
+		if err := RunReflection(context.Background(), flags); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case flags.Serve() != "":
+		log.Fatal(RunServer(context.Background(), flags))
+	case flags.Validate():
+		if err := RunValidation(os.Stdout, flags); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case flags.ListTemplates():
+		if err := RunListTemplates(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case flags.DumpTemplate() != "":
+		if err := RunDumpTemplate(os.Stdout, flags); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	if err := protokit.RunPlugin(new(gendoc.Plugin)); err != nil {
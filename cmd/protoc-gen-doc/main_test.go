@@ -2,8 +2,12 @@ package main_test
 
 import (
 	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	gendoc "github.com/daotl/protoc-gen-doc"
 	. "github.com/daotl/protoc-gen-doc/cmd/protoc-gen-doc"
 	"github.com/stretchr/testify/require"
 )
@@ -23,3 +27,51 @@ func TestHandleFlags(t *testing.T) {
 		require.Equal(t, test.result, HandleFlags(f))
 	}
 }
+
+func TestRunServerWithoutSource(t *testing.T) {
+	f := ParseFlags(new(bytes.Buffer), []string{"app", "-serve=localhost:0"})
+	err := RunServer(context.Background(), f)
+	require.EqualError(t, err, "Either -descriptor_set or proto files are required with -serve")
+}
+
+func TestRunValidation(t *testing.T) {
+	buf := new(bytes.Buffer)
+	f := ParseFlags(buf, []string{"app", "-validate", "-doc_opt=markdown,docs.md:exclude_patterns=google/.*"})
+	require.NoError(t, RunValidation(buf, f))
+	require.Equal(t, "doc_opt \"markdown,docs.md:exclude_patterns=google/.*\" is valid\n", buf.String())
+
+	buf.Reset()
+	f = ParseFlags(buf, []string{"app", "-validate", "-doc_opt=markdown,docs.md:exclude_patterns=google/("})
+	require.Error(t, RunValidation(buf, f))
+	require.Empty(t, buf.String())
+}
+
+func TestRunListTemplates(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, RunListTemplates(buf))
+	require.Contains(t, buf.String(), "html/default      Single page with a sidebar and light and dark themes\n")
+	require.Contains(t, buf.String(), "html/slate        Three-pane API reference with request and response examples\n")
+}
+
+func TestRunDumpTemplate(t *testing.T) {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-templates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	buf := new(bytes.Buffer)
+	f := ParseFlags(buf, []string{"app", "-dump_template=html/minimal", "-doc_out=" + dir})
+	require.NoError(t, RunDumpTemplate(buf, f))
+
+	file := filepath.Join(dir, "html-minimal.tmpl")
+	require.Equal(t, "Wrote html/minimal to "+file+"\n", buf.String())
+
+	tmpl, err := gendoc.FindBuiltinTemplate("html/minimal")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, tmpl.Source(), data)
+
+	f = ParseFlags(buf, []string{"app", "-dump_template=html/fancy", "-doc_out=" + dir})
+	require.EqualError(t, RunDumpTemplate(buf, f), "Unknown built-in template: html/fancy")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	gendoc "github.com/daotl/protoc-gen-doc"
+	"github.com/daotl/protoc-gen-doc/reflection"
+)
+
+// RunReflection fetches the descriptors of the gRPC server named by the `-reflect` flag and writes its documentation
+// to the `-doc_out` directory, using the `-doc_opt` flag in the same way protoc's `--doc_opt` would be used.
+func RunReflection(ctx context.Context, f *Flags) error {
+	client := &reflection.Client{Address: f.Reflect(), Plaintext: f.Plaintext()}
+	defer client.Close()
+
+	fdset, err := client.FileDescriptorSet(ctx)
+	if err != nil {
+		return err
+	}
+
+	files, err := gendoc.Generate(fdset, gendoc.Options{Parameter: f.DocOpt()})
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		name := filepath.Join(f.DocOut(), filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(name, []byte(file.Content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	gendoc "github.com/daotl/protoc-gen-doc"
+	"github.com/daotl/protoc-gen-doc/serve"
+)
+
+// RunServer serves a live preview of the docs on the address given by the `-serve` flag. The docs are generated from
+// the `-descriptor_set` file if set, otherwise from the proto files passed as arguments, and regenerated whenever any
+// of them change.
+func RunServer(ctx context.Context, f *Flags) error {
+	var source serve.Source
+	switch {
+	case f.DescriptorSet() != "":
+		source = &serve.DescriptorSetFile{Path: f.DescriptorSet()}
+	case len(f.Args()) > 0:
+		source = &serve.Protoc{ImportPaths: f.ProtoPaths(), Files: f.Args()}
+	default:
+		return errors.New("Either -descriptor_set or proto files are required with -serve")
+	}
+
+	s := &serve.Server{Source: source, Options: gendoc.Options{Parameter: f.DocOpt()}}
+	go func() {
+		if err := s.Run(ctx, func(err error) { log.Printf("Unable to generate documentation: %v", err) }); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	log.Printf("Serving documentation on http://%s", f.Serve())
+	return http.ListenAndServe(f.Serve(), s)
+}
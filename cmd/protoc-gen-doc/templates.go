@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	gendoc "github.com/daotl/protoc-gen-doc"
+)
+
+// RunListTemplates writes the name and description of every built-in template to w.
+func RunListTemplates(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, t := range gendoc.BuiltinTemplates() {
+		fmt.Fprintf(tw, "%s\t%s\n", t.Name, t.Description)
+	}
+
+	return tw.Flush()
+}
+
+// RunDumpTemplate writes the built-in template named by the `-dump_template` flag to the `-doc_out` directory, e.g.
+// `html/slate` to `html-slate.tmpl`, and reports the path of the file to w.
+func RunDumpTemplate(w io.Writer, f *Flags) error {
+	t, err := gendoc.FindBuiltinTemplate(f.DumpTemplate())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.DocOut(), os.ModePerm); err != nil {
+		return err
+	}
+
+	file := filepath.Join(f.DocOut(), strings.ReplaceAll(t.Name, "/", "-")+".tmpl")
+	if err := os.WriteFile(file, t.Source(), 0644); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "Wrote %s to %s\n", t.Name, file)
+	return err
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	gendoc "github.com/daotl/protoc-gen-doc"
+)
+
+// RunValidation checks the `-doc_opt` flag in the same way protoc's `--doc_opt` would be checked, without needing any
+// proto files, and reports success to w.
+func RunValidation(w io.Writer, f *Flags) error {
+	if err := gendoc.Validate(f.DocOpt()); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "doc_opt %q is valid\n", f.DocOpt())
+	return err
+}
@@ -0,0 +1,135 @@
+package gendoc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Owner is an owner of a proto file, read from the CODEOWNERS file given with the codeowners_file option.
+type Owner struct {
+	// The owner as written in the CODEOWNERS file, e.g. @org/team, @user or an email address.
+	Name string `json:"name"`
+	// Where to contact the owner: the GitHub page of the team or user, or a mailto: link.
+	URL string `json:"url"`
+}
+
+// codeOwnersRule is a line of a CODEOWNERS file: the files matching the pattern are owned by the owners.
+type codeOwnersRule struct {
+	pattern *regexp.Regexp
+	owners  []*Owner
+}
+
+// readCodeOwners parses the CODEOWNERS file with the given name.
+func readCodeOwners(name string) ([]codeOwnersRule, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []codeOwnersRule
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		pattern, err := compileCodeOwnersPattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid codeowners_file %s:%d: %v", name, line, err)
+		}
+
+		rule := codeOwnersRule{pattern: pattern}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.owners = append(rule.owners, newOwner(owner))
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, scanner.Err()
+}
+
+// compileCodeOwnersPattern returns the regexp matching the file names matched by the gitignore-style pattern of a
+// CODEOWNERS line. Patterns with a slash other than a trailing one are relative to the root, and the others match at
+// any depth. Patterns matching a directory match all files in it.
+func compileCodeOwnersPattern(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("(?:/.*)?$")
+
+	return regexp.Compile(expr.String())
+}
+
+// newOwner returns the owner written as given in a CODEOWNERS file, linking @org/team and @user owners to their GitHub
+// pages and email addresses to mailto: links.
+func newOwner(name string) *Owner {
+	owner := &Owner{Name: name}
+
+	switch {
+	case strings.HasPrefix(name, "@") && strings.Contains(name, "/"):
+		parts := strings.SplitN(strings.TrimPrefix(name, "@"), "/", 2)
+		owner.URL = "https://github.com/orgs/" + parts[0] + "/teams/" + parts[1]
+	case strings.HasPrefix(name, "@"):
+		owner.URL = "https://github.com/" + strings.TrimPrefix(name, "@")
+	case strings.Contains(name, "@"):
+		owner.URL = "mailto:" + name
+	}
+
+	return owner
+}
+
+// fileOwners returns the owners of the file with the given name, given by the last matching rule as in GitHub. A
+// matching rule without owners leaves the file unowned.
+func fileOwners(name string, rules []codeOwnersRule) []*Owner {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].pattern.MatchString(name) {
+			return rules[i].owners
+		}
+	}
+
+	return nil
+}
+
+// codeOwnersKey returns a string identifying the rules, for the render cache.
+func codeOwnersKey(rules []codeOwnersRule) string {
+	var key strings.Builder
+	for _, rule := range rules {
+		key.WriteString(rule.pattern.String())
+		for _, owner := range rule.owners {
+			key.WriteString(" " + owner.Name)
+		}
+		key.WriteString("\n")
+	}
+
+	return key.String()
+}
@@ -0,0 +1,74 @@
+package gendoc_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCodeOwnersFile writes a CODEOWNERS file with the given content, returning its name.
+func writeCodeOwnersFile(t *testing.T, content string) string {
+	f, err := os.CreateTemp("", "CODEOWNERS-*")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(f.Name()) })
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	return f.Name()
+}
+
+func TestCodeOwners(t *testing.T) {
+	tests := []struct {
+		content string
+		owners  []*Owner
+	}{
+		{"* @org/everyone\n", []*Owner{{Name: "@org/everyone", URL: "https://github.com/orgs/org/teams/everyone"}}},
+		{"# API owners\n*.proto @jane api@example.com # reviewers\n", []*Owner{
+			{Name: "@jane", URL: "https://github.com/jane"},
+			{Name: "api@example.com", URL: "mailto:api@example.com"},
+		}},
+		// the last matching rule wins
+		{"*.proto @org/api\nBooking.proto @org/booking\n", []*Owner{
+			{Name: "@org/booking", URL: "https://github.com/orgs/org/teams/booking"},
+		}},
+		{"Booking.proto @org/booking\n/vehicles/ @org/vehicles\n", []*Owner{
+			{Name: "@org/booking", URL: "https://github.com/orgs/org/teams/booking"},
+		}},
+		{"/vehicles/**/*.proto @org/vehicles\n", nil},
+		// a rule without owners leaves the files unowned
+		{"* @org/everyone\nBooking.proto\n", nil},
+	}
+
+	for _, test := range tests {
+		req := newBookingRequest(t, "html,index.html:codeowners_file="+writeCodeOwnersFile(t, test.content))
+		options, err := ParseOptions(req)
+		require.NoError(t, err)
+
+		file := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0]
+		require.Equal(t, test.owners, file.Owners, test.content)
+	}
+
+	_, err := ParseOptions(newBookingRequest(t, "html,index.html:codeowners_file=missing/CODEOWNERS"))
+	require.Error(t, err)
+}
+
+func TestRenderCodeOwners(t *testing.T) {
+	codeOwners := writeCodeOwnersFile(t, "*.proto @org/booking support@example.com\n")
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:codeowners_file="+codeOwners))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), `<p class="owners">Owner: `+
+		`<a href="https://github.com/orgs/org/teams/booking">@org/booking</a>, `+
+		`<a href="mailto:support@example.com">support@example.com</a></p>`)
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, "markdown,README.md:codeowners_file="+codeOwners))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(),
+		"## Booking.proto\nOwner: [@org/booking](https://github.com/orgs/org/teams/booking), "+
+			"[support@example.com](mailto:support@example.com)\n")
+}
@@ -0,0 +1,153 @@
+package gendoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CommentFindingsFile is the output file listing the findings of the comment hook (see CommentHook), written when there
+// are any.
+const CommentFindingsFile = "comment-findings.json"
+
+// commentHookRule is the rule of the lint findings reported by the comment hook.
+const commentHookRule = "comment_hook"
+
+// HookComment is the description of an entity passed to a CommentHook.
+type HookComment struct {
+	// The file declaring the entity.
+	File string `json:"file"`
+	// One of message, field, enum, enum value, service or method.
+	Kind string `json:"kind"`
+	// The fully qualified name of the entity. Fields, enum values and methods are qualified by their parent.
+	Name string `json:"name"`
+	// The description, which the hook may change.
+	Description string `json:"description"`
+	// Issues the hook found with the description, e.g. misspelled words, which are reported alongside the output.
+	Findings []string `json:"findings,omitempty"`
+}
+
+// CommentHook processes the descriptions of the documented entities before they're rendered, e.g. to check their
+// spelling, enforce terminology or filter words. It's called once per generation, with every non-empty description.
+// It can change the Description of each comment, and add Findings.
+type CommentHook interface {
+	ProcessComments(comments []*HookComment) error
+}
+
+// CommentHookFunc is a CommentHook processing each comment with the function.
+type CommentHookFunc func(comment *HookComment) error
+
+// ProcessComments calls f with each comment.
+func (f CommentHookFunc) ProcessComments(comments []*HookComment) error {
+	for _, comment := range comments {
+		if err := f(comment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commandCommentHook is the CommentHook of the comment_hook option: a command reading the comments as a JSON array on
+// its standard input, and writing them back (with the same order and length) on its standard output.
+type commandCommentHook struct {
+	command string
+}
+
+func (h *commandCommentHook) ProcessComments(comments []*HookComment) error {
+	args := strings.Fields(h.command)
+	input, err := json.Marshal(comments)
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Comment hook %s failed: %v: %s", h.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return readHookOutput(h.command, comments, stdout.Bytes())
+}
+
+// readHookOutput updates comments with the ones written by a comment hook as a JSON array (with the same order and
+// length).
+func readHookOutput(hook string, comments []*HookComment, output []byte) error {
+	processed := make([]*HookComment, 0, len(comments))
+	if err := json.Unmarshal(output, &processed); err != nil {
+		return fmt.Errorf("Invalid output of comment hook %s: %v", hook, err)
+	}
+	if len(processed) != len(comments) {
+		return fmt.Errorf("Invalid output of comment hook %s: %d comments instead of %d", hook, len(processed),
+			len(comments))
+	}
+
+	for i, comment := range processed {
+		comments[i].Description = comment.Description
+		comments[i].Findings = comment.Findings
+	}
+
+	return nil
+}
+
+// applyCommentHook passes the descriptions of the template's entities to the comment hook, keeping the descriptions it
+// changed in pluginOptions for the templates built afterwards (see NewTemplate). It returns the findings of the hook.
+func applyCommentHook(template *Template, pluginOptions *PluginOptions) ([]*LintFinding, error) {
+	comments := make([]*HookComment, 0)
+	for _, f := range template.Files {
+		walkEntities(f, func(kind, _, fullName, description string) {
+			if description != "" {
+				comments = append(comments, &HookComment{File: f.Name, Kind: kind, Name: fullName, Description: description})
+			}
+		})
+	}
+
+	original := make([]string, len(comments))
+	for i, comment := range comments {
+		original[i] = comment.Description
+	}
+
+	if err := pluginOptions.CommentHook.ProcessComments(comments); err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[string]string)
+	findings := make([]*LintFinding, 0)
+	for i, comment := range comments {
+		if comment.Description != original[i] {
+			descriptions[commentKey(comment.Kind, comment.Name)] = comment.Description
+		}
+
+		for _, message := range comment.Findings {
+			findings = append(findings, &LintFinding{
+				File:    comment.File,
+				Kind:    comment.Kind,
+				Name:    comment.Name,
+				Rule:    commentHookRule,
+				Message: message,
+			})
+		}
+	}
+	pluginOptions.commentDescriptions = descriptions
+
+	return findings, nil
+}
+
+// commentKey returns the key of the description of an entity in PluginOptions.commentDescriptions.
+func commentKey(kind, fullName string) string {
+	return kind + " " + fullName
+}
+
+// applyCommentDescriptions replaces the descriptions of the file's entities changed by the comment hook.
+func applyCommentDescriptions(f *File, pluginOptions *PluginOptions) {
+	walkDescriptions(f, func(kind, _, fullName string, description *string) {
+		if changed, ok := pluginOptions.commentDescriptions[commentKey(kind, fullName)]; ok {
+			*description = changed
+		}
+	})
+}
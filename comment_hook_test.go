@@ -0,0 +1,90 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCommentHook(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	var comments []string
+	hook := CommentHookFunc(func(comment *HookComment) error {
+		comments = append(comments, comment.Kind+" "+comment.Name)
+		if strings.Contains(comment.Description, "Represents") {
+			comment.Description = strings.ReplaceAll(comment.Description, "Represents", "Describes")
+			comment.Findings = append(comment.Findings, `Use "Describes" rather than "Represents"`)
+		}
+		return nil
+	})
+
+	files, err := Generate(set, Options{
+		Parameter:       "markdown,docs.md",
+		FilesToGenerate: []string{"Booking.proto"},
+		CommentHook:     hook,
+	})
+	require.NoError(t, err)
+
+	// the hook is called once for each entity
+	require.Contains(t, comments, "message com.example.Booking")
+	require.Contains(t, comments, "field com.example.Booking.vehicle_id")
+	require.Contains(t, comments, "method com.example.BookingService.BookVehicle")
+	seen := make(map[string]bool)
+	for _, comment := range comments {
+		require.False(t, seen[comment], comment)
+		seen[comment] = true
+	}
+
+	require.Len(t, files, 2)
+	require.Contains(t, files[0].Content, "Describes the booking of a vehicle.")
+	require.NotContains(t, files[0].Content, "Represents")
+
+	require.Equal(t, CommentFindingsFile, files[1].Name)
+	var findings []*LintFinding
+	require.NoError(t, json.Unmarshal([]byte(files[1].Content), &findings))
+	require.Contains(t, findings, &LintFinding{
+		File:    "Booking.proto",
+		Kind:    "message",
+		Name:    "com.example.Booking",
+		Rule:    "comment_hook",
+		Message: `Use "Describes" rather than "Represents"`,
+	})
+}
+
+func TestCommentHookCommand(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("markdown,docs.md:comment_hook=sed s/Represents/Describes/g")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+	require.Contains(t, resp.File[0].GetContent(), "Describes the booking of a vehicle.")
+
+	req.Parameter = proto.String("markdown,docs.md:comment_hook=false")
+	_, err = new(Plugin).Generate(req)
+	require.EqualError(t, err, "Comment hook false failed: exit status 1: ")
+
+	req.Parameter = proto.String("markdown,docs.md:comment_hook=echo []")
+	_, err = new(Plugin).Generate(req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid output of comment hook echo []: 0 comments instead of")
+
+	req.Parameter = proto.String("markdown,docs.md:comment_hook=")
+	_, err = new(Plugin).Generate(req)
+	require.EqualError(t, err, "Invalid comment_hook value: ")
+}
+
+func TestValidateCommentHook(t *testing.T) {
+	require.NoError(t, Validate("markdown,docs.md:comment_hook=sed s/a/b/"))
+	require.Error(t, Validate("markdown,docs.md:comment_hook=protoc-gen-doc-missing-hook"))
+}
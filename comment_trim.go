@@ -0,0 +1,57 @@
+package gendoc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// compileCommentLinePattern compiles a pattern of the strip_comment_lines option, which must match the start of a line.
+func compileCommentLinePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")")
+}
+
+// leadingAsterisks matches the asterisks block comments commonly start their lines with, e.g. ` * Returns a book.`.
+var leadingAsterisks = regexp.MustCompile(`^\s*\*+(\s|$)`)
+
+// trimComment normalizes a description as set up with the strip_asterisks, strip_comment_lines and
+// strip_comment_paragraphs options: the leading asterisks of block comment lines and the closing `*/` are removed, then
+// the lines starting with a match of a strip_comment_lines pattern (e.g. `Next id:`), then the paragraphs matching a
+// strip_comment_paragraphs pattern (e.g. license boilerplate).
+func trimComment(description string, pluginOptions *PluginOptions) string {
+	if !pluginOptions.StripAsterisks && len(pluginOptions.StripCommentLines) == 0 &&
+		len(pluginOptions.StripCommentParagraphs) == 0 {
+		return description
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(description, "\n") {
+		if pluginOptions.StripAsterisks {
+			line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "*/"))
+			line = leadingAsterisks.ReplaceAllString(line, "")
+		}
+		if !matchesAny(pluginOptions.StripCommentLines, strings.TrimSpace(line)) {
+			lines = append(lines, line)
+		}
+	}
+
+	// lines that were removed may leave empty paragraphs behind, which are dropped along with the stripped ones
+	paragraphs := make([]string, 0)
+	for _, paragraph := range strings.Split(strings.Join(lines, "\n"), "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if strings.TrimSpace(paragraph) != "" && !matchesAny(pluginOptions.StripCommentParagraphs, paragraph) {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+
+	return false
+}
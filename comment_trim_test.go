@@ -0,0 +1,76 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newCommentTrimRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"library.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("library.proto"),
+			Package: proto.String("library"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("Book")},
+				{Name: proto.String("Shelf")},
+			},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{
+					Path: []int32{4, 0},
+					Span: []int32{1, 0, 1},
+					LeadingDetachedComments: []string{
+						" Copyright 2024 Example Inc.\n Licensed under the Apache License, Version 2.0.\n",
+					},
+					LeadingComments: proto.String(" A book.\n Next id: 4\n\n -*- mode: protobuf -*-\n"),
+				},
+				{
+					Path: []int32{4, 1},
+					Span: []int32{2, 0, 1},
+					LeadingDetachedComments: []string{
+						"*\n * Shelves hold books.\n *\n * They're ordered.\n ",
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func TestStripCommentOptions(t *testing.T) {
+	req := newCommentTrimRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	file := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0]
+	require.Contains(t, findMessage("Book", file).Description, "Copyright 2024")
+	require.Contains(t, findMessage("Book", file).Description, "Next id: 4")
+	require.Contains(t, findMessage("Shelf", file).Description, "* They're ordered.")
+
+	req = newCommentTrimRequest("html,index.html:strip_comment_lines=Next id:,strip_comment_lines=-\\*- mode," +
+		"strip_comment_paragraphs=(?i)copyright|licensed under,strip_asterisks=true")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+
+	file = NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0]
+	require.Equal(t, "A book.", findMessage("Book", file).Description)
+	require.Equal(t, "Shelves hold books.\n\nThey're ordered.", findMessage("Shelf", file).Description)
+}
+
+func TestStripCommentOptionsValidation(t *testing.T) {
+	_, err := ParseOptions(newCommentTrimRequest("html,index.html:strip_comment_lines=("))
+	require.EqualError(t, err, "Invalid strip_comment_lines value: (")
+
+	_, err = ParseOptions(newCommentTrimRequest("html,index.html:strip_comment_paragraphs="))
+	require.EqualError(t, err, "Invalid strip_comment_paragraphs value: ")
+
+	_, err = ParseOptions(newCommentTrimRequest("html,index.html:strip_asterisks=yes"))
+	require.EqualError(t, err, "Invalid strip_asterisks value: yes")
+}
@@ -0,0 +1,180 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Coverage reports how much of an API is documented. Messages, fields, enums, enum values, services and methods are
+// counted (except for the messages protoc generates for map fields); an entity is documented when it has a
+// (non-excluded) comment.
+type Coverage struct {
+	// Coverage of each file, in the order of the template's files.
+	Files []*FileCoverage `json:"files"`
+	// The number of documented entities across all files.
+	Documented int `json:"documented"`
+	// The number of entities across all files.
+	Total int `json:"total"`
+	// The percentage of documented entities across all files (100 when there are none).
+	Percent float64 `json:"percent"`
+}
+
+// FileCoverage reports how much of a single file is documented.
+type FileCoverage struct {
+	Name         string                `json:"name"`
+	Documented   int                   `json:"documented"`
+	Total        int                   `json:"total"`
+	Percent      float64               `json:"percent"`
+	Undocumented []*UndocumentedEntity `json:"undocumented"`
+}
+
+// UndocumentedEntity is an entity without a comment.
+type UndocumentedEntity struct {
+	// One of message, field, enum, enum value, service or method.
+	Kind string `json:"kind"`
+	// The fully qualified name of the entity. Fields, enum values and methods are qualified by their parent.
+	Name string `json:"name"`
+}
+
+// Coverage computes the documentation coverage of the template's files.
+func (t *Template) Coverage() *Coverage {
+	coverage := &Coverage{Files: make([]*FileCoverage, 0, len(t.Files))}
+
+	for _, f := range t.Files {
+		file := &FileCoverage{Name: f.Name, Undocumented: make([]*UndocumentedEntity, 0)}
+		walkEntities(f, func(kind, name, fullName, description string) {
+			file.add(kind, fullName, description)
+		})
+
+		file.Percent = coveragePercent(file.Documented, file.Total)
+		coverage.Documented += file.Documented
+		coverage.Total += file.Total
+		coverage.Files = append(coverage.Files, file)
+	}
+
+	coverage.Percent = coveragePercent(coverage.Documented, coverage.Total)
+	return coverage
+}
+
+func (f *FileCoverage) add(kind, name, description string) {
+	f.Total++
+	if description != "" {
+		f.Documented++
+		return
+	}
+
+	f.Undocumented = append(f.Undocumented, &UndocumentedEntity{Kind: kind, Name: name})
+}
+
+// walkEntities calls fn for every message, field, enum, enum value, service and method of a file (skipping the messages
+// protoc generates for map fields, and the placeholders of redacted entities). Fields, enum values and methods get a
+// full name qualified by their parent.
+func walkEntities(f *File, fn func(kind, name, fullName, description string)) {
+	walkDescriptions(f, func(kind, name, fullName string, description *string) {
+		fn(kind, name, fullName, *description)
+	})
+}
+
+// walkDescriptions is walkEntities, passing the descriptions by reference so that fn can change them.
+func walkDescriptions(f *File, fn func(kind, name, fullName string, description *string)) {
+	// map entries are generated by protoc, there's nothing to document
+	mapEntries := make(map[string]bool)
+	for _, m := range f.AllMessages() {
+		for _, field := range m.Fields {
+			if field.IsMap {
+				mapEntries[field.FullType] = true
+			}
+		}
+	}
+
+	for _, m := range f.AllMessages() {
+		if mapEntries[m.FullName] || m.Redacted {
+			continue
+		}
+
+		fn("message", m.Name, m.FullName, &m.Description)
+		for _, field := range m.Fields {
+			if field.Redacted {
+				continue
+			}
+			fn("field", field.Name, m.FullName+"."+field.Name, &field.Description)
+		}
+	}
+
+	for _, e := range f.AllEnums() {
+		fn("enum", e.Name, e.FullName, &e.Description)
+		for _, value := range e.Values {
+			if value.Redacted {
+				continue
+			}
+			fn("enum value", value.Name, e.FullName+"."+value.Name, &value.Description)
+		}
+	}
+
+	for _, s := range f.Services {
+		fn("service", s.Name, s.FullName, &s.Description)
+		for _, method := range s.Methods {
+			fn("method", method.Name, s.FullName+"."+method.Name, &method.Description)
+		}
+	}
+}
+
+// coveragePercent returns documented/total as a percentage rounded to two decimal places.
+func coveragePercent(documented, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+
+	return math.Round(float64(documented)/float64(total)*10000) / 100
+}
+
+// coverageRenderer renders a coverage report, either as plain text or as JSON.
+type coverageRenderer struct {
+	json bool
+}
+
+func (r *coverageRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *coverageRenderer) ApplyTo(w io.Writer, template *Template) error {
+	coverage := template.Coverage()
+
+	if r.json {
+		data, err := json.MarshalIndent(coverage, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+		return err
+	}
+
+	for _, file := range coverage.Files {
+		if _, err := fmt.Fprintf(w, "%s: %.2f%% (%d/%d)\n", file.Name, file.Percent, file.Documented, file.Total); err != nil {
+			return err
+		}
+
+		for _, entity := range file.Undocumented {
+			if _, err := fmt.Fprintf(w, "  %-10s  %s\n", entity.Kind, entity.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "Total: %.2f%% (%d/%d)\n", coverage.Percent, coverage.Documented, coverage.Total)
+	return err
+}
+
+// checkCoverage fails when the documentation coverage of template is below threshold (a percentage).
+func checkCoverage(template *Template, threshold float64) error {
+	coverage := template.Coverage()
+	if coverage.Percent >= threshold {
+		return nil
+	}
+
+	return fmt.Errorf("Documentation coverage of %.2f%% is below the threshold of %.2f%% (%d of %d entities are undocumented)",
+		coverage.Percent, threshold, coverage.Total-coverage.Documented, coverage.Total)
+}
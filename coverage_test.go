@@ -0,0 +1,75 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func coverageTemplate(t *testing.T) *Template {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	return NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+}
+
+func TestCoverage(t *testing.T) {
+	coverage := coverageTemplate(t).Coverage()
+	require.Len(t, coverage.Files, 3)
+	require.Equal(t, 69, coverage.Documented)
+	require.Equal(t, 105, coverage.Total)
+	require.Equal(t, 65.71, coverage.Percent)
+
+	booking := coverage.Files[0]
+	require.Equal(t, "Booking.proto", booking.Name)
+	require.Equal(t, 100.0, booking.Percent)
+	require.Empty(t, booking.Undocumented)
+
+	vehicle := coverage.Files[1]
+	require.Equal(t, "Vehicle.proto", vehicle.Name)
+	require.Equal(t, 44, vehicle.Documented)
+	require.Equal(t, 60, vehicle.Total)
+	require.Contains(t, vehicle.Undocumented, &UndocumentedEntity{Kind: "field", Name: "com.example.Vehicle.kilometers"})
+	require.Contains(t, vehicle.Undocumented, &UndocumentedEntity{Kind: "enum value", Name: "com.example.Vehicle.Engine.FuelType.PETROL"})
+
+	// map entries aren't counted
+	for _, entity := range vehicle.Undocumented {
+		require.NotContains(t, entity.Name, "PropertiesEntry")
+	}
+
+	book := coverage.Files[2]
+	require.Zero(t, book.Percent)
+	require.Contains(t, book.Undocumented, &UndocumentedEntity{Kind: "service", Name: "com.book.BookService"})
+	require.Contains(t, book.Undocumented, &UndocumentedEntity{Kind: "method", Name: "com.book.BookService.GetBook"})
+}
+
+func TestCoverageWithoutEntities(t *testing.T) {
+	coverage := new(Template).Coverage()
+	require.Empty(t, coverage.Files)
+	require.Equal(t, 100.0, coverage.Percent)
+}
+
+func TestCoverageRenderers(t *testing.T) {
+	template := coverageTemplate(t)
+
+	output, err := RenderTemplate(RenderTypeCoverage, template, "")
+	require.NoError(t, err)
+
+	text := string(output)
+	require.Contains(t, text, "Booking.proto: 100.00% (25/25)\n")
+	require.Contains(t, text, "Vehicle.proto: 73.33% (44/60)\n  field       com.example.Vehicle.kilometers\n")
+	require.Contains(t, text, "  enum value  com.book.EnumSample.UNKNOWN\n")
+	require.Contains(t, text, "Total: 65.71% (69/105)\n")
+
+	output, err = RenderTemplate(RenderTypeCoverageJSON, template, "")
+	require.NoError(t, err)
+
+	coverage := new(Coverage)
+	require.NoError(t, json.Unmarshal(output, coverage))
+	require.Equal(t, template.Coverage(), coverage)
+}
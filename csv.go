@@ -0,0 +1,62 @@
+package gendoc
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// csvHeader names the columns of the csv and tsv formats.
+var csvHeader = []string{"file", "message", "field", "type", "number", "label", "deprecated", "description"}
+
+// csvRenderer writes a row for every field of every message (nested ones included, regardless of the template API),
+// for importing into spreadsheets and data catalogs. Redacted messages and fields are left out.
+type csvRenderer struct {
+	tsv bool
+}
+
+func (r *csvRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *csvRenderer) ApplyTo(w io.Writer, template *Template) error {
+	out := csv.NewWriter(w)
+	if r.tsv {
+		out.Comma = '\t'
+	}
+
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			if message.Redacted {
+				continue
+			}
+
+			for _, field := range message.Fields {
+				if field.Redacted {
+					continue
+				}
+
+				err := out.Write([]string{
+					file.Name,
+					message.FullName,
+					field.Name,
+					field.LongType,
+					strconv.Itoa(field.Number),
+					field.Label,
+					strconv.FormatBool(field.Option("deprecated") == true),
+					field.Description,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
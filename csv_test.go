@@ -0,0 +1,61 @@
+package gendoc_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCSV(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeCSV, newBookingTemplate(t), "")
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(string(output))).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"file", "message", "field", "type", "number", "label", "deprecated", "description"},
+		records[0])
+	require.Equal(t, []string{
+		"Booking.proto", "com.example.Booking", "vehicle_id", "int32", "1", "required", "false", "ID of booked vehicle.",
+	}, records[1])
+	require.Contains(t, records, []string{
+		"Booking.proto", "com.example.Booking", "color_preference", "string", "6", "optional", "true",
+		"Color preference of the customer.",
+	})
+	require.Contains(t, records, []string{
+		"Booking.proto", "com.example.BookingStatus", "description", "string", "2", "required", "false",
+		`Booking status description. E.g. "Active".`,
+	})
+}
+
+func TestRenderTSV(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeTSV, newBookingTemplate(t), "")
+	require.NoError(t, err)
+
+	reader := csv.NewReader(strings.NewReader(string(output)))
+	reader.Comma = '\t'
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(output), "file\tmessage\tfield\t"))
+	require.Equal(t, "vehicle_id", records[1][2])
+}
+
+func TestRenderCSVOmitsRedactedFields(t *testing.T) {
+	template := &Template{Files: []*File{{
+		Name: "user.proto",
+		Messages: []*Message{
+			{FullName: "org.User", Fields: []*MessageField{
+				{Name: "id", LongType: "string", Number: 1},
+				{Name: "«redacted»", Redacted: true},
+			}},
+			{FullName: "«redacted»", Redacted: true},
+		},
+	}}}
+
+	output, err := RenderTemplate(RenderTypeCSV, template, "")
+	require.NoError(t, err)
+	require.Equal(t, "file,message,field,type,number,label,deprecated,description\n"+
+		"user.proto,org.User,id,string,1,,false,\n", string(output))
+}
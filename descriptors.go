@@ -0,0 +1,111 @@
+package gendoc
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// fileDescriptor returns the protoreflect descriptor of fd. Its dependencies are resolved with the descriptors of the
+// request when they're known, and replaced with placeholders otherwise. Returns nil when fd isn't valid.
+func fileDescriptor(fd *descriptorpb.FileDescriptorProto, pluginOptions *PluginOptions) protoreflect.FileDescriptor {
+	if pluginOptions.descriptors != nil {
+		if file, err := pluginOptions.descriptors.FindFileByPath(fd.GetName()); err == nil {
+			return file
+		}
+	}
+
+	file, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fd, new(protoregistry.Files))
+	if err != nil {
+		return nil
+	}
+
+	return file
+}
+
+// descriptorsByName indexes the messages, enums and services declared in file, including nested ones, by full name.
+func descriptorsByName(file protoreflect.FileDescriptor) map[string]protoreflect.Descriptor {
+	descriptors := make(map[string]protoreflect.Descriptor)
+
+	var addEnums func(protoreflect.EnumDescriptors)
+	addEnums = func(enums protoreflect.EnumDescriptors) {
+		for i := 0; i < enums.Len(); i++ {
+			descriptors[string(enums.Get(i).FullName())] = enums.Get(i)
+		}
+	}
+
+	var addMessages func(protoreflect.MessageDescriptors)
+	addMessages = func(messages protoreflect.MessageDescriptors) {
+		for i := 0; i < messages.Len(); i++ {
+			descriptors[string(messages.Get(i).FullName())] = messages.Get(i)
+			addEnums(messages.Get(i).Enums())
+			addMessages(messages.Get(i).Messages())
+		}
+	}
+
+	addEnums(file.Enums())
+	addMessages(file.Messages())
+	for i := 0; i < file.Services().Len(); i++ {
+		descriptors[string(file.Services().Get(i).FullName())] = file.Services().Get(i)
+	}
+
+	return descriptors
+}
+
+// applyDescriptors sets the protoreflect descriptors of f and the entities it documents.
+func applyDescriptors(f *File, fd *descriptorpb.FileDescriptorProto, pluginOptions *PluginOptions) {
+	file := fileDescriptor(fd, pluginOptions)
+	if file == nil {
+		return
+	}
+
+	f.Descriptor = file
+	descriptors := descriptorsByName(file)
+	lookup := func(fullName string) protoreflect.Descriptor {
+		// entities of files without a package have a leading dot
+		return descriptors[strings.TrimPrefix(fullName, ".")]
+	}
+
+	for _, m := range f.AllMessages() {
+		desc, ok := lookup(m.FullName).(protoreflect.MessageDescriptor)
+		if m.Redacted || !ok {
+			continue
+		}
+
+		m.Descriptor = desc
+		for _, field := range m.Fields {
+			if !field.Redacted {
+				field.Descriptor = desc.Fields().ByNumber(protoreflect.FieldNumber(field.Number))
+			}
+		}
+	}
+
+	for _, e := range f.AllEnums() {
+		desc, ok := lookup(e.FullName).(protoreflect.EnumDescriptor)
+		if !ok {
+			continue
+		}
+
+		e.Descriptor = desc
+		for _, value := range e.Values {
+			if !value.Redacted {
+				value.Descriptor = desc.Values().ByName(protoreflect.Name(value.Name))
+			}
+		}
+	}
+
+	for _, s := range f.Services {
+		desc, ok := lookup(s.FullName).(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+
+		s.Descriptor = desc
+		for _, method := range s.Methods {
+			method.Descriptor = desc.Methods().ByName(protoreflect.Name(method.Name))
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestDescriptors(t *testing.T) {
+	file := NewTemplate(protokit.ParseCodeGenRequest(newBookingRequest(t, "")), new(PluginOptions)).Files[0]
+	require.Equal(t, "Booking.proto", file.Descriptor.Path())
+	require.Equal(t, protoreflect.FullName("com.example"), file.Descriptor.Package())
+
+	booking := findMessage("Booking", file)
+	require.Equal(t, protoreflect.FullName("com.example.Booking"), booking.Descriptor.FullName())
+	for _, field := range booking.Fields {
+		require.Equal(t, protoreflect.Name(field.Name), field.Descriptor.Name())
+	}
+
+	enum := findEnum("BookingStatus.StatusCode", file)
+	require.Equal(t, protoreflect.FullName("com.example.BookingStatus.StatusCode"), enum.Descriptor.FullName())
+	require.Equal(t, protoreflect.EnumNumber(200), enum.Values[0].Descriptor.Number())
+
+	service := findService("BookingService", file)
+	require.Equal(t, protoreflect.FullName("com.example.BookingService"), service.Descriptor.FullName())
+	for _, method := range service.Methods {
+		require.Equal(t, protoreflect.Name(method.Name), method.Descriptor.Name())
+	}
+
+	// without the rest of the request, imports are placeholders
+	require.True(t, file.Descriptor.Imports().Get(0).IsPlaceholder())
+}
+
+func TestRenderDescriptors(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{.Descriptor.Path}} imports {{range .Descriptor.Imports}}`+
+		`{{.Path}}{{if .IsPlaceholder}} (unresolved){{end}}{{end}}{{end}}`)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, templateFile+",output.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "Booking.proto imports github.com/pseudomuto/protokit/fixtures/extend.proto",
+		resp.File[0].GetContent())
+}
@@ -1,7 +1,7 @@
 // Package gendoc is a protoc plugin for generating documentation from your proto files.
 //
-// Typically this will not be used as a library, though nothing prevents that. Normally it'll be invoked by passing
-// `--doc_out` and `--doc_opt` values to protoc.
+// Normally it'll be invoked by passing `--doc_out` and `--doc_opt` values to protoc. It can also be used as a library
+// through Generate, which renders documentation straight from a FileDescriptorSet.
 //
 // Example: generate HTML documentation
 //
@@ -0,0 +1,65 @@
+package gendoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OlinkTarget maps the types of a package to the DocBook document they're documented in, for olink cross-references
+// between separately generated books. See the olink option.
+type OlinkTarget struct {
+	// The package whose types (including the ones of its subpackages) are documented in TargetDoc, e.g. `com.example`.
+	Package string
+	// The targetdoc of the olinks to the package's types, as known to the olink database, e.g. `vehicles`.
+	TargetDoc string
+}
+
+// parseOlinkTarget parses the value of the olink option: `package=targetdoc`.
+func parseOlinkTarget(value string) (OlinkTarget, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return OlinkTarget{}, fmt.Errorf("Invalid olink value: %v", value)
+	}
+
+	return OlinkTarget{Package: parts[0], TargetDoc: parts[1]}, nil
+}
+
+// olinkTargetDoc returns the targetdoc of the document fullName is documented in, or "" when no olink target covers
+// it. The target of the longest matching package wins.
+func (t *Template) olinkTargetDoc(fullName string) string {
+	doc, longest := "", -1
+	for _, target := range t.OlinkTargets {
+		if strings.HasPrefix(fullName, target.Package+".") && len(target.Package) > longest {
+			doc, longest = target.TargetDoc, len(target.Package)
+		}
+	}
+
+	return doc
+}
+
+// docbookIDs returns the xml:ids of the sections the DocBook template writes for the template's messages, enums,
+// services and scalar value types.
+func (t *Template) docbookIDs() map[string]bool {
+	ids := t.documentedTypes()
+	for _, s := range t.Scalars {
+		ids[s.ProtoType] = true
+	}
+
+	return ids
+}
+
+// docbookLink returns the DocBook 5 markup of a reference to the type fullName, shown as text: a link when the type is
+// documented in the same document, an olink when it's documented in another one (see the olink option), and the plain
+// text otherwise, so that the output never refers to a missing xml:id.
+func docbookLink(ids map[string]bool, t *Template, fullName, text string) string {
+	fullName = strings.TrimPrefix(fullName, ".")
+	if ids[fullName] {
+		return fmt.Sprintf(`<link linkend="%s">%s</link>`, fullName, text)
+	}
+
+	if doc := t.olinkTargetDoc(fullName); doc != "" {
+		return fmt.Sprintf(`<olink targetdoc="%s" targetptr="%s">%s</olink>`, doc, fullName, text)
+	}
+
+	return text
+}
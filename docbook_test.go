@@ -0,0 +1,55 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestParseOptionsForOlink(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("docbook,docs.xml:olink=google=googleapis,olink=google.longrunning=lro")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, []OlinkTarget{
+		{Package: "google", TargetDoc: "googleapis"},
+		{Package: "google.longrunning", TargetDoc: "lro"},
+	}, options.OlinkTargets)
+
+	for _, value := range []string{"google", "=googleapis", "google="} {
+		req.Parameter = proto.String("docbook,docs.xml:olink=" + value)
+		_, err = ParseOptions(req)
+		require.EqualError(t, err, "Invalid olink value: "+value)
+	}
+}
+
+func TestRenderDocBook5(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "docbook,docs.xml"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<article xmlns="http://docbook.org/ns/docbook" `+
+		`xmlns:xlink="http://www.w3.org/1999/xlink" version="5.0">`)
+	require.Contains(t, content, `<section xml:id="Booking-proto"><?dbhtml filename="Booking-proto.html"?>`)
+	require.Contains(t, content, `<section xml:id="com.example.Booking">`)
+	require.Contains(t, content, `<entry><link linkend="com.example.BookingStatus">BookingStatus</link></entry>`)
+	require.Contains(t, content, `<entry xml:id="int32">int32</entry>`)
+	require.NotContains(t, content, ` id="`)
+}
+
+func TestRenderDocBookOlinks(t *testing.T) {
+	resp, err := new(Plugin).Generate(newOperationsRequest(t, "docbook,docs.xml:olink=google=googleapis,"+
+		"olink=google.longrunning=lro"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<olink targetdoc="lro" targetptr="google.longrunning.Operation">`+
+		`.google.longrunning.Operation</olink>`)
+	require.Contains(t, content, `Response: <olink targetdoc="googleapis" targetptr="google.protobuf.Empty">`+
+		`google.protobuf.Empty</olink>`)
+	require.Contains(t, content, `<link linkend="library.Book">Book</link>`)
+}
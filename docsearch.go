@@ -0,0 +1,126 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+)
+
+// DocSearchRecord is a record of the DocSearch index written with the docsearch option, in the format of the records
+// of the DocSearch crawler (see https://docsearch.algolia.com/docs/record-extractor), so that hosted docs can be
+// searched without crawling them. Every message, enum and service is a lvl1 record, and their fields, values and
+// methods lvl2 records below them. The package is the lvl0 of all records of its files.
+type DocSearchRecord struct {
+	// A unique ID of the record: the full name of the entity.
+	ObjectID string `json:"objectID"`
+	// The URL of the section documenting the entity, and the same without the anchor. Absolute when site_url is set,
+	// and relative to the output root otherwise.
+	URL              string `json:"url"`
+	URLWithoutAnchor string `json:"url_without_anchor"`
+	Anchor           string `json:"anchor"`
+	// The level of the entity in the hierarchy (lvl1 or lvl2).
+	Type string `json:"type"`
+	// The names of the package (lvl0), the entity or its parent (lvl1) and the entity (lvl2).
+	Hierarchy DocSearchHierarchy `json:"hierarchy"`
+	// The description of the entity. Null when it isn't documented.
+	Content *string `json:"content"`
+	// One of message, field, enum, enum value, service or method (see IndexEntry).
+	Kind string `json:"kind"`
+	// The weights DocSearch ranks the records with: entities higher up in the hierarchy come first.
+	Weight DocSearchWeight `json:"weight"`
+}
+
+// DocSearchHierarchy is the hierarchy of a DocSearchRecord. DocSearch expects all levels to be present, those below
+// the record's level being null.
+type DocSearchHierarchy struct {
+	Lvl0 *string `json:"lvl0"`
+	Lvl1 *string `json:"lvl1"`
+	Lvl2 *string `json:"lvl2"`
+	Lvl3 *string `json:"lvl3"`
+	Lvl4 *string `json:"lvl4"`
+	Lvl5 *string `json:"lvl5"`
+	Lvl6 *string `json:"lvl6"`
+}
+
+// DocSearchWeight is the ranking of a DocSearchRecord.
+type DocSearchWeight struct {
+	PageRank int `json:"pageRank"`
+	Level    int `json:"level"`
+	Position int `json:"position"`
+}
+
+// docSearchLevelWeights are the level weights of the lvl1 and lvl2 records, as given by the DocSearch crawler.
+var docSearchLevelWeights = map[string]int{"lvl1": 90, "lvl2": 80}
+
+// hasDocSearch returns whether a DocSearch index should be written. Its records link to the sections of HTML pages.
+func hasDocSearch(pluginOptions *PluginOptions) bool {
+	return pluginOptions.DocSearch != "" && pluginOptions.Type == RenderTypeHTML
+}
+
+// docSearchRecords returns the records of the entities documented by the pages of fdsGroup, in the order of dirs and
+// of the entities on each page.
+func docSearchRecords(fdsGroup map[string][]*protokit.FileDescriptor, dirs []string,
+	pluginOptions *PluginOptions) []*DocSearchRecord {
+	records := make([]*DocSearchRecord, 0)
+
+	for _, dir := range dirs {
+		page := filepath.ToSlash(outputName(pluginOptions, dir))
+		if pluginOptions.SiteURL != "" {
+			page = pageURL(pluginOptions, page)
+		}
+
+		for _, f := range NewTemplate(fdsGroup[dir], pluginOptions).Files {
+			lvl0 := f.Package
+			if lvl0 == "" {
+				lvl0 = f.Name
+			}
+
+			parents := make(map[string]string)
+			walkEntities(f, func(kind, name, fullName, description string) {
+				record := &DocSearchRecord{
+					ObjectID: fullName,
+					Anchor:   fullName,
+					Type:     "lvl1",
+					Kind:     kind,
+				}
+				record.Hierarchy.Lvl0 = &lvl0
+
+				switch kind {
+				case "field", "enum value", "method":
+					parent := strings.TrimSuffix(fullName, "."+name)
+					parentName := parents[parent]
+					record.Anchor = parent
+					record.Type = "lvl2"
+					record.Hierarchy.Lvl1 = &parentName
+					record.Hierarchy.Lvl2 = &name
+				default:
+					parents[fullName] = name
+					record.Hierarchy.Lvl1 = &name
+				}
+
+				record.URLWithoutAnchor = page
+				record.URL = page + "#" + record.Anchor
+				if content := strings.TrimSpace(description); content != "" {
+					record.Content = &content
+				}
+				record.Weight = DocSearchWeight{Level: docSearchLevelWeights[record.Type], Position: len(records)}
+
+				records = append(records, record)
+			})
+		}
+	}
+
+	return records
+}
+
+// renderDocSearch renders the records of the DocSearch index as a JSON array, ready to be uploaded to Algolia.
+func renderDocSearch(records []*DocSearchRecord) (string, error) {
+	content, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(content) + "\n", nil
+}
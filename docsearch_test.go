@@ -0,0 +1,95 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func generateDocSearch(t *testing.T, parameter string) (map[string]*DocSearchRecord, []*DocSearchRecord) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String(parameter)
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	var content string
+	for _, f := range resp.File {
+		if f.GetName() == "search.json" {
+			content = f.GetContent()
+		}
+	}
+	require.NotEmpty(t, content)
+
+	records := make([]*DocSearchRecord, 0)
+	require.NoError(t, json.Unmarshal([]byte(content), &records))
+
+	byID := make(map[string]*DocSearchRecord, len(records))
+	for _, record := range records {
+		byID[record.ObjectID] = record
+	}
+	return byID, records
+}
+
+func TestParseOptionsForDocSearch(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:docsearch=search.json"))
+	require.NoError(t, err)
+	require.Equal(t, "search.json", options.DocSearch)
+
+	_, err = ParseOptions(&pluginpb.CodeGeneratorRequest{Parameter: proto.String("html,index.html:docsearch=")})
+	require.Error(t, err)
+}
+
+func TestDocSearch(t *testing.T) {
+	byID, records := generateDocSearch(t, "html,index.html,source_relative:docsearch=search.json")
+
+	message := byID["com.example.Booking"]
+	require.NotNil(t, message)
+	require.Equal(t, "lvl1", message.Type)
+	require.Equal(t, "message", message.Kind)
+	require.Equal(t, "index.html#com.example.Booking", message.URL)
+	require.Equal(t, "index.html", message.URLWithoutAnchor)
+	require.Equal(t, "com.example", *message.Hierarchy.Lvl0)
+	require.Equal(t, "Booking", *message.Hierarchy.Lvl1)
+	require.Nil(t, message.Hierarchy.Lvl2)
+	require.Equal(t, 90, message.Weight.Level)
+
+	field := byID["com.example.Booking.vehicle_id"]
+	require.NotNil(t, field)
+	require.Equal(t, "lvl2", field.Type)
+	require.Equal(t, "index.html#com.example.Booking", field.URL)
+	require.Equal(t, "Booking", *field.Hierarchy.Lvl1)
+	require.Equal(t, "vehicle_id", *field.Hierarchy.Lvl2)
+	require.Equal(t, "ID of booked vehicle.", *field.Content)
+	require.Equal(t, 80, field.Weight.Level)
+
+	method := byID["com.example.BookingService.BookVehicle"]
+	require.NotNil(t, method)
+	require.Equal(t, "method", method.Kind)
+	require.Equal(t, "index.html#com.example.BookingService", method.URL)
+
+	book := byID["com.book.Book"]
+	require.NotNil(t, book)
+	require.Equal(t, "nested/index.html#com.book.Book", book.URL)
+
+	for i, record := range records {
+		require.Equal(t, i, record.Weight.Position)
+	}
+}
+
+func TestDocSearchWithSiteURL(t *testing.T) {
+	byID, _ := generateDocSearch(t, "html,index.html:docsearch=search.json,site_url=https://docs.example.com/api/")
+
+	message := byID["com.example.Booking"]
+	require.NotNil(t, message)
+	require.Equal(t, "https://docs.example.com/api/index.html#com.example.Booking", message.URL)
+	require.Equal(t, "https://docs.example.com/api/index.html", message.URLWithoutAnchor)
+}
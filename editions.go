@@ -0,0 +1,195 @@
+package gendoc
+
+import (
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// The syntax of files using editions, as given by FileDescriptorProto.syntax.
+const syntaxEditions = "editions"
+
+// Features are the resolved features of an editions file: the defaults of its edition, overridden by its `features`
+// options. Values are the names of the enum values, as written in options, e.g. `EXPLICIT` for field_presence.
+type Features struct {
+	FieldPresence         string `json:"fieldPresence"`
+	EnumType              string `json:"enumType"`
+	RepeatedFieldEncoding string `json:"repeatedFieldEncoding"`
+	UTF8Validation        string `json:"utf8Validation"`
+	MessageEncoding       string `json:"messageEncoding"`
+	JSONFormat            string `json:"jsonFormat"`
+}
+
+// FeatureValue is the value of a feature, named as in options, e.g. field_presence.
+type FeatureValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// List returns the values of the features, in the order they're declared in descriptor.proto.
+func (f Features) List() []FeatureValue {
+	return []FeatureValue{
+		{"field_presence", f.FieldPresence},
+		{"enum_type", f.EnumType},
+		{"repeated_field_encoding", f.RepeatedFieldEncoding},
+		{"utf8_validation", f.UTF8Validation},
+		{"message_encoding", f.MessageEncoding},
+		{"json_format", f.JSONFormat},
+	}
+}
+
+// editionDefaults returns the features of edition, before any `features` option. proto2 and proto3 files behave as if
+// they used the EDITION_PROTO2 and EDITION_PROTO3 defaults.
+func editionDefaults(edition descriptorpb.Edition) *descriptorpb.FeatureSet {
+	switch {
+	case edition == descriptorpb.Edition_EDITION_PROTO2 || edition < descriptorpb.Edition_EDITION_PROTO3:
+		return &descriptorpb.FeatureSet{
+			FieldPresence:         descriptorpb.FeatureSet_EXPLICIT.Enum(),
+			EnumType:              descriptorpb.FeatureSet_CLOSED.Enum(),
+			RepeatedFieldEncoding: descriptorpb.FeatureSet_EXPANDED.Enum(),
+			Utf8Validation:        descriptorpb.FeatureSet_NONE.Enum(),
+			MessageEncoding:       descriptorpb.FeatureSet_LENGTH_PREFIXED.Enum(),
+			JsonFormat:            descriptorpb.FeatureSet_LEGACY_BEST_EFFORT.Enum(),
+		}
+	case edition == descriptorpb.Edition_EDITION_PROTO3:
+		return &descriptorpb.FeatureSet{
+			FieldPresence:         descriptorpb.FeatureSet_IMPLICIT.Enum(),
+			EnumType:              descriptorpb.FeatureSet_OPEN.Enum(),
+			RepeatedFieldEncoding: descriptorpb.FeatureSet_PACKED.Enum(),
+			Utf8Validation:        descriptorpb.FeatureSet_VERIFY.Enum(),
+			MessageEncoding:       descriptorpb.FeatureSet_LENGTH_PREFIXED.Enum(),
+			JsonFormat:            descriptorpb.FeatureSet_ALLOW.Enum(),
+		}
+	}
+
+	// the defaults of these features haven't changed since edition 2023
+	return &descriptorpb.FeatureSet{
+		FieldPresence:         descriptorpb.FeatureSet_EXPLICIT.Enum(),
+		EnumType:              descriptorpb.FeatureSet_OPEN.Enum(),
+		RepeatedFieldEncoding: descriptorpb.FeatureSet_PACKED.Enum(),
+		Utf8Validation:        descriptorpb.FeatureSet_VERIFY.Enum(),
+		MessageEncoding:       descriptorpb.FeatureSet_LENGTH_PREFIXED.Enum(),
+		JsonFormat:            descriptorpb.FeatureSet_ALLOW.Enum(),
+	}
+}
+
+// fileEdition returns the edition of fd, EDITION_PROTO2 or EDITION_PROTO3 for files that don't use editions.
+func fileEdition(fd *descriptorpb.FileDescriptorProto) descriptorpb.Edition {
+	switch fd.GetSyntax() {
+	case syntaxEditions:
+		return fd.GetEdition()
+	case "proto3":
+		return descriptorpb.Edition_EDITION_PROTO3
+	}
+
+	return descriptorpb.Edition_EDITION_PROTO2
+}
+
+// resolveFeatures returns the features of parent overridden by the ones set in features, which may be nil.
+func resolveFeatures(parent, features *descriptorpb.FeatureSet) *descriptorpb.FeatureSet {
+	resolved := proto.Clone(parent).(*descriptorpb.FeatureSet)
+	if features != nil {
+		proto.Merge(resolved, features)
+	}
+
+	return resolved
+}
+
+// fileFeatures returns the resolved features of fd.
+func fileFeatures(fd *descriptorpb.FileDescriptorProto) *descriptorpb.FeatureSet {
+	return resolveFeatures(editionDefaults(fileEdition(fd)), fd.GetOptions().GetFeatures())
+}
+
+// messageFeatures returns the resolved features of pm, inherited from its parent messages and file.
+func messageFeatures(pm *protokit.Descriptor) *descriptorpb.FeatureSet {
+	var parent *descriptorpb.FeatureSet
+	if pm.GetParent() != nil {
+		parent = messageFeatures(pm.GetParent())
+	} else {
+		parent = fileFeatures(pm.GetFile().FileDescriptorProto)
+	}
+
+	return resolveFeatures(parent, pm.GetOptions().GetFeatures())
+}
+
+// fieldFeatures returns the resolved features of pf, inherited from its oneof, parent messages and file.
+func fieldFeatures(pf *protokit.FieldDescriptor) *descriptorpb.FeatureSet {
+	pm := pf.GetMessage()
+	features := messageFeatures(pm)
+	if pf.OneofIndex != nil && int(pf.GetOneofIndex()) < len(pm.GetOneofDecl()) {
+		features = resolveFeatures(features, pm.GetOneofDecl()[pf.GetOneofIndex()].GetOptions().GetFeatures())
+	}
+
+	return resolveFeatures(features, pf.GetOptions().GetFeatures())
+}
+
+// effectivePresence returns whether the singular field pf tracks presence: explicit when it's a message, a member of
+// a oneof, a proto3 optional or a required field, or when its field_presence feature is EXPLICIT, and implicit
+// otherwise. It returns an empty string for repeated fields, which don't track presence.
+func effectivePresence(pf *protokit.FieldDescriptor, features *descriptorpb.FeatureSet) string {
+	switch {
+	case pf.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+		return ""
+	case pf.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE,
+		pf.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP,
+		pf.OneofIndex != nil,
+		pf.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED,
+		features.GetFieldPresence() != descriptorpb.FeatureSet_IMPLICIT:
+		return "explicit"
+	}
+
+	return "implicit"
+}
+
+// effectiveUTF8Validation returns whether the values of the string field pf are validated as UTF-8.
+func effectiveUTF8Validation(pf *protokit.FieldDescriptor, features *descriptorpb.FeatureSet) bool {
+	return pf.GetType() == descriptorpb.FieldDescriptorProto_TYPE_STRING &&
+		features.GetUtf8Validation() == descriptorpb.FeatureSet_VERIFY
+}
+
+// effectivePacked returns whether the values of the repeated scalar field pf are packed on the wire, as set by its
+// packed option in proto2 and proto3 files, or by its repeated_field_encoding feature.
+func effectivePacked(pf *protokit.FieldDescriptor, features *descriptorpb.FeatureSet) bool {
+	if pf.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return false
+	}
+
+	switch pf.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+		descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	}
+
+	if pf.GetOptions() != nil && pf.GetOptions().Packed != nil {
+		return pf.GetOptions().GetPacked()
+	}
+
+	return features.GetRepeatedFieldEncoding() == descriptorpb.FeatureSet_PACKED
+}
+
+// newFeatures returns the Features of a resolved feature set.
+func newFeatures(fs *descriptorpb.FeatureSet) *Features {
+	return &Features{
+		FieldPresence:         fs.GetFieldPresence().String(),
+		EnumType:              fs.GetEnumType().String(),
+		RepeatedFieldEncoding: fs.GetRepeatedFieldEncoding().String(),
+		UTF8Validation:        fs.GetUtf8Validation().String(),
+		MessageEncoding:       fs.GetMessageEncoding().String(),
+		JSONFormat:            fs.GetJsonFormat().String(),
+	}
+}
+
+// fileSyntax returns the syntax of fd (proto2, proto3 or editions), its edition (e.g. 2023) for editions files, and
+// its resolved features for editions files.
+func fileSyntax(fd *descriptorpb.FileDescriptorProto) (string, string, *Features) {
+	switch fd.GetSyntax() {
+	case syntaxEditions:
+		return syntaxEditions, strings.TrimPrefix(fd.GetEdition().String(), "EDITION_"), newFeatures(fileFeatures(fd))
+	case "proto3":
+		return "proto3", "", nil
+	}
+
+	return "proto2", "", nil
+}
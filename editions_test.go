@@ -0,0 +1,227 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var (
+	typeString    = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	typeInt32     = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+	labelOptional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	labelRepeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+)
+
+func newEditionsField(
+	name string,
+	number int32,
+	fieldType *descriptorpb.FieldDescriptorProto_Type,
+	label *descriptorpb.FieldDescriptorProto_Label,
+	features *descriptorpb.FeatureSet,
+) *descriptorpb.FieldDescriptorProto {
+	field := &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number), Type: fieldType,
+		Label: label}
+	if features != nil {
+		field.Options = &descriptorpb.FieldOptions{Features: features}
+	}
+
+	return field
+}
+
+func newEditionsRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"library.proto", "legacy.proto", "shelf.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("library.proto"),
+				Package: proto.String("library"),
+				Syntax:  proto.String("editions"),
+				Edition: descriptorpb.Edition_EDITION_2023.Enum(),
+				Options: &descriptorpb.FileOptions{Features: &descriptorpb.FeatureSet{
+					FieldPresence: descriptorpb.FeatureSet_IMPLICIT.Enum(),
+				}},
+				MessageType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("Book"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						newEditionsField("title", 1, typeString, labelOptional, nil),
+						newEditionsField("author", 2, typeString, labelOptional, &descriptorpb.FeatureSet{
+							FieldPresence: descriptorpb.FeatureSet_EXPLICIT.Enum(),
+						}),
+						newEditionsField("note", 3, typeString, labelOptional, &descriptorpb.FeatureSet{
+							Utf8Validation: descriptorpb.FeatureSet_NONE.Enum(),
+						}),
+						newEditionsField("pages", 4, typeInt32, labelRepeated, nil),
+						newEditionsField("chapters", 5, typeInt32, labelRepeated, &descriptorpb.FeatureSet{
+							RepeatedFieldEncoding: descriptorpb.FeatureSet_EXPANDED.Enum(),
+						}),
+					},
+				}},
+			},
+			{
+				Name:    proto.String("legacy.proto"),
+				Package: proto.String("library"),
+				MessageType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("Record"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						newEditionsField("name", 1, typeString, labelOptional, nil),
+						newEditionsField("ids", 2, typeInt32, labelRepeated, nil),
+						{
+							Name: proto.String("codes"), Number: proto.Int32(3), Type: typeInt32, Label: labelRepeated,
+							Options: &descriptorpb.FieldOptions{Packed: proto.Bool(true)},
+						},
+					},
+				}},
+			},
+			{
+				Name:    proto.String("shelf.proto"),
+				Package: proto.String("library"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("Shelf"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						newEditionsField("count", 1, typeInt32, labelOptional, nil),
+						{
+							Name: proto.String("book"), Number: proto.Int32(2), Label: labelOptional,
+							Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+							TypeName: proto.String(".library.Book"),
+						},
+						{
+							Name: proto.String("label"), Number: proto.Int32(3), Type: typeString, Label: labelOptional,
+							OneofIndex: proto.Int32(0), Proto3Optional: proto.Bool(true),
+						},
+					},
+					OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("_label")}},
+				}},
+			},
+		},
+	}
+}
+
+func TestFileSyntax(t *testing.T) {
+	req := newEditionsRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	files := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files
+
+	require.Equal(t, "editions", files[0].Syntax)
+	require.Equal(t, "2023", files[0].Edition)
+	require.Equal(t, &Features{
+		FieldPresence:         "IMPLICIT",
+		EnumType:              "OPEN",
+		RepeatedFieldEncoding: "PACKED",
+		UTF8Validation:        "VERIFY",
+		MessageEncoding:       "LENGTH_PREFIXED",
+		JSONFormat:            "ALLOW",
+	}, files[0].Features)
+
+	// files without a syntax statement are proto2 files
+	require.Equal(t, "proto2", files[1].Syntax)
+	require.Empty(t, files[1].Edition)
+	require.Nil(t, files[1].Features)
+
+	require.Equal(t, "proto3", files[2].Syntax)
+	require.Nil(t, files[2].Features)
+}
+
+func TestFileSyntaxOutput(t *testing.T) {
+	resp, err := new(Plugin).Generate(newEditionsRequest("html,index.html:show_syntax=true"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<span class="syntax-badge">edition 2023</span></h2>`)
+	require.Contains(t, content, `<p class="features">Features: <code>field_presence = IMPLICIT</code>, `+
+		`<code>enum_type = OPEN</code>, `)
+	require.Contains(t, content, `<span class="syntax-badge">proto2</span></h2>`)
+	require.Contains(t, content, `<span class="syntax-badge">proto3</span></h2>`)
+
+	resp, err = new(Plugin).Generate(newEditionsRequest("markdown,README.md:show_syntax=true"))
+	require.NoError(t, err)
+
+	content = resp.File[0].GetContent()
+	require.Contains(t, content, "## library.proto\n`edition = \"2023\"`<br>Features: `field_presence = IMPLICIT`, "+
+		"`enum_type = OPEN`, `repeated_field_encoding = PACKED`, `utf8_validation = VERIFY`, "+
+		"`message_encoding = LENGTH_PREFIXED`, `json_format = ALLOW`\n")
+	require.Contains(t, content, "## legacy.proto\n`syntax = \"proto2\"`\n")
+	require.Contains(t, content, "## shelf.proto\n`syntax = \"proto3\"`\n")
+
+	// the syntax is left out unless the show_syntax option is enabled
+	for _, parameter := range []string{"html,index.html", "markdown,README.md"} {
+		resp, err = new(Plugin).Generate(newEditionsRequest(parameter))
+		require.NoError(t, err)
+		require.NotContains(t, resp.File[0].GetContent(), "proto2")
+		require.NotContains(t, resp.File[0].GetContent(), "field_presence")
+	}
+}
+
+func TestEffectiveFieldFeatures(t *testing.T) {
+	req := newEditionsRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	files := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files
+
+	tests := []struct {
+		file, message, field string
+		presence             string
+		utf8Validation       bool
+		packed               bool
+	}{
+		// the file sets field_presence = IMPLICIT, which fields can override
+		{"library.proto", "Book", "title", "implicit", true, false},
+		{"library.proto", "Book", "author", "explicit", true, false},
+		{"library.proto", "Book", "note", "implicit", false, false},
+		{"library.proto", "Book", "pages", "", false, true},
+		{"library.proto", "Book", "chapters", "", false, false},
+		{"legacy.proto", "Record", "name", "explicit", false, false},
+		{"legacy.proto", "Record", "ids", "", false, false},
+		{"legacy.proto", "Record", "codes", "", false, true},
+		{"shelf.proto", "Shelf", "count", "implicit", false, false},
+		{"shelf.proto", "Shelf", "book", "explicit", false, false},
+		{"shelf.proto", "Shelf", "label", "explicit", true, false},
+	}
+
+	for _, test := range tests {
+		var file *File
+		for _, f := range files {
+			if f.Name == test.file {
+				file = f
+			}
+		}
+
+		field := findField(test.field, findMessage(test.message, file))
+		require.Equal(t, test.presence, field.EffectivePresence, test.field)
+		require.Equal(t, test.utf8Validation, field.EffectiveUTF8Validation, test.field)
+		require.Equal(t, test.packed, field.EffectivePacked, test.field)
+	}
+}
+
+func TestProto3Optional(t *testing.T) {
+	req := newEditionsRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	shelf := findMessage("Shelf", NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[2])
+
+	field := findField("label", shelf)
+	require.True(t, field.HasPresence)
+	require.True(t, field.Proto3Optional)
+
+	field = findField("count", shelf)
+	require.False(t, field.HasPresence)
+	require.False(t, field.Proto3Optional)
+
+	resp, err := new(Plugin).Generate(newEditionsRequest("markdown,README.md"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "</a> label | [string](#string) | optional (explicit presence) |")
+	require.Contains(t, content, "</a> count | [int32](#int32) |  |")
+}
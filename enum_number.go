@@ -0,0 +1,47 @@
+package gendoc
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// EnumNumberFormatDecimal shows enum value numbers in decimal, e.g. `31`.
+	EnumNumberFormatDecimal = "decimal"
+	// EnumNumberFormatHex shows enum value numbers in hexadecimal, e.g. `0x1F`.
+	EnumNumberFormatHex = "hex"
+	// EnumNumberFormatBoth shows enum value numbers in hexadecimal followed by decimal, e.g. `0x1F (31)`.
+	EnumNumberFormatBoth = "both"
+)
+
+func isEnumNumberFormat(format string) bool {
+	switch format {
+	case EnumNumberFormatDecimal, EnumNumberFormatHex, EnumNumberFormatBoth:
+		return true
+	}
+
+	return false
+}
+
+// FormatEnumNumber formats the number of an enum value (as found in EnumValue.Number) in the given format. An empty
+// format means EnumNumberFormatDecimal.
+func FormatEnumNumber(number, format string) string {
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return number
+	}
+
+	hex := fmt.Sprintf("0x%X", n)
+	if n < 0 {
+		hex = fmt.Sprintf("-0x%X", -n)
+	}
+
+	switch format {
+	case EnumNumberFormatHex:
+		return hex
+	case EnumNumberFormatBoth:
+		return fmt.Sprintf("%s (%d)", hex, n)
+	}
+
+	return number
+}
@@ -0,0 +1,66 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestFormatEnumNumber(t *testing.T) {
+	tests := []struct {
+		number   string
+		format   string
+		expected string
+	}{
+		{"31", "", "31"},
+		{"31", EnumNumberFormatDecimal, "31"},
+		{"31", EnumNumberFormatHex, "0x1F"},
+		{"0", EnumNumberFormatHex, "0x0"},
+		{"-1", EnumNumberFormatHex, "-0x1"},
+		{"31", EnumNumberFormatBoth, "0x1F (31)"},
+		{"-255", EnumNumberFormatBoth, "-0xFF (-255)"},
+		{"not a number", EnumNumberFormatHex, "not a number"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, FormatEnumNumber(test.number, test.format), test)
+	}
+}
+
+func TestParseOptionsForEnumNumberFormat(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:enum_number_format=hex")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, EnumNumberFormatHex, options.EnumNumberFormat)
+
+	req.Parameter = proto.String("html,index.html:enum_number_format=octal")
+	_, err = ParseOptions(req)
+	require.EqualError(t, err, "Invalid enum_number_format value: octal")
+}
+
+func TestRenderEnumNumbers(t *testing.T) {
+	expected := map[string]string{
+		"html":     "<td>BAD_REQUEST</td>\n                  <td>0x190</td>",
+		"markdown": "BAD_REQUEST\"></a> BAD_REQUEST | 0x190 | BAD result. |",
+		"docbook":  "<entry>BAD_REQUEST</entry>\n              <entry>0x190</entry>",
+	}
+
+	for format, snippet := range expected {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, format+",docs:enum_number_format=hex"))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), snippet, format)
+	}
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "markdown,docs:enum_number_format=both"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "OK\"></a> OK | 0xC8 (200) | OK result. |")
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, "markdown,docs"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "OK\"></a> OK | 200 | OK result. |")
+}
@@ -0,0 +1,180 @@
+package gendoc
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// exampleDepth is how deep examples follow message-typed fields. Deeper messages are shown as `{}`.
+const exampleDepth = 4
+
+// exampleScalars are the placeholder values of scalar fields in examples, as written by the JSON mapping of proto3:
+// 64-bit integers are strings and bytes are base64 encoded.
+var exampleScalars = map[string]interface{}{
+	"double":   0.0,
+	"float":    0.0,
+	"int32":    0,
+	"int64":    "0",
+	"uint32":   0,
+	"uint64":   "0",
+	"sint32":   0,
+	"sint64":   "0",
+	"fixed32":  0,
+	"fixed64":  "0",
+	"sfixed32": 0,
+	"sfixed64": "0",
+	"bool":     true,
+	"string":   "string",
+	"bytes":    "Ynl0ZXM=",
+}
+
+// exampleWellKnownTypes are the placeholder values of the well-known types that have a special JSON representation.
+var exampleWellKnownTypes = map[string]interface{}{
+	"google.protobuf.Timestamp": "1970-01-01T00:00:00Z",
+	"google.protobuf.Duration":  "0s",
+	"google.protobuf.FieldMask": "",
+	"google.protobuf.Struct":    exampleObject{},
+	"google.protobuf.Value":     nil,
+	"google.protobuf.ListValue": []interface{}{},
+	"google.protobuf.Empty":     exampleObject{},
+}
+
+// exampleMember is a member of an exampleObject.
+type exampleMember struct {
+	name  string
+	value interface{}
+}
+
+// exampleObject is a JSON object whose members keep the order of the message's fields.
+type exampleObject []exampleMember
+
+// MarshalJSON implements json.Marshaler.
+func (o exampleObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		name, err := json.Marshal(member.name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// Example returns an example of a message in the JSON format, with every field set to a placeholder value of its type:
+// the first value of enums, and an example of the message type of message-typed fields. Only the first field of each
+// oneof is set. Messages that aren't documented in the same output, recursive fields and fields more than a few levels
+// deep are shown as empty objects. The message is given as a *Message or by its full name.
+//
+// Templates use it as `{{example .RequestFullType}}`. The slate template shows it next to each method.
+func (t *Template) Example(message interface{}) (string, error) {
+	fields, err := t.Expand(message, exampleDepth)
+	if err != nil {
+		return "", err
+	}
+
+	enums := make(map[string]*Enum)
+	for _, f := range t.Files {
+		for _, e := range f.AllEnums() {
+			enums[e.FullName] = e
+		}
+	}
+
+	data, err := json.MarshalIndent(exampleFields(fields, enums), "", "  ")
+	return string(data), err
+}
+
+// exampleFields returns an example of a message with the given fields.
+func exampleFields(fields []*ExpandedField, enums map[string]*Enum) exampleObject {
+	object := make(exampleObject, 0, len(fields))
+	oneofs := make(map[string]bool)
+	for _, f := range fields {
+		if f.Redacted {
+			continue
+		}
+
+		if f.IsOneof && f.OneofDecl != "" {
+			if oneofs[f.OneofDecl] {
+				continue
+			}
+			oneofs[f.OneofDecl] = true
+		}
+
+		var value interface{}
+		switch {
+		case f.IsMap:
+			value = exampleMapEntry(f, enums)
+		case f.Label == "repeated":
+			value = []interface{}{exampleValue(f, enums)}
+		default:
+			value = exampleValue(f, enums)
+		}
+
+		object = append(object, exampleMember{f.Name, value})
+	}
+
+	return object
+}
+
+// exampleValue returns an example of a single value of the type of f.
+func exampleValue(f *ExpandedField, enums map[string]*Enum) interface{} {
+	if value, ok := exampleScalars[f.FullType]; ok {
+		return value
+	}
+
+	if value, ok := exampleWellKnownTypes[f.FullType]; ok {
+		return value
+	}
+
+	if e, ok := enums[f.FullType]; ok {
+		if len(e.Values) > 0 {
+			return e.Values[0].Name
+		}
+		return 0
+	}
+
+	return exampleFields(f.Fields, enums)
+}
+
+// exampleMapEntry returns an example of a map field, with one entry.
+func exampleMapEntry(f *ExpandedField, enums map[string]*Enum) interface{} {
+	var key, value *ExpandedField
+	for _, entryField := range f.Fields {
+		switch entryField.Name {
+		case "key":
+			key = entryField
+		case "value":
+			value = entryField
+		}
+	}
+
+	if key == nil || value == nil {
+		return exampleObject{}
+	}
+
+	// JSON object keys are always strings.
+	name := "0"
+	switch key.FullType {
+	case "string":
+		name = "key"
+	case "bool":
+		name = "true"
+	}
+
+	return exampleObject{{name, exampleValue(value, enums)}}
+}
@@ -0,0 +1,53 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExample(t *testing.T) {
+	template := NewTemplate(protokit.ParseCodeGenRequest(newBookingRequest(t, "")), new(PluginOptions))
+
+	example, err := template.Example("com.example.BookingStatus")
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "id": 0,
+  "description": "string",
+  "status_code": "OK"
+}`, example)
+
+	example, err = template.Example(findMessage("Booking", template.Files[0]))
+	require.NoError(t, err)
+	require.Contains(t, example, "  \"status\": {\n    \"id\": 0,\n")
+	require.Contains(t, example, "  \"confirmation_sent\": true,\n")
+
+	example, err = template.Example(".google.protobuf.Empty")
+	require.NoError(t, err)
+	require.Equal(t, "{}", example)
+
+	_, err = template.Example(42)
+	require.EqualError(t, err, "Invalid expand message: 42")
+}
+
+func TestExampleOfCollections(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+
+	example, err := template.Example("com.example.Vehicle")
+	require.NoError(t, err)
+	require.Contains(t, example, "  \"rates\": [\n    0\n  ],\n")
+	require.Contains(t, example, "  \"properties\": {\n    \"key\": \"string\"\n  },\n")
+
+	// only the first field of each oneof is set
+	require.Contains(t, example, `"kilometers": 0`)
+	require.NotContains(t, example, `"lightyears"`)
+	require.Contains(t, example, `"human_name": "string"`)
+	require.NotContains(t, example, `"cat_name"`)
+}
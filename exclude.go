@@ -0,0 +1,101 @@
+package gendoc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/proto"
+)
+
+// ExcludeOption excludes the entities whose option Name is set to Value, e.g. `company.internal` set to `true`. See
+// the exclude_option option.
+type ExcludeOption struct {
+	// The name of a standard option (e.g. `deprecated`) or the full name of a custom one (e.g. `company.internal`).
+	Name string
+	// The option's value, compared to the value as text. Enum values are given by name.
+	Value string
+}
+
+// parseExcludeOption parses the value of the exclude_option option: `name=value`, or `name` for `name=true`.
+func parseExcludeOption(value string) (ExcludeOption, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if parts[0] == "" {
+		return ExcludeOption{}, fmt.Errorf("Invalid exclude_option value: %v", value)
+	}
+
+	if len(parts) == 1 {
+		return ExcludeOption{Name: parts[0], Value: "true"}, nil
+	}
+
+	return ExcludeOption{Name: parts[0], Value: parts[1]}, nil
+}
+
+// compilePackagePattern compiles a pattern of the exclude_packages option, which must match whole package names.
+func compilePackagePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// excludedByOption reports whether opts, the options of a file or an entity in it, are set to a value excluded with
+// the exclude_option option.
+func excludedByOption(opts proto.Message, pluginOptions *PluginOptions) bool {
+	if len(pluginOptions.ExcludeOptions) == 0 {
+		return false
+	}
+
+	values := decodeOptions(opts, pluginOptions)
+	for _, excluded := range pluginOptions.ExcludeOptions {
+		if value, ok := values[excluded.Name]; ok && fmt.Sprint(value) == excluded.Value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactedName is the name of the placeholders shown instead of excluded entities with the redact option.
+const redactedName = "«redacted»"
+
+// redactedMessage returns the placeholder shown instead of an excluded message.
+func redactedMessage() *Message {
+	return &Message{
+		Name:       redactedName,
+		LongName:   redactedName,
+		Redacted:   true,
+		Extensions: make([]*MessageExtension, 0),
+		Fields:     make([]*MessageField, 0),
+	}
+}
+
+// redactedField returns the placeholder shown instead of an excluded field. It keeps the field's number, so readers can
+// tell which numbers are taken.
+func redactedField(number int32) *MessageField {
+	return &MessageField{Name: redactedName, Number: int(number), Redacted: true}
+}
+
+// redactedEnumValue returns the placeholder shown instead of an excluded enum value. It keeps the value's number, so
+// readers can tell which numbers are taken.
+func redactedEnumValue(number int32) *EnumValue {
+	return &EnumValue{Name: redactedName, Number: fmt.Sprint(number), Redacted: true}
+}
+
+// excludedByComment reports whether the leading or trailing comment of a field, enum value or method consists of an
+// exclude directive alone, e.g. `int32 legacy = 4; // @exclude`. Such entities are omitted from the output, whereas a
+// directive followed by text only removes that paragraph from the description.
+func excludedByComment(comment *protokit.Comment, pluginOptions *PluginOptions) bool {
+	if comment == nil {
+		return false
+	}
+
+	for _, c := range []string{comment.GetLeading(), comment.GetTrailing()} {
+		c = strings.TrimSpace(strings.Trim(c, "*/\n "))
+		for _, directive := range pluginOptions.ExcludeDirectives {
+			if c == directive {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,199 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestParseOptionsForExclusion(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:exclude_packages=internal.*,legacy," +
+		"exclude_option=company.internal=true,exclude_option=deprecated")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Len(t, options.ExcludePackages, 2)
+	require.True(t, options.ExcludePackages[0].MatchString("internal.billing"))
+	require.False(t, options.ExcludePackages[0].MatchString("api.internal"))
+	require.True(t, options.ExcludePackages[1].MatchString("legacy"))
+	require.False(t, options.ExcludePackages[1].MatchString("legacy.v1"))
+	require.Equal(t, []ExcludeOption{{Name: "company.internal", Value: "true"}, {Name: "deprecated", Value: "true"}},
+		options.ExcludeOptions)
+
+	req.Parameter = proto.String("html,index.html:exclude_option==true")
+	_, err = ParseOptions(req)
+	require.EqualError(t, err, "Invalid exclude_option value: =true")
+
+	req.Parameter = proto.String("html,index.html:exclude_packages=(")
+	_, err = ParseOptions(req)
+	require.Error(t, err)
+}
+
+func TestExcludePackages(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{.Name}} {{end}}`)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, templateFile+",out.txt:exclude_packages=com"))
+	require.NoError(t, err)
+	require.Equal(t, "Booking.proto ", resp.File[0].GetContent())
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, templateFile+",out.txt:exclude_packages=com.*"))
+	require.NoError(t, err)
+	require.Empty(t, resp.File)
+}
+
+func TestExcludeOption(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{range .Messages}}{{.Name}}:{{range .Fields}} {{.Name}}{{end}}`+
+		`{{end}}{{end}}`)
+
+	resp, err := new(Plugin).Generate(newAnnotatedRequest(t, templateFile+",out.txt:exclude_option=org.sensitive=true"))
+	require.NoError(t, err)
+	require.Equal(t, "User: id", resp.File[0].GetContent())
+
+	resp, err = new(Plugin).Generate(newAnnotatedRequest(t, templateFile+",out.txt:exclude_option=org.sensitive=false"))
+	require.NoError(t, err)
+	require.Equal(t, "User: id password", resp.File[0].GetContent())
+
+	// standard options work too
+	resp, err = new(Plugin).Generate(newAnnotatedRequest(t, templateFile+",out.txt:exclude_option=deprecated"))
+	require.NoError(t, err)
+	require.Equal(t, "User: id", resp.File[0].GetContent())
+}
+
+func TestExcludeOptionOnEntities(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{range .Messages}}{{.Name}} {{end}}`+
+		`{{range .Services}}{{.Name}} {{end}}{{range .Enums}}{{.Name}}:{{range .Values}} {{.Name}}{{end}} {{end}}{{end}}`)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, templateFile+",out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "Booking BookingStatus CustomExcludedMessage BookingService "+
+		"StatusCode: OK BAD_REQUEST BookingType: IMMEDIATE FUTURE ", resp.File[0].GetContent())
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, templateFile+
+		",out.txt:exclude_option=com.pseudomuto.protokit.v1.extend_message=true"+
+		",exclude_option=com.pseudomuto.protokit.v1.extend_service=true"+
+		",exclude_option=com.pseudomuto.protokit.v1.extend_enum_value=true"))
+	require.NoError(t, err)
+	require.Equal(t, "BookingStatus CustomExcludedMessage StatusCode: OK BAD_REQUEST BookingType: IMMEDIATE ",
+		resp.File[0].GetContent())
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, templateFile+
+		",out.txt:exclude_option=com.pseudomuto.protokit.v1.extend_file=true"))
+	require.NoError(t, err)
+	require.Empty(t, resp.File)
+}
+
+// newExcludeDirectivesRequest returns a request documenting a field, an enum value and a method that are excluded with
+// an @exclude comment, next to siblings that aren't.
+func newExcludeDirectivesRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "exclude.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "shop.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestExcludeDirectiveOnEntities(t *testing.T) {
+	file := NewTemplate(protokit.ParseCodeGenRequest(newExcludeDirectivesRequest(t, "")),
+		&PluginOptions{ExcludeDirectives: []string{"@exclude"}}).Files[0]
+
+	message := findMessage("Order", file)
+	require.Len(t, message.Fields, 2)
+	require.Equal(t, "id", message.Fields[0].Name)
+	require.Equal(t, "note", message.Fields[1].Name)
+	require.Empty(t, message.Fields[1].Description) // a directive followed by text only excludes the comment
+
+	enum := findEnum("State", file)
+	require.Len(t, enum.Values, 2)
+	require.Equal(t, "CLOSED", enum.Values[1].Name)
+
+	service := findService("Shop", file)
+	require.Len(t, service.Methods, 1)
+	require.Equal(t, "GetOrder", service.Methods[0].Name)
+
+	// custom directives replace the default one
+	file = NewTemplate(protokit.ParseCodeGenRequest(newExcludeDirectivesRequest(t, "")),
+		&PluginOptions{ExcludeDirectives: []string{"@skip"}}).Files[0]
+	require.Len(t, findMessage("Order", file).Fields, 3)
+}
+
+func TestRenderExcludeDirectiveOnEntities(t *testing.T) {
+	for _, format := range []string{"html", "markdown", "json", "docbook"} {
+		resp, err := new(Plugin).Generate(newExcludeDirectivesRequest(t, format+",docs"))
+		require.NoError(t, err)
+
+		content := resp.File[0].GetContent()
+		require.Contains(t, content, "note", format)
+		require.Contains(t, content, "CLOSED", format)
+		require.Contains(t, content, "GetOrder", format)
+		require.NotContains(t, content, "legacy_id", format)
+		require.NotContains(t, content, "LOST", format)
+		require.NotContains(t, content, "PurgeOrder", format)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:redact=true")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.True(t, options.Redact)
+
+	req.Parameter = proto.String("html,index.html:redact=maybe")
+	_, err = ParseOptions(req)
+	require.EqualError(t, err, "Invalid redact value: maybe")
+
+	file := NewTemplate(protokit.ParseCodeGenRequest(newExcludeDirectivesRequest(t, "")),
+		&PluginOptions{ExcludeDirectives: []string{"@exclude"}, Redact: true}).Files[0]
+
+	fields := findMessage("Order", file).Fields
+	require.Len(t, fields, 3)
+	require.Equal(t, &MessageField{Name: "«redacted»", Number: 2, Redacted: true}, fields[1])
+	require.False(t, fields[2].Redacted)
+
+	values := findEnum("State", file).Values
+	require.Len(t, values, 3)
+	require.Equal(t, &EnumValue{Name: "«redacted»", Number: "1", Redacted: true}, values[1])
+
+	// methods are left out as usual
+	require.Len(t, findService("Shop", file).Methods, 1)
+}
+
+func TestRedactMessages(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{range .Messages}}{{.Name}}{{if .Redacted}}!{{end}} {{end}}{{end}}`)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, templateFile+
+		",out.txt:exclude_option=com.pseudomuto.protokit.v1.extend_message=true,redact=true"))
+	require.NoError(t, err)
+	require.Equal(t, "BookingStatus CustomExcludedMessage «redacted»! ", resp.File[0].GetContent())
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, "coverage_json,coverage.json:"+
+		"exclude_option=com.pseudomuto.protokit.v1.extend_message=true,redact=true"))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), "«redacted»")
+}
+
+func TestRenderRedacted(t *testing.T) {
+	expected := map[string]string{
+		"html":     "<td>«redacted»</td>\n                    <td></td>",
+		"markdown": "| «redacted» |  |  |  |",
+		"json":     `"name": "«redacted»",`,
+		"docbook":  "<entry>«redacted»</entry>\n              <entry></entry>",
+	}
+
+	for format, snippet := range expected {
+		resp, err := new(Plugin).Generate(newExcludeDirectivesRequest(t, format+",docs:redact=true"))
+		require.NoError(t, err)
+
+		content := resp.File[0].GetContent()
+		require.Contains(t, content, snippet, format)
+		require.NotContains(t, content, "legacy_id", format)
+		require.NotContains(t, content, "LOST", format)
+	}
+}
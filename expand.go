@@ -0,0 +1,129 @@
+package gendoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expandMethodTypes fills in the request and response fields of the methods in files, one level deep. Only messages
+// within files are expanded.
+func expandMethodTypes(files []*File) {
+	messages := messagesByName(files)
+
+	for _, f := range files {
+		for _, s := range f.Services {
+			for _, method := range s.Methods {
+				if m, ok := messages[method.RequestFullType]; ok {
+					method.RequestFields = m.Fields
+				}
+
+				if m, ok := messages[method.ResponseFullType]; ok {
+					method.ResponseFields = m.Fields
+				}
+			}
+		}
+	}
+}
+
+// messagesByName returns the messages in files, including nested ones, keyed by full name.
+func messagesByName(files []*File) map[string]*Message {
+	messages := make(map[string]*Message)
+	for _, f := range files {
+		for _, m := range f.AllMessages() {
+			messages[m.FullName] = m
+		}
+	}
+
+	return messages
+}
+
+// documentedTypes returns the full names of the messages, enums and services documented in the template.
+func (t *Template) documentedTypes() map[string]bool {
+	types := make(map[string]bool)
+	for _, f := range t.Files {
+		for _, m := range f.AllMessages() {
+			types[m.FullName] = true
+		}
+		for _, e := range f.AllEnums() {
+			types[e.FullName] = true
+		}
+		for _, s := range f.Services {
+			types[s.FullName] = true
+		}
+	}
+
+	return types
+}
+
+// maxSandboxExpandDepth is the deepest expansion allowed in sandbox mode, where templates mustn't be able to exhaust
+// resources. Without cycles, the number of expanded fields can still grow exponentially with the depth.
+const maxSandboxExpandDepth = 8
+
+// ExpandedField is a message field along with the fields of its message type, as returned by Template.Expand.
+type ExpandedField struct {
+	*MessageField
+
+	// The fields of the field's message type. Empty for scalar and enum fields, and when the depth limit is reached.
+	Fields []*ExpandedField `json:"fields,omitempty"`
+	// Whether the field's message type is one of the messages it's nested in, in which case it isn't expanded again.
+	Recursive bool `json:"recursive,omitempty"`
+}
+
+// Expand returns the fields of a message, following message-typed fields up to depth levels deep: 0 returns the
+// message's own fields only. The message is given as a *Message or by its full name. Messages that aren't documented
+// in the same output aren't expanded.
+//
+// Templates use it as `{{expand .Message depth}}`, e.g. `{{range expand $method.RequestFullType 2}}`.
+func (t *Template) Expand(message interface{}, depth int) ([]*ExpandedField, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("Invalid expand depth: %d", depth)
+	}
+	if t.Sandbox && depth > maxSandboxExpandDepth {
+		return nil, fmt.Errorf("Invalid expand depth: %d (at most %d in sandbox mode)", depth, maxSandboxExpandDepth)
+	}
+
+	messages := messagesByName(t.Files)
+
+	var root *Message
+	switch m := message.(type) {
+	case *Message:
+		root = m
+	case string:
+		root = messages[strings.TrimPrefix(m, ".")]
+	default:
+		return nil, fmt.Errorf("Invalid expand message: %v", message)
+	}
+
+	if root == nil {
+		return nil, nil
+	}
+
+	return expandFields(root, depth, messages, map[string]bool{root.FullName: true}), nil
+}
+
+// expandFields expands the fields of m. path holds the messages being expanded, which mustn't be expanded again.
+func expandFields(m *Message, depth int, messages map[string]*Message, path map[string]bool) []*ExpandedField {
+	fields := make([]*ExpandedField, 0, len(m.Fields))
+	for _, field := range m.Fields {
+		expanded := &ExpandedField{MessageField: field}
+		fields = append(fields, expanded)
+
+		fieldType, ok := messages[field.FullType]
+		if !ok {
+			continue
+		}
+
+		if path[fieldType.FullName] {
+			expanded.Recursive = true
+			continue
+		}
+
+		if depth > 0 {
+			path[fieldType.FullName] = true
+			expanded.Fields = expandFields(fieldType, depth-1, messages, path)
+			delete(path, fieldType.FullName)
+		}
+	}
+
+	return fields
+}
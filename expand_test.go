@@ -0,0 +1,182 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newBookingRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestParseOptionsForExpandMethodTypes(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("markdown,docs.md:expand_method_types=true")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.True(t, options.ExpandMethodTypes)
+
+	req.Parameter = proto.String("markdown,docs.md:expand_method_types=yes")
+	_, err = ParseOptions(req)
+	require.EqualError(t, err, "Invalid expand_method_types value: yes")
+}
+
+func TestExpandMethodTypes(t *testing.T) {
+	req := newBookingRequest(t, "")
+
+	file := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions)).Files[0]
+	method := findServiceMethod("BookVehicle", findService("BookingService", file))
+	require.Nil(t, method.RequestFields)
+	require.Nil(t, method.ResponseFields)
+
+	file = NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{ExpandMethodTypes: true}).Files[0]
+	method = findServiceMethod("BookVehicle", findService("BookingService", file))
+	require.Equal(t, findMessage("Booking", file).Fields, method.RequestFields)
+	require.Equal(t, findMessage("BookingStatus", file).Fields, method.ResponseFields)
+	require.Equal(t, "vehicle_id", method.RequestFields[0].Name)
+}
+
+func TestRenderExpandedMethodTypes(t *testing.T) {
+	expected := map[string][]string{
+		"html": {
+			`<h4 id="com.example.BookingService.BookVehicle-fields">BookVehicle<a class="permalink" ` +
+				`href="#com.example.BookingService.BookVehicle-fields" aria-label="Link to this section">¶</a></h4>` +
+				"\n            \n            <h5>Request Fields</h5>",
+			`<td>vehicle_id</td>`,
+			`<h5>Response Fields</h5>`,
+		},
+		"markdown": {
+			"#### BookVehicle\n\n##### Request Fields\n\n| Field | Type | Label | Description |",
+			"| status | [BookingStatus](#com-example-BookingStatus) | required | Status of the booking. |",
+			"##### Response Fields",
+		},
+		"docbook": {
+			`<title><methodname>BookVehicle</methodname> Request Fields</title>`,
+			`<title><methodname>BookVehicle</methodname> Response Fields</title>`,
+		},
+	}
+
+	for format, snippets := range expected {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, format+",docs:expand_method_types=true"))
+		require.NoError(t, err)
+
+		content := resp.File[0].GetContent()
+		for _, snippet := range snippets {
+			require.Contains(t, content, snippet, format)
+		}
+
+		resp, err = new(Plugin).Generate(newBookingRequest(t, format+",docs"))
+		require.NoError(t, err)
+		require.NotContains(t, resp.File[0].GetContent(), "Request Fields", format)
+	}
+}
+
+// treeTemplate returns a template with a recursive message, and messages nested a few levels deep.
+func treeTemplate() *Template {
+	return &Template{
+		Files: []*File{{
+			Name:    "tree.proto",
+			Package: "tree",
+			Messages: []*Message{
+				{
+					Name:     "Tree",
+					FullName: "tree.Tree",
+					Fields: []*MessageField{
+						{Name: "name", LongType: "string", FullType: "string"},
+						{Name: "children", LongType: "Tree", FullType: "tree.Tree", Label: "repeated"},
+						{Name: "owner", LongType: "Person", FullType: "tree.Person"},
+					},
+				},
+				{
+					Name:     "Person",
+					FullName: "tree.Person",
+					Fields: []*MessageField{
+						{Name: "address", LongType: "Address", FullType: "tree.Address"},
+						{Name: "created", LongType: "google.protobuf.Timestamp", FullType: "google.protobuf.Timestamp"},
+					},
+				},
+				{
+					Name:     "Address",
+					FullName: "tree.Address",
+					Fields:   []*MessageField{{Name: "city", LongType: "string", FullType: "string"}},
+				},
+			},
+		}},
+	}
+}
+
+func fieldNames(fields []*ExpandedField) []string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+func TestExpand(t *testing.T) {
+	template := treeTemplate()
+
+	fields, err := template.Expand(template.Files[0].Messages[0], 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "children", "owner"}, fieldNames(fields))
+	require.Empty(t, fields[2].Fields)
+
+	fields, err = template.Expand("tree.Tree", 1)
+	require.NoError(t, err)
+	require.True(t, fields[1].Recursive)
+	require.Empty(t, fields[1].Fields)
+	require.False(t, fields[2].Recursive)
+	require.Equal(t, []string{"address", "created"}, fieldNames(fields[2].Fields))
+	require.Empty(t, fields[2].Fields[0].Fields)
+
+	fields, err = template.Expand(".tree.Tree", 5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"city"}, fieldNames(fields[2].Fields[0].Fields))
+	require.Empty(t, fields[2].Fields[1].Fields) // not documented in this output
+
+	fields, err = template.Expand("tree.Missing", 1)
+	require.NoError(t, err)
+	require.Nil(t, fields)
+}
+
+func TestExpandErrors(t *testing.T) {
+	template := treeTemplate()
+
+	_, err := template.Expand("tree.Tree", -1)
+	require.EqualError(t, err, "Invalid expand depth: -1")
+
+	_, err = template.Expand(42, 1)
+	require.EqualError(t, err, "Invalid expand message: 42")
+
+	_, err = template.Expand("tree.Tree", 9)
+	require.NoError(t, err)
+
+	template.Sandbox = true
+	_, err = template.Expand("tree.Tree", 9)
+	require.EqualError(t, err, "Invalid expand depth: 9 (at most 8 in sandbox mode)")
+}
+
+func TestExpandInTemplate(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeHTML, treeTemplate(), `{{define "fields"}}{{range .}}[{{.Name}}`+
+		`{{if .Recursive}}*{{end}}{{template "fields" .Fields}}]{{end}}{{end}}`+
+		`{{range .Files}}{{range .Messages}}{{.Name}}: {{template "fields" (expand . 2)}}
+{{end}}{{end}}`)
+	require.NoError(t, err)
+	require.Equal(t, `Tree: [name][children*][owner[address[city]][created]]
+Person: [address[city]][created]
+Address: [city]
+`, string(output))
+}
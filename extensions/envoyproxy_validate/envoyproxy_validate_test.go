@@ -6,8 +6,8 @@ import (
 	"github.com/daotl/protoc-gen-doc/extensions"
 	. "github.com/daotl/protoc-gen-doc/extensions/lyft_validate"
 	"github.com/envoyproxy/protoc-gen-validate/validate"
-	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestTransform(t *testing.T) {
@@ -6,24 +6,28 @@ import (
 	"strings"
 
 	"github.com/daotl/protoc-gen-doc/extensions"
-	"github.com/golang/protobuf/proto"
-	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	validator "github.com/mwitkow/go-proto-validators"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/runtime/protoimpl"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 func init() {
 	// NOTE: mwitkow/go-proto-validators uses gogo/profobuf/proto and therefore
 	// only registers the extension under gogo. We need to register it under
-	// golang/protobuf/proto with the same properties, except using the
-	// golang/protobuf FieldOptions descriptor.
-	proto.RegisterExtension(&proto.ExtensionDesc{
-		ExtendedType:  (*descriptor.FieldOptions)(nil),
+	// google.golang.org/protobuf with the same properties, except using the
+	// descriptorpb FieldOptions descriptor.
+	err := protoregistry.GlobalTypes.RegisterExtension(&protoimpl.ExtensionInfo{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
 		ExtensionType: validator.E_Field.ExtensionType,
 		Field:         validator.E_Field.Field,
 		Name:          validator.E_Field.Name,
 		Tag:           validator.E_Field.Tag,
 		Filename:      validator.E_Field.Filename,
 	})
+	if err != nil {
+		panic(err)
+	}
 }
 
 // ValidatorRule represents a single validator rule from the (validator.field) method option extension.
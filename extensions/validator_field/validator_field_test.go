@@ -5,9 +5,9 @@ import (
 
 	"github.com/daotl/protoc-gen-doc/extensions"
 	. "github.com/daotl/protoc-gen-doc/extensions/validator_field"
-	"github.com/golang/protobuf/proto"
 	validator "github.com/mwitkow/go-proto-validators"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestTransform(t *testing.T) {
@@ -0,0 +1,69 @@
+package gendoc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ExecRenderPrefix precedes the command of an external renderer in place of the format or template of the plugin's
+// parameter, e.g. `exec:./render.py --flavor=wiki,docs.txt`. The command can't contain commas or colons.
+const ExecRenderPrefix = "exec:"
+
+// ExecRendererPageEnv is the environment variable giving external renderers (including wasm: ones) the path of the
+// output file they're rendering, relative to the output directory.
+const ExecRendererPageEnv = "PROTOC_GEN_DOC_PAGE"
+
+// execRenderer is the Renderer of the exec: format: a command reading the template as JSON (the document the json
+// format renders) on its standard input, and writing the output file on its standard output.
+type execRenderer struct {
+	command string
+	hash    string // of the files the command runs, so rebuilding them invalidates cached output
+}
+
+// newExecRenderer returns the renderer running command. The executable and the arguments naming files (e.g. the
+// script run by an interpreter) are hashed, ignoring the files that can't be read, since they fail the command later.
+func newExecRenderer(command string) *execRenderer {
+	args := strings.Fields(command)
+	contents := make([]string, 0, len(args))
+	for i, arg := range args {
+		if i == 0 {
+			if path, err := exec.LookPath(arg); err == nil {
+				arg = path
+			}
+		}
+
+		if data, err := os.ReadFile(arg); err == nil {
+			contents = append(contents, sha256Hex(string(data)))
+		}
+	}
+
+	return &execRenderer{command: command, hash: sha256Hex(strings.Join(contents, ","))}
+}
+
+func (r *execRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *execRenderer) ApplyTo(w io.Writer, template *Template) error {
+	input, err := new(jsonRenderer).Apply(template)
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	args := strings.Fields(r.command)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), ExecRendererPageEnv+"="+template.Page)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("Renderer %s failed: %v: %s", r.command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRenderer writes an executable shell script rendering the JSON template it's given, returning its name.
+func writeRenderer(t *testing.T, script string) string {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-renderer")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	name := filepath.Join(dir, "render.sh")
+	require.NoError(t, os.WriteFile(name, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	return name
+}
+
+func TestParseOptionsForExecRenderer(t *testing.T) {
+	req := newBookingRequest(t, "exec:./render.py --flavor=wiki,docs.txt,source_relative:locale=de")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.NotNil(t, options.Renderer)
+	require.Empty(t, options.TemplateFile)
+	require.Equal(t, "docs.txt", options.OutputFile)
+	require.True(t, options.SourceRelative)
+	require.Equal(t, "de", options.Locale)
+
+	_, err = ParseOptions(newBookingRequest(t, "exec:,docs.txt"))
+	require.EqualError(t, err, "Invalid parameter: ,docs.txt")
+
+	require.NoError(t, Validate("exec:sh render.sh,docs.txt"))
+	require.Error(t, Validate("exec:missing-renderer,docs.txt"))
+}
+
+func TestExecRenderer(t *testing.T) {
+	renderer := writeRenderer(t, `echo "$PROTOC_GEN_DOC_PAGE"; grep -o '"name": "Booking.proto"' | head -n 1`)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "exec:"+renderer+",docs.txt"))
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+	require.Equal(t, "docs.txt", resp.File[0].GetName())
+	require.Equal(t, "docs.txt\n\"name\": \"Booking.proto\"\n", resp.File[0].GetContent())
+
+	renderer = writeRenderer(t, "echo 'no template given' >&2; exit 3")
+	_, err = new(Plugin).Generate(newBookingRequest(t, "exec:"+renderer+",docs.txt"))
+	require.EqualError(t, err, "Renderer "+renderer+" failed: exit status 3: no template given")
+}
+
+// fileNamesRenderer renders the names of the documented files.
+type fileNamesRenderer struct{}
+
+func (r *fileNamesRenderer) Apply(template *Template) ([]byte, error) {
+	names := make([]string, 0, len(template.Files))
+	for _, f := range template.Files {
+		names = append(names, f.Name)
+	}
+
+	return []byte(strings.Join(names, "\n")), nil
+}
+
+func TestGenerateWithRenderer(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	files, err := Generate(set, Options{
+		Parameter:       "markdown,docs.md",
+		FilesToGenerate: []string{"Booking.proto", "Vehicle.proto"},
+		Renderer:        new(fileNamesRenderer),
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "docs.md", files[0].Name)
+	require.Equal(t, "Booking.proto\nVehicle.proto", files[0].Content)
+}
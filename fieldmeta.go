@@ -0,0 +1,99 @@
+package gendoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldMetaMapping names the field options that FieldMeta is read from, e.g. `acme.unit` or `acme.range.min` (the min
+// field of the acme.range option). It's given as the `fieldMeta` key of the meta_file.
+type FieldMetaMapping struct {
+	Unit string `json:"unit,omitempty"`
+	Min  string `json:"min,omitempty"`
+	Max  string `json:"max,omitempty"`
+}
+
+// FieldMeta describes the unit and range of a field's values, read from the field options named by the
+// FieldMetaMapping.
+type FieldMeta struct {
+	Unit string `json:"unit,omitempty"`
+	Min  string `json:"min,omitempty"`
+	Max  string `json:"max,omitempty"`
+}
+
+// String returns the range and unit, e.g. `[0, 100] %`, `≥ 0 ms` or `kg`.
+func (m FieldMeta) String() string {
+	var constraint string
+	switch {
+	case m.Min != "" && m.Max != "":
+		constraint = fmt.Sprintf("[%s, %s]", m.Min, m.Max)
+	case m.Min != "":
+		constraint = "≥ " + m.Min
+	case m.Max != "":
+		constraint = "≤ " + m.Max
+	}
+
+	return strings.TrimSpace(constraint + " " + m.Unit)
+}
+
+// HasFieldMeta returns whether any of the message's fields has a unit or range.
+func (m Message) HasFieldMeta() bool {
+	for _, field := range m.Fields {
+		if field.Meta != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFieldMeta reads the FieldMeta of a field from its options, or returns nil if none of the mapped options are set.
+func parseFieldMeta(opts map[string]interface{}, mapping *FieldMetaMapping) *FieldMeta {
+	if mapping == nil || len(opts) == 0 {
+		return nil
+	}
+
+	meta := &FieldMeta{
+		Unit: fieldMetaValue(opts, mapping.Unit),
+		Min:  fieldMetaValue(opts, mapping.Min),
+		Max:  fieldMetaValue(opts, mapping.Max),
+	}
+	if *meta == (FieldMeta{}) {
+		return nil
+	}
+
+	return meta
+}
+
+// fieldMetaValue returns the value at path, an option name optionally followed by the names of nested fields, e.g.
+// `acme.range.min`. Since option names contain dots too, the longest option name path starts with is used.
+func fieldMetaValue(opts map[string]interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	parts := strings.Split(path, ".")
+	for i := len(parts); i > 0; i-- {
+		value, ok := opts[strings.Join(parts[:i], ".")]
+		if !ok {
+			continue
+		}
+
+		for _, name := range parts[i:] {
+			fields, ok := value.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+			if value, ok = fields[name]; !ok {
+				return ""
+			}
+		}
+
+		if _, ok := value.(map[string]interface{}); ok || value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	}
+
+	return ""
+}
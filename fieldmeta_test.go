@@ -0,0 +1,91 @@
+package gendoc_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newMeasurementRequest returns a request documenting a message whose fields use the unit and range options of
+// acme/units.proto. Like newAnnotatedRequest, the options are unknown fields.
+func newMeasurementRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "measurement.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "acme/weather.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+// writeFieldMetaFile writes a meta_file mapping the options of acme/units.proto, returning its name.
+func writeFieldMetaFile(t *testing.T) string {
+	meta, err := os.CreateTemp("", "meta-*.json")
+	require.NoError(t, err)
+
+	_, err = meta.WriteString(`{"title": "Weather", "fieldMeta": {"unit": "acme.unit", "min": "acme.range.min", ` +
+		`"max": "acme.range.max"}}`)
+	require.NoError(t, err)
+	require.NoError(t, meta.Close())
+
+	return meta.Name()
+}
+
+func TestFieldMetaString(t *testing.T) {
+	require.Equal(t, "[0, 100] %", FieldMeta{Unit: "%", Min: "0", Max: "100"}.String())
+	require.Equal(t, "≥ 0 K", FieldMeta{Unit: "K", Min: "0"}.String())
+	require.Equal(t, "≤ 10", FieldMeta{Max: "10"}.String())
+	require.Equal(t, "ms", FieldMeta{Unit: "ms"}.String())
+}
+
+func TestParseOptionsForFieldMeta(t *testing.T) {
+	meta := writeFieldMetaFile(t)
+	defer os.Remove(meta)
+
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:meta_file=" + meta)
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "Weather", options.Title)
+	require.Equal(t, &FieldMetaMapping{Unit: "acme.unit", Min: "acme.range.min", Max: "acme.range.max"},
+		options.FieldMeta)
+}
+
+func TestRenderFieldMeta(t *testing.T) {
+	meta := writeFieldMetaFile(t)
+	defer os.Remove(meta)
+
+	resp, err := new(Plugin).Generate(newMeasurementRequest(t, "markdown,docs.md:meta_file="+meta))
+	require.NoError(t, err)
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "| Field | Type | Label | Constraints | Description |")
+	require.Contains(t, content, "</a> humidity | [double](#double) |  | [0, 100] % |  |")
+	require.Contains(t, content, "</a> temperature | [double](#double) |  | ≥ 0 K |  |")
+	require.Contains(t, content, "</a> wind_direction | [double](#double) |  |  |  |")
+
+	for _, format := range []string{"html,index.html:", "html,index.html:template=slate,",
+		"html,index.html:template=minimal,", "html,index.html:template=print,"} {
+		resp, err := new(Plugin).Generate(newMeasurementRequest(t, format+"meta_file="+meta))
+		require.NoError(t, err, format)
+		require.Contains(t, resp.File[0].GetContent(), "Constraints", format)
+		require.Contains(t, resp.File[0].GetContent(), "<td>[0, 100] %</td>", format)
+	}
+
+	resp, err = new(Plugin).Generate(newMeasurementRequest(t, "json,docs.json:meta_file="+meta))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), `"meta": {
+                "unit": "%",
+                "min": "0",
+                "max": "100"
+              }`)
+
+	// without a mapping, there's no column
+	resp, err = new(Plugin).Generate(newMeasurementRequest(t, "markdown,docs.md"))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), "Constraints")
+}
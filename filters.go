@@ -3,50 +3,223 @@ package gendoc
 import (
 	"fmt"
 	"html/template"
+	"reflect"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 var (
-	paraPattern         = regexp.MustCompile(`(\n|\r|\r\n)\s*`)
+	// line breaks, including the Unicode next line, line separator and paragraph separator characters
+	lineBreakPattern    = regexp.MustCompile("\r\n|[\r\n\u0085\u2028\u2029]")
 	spacePattern        = regexp.MustCompile("( )+")
 	multiNewlinePattern = regexp.MustCompile(`(\r\n|\r|\n){2,}`)
-	specialCharsPattern = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+	specialCharsPattern = regexp.MustCompile(`[^\p{L}\p{M}\p{N}_-]`)
+	protoTokenPattern   = regexp.MustCompile(`//.*$|"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\b[A-Za-z_][A-Za-z0-9_]*\b|\b[0-9][0-9A-Za-z.]*\b`)
+
+	protoKeywords = map[string]bool{
+		"syntax": true, "edition": true, "package": true, "import": true, "public": true, "weak": true,
+		"option": true, "message": true, "enum": true, "service": true, "rpc": true, "returns": true,
+		"stream": true, "oneof": true, "map": true, "extend": true, "extensions": true, "reserved": true,
+		"to": true, "max": true, "optional": true, "required": true, "repeated": true, "group": true,
+		"true": true, "false": true,
+	}
 )
 
 // PFilter splits the content by new lines and wraps each one in a <p> tag.
 func PFilter(content string) template.HTML {
-	paragraphs := paraPattern.Split(content, -1)
+	paragraphs := splitLines(content)
 	return template.HTML(fmt.Sprintf("<p>%s</p>", strings.Join(paragraphs, "</p><p>")))
 }
 
 // ParaFilter splits the content by new lines and wraps each one in a <para> tag.
 func ParaFilter(content string) string {
-	paragraphs := paraPattern.Split(content, -1)
+	paragraphs := splitLines(content)
 	return fmt.Sprintf("<para>%s</para>", strings.Join(paragraphs, "</para><para>"))
 }
 
+// splitLines splits content into its lines, without the ASCII whitespace they start with (other whitespace, like the
+// ideographic space indenting Chinese and Japanese paragraphs, is kept). Blank lines are dropped, except at the end.
+func splitLines(content string) []string {
+	lines := lineBreakPattern.Split(content, -1)
+	kept := lines[:1]
+	for i, line := range lines[1:] {
+		line = strings.TrimLeftFunc(line, isASCIISpace)
+		if !isBlank(line) || i == len(lines)-2 {
+			kept = append(kept, line)
+		}
+	}
+
+	return kept
+}
+
+// isASCIISpace reports whether r is an ASCII whitespace character.
+func isASCIISpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v'
+}
+
+// isBlank reports whether line only holds whitespace, including Unicode whitespace like full-width spaces.
+func isBlank(line string) bool {
+	return strings.TrimFunc(line, unicode.IsSpace) == ""
+}
+
+// isUnspacedRune reports whether r belongs to a script written without spaces between words (Chinese and Japanese),
+// or is a full-width punctuation mark.
+func isUnspacedRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) ||
+		(r >= 0x3000 && r <= 0x303f) || // CJK symbols and punctuation
+		(r >= 0xff00 && r <= 0xffef) // halfwidth and fullwidth forms
+}
+
+// joinLines appends a line continuing text to it. They're separated by a space, except when text ends or the line
+// starts with a character of a script written without spaces (see isUnspacedRune), where a line break isn't a space.
+func joinLines(text, line string) string {
+	text, line = strings.TrimSpace(text), strings.TrimSpace(line)
+	if text == "" || line == "" {
+		return text + line
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(text)
+	first, _ := utf8.DecodeRuneInString(line)
+	if isUnspacedRune(last) || isUnspacedRune(first) {
+		return text + line
+	}
+
+	return text + " " + line
+}
+
+// RtfFilter escapes content for use in RTF documents. Backslashes and braces are escaped, characters outside of ASCII
+// are written as \uN? escapes, and line breaks become \line (or \par between paragraphs).
+func RtfFilter(content string) string {
+	normalized := strings.Replace(content, "\r\n", "\n", -1)
+
+	var b strings.Builder
+	for i, paragraph := range multiNewlinePattern.Split(normalized, -1) {
+		if i > 0 {
+			b.WriteString(`\par `)
+		}
+
+		for _, r := range paragraph {
+			switch {
+			case r == '\\' || r == '{' || r == '}':
+				b.WriteByte('\\')
+				b.WriteRune(r)
+			case r == '\n' || r == '\r':
+				b.WriteString(`\line `)
+			case r == '\t':
+				b.WriteString(`\tab `)
+			case r < 0x20:
+				continue
+			case r < 0x80:
+				b.WriteRune(r)
+			default:
+				// RTF takes UTF-16 code units as signed 16-bit numbers, followed by a fallback character
+				for _, unit := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(&b, `\u%d?`, int16(unit))
+				}
+			}
+		}
+	}
+
+	return b.String()
+}
+
 // NoBrFilter removes single CR and LF from content, replacing them with <br> for proper
 // rendering in markdown and HTML tables.
 func NoBrFilter(content string) template.HTML {
-	normalized := strings.Replace(content, "\r\n", "\n", -1)
-	paragraphs := multiNewlinePattern.Split(normalized, -1)
-	for i, p := range paragraphs {
-		// First normalize multiple spaces to single space
-		p = spacePattern.ReplaceAllString(p, " ")
-		// Then replace newlines with <br>
-		withoutCR := strings.Replace(p, "\r", "<br>", -1)
-		withoutLF := strings.Replace(withoutCR, "\n", "<br>", -1)
-		// Trim any extra spaces around <br> tags
-		withoutLF = strings.Replace(withoutLF, " <br>", "<br>", -1)
-		withoutLF = strings.Replace(withoutLF, "<br> ", "<br>", -1)
-		paragraphs[i] = withoutLF
+	lines := lineBreakPattern.Split(content, -1)
+	paragraphs := make([]string, 0)
+	paragraph := make([]string, 0, len(lines))
+	for i, line := range lines {
+		// blank lines (including lines of Unicode whitespace) between others separate paragraphs
+		if i > 0 && i < len(lines)-1 && isBlank(line) {
+			if len(paragraph) > 0 {
+				paragraphs = append(paragraphs, noBrParagraph(paragraph))
+				paragraph = paragraph[:0]
+			}
+			continue
+		}
+
+		paragraph = append(paragraph, line)
 	}
+	paragraphs = append(paragraphs, noBrParagraph(paragraph))
+
 	// Join paragraphs with <br><br> instead of \n\n for proper table rendering
 	return template.HTML(strings.Join(paragraphs, "<br><br>"))
 }
 
+// noBrParagraph joins the lines of a paragraph with <br>, normalizing multiple spaces to a single one and trimming the
+// ASCII whitespace around the <br> tags.
+func noBrParagraph(lines []string) string {
+	for i, line := range lines {
+		line = spacePattern.ReplaceAllString(line, " ")
+		if i > 0 {
+			line = strings.TrimLeftFunc(line, isASCIISpace)
+		}
+		if i < len(lines)-1 {
+			line = strings.TrimRightFunc(line, isASCIISpace)
+		}
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "<br>")
+}
+
+// DisplayFilter formats an arbitrary value (e.g. the payload of an extension option) using the %+v verb. Pointers are
+// followed first, so the output doesn't depend on memory addresses.
+func DisplayFilter(value interface{}) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%+v", v.Interface())
+}
+
 // AnchorFilter replaces all special characters with URL friendly dashes
 func AnchorFilter(str string) string {
 	return specialCharsPattern.ReplaceAllString(strings.ReplaceAll(str, "/", "_"), "-")
 }
+
+// HighlightFilter wraps the tokens of a line of proto source in <span> tags so that keywords, strings, numbers, and
+// comments can be styled individually. All content is HTML escaped.
+func HighlightFilter(line string) template.HTML {
+	var b strings.Builder
+	last := 0
+
+	for _, loc := range protoTokenPattern.FindAllStringIndex(line, -1) {
+		token := line[loc[0]:loc[1]]
+		class := ""
+
+		switch {
+		case strings.HasPrefix(token, "//"):
+			class = "comment"
+		case strings.HasPrefix(token, "\"") || strings.HasPrefix(token, "'"):
+			class = "string"
+		case token[0] >= '0' && token[0] <= '9':
+			class = "number"
+		case protoKeywords[token]:
+			class = "keyword"
+		}
+
+		if class == "" {
+			continue
+		}
+
+		b.WriteString(template.HTMLEscapeString(line[last:loc[0]]))
+		b.WriteString(fmt.Sprintf(`<span class="%s">%s</span>`, class, template.HTMLEscapeString(token)))
+		last = loc[1]
+	}
+
+	b.WriteString(template.HTMLEscapeString(line[last:]))
+	return template.HTML(b.String())
+}
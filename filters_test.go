@@ -2,6 +2,7 @@ package gendoc_test
 
 import (
 	html "html/template"
+	"strings"
 	"testing"
 
 	. "github.com/daotl/protoc-gen-doc"
@@ -50,15 +51,88 @@ func TestNoBrFilter(t *testing.T) {
 	}
 }
 
+func TestPFilterWithUnicode(t *testing.T) {
+	tests := map[string]string{
+		"予約を作成します。\n車両を指定してください。":                   "<p>予約を作成します。</p><p>車両を指定してください。</p>",
+		"第一段落。\n\u3000\n\u3000第二段落。":                "<p>第一段落。</p><p>\u3000第二段落。</p>",
+		"Line one.\u2028Line two.\u2029Line three.": "<p>Line one.</p><p>Line two.</p><p>Line three.</p>",
+		"מזמין רכב.\nהזמנה 📅 חדשה.":                 "<p>מזמין רכב.</p><p>הזמנה 📅 חדשה.</p>",
+		"Ends with a break.\n":                      "<p>Ends with a break.</p><p></p>",
+	}
+
+	for input, output := range tests {
+		require.Equal(t, html.HTML(output), PFilter(input), input)
+		require.Equal(t, strings.NewReplacer("<p>", "<para>", "</p>", "</para>").Replace(output), ParaFilter(input))
+	}
+}
+
+func TestNoBrFilterWithUnicode(t *testing.T) {
+	tests := map[string]string{
+		"予約を作成します。\n車両を指定してください。":                   "予約を作成します。<br>車両を指定してください。",
+		"第一段落。\n\u3000\n\u3000第二段落。":                "第一段落。<br><br>\u3000第二段落。",
+		"Non\u00a0breaking \nspace\u00a0\u00a0kept": "Non\u00a0breaking<br>space\u00a0\u00a0kept",
+		"Line one.\u2028Line two.":                  "Line one.<br>Line two.",
+		"הזמנה 📅\n\nחדשה 🚗.":                        "הזמנה 📅<br><br>חדשה 🚗.",
+		"Trailing break\n":                          "Trailing break<br>",
+	}
+
+	for input, output := range tests {
+		require.Equal(t, html.HTML(output), NoBrFilter(input), input)
+	}
+}
+
 func TestAnchorFilter(t *testing.T) {
 	tests := map[string]string{
 		"com/example/test.proto":  "com_example_test-proto",
 		"com.example.SomeRequest": "com-example-SomeRequest",
-		"héllô":                   "h-ll-",
+		"héllô":                   "héllô",
 		"un_modified-Content":     "un_modified-Content",
+		"予約/サービス.proto":           "予約_サービス-proto",
+		"שלום עולם":               "שלום-עולם",
+		"book 📚":                  "book--",
 	}
 
 	for input, output := range tests {
 		require.Equal(t, output, AnchorFilter(input))
 	}
 }
+
+func TestHighlightFilter(t *testing.T) {
+	tests := map[string]string{
+		"message Booking {":               `<span class="keyword">message</span> Booking {`,
+		"int32 id = 1; // The id":         `int32 id = <span class="number">1</span>; <span class="comment">// The id</span>`,
+		`string s = 2 [default = "<b>"];`: `string s = <span class="number">2</span> [default = <span class="string">&#34;&lt;b&gt;&#34;</span>];`,
+		"a < b":                           "a &lt; b",
+	}
+
+	for input, output := range tests {
+		require.Equal(t, html.HTML(output), HighlightFilter(input))
+	}
+}
+
+func TestRtfFilter(t *testing.T) {
+	tests := map[string]string{
+		"plain text":                     "plain text",
+		`C:\path {braces}`:               `C:\\path \{braces\}`,
+		"line one\nline two":             `line one\line line two`,
+		"paragraph one\n\nparagraph two": `paragraph one\par paragraph two`,
+		"caf\u00e9 \u00fcber":            `caf\u233? \u252?ber`,
+		"\u4e2d\u6587":                   `\u20013?\u25991?`,
+		"emoji \U0001F600":               `emoji \u-10179?\u-8704?`,
+	}
+
+	for input, output := range tests {
+		require.Equal(t, output, RtfFilter(input), input)
+	}
+}
+
+func TestDisplayFilter(t *testing.T) {
+	enabled := true
+	rule := &struct{ Name, Value string }{"max_len", "10"}
+
+	require.Equal(t, "true", DisplayFilter(&enabled))
+	require.Equal(t, "{Name:max_len Value:10}", DisplayFilter(rule))
+	require.Equal(t, "42", DisplayFilter(42))
+	require.Equal(t, "<nil>", DisplayFilter((*bool)(nil)))
+	require.Equal(t, "<nil>", DisplayFilter(nil))
+}
@@ -7,3 +7,17 @@ package fixtures
 // compiled without the flag), only pass this flag when compiling the one message explicitly testing proto3 optional fields.
 // Once this feature is no longer behind an experimental flag, compilation of Cookie.proto can be moved to the above protoc command.
 //go:generate protoc --experimental_allow_proto3_optional --descriptor_set_out=cookie.pb --include_imports --include_source_info -I. -I../thirdparty Cookie.proto
+
+// The fixtures of single features, each in a descriptor set of its own since several declare the same package. The
+// googleapis directory holds the googleapis protos they import, trimmed to what they use.
+//go:generate protoc --descriptor_set_out=resources.pb --include_imports --include_source_info -Iresources -Igoogleapis library.proto
+//go:generate protoc --descriptor_set_out=operations.pb --include_imports --include_source_info -Ioperations -Igoogleapis library.proto
+//go:generate protoc --descriptor_set_out=errors.pb --include_imports --include_source_info -Ierrors library.proto
+//go:generate protoc --descriptor_set_out=exclude.pb --include_imports --include_source_info -Iexclude shop.proto
+//go:generate protoc --descriptor_set_out=annotated.pb --include_imports --include_source_info -Iannotated org/user.proto
+//go:generate protoc --descriptor_set_out=inventory.pb --include_imports --include_source_info -Iinventory inventory.proto
+//go:generate protoc --descriptor_set_out=snippets.pb --include_imports --include_source_info -Isnippets library.proto
+//go:generate protoc --descriptor_set_out=measurement.pb --include_imports --include_source_info -Imeasurement acme/weather.proto
+
+// The WebAssembly module used to test the wasm: format and comment hook (requires wabt).
+//go:generate wat2wasm upper.wat -o upper.wasm
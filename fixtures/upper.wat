@@ -0,0 +1,34 @@
+;; A WASI command copying its standard input to its standard output in upper case, used to test WebAssembly renderers
+;; and comment hooks. Characters following a backslash are kept as they are, so that JSON escapes stay valid.
+(module
+  (import "wasi_snapshot_preview1" "fd_read" (func $fd_read (param i32 i32 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+
+  ;; The iovec is at 0, the count of bytes read or written at 8 and the buffer at 16.
+  (func (export "_start")
+    (local $n i32) (local $i i32) (local $c i32) (local $escaped i32)
+    (loop $read
+      (i32.store (i32.const 0) (i32.const 16))
+      (i32.store (i32.const 4) (i32.const 4096))
+      (i32.store (i32.const 8) (i32.const 0))
+      (drop (call $fd_read (i32.const 0) (i32.const 0) (i32.const 1) (i32.const 8)))
+      (local.set $n (i32.load (i32.const 8)))
+      (if (i32.eqz (local.get $n)) (then (return)))
+
+      (local.set $i (i32.const 0))
+      (loop $upper
+        (local.set $c (i32.load8_u offset=16 (local.get $i)))
+        (if (local.get $escaped)
+          (then (local.set $escaped (i32.const 0)))
+          (else
+            (local.set $escaped (i32.eq (local.get $c) (i32.const 92)))
+            (if (i32.lt_u (i32.sub (local.get $c) (i32.const 97)) (i32.const 26))
+              (then (i32.store8 offset=16 (local.get $i) (i32.sub (local.get $c) (i32.const 32)))))))
+        (local.set $i (i32.add (local.get $i) (i32.const 1)))
+        (br_if $upper (i32.lt_u (local.get $i) (local.get $n))))
+
+      (i32.store (i32.const 4) (local.get $n))
+      (drop (call $fd_write (i32.const 1) (i32.const 0) (i32.const 1) (i32.const 8)))
+      (br $read)))
+)
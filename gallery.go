@@ -0,0 +1,109 @@
+package gendoc
+
+import (
+	"fmt"
+)
+
+// Layouts of the built-in HTML template, selected with the template option.
+const (
+	// HTMLTemplateDefault lists the documentation next to a sidebar with the table of contents. It's also the layout of
+	// the built-in templates of the other formats.
+	HTMLTemplateDefault = "default"
+	// HTMLTemplateSlate is a three-pane layout in the style of Slate: the table of contents, the documentation, and an
+	// example request and response of every method (and an example of every message) in a dark column on the right.
+	HTMLTemplateSlate = "slate"
+	// HTMLTemplateMinimal is a single column of unstyled tables, for embedding into other pages or restyling from
+	// scratch.
+	HTMLTemplateMinimal = "minimal"
+	// HTMLTemplatePrint is laid out for printing and PDF export: no navigation, page breaks between files, and link
+	// targets spelled out.
+	HTMLTemplatePrint = "print"
+)
+
+// TemplateBlocksPrefix is the prefix of the names of the blocks of the built-in templates, which every template can
+// use: e.g. `{{template "gendoc/default/field-table" .}}` renders the fields of a message the way the default HTML
+// template does. Custom templates can override a block by defining it again.
+const TemplateBlocksPrefix = "gendoc/"
+
+// BuiltinTemplate is a template embedded in the binary. See BuiltinTemplates.
+type BuiltinTemplate struct {
+	// The name the template is listed and dumped with: its format and layout, e.g. `html/slate`.
+	Name string
+	// The format the template renders.
+	Type RenderType
+	// The layout of the template: HTMLTemplateDefault, or one of the other HTMLTemplate layouts for the html format.
+	Layout string
+	// What the template looks like, in a sentence.
+	Description string
+	// The file of the template in the resources directory.
+	file string
+}
+
+// Source returns the text of the template, e.g. to write it out as the starting point of a custom template.
+func (t *BuiltinTemplate) Source() []byte {
+	data, err := templatesFS.ReadFile("resources/" + t.file)
+	if err != nil {
+		// every file is embedded, see TestBuiltinTemplates
+		panic(err)
+	}
+
+	return data
+}
+
+// builtinTemplates are the templates embedded in the binary, sorted by name.
+var builtinTemplates = []*BuiltinTemplate{
+	{"docbook/default", RenderTypeDocBook, HTMLTemplateDefault, "DocBook 5 article", "docbook.tmpl"},
+	{"html/default", RenderTypeHTML, HTMLTemplateDefault, "Single page with a sidebar and light and dark themes",
+		"html.tmpl"},
+	{"html/minimal", RenderTypeHTML, HTMLTemplateMinimal, "Single column of plain tables with hardly any styling",
+		"minimal.tmpl"},
+	{"html/print", RenderTypeHTML, HTMLTemplatePrint, "Print-friendly page for paper and PDF export", "print.tmpl"},
+	{"html/slate", RenderTypeHTML, HTMLTemplateSlate, "Three-pane API reference with request and response examples",
+		"slate.tmpl"},
+	{"markdown/default", RenderTypeMarkdown, HTMLTemplateDefault,
+		"Markdown with HTML anchors (the gfm format renders it as GitHub Flavored Markdown)", "markdown.tmpl"},
+	{"rst/default", RenderTypeRST, HTMLTemplateDefault, "reStructuredText for Sphinx", "rst.tmpl"},
+	{"rtf/default", RenderTypeRTF, HTMLTemplateDefault, "Rich Text Format document for word processors", "rtf.tmpl"},
+	{"wiki/default", RenderTypeWiki, HTMLTemplateDefault, "Package page of a GitHub or Gollum wiki", "wiki.tmpl"},
+}
+
+// BuiltinTemplates returns the templates embedded in the binary, sorted by name.
+func BuiltinTemplates() []*BuiltinTemplate {
+	return append([]*BuiltinTemplate(nil), builtinTemplates...)
+}
+
+// FindBuiltinTemplate returns the built-in template with the given name (e.g. `html/slate`), or an error if there's
+// none.
+func FindBuiltinTemplate(name string) (*BuiltinTemplate, error) {
+	for _, t := range builtinTemplates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown built-in template: %v", name)
+}
+
+// builtinTemplate returns the built-in template rendering rt in the given layout (HTMLTemplateDefault when empty), or
+// nil if there's none. The gfm format uses the Markdown template.
+func builtinTemplate(rt RenderType, layout string) *BuiltinTemplate {
+	if rt == RenderTypeGFM {
+		rt = RenderTypeMarkdown
+	}
+	if layout == "" {
+		layout = HTMLTemplateDefault
+	}
+
+	for _, t := range builtinTemplates {
+		if t.Type == rt && t.Layout == layout {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// isHTMLTemplate returns whether layout is a valid value of the template option.
+func isHTMLTemplate(layout string) bool {
+	return builtinTemplate(RenderTypeHTML, layout) != nil
+}
@@ -0,0 +1,90 @@
+package gendoc_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+)
+
+func newBookingTemplate(t *testing.T) *Template {
+	return NewTemplate(protokit.ParseCodeGenRequest(newBookingRequest(t, "")), &PluginOptions{})
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	templates := BuiltinTemplates()
+	names := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		require.NotEmpty(t, tmpl.Source(), tmpl.Name)
+		require.NotEmpty(t, tmpl.Description, tmpl.Name)
+		require.True(t, strings.HasSuffix(tmpl.Name, "/"+tmpl.Layout), tmpl.Name)
+		names = append(names, tmpl.Name)
+	}
+
+	require.True(t, sort.StringsAreSorted(names))
+	require.Contains(t, names, "html/minimal")
+	require.Contains(t, names, "html/print")
+
+	// the list is a copy
+	templates[0] = nil
+	require.NotNil(t, BuiltinTemplates()[0])
+}
+
+func TestFindBuiltinTemplate(t *testing.T) {
+	tmpl, err := FindBuiltinTemplate("html/slate")
+	require.NoError(t, err)
+	require.Equal(t, RenderTypeHTML, tmpl.Type)
+	require.Equal(t, HTMLTemplateSlate, tmpl.Layout)
+	require.Contains(t, string(tmpl.Source()), `<div class="dark-box"></div>`)
+
+	_, err = FindBuiltinTemplate("html/fancy")
+	require.EqualError(t, err, "Unknown built-in template: html/fancy")
+}
+
+func TestRenderMinimal(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:template=minimal"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<li><a href="#Booking-proto">Booking.proto</a></li>`)
+	require.Contains(t, content, `<h3 id="com-example-BookingService">BookingService</h3>`)
+	require.Contains(t, content, `<tr id="int32">`)
+	require.NotContains(t, content, "sidebar")
+}
+
+func TestRenderPrint(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:template=print"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "@page {")
+	require.Contains(t, content, `<h2 class="file" id="Booking-proto">Booking.proto</h2>`)
+	require.Contains(t, content, `<a class="ref" href="#com-example-Booking">Booking</a>`)
+	require.NotContains(t, content, "sidebar")
+}
+
+func TestDumpedTemplatesRender(t *testing.T) {
+	// both renderings are compared, so they mustn't show the time they were generated at
+	newReproducibleTemplate := func() *Template {
+		return NewTemplate(protokit.ParseCodeGenRequest(newBookingRequest(t, "")), &PluginOptions{Reproducible: true})
+	}
+
+	for _, tmpl := range BuiltinTemplates() {
+		template := newReproducibleTemplate()
+		template.Theme.Layout = tmpl.Layout
+		output, err := RenderTemplate(tmpl.Type, template, string(tmpl.Source()))
+		require.NoError(t, err, tmpl.Name)
+		require.Contains(t, string(output), "BookingService", tmpl.Name)
+
+		// custom templates are rendered without HTML escaping, so only the text formats render exactly like the
+		// built-in ones
+		if tmpl.Type == RenderTypeRST || tmpl.Type == RenderTypeRTF || tmpl.Type == RenderTypeDocBook {
+			builtin, err := RenderTemplate(tmpl.Type, newReproducibleTemplate(), "")
+			require.NoError(t, err, tmpl.Name)
+			require.Equal(t, string(builtin), string(output), tmpl.Name)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package gendoc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// Options configures Generate.
+type Options struct {
+	// Parameter uses the same format as the plugin's `--doc_opt` value, for example
+	// "markdown,docs.md:camel_case_fields=true". When empty, HTML is written to index.html.
+	Parameter string
+	// FilesToGenerate lists the names of the files in the set to document. When empty, every file in the set is
+	// documented.
+	FilesToGenerate []string
+	// CommentHook processes the descriptions of the documented entities before they're rendered, in place of the
+	// comment_hook option.
+	CommentHook CommentHook
+	// Renderer renders the output files in place of the format or template of the Parameter, e.g. a Processor of
+	// your own.
+	Renderer Processor
+}
+
+// OutputFile is a file produced by Generate.
+type OutputFile struct {
+	// Name is the path of the file, relative to the output directory.
+	Name string
+	// Content is the generated documentation.
+	Content string
+}
+
+// Generate renders documentation for the files in a descriptor set without going through protoc. This makes it
+// possible to document services at runtime, e.g. from the descriptors they expose via reflection.
+//
+// The set must contain all dependencies of the files being documented, in the same way `protoc --include_imports`
+// produces them. Source info (`--include_source_info`) is needed for comments to be included.
+//
+// Example: generating Markdown for every file in a set
+//
+//	files, err := gendoc.Generate(fdset, gendoc.Options{Parameter: "markdown,docs.md"})
+func Generate(fdset *descriptorpb.FileDescriptorSet, opts Options) ([]OutputFile, error) {
+	if fdset == nil {
+		return nil, errors.New("Unable to generate documentation for a nil descriptor set")
+	}
+
+	names := opts.FilesToGenerate
+	if len(names) == 0 {
+		for _, fd := range fdset.GetFile() {
+			names = append(names, fd.GetName())
+		}
+	}
+
+	known := make(map[string]bool, len(fdset.GetFile()))
+	for _, fd := range fdset.GetFile() {
+		known[fd.GetName()] = true
+	}
+
+	for _, name := range names {
+		if !known[name] {
+			return nil, fmt.Errorf("File not found in descriptor set: %s", name)
+		}
+	}
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: names,
+		Parameter:      proto.String(opts.Parameter),
+		ProtoFile:      fdset.GetFile(),
+	}
+
+	options, err := ParseOptions(req)
+	if err != nil {
+		return nil, err
+	}
+	// the modules loaded from the parameter are closed even when opts replaces them
+	defer closeWasmModules(options.Renderer, options.CommentHook)
+	if opts.CommentHook != nil {
+		options.CommentHook = opts.CommentHook
+	}
+	if opts.Renderer != nil {
+		options.Renderer = opts.Renderer
+	}
+
+	return generateFiles(req, options)
+}
+
+// Validate checks a `--doc_opt` value without generating any documentation: the options are parsed (including the
+// exclude patterns), and the files they refer to (a custom template, css_file and meta_file) are read. Custom templates
+// are compiled, so syntax errors and calls to unknown functions are reported, but errors that only occur while
+// rendering (e.g. referring to a missing field) are not.
+func Validate(parameter string) error {
+	options, err := ParseOptions(&pluginpb.CodeGeneratorRequest{Parameter: proto.String(parameter)})
+	if err != nil {
+		return err
+	}
+	defer closeWasmModules(options.Renderer, options.CommentHook)
+
+	if options.CSSFile != "" {
+		if _, err := os.ReadFile(options.CSSFile); err != nil {
+			return err
+		}
+	}
+
+	if _, err := readSnippets(options); err != nil {
+		return err
+	}
+
+	if hook, ok := options.CommentHook.(*commandCommentHook); ok {
+		if _, err := exec.LookPath(strings.Fields(hook.command)[0]); err != nil {
+			return err
+		}
+	}
+
+	if renderer, ok := options.Renderer.(*execRenderer); ok {
+		if _, err := exec.LookPath(strings.Fields(renderer.command)[0]); err != nil {
+			return err
+		}
+	}
+
+	if options.TemplateFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(options.TemplateFile)
+	if err != nil {
+		return err
+	}
+
+	renderer := &textRenderer{inputTemplate: string(data), name: options.TemplateFile}
+	_, err = renderer.parse(NewTemplate(nil, options))
+	return err
+}
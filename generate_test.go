@@ -0,0 +1,95 @@
+package gendoc_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	files, err := Generate(set, Options{
+		Parameter:       "markdown,docs.md",
+		FilesToGenerate: []string{"Booking.proto", "Vehicle.proto"},
+	})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "docs.md", files[0].Name)
+	require.Contains(t, files[0].Content, "## Booking.proto")
+	require.Contains(t, files[0].Content, "## Vehicle.proto")
+	require.NotContains(t, files[0].Content, "## nested/Book.proto")
+}
+
+func TestGenerateAllFilesByDefault(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	files, err := Generate(set, Options{})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "index.html", files[0].Name)
+
+	for _, fd := range set.GetFile() {
+		require.Contains(t, files[0].Content, `<h2 id="`+fd.GetName()+`" data-copy="#`+fd.GetName()+`">`)
+	}
+}
+
+func TestGenerateWithInvalidInput(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	_, err = Generate(nil, Options{})
+	require.Error(t, err)
+
+	_, err = Generate(set, Options{FilesToGenerate: []string{"Missing.proto"}})
+	require.Error(t, err)
+
+	_, err = Generate(set, Options{Parameter: "markdown"})
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"",
+		"markdown,docs.md",
+		"html,index.html,source_relative:exclude_patterns=google/.*,index=true",
+		"resources/html.tmpl,index.html:template_sandbox=true",
+	}
+
+	for _, parameter := range valid {
+		require.NoError(t, Validate(parameter), parameter)
+	}
+}
+
+func TestValidateWithInvalidParameter(t *testing.T) {
+	tmpl, err := os.CreateTemp("", "broken-*.tmpl")
+	require.NoError(t, err)
+	defer os.Remove(tmpl.Name())
+
+	_, err = tmpl.WriteString("{{range .Files}}\n{{env \"HOME\"}}\n{{end}}")
+	require.NoError(t, err)
+	require.NoError(t, tmpl.Close())
+
+	invalid := map[string]string{
+		"html,index.html:exclude_patterns=google/(":         "error parsing regexp",
+		"html,index.html:css_file=does/not/exist.css":       "does/not/exist.css",
+		"does/not/exist.tmpl,index.txt":                     "does/not/exist.tmpl",
+		"html,index.html:theme=purple":                      "Invalid theme value: purple",
+		tmpl.Name() + ",index.txt:template_sandbox=true":    tmpl.Name() + `:2: function "env" not defined`,
+		"resources/html.tmpl,index.html:template_sandbox=1": "Invalid template_sandbox value: 1",
+	}
+
+	for parameter, message := range invalid {
+		err := Validate(parameter)
+		require.Error(t, err, parameter)
+		require.Contains(t, err.Error(), message, parameter)
+	}
+
+	// only the sandbox disallows env
+	require.NoError(t, Validate(tmpl.Name()+",index.txt"))
+}
@@ -0,0 +1,45 @@
+package gendoc_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newInventoryRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "inventory.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "inventory.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestRenderGFM(t *testing.T) {
+	resp, err := new(Plugin).Generate(newInventoryRequest(t, "gfm,README.md"))
+	require.NoError(t, err)
+	require.Equal(t, "README.md", resp.File[0].GetName())
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "\n<details>\n<summary>Fields (11)</summary>\n\n| Field | Type | Label | Description |\n")
+	require.Contains(t, content, "</a> slot_11 | [string](#string) |  |  |\n\n</details>\n")
+	require.Contains(t, content, "### Crate\n\n> [!WARNING]\n> Deprecated.\n")
+	require.Contains(t, content, "### Size\n\n> [!WARNING]\n> Deprecated.\n")
+	require.Contains(t, content, "### Stock\n\n> [!WARNING]\n> Deprecated.\n")
+	require.Equal(t, 1, strings.Count(content, "<details>"))
+}
+
+func TestRenderMarkdownWithoutGFM(t *testing.T) {
+	resp, err := new(Plugin).Generate(newInventoryRequest(t, "markdown,README.md"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.NotContains(t, content, "<details>")
+	require.Contains(t, content, "### Crate\n\n\nUsed by:")
+	require.NotContains(t, content, "[!WARNING]")
+}
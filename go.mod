@@ -1,18 +1,21 @@
 module github.com/daotl/protoc-gen-doc
 
-go 1.17
+go 1.18
 
 require (
 	github.com/Masterminds/semver v1.5.0 // indirect
 	github.com/Masterminds/sprig v2.22.0+incompatible
 	github.com/envoyproxy/protoc-gen-validate v1.0.2
 	github.com/gogo/protobuf v1.3.2 // indirect
-	github.com/golang/protobuf v1.5.4
+	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/huandu/xstrings v1.4.0 // indirect
 	github.com/imdario/mergo v0.3.13 // indirect
 	github.com/mwitkow/go-proto-validators v0.3.2
 	github.com/pseudomuto/protokit v0.2.0
 	github.com/stretchr/testify v1.8.4
+	github.com/tetratelabs/wazero v1.2.1
+	golang.org/x/net v0.21.0
+	google.golang.org/protobuf v1.33.0
 )
 
 require (
@@ -30,8 +33,8 @@ require (
 	github.com/mitchellh/reflectwalk v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.9.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
 	google.golang.org/genproto v0.0.0-20240205150955-31a09d347014 // indirect
-	google.golang.org/protobuf v1.33.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,242 @@
+package gendoc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ImportGraph is the graph of import relationships between files.
+type ImportGraph struct {
+	// The template's files, followed by the files they import that aren't part of the template (sorted by name).
+	Nodes []*ImportNode `json:"nodes"`
+	// An edge for every import statement, in the order of the files and their imports.
+	Edges []*ImportEdge `json:"edges"`
+}
+
+// ImportNode is a file in an ImportGraph.
+type ImportNode struct {
+	// A unique identifier, safe to use in any graph format.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Whether the file is documented, i.e. part of the template rather than only imported.
+	Documented bool `json:"documented"`
+}
+
+// ImportEdge is an import of one file by another.
+type ImportEdge struct {
+	From   *ImportNode `json:"from"`
+	To     *ImportNode `json:"to"`
+	Public bool        `json:"public"`
+	Weak   bool        `json:"weak"`
+	// Whether the import is part of an import cycle.
+	Cycle bool `json:"cycle"`
+}
+
+// ImportGraph returns the graph of imports between the template's files and the files they import.
+func (t *Template) ImportGraph() *ImportGraph {
+	graph := &ImportGraph{Nodes: make([]*ImportNode, 0), Edges: make([]*ImportEdge, 0)}
+	nodes := make(map[string]*ImportNode)
+
+	addNode := func(name string, documented bool) {
+		node := &ImportNode{ID: fmt.Sprintf("file%d", len(graph.Nodes)), Name: name, Documented: documented}
+		nodes[name] = node
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, f := range t.Files {
+		addNode(f.Name, true)
+	}
+
+	external := make([]string, 0)
+	for _, f := range t.Files {
+		for _, i := range f.Imports {
+			if _, ok := nodes[i.Name]; !ok {
+				nodes[i.Name] = nil
+				external = append(external, i.Name)
+			}
+		}
+	}
+
+	sort.Strings(external)
+	for _, name := range external {
+		addNode(name, false)
+	}
+
+	for _, f := range t.Files {
+		for _, i := range f.Imports {
+			graph.Edges = append(graph.Edges, &ImportEdge{From: nodes[f.Name], To: nodes[i.Name], Public: i.Public, Weak: i.Weak})
+		}
+	}
+
+	markCycles(graph)
+	return graph
+}
+
+// markCycles flags the edges that are part of a cycle, i.e. those within a strongly connected component (found using
+// Tarjan's algorithm).
+func markCycles(graph *ImportGraph) {
+	adjacent := make(map[*ImportNode][]*ImportNode)
+	for _, edge := range graph.Edges {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+	}
+
+	index := make(map[*ImportNode]int)
+	lowLink := make(map[*ImportNode]int)
+	onStack := make(map[*ImportNode]bool)
+	component := make(map[*ImportNode]int)
+	stack := make([]*ImportNode, 0)
+	components := 0
+
+	var connect func(node *ImportNode)
+	connect = func(node *ImportNode) {
+		index[node] = len(index)
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range adjacent[node] {
+			if _, visited := index[next]; !visited {
+				connect(next)
+				if lowLink[next] < lowLink[node] {
+					lowLink[node] = lowLink[next]
+				}
+			} else if onStack[next] && index[next] < lowLink[node] {
+				lowLink[node] = index[next]
+			}
+		}
+
+		if lowLink[node] != index[node] {
+			return
+		}
+
+		for {
+			member := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[member] = false
+			component[member] = components
+			if member == node {
+				break
+			}
+		}
+		components++
+	}
+
+	for _, node := range graph.Nodes {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+
+	sizes := make(map[int]int)
+	for _, c := range component {
+		sizes[c]++
+	}
+
+	for _, edge := range graph.Edges {
+		c := component[edge.From]
+		edge.Cycle = c == component[edge.To] && (sizes[c] > 1 || edge.From == edge.To)
+	}
+}
+
+// dotRenderer renders the import graph in the Graphviz DOT language. Files that are only imported have a dashed
+// outline, public imports are bold and labelled, weak imports are dotted and cycles are red.
+type dotRenderer struct{}
+
+func (r *dotRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *dotRenderer) ApplyTo(w io.Writer, template *Template) error {
+	graph := template.ImportGraph()
+
+	var b strings.Builder
+	b.WriteString("digraph imports {\n  rankdir=LR;\n  node [shape=box];\n\n")
+
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%q", node.ID, node.Name)
+		if !node.Documented {
+			b.WriteString(", style=dashed")
+		}
+		b.WriteString("];\n")
+	}
+
+	if len(graph.Edges) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, edge := range graph.Edges {
+		attrs := make([]string, 0)
+		if edge.Public {
+			attrs = append(attrs, "style=bold", `label="public"`)
+		} else if edge.Weak {
+			attrs = append(attrs, "style=dotted", `label="weak"`)
+		}
+		if edge.Cycle {
+			attrs = append(attrs, "color=red")
+		}
+
+		fmt.Fprintf(&b, "  %s -> %s", edge.From.ID, edge.To.ID)
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidRenderer renders the import graph as a Mermaid flowchart, styled like the DOT output.
+type mermaidRenderer struct{}
+
+func (r *mermaidRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *mermaidRenderer) ApplyTo(w io.Writer, template *Template) error {
+	graph := template.ImportGraph()
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	for _, node := range graph.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", node.ID, strings.ReplaceAll(node.Name, `"`, "#quot;"))
+	}
+
+	cycles := make([]string, 0)
+	for i, edge := range graph.Edges {
+		arrow := "-->"
+		if edge.Public {
+			arrow = "==>|public|"
+		} else if edge.Weak {
+			arrow = "-.->|weak|"
+		}
+
+		fmt.Fprintf(&b, "  %s %s %s\n", edge.From.ID, arrow, edge.To.ID)
+		if edge.Cycle {
+			cycles = append(cycles, fmt.Sprint(i))
+		}
+	}
+
+	external := make([]string, 0)
+	for _, node := range graph.Nodes {
+		if !node.Documented {
+			external = append(external, node.ID)
+		}
+	}
+
+	if len(external) > 0 {
+		fmt.Fprintf(&b, "  classDef external stroke-dasharray: 5 5\n  class %s external\n", strings.Join(external, ","))
+	}
+
+	if len(cycles) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:red\n", strings.Join(cycles, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,102 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func cyclicTemplate() *Template {
+	return &Template{
+		Files: []*File{
+			{Name: "a.proto", Imports: []*FileImport{{Name: "b.proto", Public: true}, {Name: "other.proto", Weak: true}}},
+			{Name: "b.proto", Imports: []*FileImport{{Name: "c.proto"}}},
+			{Name: "c.proto", Imports: []*FileImport{{Name: "a.proto"}, {Name: "d.proto"}}},
+			{Name: "d.proto", Imports: []*FileImport{{Name: "d.proto"}}},
+		},
+	}
+}
+
+func TestImportGraph(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+
+	require.Equal(t, []*FileImport{{Name: "github.com/pseudomuto/protokit/fixtures/extend.proto"}}, template.Files[0].Imports)
+	require.Empty(t, template.Files[2].Imports)
+
+	graph := template.ImportGraph()
+	require.Len(t, graph.Nodes, 4)
+	require.Equal(t, "nested/Book.proto", graph.Nodes[2].Name)
+	require.True(t, graph.Nodes[2].Documented)
+	require.Equal(t, "github.com/pseudomuto/protokit/fixtures/extend.proto", graph.Nodes[3].Name)
+	require.False(t, graph.Nodes[3].Documented)
+
+	require.Len(t, graph.Edges, 2)
+	require.Equal(t, "Booking.proto", graph.Edges[0].From.Name)
+	require.Equal(t, graph.Nodes[3], graph.Edges[0].To)
+	require.False(t, graph.Edges[0].Cycle)
+}
+
+func TestImportGraphCycles(t *testing.T) {
+	graph := cyclicTemplate().ImportGraph()
+	require.Len(t, graph.Nodes, 5)
+	require.Equal(t, "other.proto", graph.Nodes[4].Name)
+
+	cycles := make([]bool, 0)
+	for _, edge := range graph.Edges {
+		cycles = append(cycles, edge.Cycle)
+	}
+
+	// a -> b, a -> other, b -> c, c -> a, c -> d, d -> d
+	require.Equal(t, []bool{true, false, true, true, false, true}, cycles)
+}
+
+func TestDotRenderType(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeDot, cyclicTemplate(), "")
+	require.NoError(t, err)
+	require.Equal(t, `digraph imports {
+  rankdir=LR;
+  node [shape=box];
+
+  file0 [label="a.proto"];
+  file1 [label="b.proto"];
+  file2 [label="c.proto"];
+  file3 [label="d.proto"];
+  file4 [label="other.proto", style=dashed];
+
+  file0 -> file1 [style=bold, label="public", color=red];
+  file0 -> file4 [style=dotted, label="weak"];
+  file1 -> file2 [color=red];
+  file2 -> file0 [color=red];
+  file2 -> file3;
+  file3 -> file3 [color=red];
+}
+`, string(output))
+}
+
+func TestMermaidRenderType(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeMermaid, cyclicTemplate(), "")
+	require.NoError(t, err)
+	require.Equal(t, `flowchart LR
+  file0["a.proto"]
+  file1["b.proto"]
+  file2["c.proto"]
+  file3["d.proto"]
+  file4["other.proto"]
+  file0 ==>|public| file1
+  file0 -.->|weak| file4
+  file1 --> file2
+  file2 --> file0
+  file2 --> file3
+  file3 --> file3
+  classDef external stroke-dasharray: 5 5
+  class file4 external
+  linkStyle 0,2,3,5 stroke:red
+`, string(output))
+}
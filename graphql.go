@@ -0,0 +1,183 @@
+package gendoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// graphqlScalars maps scalar value types to GraphQL scalars. GraphQL's Int is a signed 32-bit integer, so 64-bit
+// integers are strings like in the proto3 JSON mapping, and unsigned 32-bit integers are floats, which hold them
+// exactly. Bytes are base64 encoded strings.
+var graphqlScalars = map[string]string{
+	"double":   "Float",
+	"float":    "Float",
+	"int32":    "Int",
+	"int64":    "String",
+	"uint32":   "Float",
+	"uint64":   "String",
+	"sint32":   "Int",
+	"sint64":   "String",
+	"fixed32":  "Float",
+	"fixed64":  "String",
+	"sfixed32": "Int",
+	"sfixed64": "String",
+	"bool":     "Boolean",
+	"string":   "String",
+	"bytes":    "String",
+}
+
+// graphqlJSONScalar is the custom scalar used for fields whose type isn't part of the documented files.
+const graphqlJSONScalar = "JSON"
+
+// graphqlRenderer projects messages and enums into GraphQL SDL object and enum types. This is experimental, see the
+// README for the mapping.
+type graphqlRenderer struct{}
+
+func (r *graphqlRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *graphqlRenderer) ApplyTo(w io.Writer, template *Template) error {
+	// the GraphQL name of every documented type, keyed by full name
+	names := make(map[string]string)
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			names[message.FullName] = graphqlTypeName(message.LongName)
+		}
+		for _, enum := range file.AllEnums() {
+			names[enum.FullName] = graphqlTypeName(enum.LongName)
+		}
+	}
+
+	var types strings.Builder
+	usesJSON := false
+	for _, file := range template.Files {
+		for _, message := range file.AllMessages() {
+			if message.Redacted {
+				continue
+			}
+
+			types.WriteString("\n")
+			writeGraphQLDescription(&types, "", message.Description)
+			fmt.Fprintf(&types, "type %s {\n", names[message.FullName])
+
+			fields := 0
+			for _, field := range message.Fields {
+				if field.Redacted {
+					continue
+				}
+				fields++
+
+				description := field.Description
+				if field.IsOneof && !strings.HasPrefix(field.OneofDecl, "_") {
+					description = strings.TrimSpace(description + "\n\n" +
+						fmt.Sprintf("Part of oneof `%s`: at most one of its fields is set.", field.OneofDecl))
+				}
+				writeGraphQLDescription(&types, "  ", description)
+
+				fieldType, ok := graphqlFieldType(field, names)
+				usesJSON = usesJSON || !ok
+				fmt.Fprintf(&types, "  %s: %s%s\n", lowerCamelCase(field.Name), fieldType,
+					graphqlDeprecated(field.Options))
+			}
+
+			// GraphQL types need at least one field
+			if fields == 0 {
+				types.WriteString("  \"The message has no fields.\"\n  _: Boolean\n")
+			}
+
+			types.WriteString("}\n")
+		}
+
+		for _, enum := range file.AllEnums() {
+			types.WriteString("\n")
+			writeGraphQLDescription(&types, "", enum.Description)
+			fmt.Fprintf(&types, "enum %s {\n", names[enum.FullName])
+			for _, value := range enum.Values {
+				if value.Redacted {
+					continue
+				}
+
+				writeGraphQLDescription(&types, "  ", value.Description)
+				fmt.Fprintf(&types, "  %s%s\n", value.Name, graphqlDeprecated(value.Options))
+			}
+			types.WriteString("}\n")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# GraphQL projection of the protocol buffer messages and enums (experimental).\n")
+	if usesJSON {
+		fmt.Fprintf(&b, "\n\"Any value of a type that isn't documented here, in its proto3 JSON mapping.\"\nscalar %s\n",
+			graphqlJSONScalar)
+	}
+	b.WriteString(types.String())
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphqlTypeName returns the GraphQL name of a message or enum, given its long name, e.g. `Vehicle_Category` for
+// `Vehicle.Category`.
+func graphqlTypeName(longName string) string {
+	return strings.ReplaceAll(longName, ".", "_")
+}
+
+// lowerCamelCase returns the camel case GraphQL name of a field, which is also its default JSON name, e.g.
+// `colorPreference` for `color_preference`.
+func lowerCamelCase(name string) string {
+	var b strings.Builder
+	for i, word := range strings.Split(name, "_") {
+		if i > 0 && word != "" {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+
+	return b.String()
+}
+
+// graphqlFieldType returns the GraphQL type of field, and false if it's the JSON scalar used for types that aren't
+// documented. Maps are lists of their entry type, and required fields are non-null.
+func graphqlFieldType(field *MessageField, names map[string]string) (string, bool) {
+	name, ok := graphqlScalars[field.FullType]
+	if !ok {
+		name, ok = names[field.FullType]
+	}
+	if !ok {
+		name = graphqlJSONScalar
+	}
+
+	switch {
+	case field.Label == "repeated":
+		return "[" + name + "!]", ok
+	case field.Label == "required":
+		return name + "!", ok
+	}
+
+	return name, ok
+}
+
+// graphqlDeprecated returns the @deprecated directive for entities with the deprecated option.
+func graphqlDeprecated(options map[string]interface{}) string {
+	if options["deprecated"] == true {
+		return " @deprecated"
+	}
+
+	return ""
+}
+
+// writeGraphQLDescription writes text as a block string description.
+func writeGraphQLDescription(b *strings.Builder, indent, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(strings.ReplaceAll(text, `"""`, `\"""`), "\n") {
+		fmt.Fprintf(b, "%s\n", strings.TrimRight(indent+line, " "))
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
@@ -0,0 +1,93 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderGraphQL(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	output, err := RenderTemplate(RenderTypeGraphQL, template, "")
+	require.NoError(t, err)
+	content := string(output)
+
+	require.Contains(t, content, `  """
+  Status of the booking.
+  """
+  status: BookingStatus!
+`)
+	require.Contains(t, content, `  colorPreference: String @deprecated
+`)
+	require.Contains(t, content, `  """
+  Part of oneof `+"`travel`"+`: at most one of its fields is set.
+  """
+  lightyears: String
+`)
+	require.Contains(t, content, `  rates: [Int!]
+`)
+
+	// maps are lists of their entries
+	require.Contains(t, content, `  properties: [Vehicle_PropertiesEntry!]
+`)
+	require.Contains(t, content, `type Vehicle_PropertiesEntry {
+  key: String
+  value: String
+}
+`)
+
+	require.Contains(t, content, `"""
+A flag for the status result.
+"""
+enum BookingStatus_StatusCode {
+  """
+  OK result.
+  """
+  OK
+`)
+	require.Contains(t, content, `type EmptyMessage {
+  "The message has no fields."
+  _: Boolean
+}
+`)
+	require.NotContains(t, content, "scalar JSON")
+}
+
+func TestRenderGraphQLWithUndocumentedTypes(t *testing.T) {
+	template := &Template{Files: []*File{{
+		Name: "event.proto",
+		Messages: []*Message{{
+			LongName:    "Event",
+			FullName:    "org.Event",
+			Description: `Quotes like """ are escaped.`,
+			Fields: []*MessageField{
+				{Name: "created_at", FullType: "google.protobuf.Timestamp", Label: "optional"},
+				{Name: "payload", FullType: "bytes", Label: "optional"},
+				{Name: "«redacted»", Redacted: true},
+			},
+		}},
+	}}}
+
+	output, err := RenderTemplate(RenderTypeGraphQL, template, "")
+	require.NoError(t, err)
+	require.Equal(t, `# GraphQL projection of the protocol buffer messages and enums (experimental).
+
+"Any value of a type that isn't documented here, in its proto3 JSON mapping."
+scalar JSON
+
+"""
+Quotes like \""" are escaped.
+"""
+type Event {
+  createdAt: JSON
+  payload: String
+}
+`, string(output))
+}
@@ -0,0 +1,27 @@
+package gendoc
+
+import (
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// isGroupField returns whether pf is a proto2 group, i.e. a field declaring its type as a nested message. Fields of
+// editions files have the group type when they're delimited encoded, which doesn't make them groups.
+func isGroupField(pf *protokit.FieldDescriptor) bool {
+	return pf.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP && pf.GetFile().GetSyntax() != syntaxEditions
+}
+
+// messageGroupField returns the group field declaring pm, or nil when pm isn't declared by a group.
+func messageGroupField(pm *protokit.Descriptor) *protokit.FieldDescriptor {
+	if pm.GetParent() == nil {
+		return nil
+	}
+
+	for _, f := range pm.GetParent().Fields {
+		if isGroupField(f) && f.GetTypeName() == "."+pm.GetFullName() {
+			return f
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newGroupRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"search.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("search.proto"),
+			Package: proto.String("search"),
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("SearchResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name: proto.String("result"), Number: proto.Int32(1),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_GROUP.Enum(),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+						TypeName: proto.String(".search.SearchResponse.Result"),
+					},
+					{
+						Name: proto.String("next"), Number: proto.Int32(4),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						TypeName: proto.String(".search.SearchResponse.Page"),
+					},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Result"),
+						Field: []*descriptorpb.FieldDescriptorProto{{
+							Name: proto.String("url"), Number: proto.Int32(2),
+							Type:  descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+							Label: descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum(),
+						}},
+					},
+					{Name: proto.String("Page")},
+				},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{
+					Path:            []int32{4, 0, 2, 0},
+					Span:            []int32{1, 0, 1},
+					LeadingComments: proto.String(" A search result.\n"),
+				},
+			}},
+		}},
+	}
+}
+
+func TestGroups(t *testing.T) {
+	req := newGroupRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	file := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0]
+
+	response := findMessage("SearchResponse", file)
+	require.True(t, findField("result", response).IsGroup)
+	require.Equal(t, "SearchResponse.Result", findField("result", response).LongType)
+	require.False(t, findField("next", response).IsGroup)
+
+	// the group's message is documented with the comment of the group
+	result := findMessage("SearchResponse.Result", file)
+	require.Equal(t, "result", result.GroupField)
+	require.Equal(t, "A search result.", result.Description)
+	require.Len(t, result.Fields, 1)
+
+	require.Empty(t, findMessage("SearchResponse.Page", file).GroupField)
+}
+
+func TestGroupsOutput(t *testing.T) {
+	resp, err := new(Plugin).Generate(newGroupRequest("markdown,README.md"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "</a> result | [SearchResponse.Result](#search-SearchResponse-Result) | "+
+		"repeated group | A search result. |")
+	require.Contains(t, content, "</a> next | [SearchResponse.Page](#search-SearchResponse-Page) | optional |")
+	require.Contains(t, content, "### SearchResponse.Result\nA search result.")
+}
@@ -0,0 +1,68 @@
+package gendoc
+
+import (
+	"sort"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// parseImportedFiles parses the files imported (directly or not) by the files to generate of req, ordered by name.
+func parseImportedFiles(req *pluginpb.CodeGeneratorRequest) []*protokit.FileDescriptor {
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, f := range req.GetProtoFile() {
+		files[f.GetName()] = f
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range req.GetFileToGenerate() {
+		seen[name] = true
+	}
+
+	imports := make([]string, 0)
+	queue := append([]string(nil), req.GetFileToGenerate()...)
+	for len(queue) > 0 {
+		f := files[queue[0]]
+		queue = queue[1:]
+
+		for _, dep := range f.GetDependency() {
+			if !seen[dep] && files[dep] != nil {
+				seen[dep] = true
+				imports = append(imports, dep)
+				queue = append(queue, dep)
+			}
+		}
+	}
+	if len(imports) == 0 {
+		return nil
+	}
+
+	fds := protokit.ParseCodeGenRequest(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: imports,
+		ProtoFile:      req.GetProtoFile(),
+	})
+	sort.SliceStable(fds, func(i, j int) bool { return fds[i].GetName() < fds[j].GetName() })
+
+	return fds
+}
+
+// markImported flags the files of the template named in imported, and moves them after the other files so that
+// templates can show them in a section of their own.
+func (t *Template) markImported(imported map[string]bool) {
+	if len(imported) == 0 {
+		return
+	}
+
+	for _, f := range t.Files {
+		f.Imported = imported[f.Name]
+	}
+
+	sort.SliceStable(t.Files, func(i, j int) bool { return !t.Files[i].Imported && t.Files[j].Imported })
+}
+
+// ImportedPackages returns the packages of the imported files documented with the include_imports option, in the
+// same form as Packages.
+func (t *Template) ImportedPackages() []*Package {
+	return t.packages(true)
+}
@@ -0,0 +1,98 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// writeTemplate writes a custom template to a temporary file, returning its path.
+func writeTemplate(t *testing.T, content string) string {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-template")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	templateFile := filepath.Join(dir, "custom.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(content), 0644))
+	return templateFile
+}
+
+func TestParseOptionsForIncludeImports(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:include_imports=true")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.True(t, options.IncludeImports)
+
+	req.Parameter = proto.String("html,index.html:include_imports=1")
+	_, err = ParseOptions(req)
+	require.EqualError(t, err, "Invalid include_imports value: 1")
+}
+
+func TestIncludeImports(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{.Name}}{{if .Imported}} (imported){{end}}
+{{end}}{{range .ImportedPackages}}{{.Name}}:{{range .Files}} {{.Name}}{{end}}
+{{end}}`)
+
+	// Booking.proto imports extend.proto, which imports descriptor.proto. Vehicle.proto isn't imported.
+	resp, err := new(Plugin).Generate(newBookingRequest(t, templateFile+",out.txt:include_imports=true"))
+	require.NoError(t, err)
+	require.Equal(t, `Booking.proto
+github.com/pseudomuto/protokit/fixtures/extend.proto (imported)
+google/protobuf/descriptor.proto (imported)
+com.pseudomuto.protokit.v1: github.com/pseudomuto/protokit/fixtures/extend.proto
+google.protobuf: google/protobuf/descriptor.proto
+`, resp.File[0].GetContent())
+
+	parameter := templateFile + ",out.txt:include_imports=true,exclude_patterns=google/.*"
+	resp, err = new(Plugin).Generate(newBookingRequest(t, parameter))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), "descriptor.proto")
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, templateFile+",out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "Booking.proto\n", resp.File[0].GetContent())
+}
+
+func TestRenderImportedTypes(t *testing.T) {
+	expected := map[string][]string{
+		"html": {
+			`<summary aria-expanded="true"><a href="#imported-types">Imported Types</a></summary>`,
+			`<h2 id="imported-types">Imported Types<a class="permalink" href="#imported-types" ` +
+				`aria-label="Link to this section">¶</a></h2>`,
+			`<h3 id="google.protobuf.FileDescriptorSet" data-copy="google.protobuf.FileDescriptorSet">` +
+				`FileDescriptorSet<a class="permalink" href="#google.protobuf.FileDescriptorSet"`,
+		},
+		"markdown": {
+			"- [Imported Types](#imported-types)\n- [google/protobuf/descriptor.proto](#google_protobuf_descriptor-proto)",
+			"## Imported Types\n\n\n<a name=\"google_protobuf_descriptor-proto\"></a>",
+		},
+		"docbook": {
+			"<section xml:id=\"imported-types\">\n  <title>Imported Types</title>\n" +
+				"  <section xml:id=\"google_protobuf_descriptor-proto\">" +
+				"<?dbhtml filename=\"google_protobuf_descriptor-proto.html\"?>\n" +
+				"    <title>google/protobuf/descriptor.proto</title>",
+		},
+	}
+
+	for format, snippets := range expected {
+		parameter := format + ",docs:include_imports=true,exclude_patterns=.*extend.proto"
+		resp, err := new(Plugin).Generate(newBookingRequest(t, parameter))
+		require.NoError(t, err)
+
+		content := resp.File[0].GetContent()
+		for _, snippet := range snippets {
+			require.Contains(t, content, snippet, format)
+		}
+
+		// the imported files come after the documented ones
+		require.Less(t, strings.Index(content, "Booking.proto"), strings.Index(content, "Imported Types"), format)
+	}
+}
@@ -0,0 +1,85 @@
+package gendoc
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// IndexEntry is an entry of the alphabetical index of messages, fields, enums, enum values, services and methods
+// (see the index option). Templates link to an entry with `{{.Page}}#{{.Anchor}}`.
+type IndexEntry struct {
+	// The short name of the entity.
+	Name string `json:"name"`
+	// One of message, field, enum, enum value, service or method.
+	Kind string `json:"kind"`
+	// The fully qualified name of the entity. Fields, enum values and methods are qualified by their parent.
+	FullName string `json:"fullName"`
+	// The file declaring the entity.
+	File string `json:"file"`
+	// The anchor of the section documenting the entity. Fields, enum values and methods link to their parent's section.
+	Anchor string `json:"anchor"`
+	// The path of the page documenting the entity, relative to the page the index is shown on. Empty when the entity
+	// is documented on the same page.
+	Page string `json:"page"`
+}
+
+// index returns the index entries of the template's files, linking to page. Entries are sorted by name.
+func (t *Template) index(page string) []*IndexEntry {
+	entries := make([]*IndexEntry, 0)
+
+	for _, f := range t.Files {
+		walkEntities(f, func(kind, name, fullName, description string) {
+			anchor := fullName
+			switch kind {
+			case "field", "enum value", "method":
+				anchor = strings.TrimSuffix(fullName, "."+name)
+			}
+
+			entries = append(entries, &IndexEntry{
+				Name:     name,
+				Kind:     kind,
+				FullName: fullName,
+				File:     f.Name,
+				Anchor:   anchor,
+				Page:     page,
+			})
+		})
+	}
+
+	sortIndex(entries)
+	return entries
+}
+
+// sortIndex sorts entries by name (ignoring case), and then by full name.
+func sortIndex(entries []*IndexEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := strings.ToLower(entries[i].Name), strings.ToLower(entries[j].Name)
+		if a != b {
+			return a < b
+		}
+
+		return entries[i].FullName < entries[j].FullName
+	})
+}
+
+// hasIndex returns whether an index should be rendered. Only the formats meant for reading (including custom
+// templates, whose type is always html) include one.
+func hasIndex(pluginOptions *PluginOptions) bool {
+	if !pluginOptions.Index {
+		return false
+	}
+
+	switch pluginOptions.Type {
+	case RenderTypeDocBook, RenderTypeHTML, RenderTypeMarkdown, RenderTypeGFM:
+		return true
+	}
+
+	return false
+}
+
+// indexPageName returns the name of the separate index page written in source_relative mode, e.g. glossary.html when
+// the output file is index.html.
+func indexPageName(outputFile string) string {
+	return "glossary" + path.Ext(outputFile)
+}
@@ -0,0 +1,88 @@
+package gendoc_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestIndexInSingleFileOutput(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String("html,index.html:index=true")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	html := resp.File[0].GetContent()
+	require.Contains(t, html, `<li><a href="#index">Index</a></li>`)
+	require.Contains(t, html,
+		`<h2 id="index">Index<a class="permalink" href="#index" aria-label="Link to this section">¶</a></h2>`)
+	require.Contains(t, html, `<td><a href="#com.book.Book">Book</a></td>
+                <td>message</td>
+                <td data-copy="com.book.Book">com.book.Book</td>`)
+	require.Contains(t, html, `<td><a href="#com.example.BookingService">BookVehicle</a></td>
+                <td>method</td>
+                <td data-copy="com.example.BookingService.BookVehicle">com.example.BookingService.BookVehicle</td>`)
+
+	// entries are sorted by name, ignoring case
+	index := html[strings.Index(html, `<h2 id="index">`):]
+	require.Less(t, strings.Index(index, `>author</a>`), strings.Index(index, `>Book</a>`))
+	require.Less(t, strings.Index(index, `>Book</a>`), strings.Index(index, `>books</a>`))
+}
+
+func TestIndexInMarkdownOutput(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "nested/Book.proto")
+	req.Parameter = proto.String("markdown,docs.md:index=true,locale=de")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	markdown := resp.File[0].GetContent()
+	require.Contains(t, markdown, "- [Index](#index)\n")
+	require.Contains(t, markdown, "| Name | Art | Vollständiger Name |\n")
+	require.Contains(t, markdown, "| [isbn](#com-book-Book) | Feld | com.book.Book.isbn |\n")
+}
+
+func TestIndexPageForSourceRelative(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String("html,index.html,source_relative:index=true")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 3)
+	require.Equal(t, "index.html", resp.File[0].GetName())
+	require.Equal(t, "nested/index.html", resp.File[1].GetName())
+	require.Equal(t, "glossary.html", resp.File[2].GetName())
+
+	require.NotContains(t, resp.File[0].GetContent(), `<h2 id="index">`)
+
+	glossary := resp.File[2].GetContent()
+	require.Contains(t, glossary, `<td><a href="nested/index.html#com.book.Book">Book</a></td>`)
+	require.Contains(t, glossary, `<td><a href="index.html#com.example.BookingStatus">BookingStatus</a></td>`)
+}
+
+func TestIndexIsIgnoredForDataFormats(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String("json,docs.json,source_relative:index=true")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 2)
+}
@@ -0,0 +1,31 @@
+package gendoc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseLabelOption parses a value of the label option, LABEL=TEXT, where LABEL is required, optional or repeated.
+func parseLabelOption(value string) (string, string, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("Invalid label value: %v", value)
+	}
+
+	switch parts[0] {
+	case "required", "optional", "repeated":
+		return parts[0], parts[1], nil
+	}
+
+	return "", "", fmt.Errorf("Invalid label value: %v", value)
+}
+
+// FormatLabel returns the text shown for a field label (as found in MessageField.Label): its replacement in labels
+// (see the label option) when there's one, and the label itself otherwise.
+func FormatLabel(label string, labels map[string]string) string {
+	if text, ok := labels[label]; ok {
+		return text
+	}
+
+	return label
+}
@@ -0,0 +1,52 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestFormatLabel(t *testing.T) {
+	labels := map[string]string{"repeated": "array", "optional": ""}
+
+	require.Equal(t, "array", FormatLabel("repeated", labels))
+	require.Equal(t, "", FormatLabel("optional", labels))
+	require.Equal(t, "required", FormatLabel("required", labels))
+	require.Equal(t, "", FormatLabel("", labels))
+	require.Equal(t, "repeated", FormatLabel("repeated", nil))
+}
+
+func TestParseOptionsForLabels(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:label=repeated=array,label=optional=,label=required=obligatoire")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"repeated": "array", "optional": "", "required": "obligatoire"}, options.Labels)
+
+	for _, value := range []string{"repeated", "singular=one", "=array"} {
+		req.Parameter = proto.String("html,index.html:label=" + value)
+		_, err = ParseOptions(req)
+		require.EqualError(t, err, "Invalid label value: "+value)
+	}
+}
+
+func TestRenderLabels(t *testing.T) {
+	expected := map[string]string{
+		"html": "<td>status_code</td>\n                    <td><a href=\"#com.example.BookingStatus.StatusCode\">" +
+			"BookingStatus.StatusCode</a></td>\n                    <td>may be set</td>",
+		"markdown": "</a> status_code | [BookingStatus.StatusCode](#com-example-BookingStatus-StatusCode) | " +
+			"may be set |",
+		"docbook": "<entry>may be set</entry>",
+	}
+
+	for format, snippet := range expected {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, format+",docs:label=optional=may be set"))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), snippet, format)
+		require.NotContains(t, resp.File[0].GetContent(), ">optional<", format)
+	}
+}
@@ -0,0 +1,253 @@
+package gendoc
+
+import (
+	"fmt"
+	"html"
+	html_template "html/template"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+)
+
+// isMarkdown returns whether rt renders Markdown.
+func isMarkdown(rt RenderType) bool {
+	return rt == RenderTypeMarkdown || rt == RenderTypeGFM || rt == RenderTypeWiki
+}
+
+// hasAnchoredSections returns whether the sections of the output are named with AnchorFilter: the Markdown formats and
+// the built-in HTML layouts other than the default one do, while the default layout and custom templates are expected
+// to use full names as ids.
+func hasAnchoredSections(pluginOptions *PluginOptions) bool {
+	if isMarkdown(pluginOptions.Type) {
+		return true
+	}
+
+	return pluginOptions.Type == RenderTypeHTML && pluginOptions.TemplateFile == "" &&
+		pluginOptions.HTMLTemplate != "" && pluginOptions.HTMLTemplate != HTMLTemplateDefault
+}
+
+// typePages returns the output file (relative to the output root, with forward slashes) each type of fdsGroup is
+// documented in, keyed by full name, for the links between the output files of the groups.
+func typePages(fdsGroup map[string][]*protokit.FileDescriptor, pluginOptions *PluginOptions) map[string]string {
+	pages := make(map[string]string)
+	for group, fds := range fdsGroup {
+		page := filepath.ToSlash(outputName(pluginOptions, group))
+		for fullName := range NewTemplate(fds, pluginOptions).documentedTypes() {
+			pages[fullName] = page
+		}
+	}
+
+	return pages
+}
+
+// stringMapKey returns a string describing m (e.g. the pages of typePages), for the cache keys of the outputs depending
+// on it.
+func stringMapKey(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key + "=" + m[key] + "\n")
+	}
+
+	return b.String()
+}
+
+// relativePath returns the path of the output file page (relative to the output root) as seen from the output file
+// being rendered. Both use forward slashes.
+func (t *Template) relativePath(page string) string {
+	return relativeAssetURL(filepath.FromSlash(path.Dir(t.Page)), page)
+}
+
+// fragment returns the fragment of the URL of the section (or table row) documenting the entity fullName: its full
+// name, or its anchor (see AnchorFilter) for the outputs naming sections that way.
+func (t *Template) fragment(fullName string) string {
+	if t.anchored {
+		return AnchorFilter(fullName)
+	}
+
+	return fullName
+}
+
+// typeURL returns the URL of the section documenting the type fullName, relative to the output file being rendered:
+// just the fragment for the types documented in it (types) and for scalar value types, and "" for the types that
+// aren't documented in any output file.
+func (t *Template) typeURL(types map[string]bool, fullName string) string {
+	fullName = strings.TrimPrefix(fullName, ".")
+	fragment := t.fragment(fullName)
+
+	if types[fullName] {
+		return "#" + fragment
+	}
+
+	if page, ok := t.Pages[fullName]; ok {
+		return t.relativePath(page) + "#" + fragment
+	}
+
+	for _, s := range t.Scalars {
+		if s.ProtoType == fullName {
+			return "#" + fragment
+		}
+	}
+
+	return ""
+}
+
+// typeLink returns a link to the type fullName, shown as text: an HTML link, or a Markdown link for Markdown output.
+// Types that aren't documented in any output file are shown as the text. The text is HTML escaped either way.
+func (t *Template) typeLink(types map[string]bool, fullName, text string) html_template.HTML {
+	url, text := t.typeURL(types, fullName), html.EscapeString(text)
+	switch {
+	case url == "":
+		return html_template.HTML(text)
+	case t.markdown:
+		return html_template.HTML(fmt.Sprintf("[%s](%s)", text, url))
+	}
+
+	return html_template.HTML(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), text))
+}
+
+// commentRefPattern matches the references to other entities in comments: names in square brackets or backticks, e.g.
+// [Booking], [com.example.Booking] or `Booking.vehicle_id`. A leading dot makes a name fully qualified.
+var commentRefPattern = regexp.MustCompile("\\[(\\.?[A-Za-z_][\\w.]*)\\]|`(\\.?[A-Za-z_][\\w.]*)`")
+
+// commentTargets maps the names comments can refer to entities by to the full names of the entities. A name is the
+// full name of an entity or any dot-separated suffix of it, where fields, enum values and methods have to be qualified
+// with (at least) the name of their parent, e.g. `Booking.vehicle_id`. Names shared by several entities map to "".
+type commentTargets struct {
+	names map[string]string
+	// The full names of the entities, mapped to whether they aren't types but documented by table rows of their
+	// parent's section.
+	members map[string]bool
+}
+
+// commentTargets returns the entities comments can refer to: the types documented in any output file, and the fields,
+// enum values and methods documented in the output file being rendered (types), when the output has anchors for them.
+func (t *Template) commentTargets(types map[string]bool) *commentTargets {
+	targets := &commentTargets{names: make(map[string]string), members: make(map[string]bool)}
+	add := func(fullName string, minParts int) {
+		if _, ok := targets.members[fullName]; !ok {
+			targets.members[fullName] = minParts > 1
+		}
+
+		parts := strings.Split(fullName, ".")
+		for i := len(parts) - minParts; i >= 0; i-- {
+			name := strings.Join(parts[i:], ".")
+			if other, ok := targets.names[name]; ok && other != fullName {
+				targets.names[name] = ""
+			} else if !ok {
+				targets.names[name] = fullName
+			}
+		}
+	}
+
+	for fullName := range types {
+		add(fullName, 1)
+	}
+	for fullName := range t.Pages {
+		add(fullName, 1)
+	}
+
+	// only the default HTML layout, custom templates and the Markdown formats have anchors for table rows
+	if t.anchored && !t.markdown {
+		return targets
+	}
+
+	for _, f := range t.Files {
+		for _, m := range f.AllMessages() {
+			for _, field := range m.Fields {
+				if !field.Redacted {
+					add(m.FullName+"."+field.Name, 2)
+				}
+			}
+		}
+		for _, e := range f.AllEnums() {
+			for _, v := range e.Values {
+				if !v.Redacted {
+					add(e.FullName+"."+v.Name, 2)
+				}
+			}
+		}
+		for _, s := range f.Services {
+			for _, m := range s.Methods {
+				add(s.FullName+"."+m.Name, 2)
+			}
+		}
+	}
+
+	return targets
+}
+
+// url returns the URL of the section or table row documenting the entity name refers to, or "" when it doesn't refer
+// to exactly one entity.
+func (c *commentTargets) url(t *Template, types map[string]bool, name string) string {
+	fullName := c.names[name]
+	if strings.HasPrefix(name, ".") {
+		if _, ok := c.members[name[1:]]; ok {
+			fullName = name[1:]
+		}
+	}
+
+	member, ok := c.members[fullName]
+	switch {
+	case !ok:
+		return ""
+	case member:
+		return "#" + t.fragment(fullName)
+	}
+
+	return t.typeURL(types, fullName)
+}
+
+// commentLinks turns the references to other entities in content (see commentRefPattern) into links, which are HTML
+// or Markdown links depending on the output. content must be escaped or sanitized already. References in brackets that
+// are followed by a link target (as in the Markdown link `[Booking](booking.md)`), and names that don't refer to
+// exactly one entity, are left as they are.
+func (t *Template) commentLinks(targets *commentTargets, types map[string]bool, content string) string {
+	var b strings.Builder
+	last := 0
+
+	for _, loc := range commentRefPattern.FindAllStringSubmatchIndex(content, -1) {
+		code := loc[2] < 0
+		name := ""
+		if code {
+			name = content[loc[4]:loc[5]]
+		} else {
+			name = content[loc[2]:loc[3]]
+			if loc[1] < len(content) && (content[loc[1]] == '(' || content[loc[1]] == '[') {
+				continue
+			}
+		}
+
+		url := targets.url(t, types, name)
+		if url == "" {
+			continue
+		}
+
+		text := strings.TrimPrefix(name, ".")
+		b.WriteString(content[last:loc[0]])
+		last = loc[1]
+
+		switch {
+		case t.markdown && code:
+			fmt.Fprintf(&b, "[`%s`](%s)", text, url)
+		case t.markdown:
+			fmt.Fprintf(&b, "[%s](%s)", text, url)
+		case code:
+			fmt.Fprintf(&b, `<a href="%s"><code>%s</code></a>`, html.EscapeString(url), text)
+		default:
+			fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(url), text)
+		}
+	}
+
+	b.WriteString(content[last:])
+	return b.String()
+}
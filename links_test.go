@@ -0,0 +1,132 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestLinkFuncsForSourceRelative(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	templateFile := writeTemplate(t, `{{relpath "index.txt"}} {{typeurl "com.book.Book"}} `+
+		`{{typeurl "com.example.Booking"}} {{typeurl ".int32"}} [{{typeurl "com.example.Unknown"}}] `+
+		`{{link "com.example.Booking" "<Booking>"}} {{link "com.example.Unknown" "Unknown"}}`)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String(templateFile + ",index.txt,source_relative")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 2)
+
+	require.Equal(t, "index.txt", resp.File[0].GetName())
+	require.Equal(t, `index.txt nested/index.txt#com.book.Book #com.example.Booking #int32 [] `+
+		`<a href="#com.example.Booking">&lt;Booking&gt;</a> Unknown`, resp.File[0].GetContent())
+
+	require.Equal(t, "nested/index.txt", resp.File[1].GetName())
+	require.Equal(t, `../index.txt #com.book.Book ../index.txt#com.example.Booking #int32 [] `+
+		`<a href="../index.txt#com.example.Booking">&lt;Booking&gt;</a> Unknown`, resp.File[1].GetContent())
+}
+
+func TestLinkFuncsForMarkdown(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("markdown,docs.md")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), options)
+	template.Pages = map[string]string{"com.book.Book": "nested/docs.md"}
+
+	output, err := RenderTemplate(RenderTypeMarkdown, template,
+		`{{link "com.example.Booking" "Booking"}} {{link "com.book.Book" "Book"}} {{typeurl "com.book.Book"}}`)
+	require.NoError(t, err)
+	require.Equal(t, "[Booking](#com-example-Booking) [Book](nested/docs.md#com-book-Book) "+
+		"nested/docs.md#com-book-Book", string(output))
+}
+
+func newCommentLinksRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"library.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("library.proto"),
+			Package: proto.String("library"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Book"),
+					Field: []*descriptorpb.FieldDescriptorProto{{
+						Name:   proto.String("name"),
+						Number: proto.Int32(1),
+						Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					}},
+				},
+				{
+					Name:       proto.String("Shelf"),
+					NestedType: []*descriptorpb.DescriptorProto{{Name: proto.String("Book")}},
+				},
+			},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name:  proto.String("BookView"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{{Name: proto.String("BASIC"), Number: proto.Int32(0)}},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, Span: []int32{1, 0, 1}, LeadingComments: proto.String(
+					" See [Shelf], [Shelf.Book], [.library.Book], [Book], `BookView.BASIC`, [Book.name](http://x) and" +
+						" [Unknown].\n")},
+				{Path: []int32{4, 0, 2, 0}, Span: []int32{2, 0, 1}, LeadingComments: proto.String(" On a [Shelf].\n")},
+			}},
+		}},
+	}
+}
+
+func TestCommentLinks(t *testing.T) {
+	resp, err := new(Plugin).Generate(newCommentLinksRequest("html,index.html"))
+	require.NoError(t, err)
+
+	// names shared by several types (Book) aren't linked, and neither are Markdown links
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<p>See <a href="#library.Shelf">Shelf</a>, `+
+		`<a href="#library.Shelf.Book">Shelf.Book</a>, <a href="#library.Book">library.Book</a>, [Book], `+
+		`<a href="#library.BookView.BASIC"><code>BookView.BASIC</code></a>, [Book.name](http://x) and [Unknown].</p>`)
+	require.Contains(t, content, `<td><p>On a <a href="#library.Shelf">Shelf</a>. </p></td>`)
+
+	resp, err = new(Plugin).Generate(newCommentLinksRequest("markdown,README.md"))
+	require.NoError(t, err)
+
+	content = resp.File[0].GetContent()
+	require.Contains(t, content, "See [Shelf](#library-Shelf), [Shelf.Book](#library-Shelf-Book), "+
+		"[library.Book](#library-Book), [Book], [`BookView.BASIC`](#library-BookView-BASIC), "+
+		"[Book.name](http://x) and [Unknown].\n")
+	require.Contains(t, content, "| On a [Shelf](#library-Shelf). |")
+}
+
+func TestCommentLinksInAnchoredLayouts(t *testing.T) {
+	resp, err := new(Plugin).Generate(newCommentLinksRequest("html,index.html:template=slate"))
+	require.NoError(t, err)
+
+	// the layout has no anchors for enum values
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<p>See <a href="#library-Shelf">Shelf</a>, `)
+	require.Contains(t, content, "[Book], `BookView.BASIC`, [Book.name](http://x)")
+}
+
+func TestCommentLinksOption(t *testing.T) {
+	resp, err := new(Plugin).Generate(newCommentLinksRequest("html,index.html:comment_links=false"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "<p>See [Shelf], [Shelf.Book], [.library.Book], [Book]")
+
+	_, err = new(Plugin).Generate(newCommentLinksRequest("html,index.html:comment_links=no"))
+	require.EqualError(t, err, "Invalid comment_links value: no")
+}
@@ -0,0 +1,202 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Available lint rules.
+const (
+	// LintRuleStartsWithName requires comments to start with the name of the entity they document.
+	LintRuleStartsWithName = "starts_with_name"
+	// LintRuleNoTodo disallows TODO and FIXME notes in comments.
+	LintRuleNoTodo = "no_todo"
+	// LintRuleMaxLineLength limits the length of each comment line (see LintConfig.MaxLineLength).
+	LintRuleMaxLineLength = "max_line_length"
+	// LintRuleRequireSince requires comments to contain an @since tag.
+	LintRuleRequireSince = "require_since"
+)
+
+// DefaultLintMaxLineLength is the default maximum length of a comment line.
+const DefaultLintMaxLineLength = 120
+
+// DefaultLintRules are the rules checked when none are configured. LintRuleRequireSince is opt-in.
+var DefaultLintRules = []string{LintRuleStartsWithName, LintRuleNoTodo, LintRuleMaxLineLength}
+
+var lintRules = map[string]func(config *LintConfig, name, description string) string{
+	LintRuleStartsWithName: lintStartsWithName,
+	LintRuleNoTodo:         lintNoTodo,
+	LintRuleMaxLineLength:  lintMaxLineLength,
+	LintRuleRequireSince:   lintRequireSince,
+}
+
+// LintConfig selects the rules comments are checked against.
+type LintConfig struct {
+	// The rules to check. DefaultLintRules are used when empty.
+	Rules []string
+	// The maximum length (in characters) of a comment line. DefaultLintMaxLineLength is used when not positive.
+	MaxLineLength int
+}
+
+// LintFinding is a violation of a lint rule.
+type LintFinding struct {
+	// The file declaring the entity.
+	File string `json:"file"`
+	// One of message, field, enum, enum value, service or method.
+	Kind string `json:"kind"`
+	// The fully qualified name of the entity. Fields, enum values and methods are qualified by their parent.
+	Name string `json:"name"`
+	// The violated rule.
+	Rule string `json:"rule"`
+	// A description of the violation.
+	Message string `json:"message"`
+}
+
+func newLintConfig(pluginOptions *PluginOptions) *LintConfig {
+	return &LintConfig{Rules: pluginOptions.LintRules, MaxLineLength: pluginOptions.LintMaxLineLength}
+}
+
+// isLintRule returns whether rule is the name of a known lint rule.
+func isLintRule(rule string) bool {
+	_, ok := lintRules[rule]
+	return ok
+}
+
+// LintFindings checks the comments of the template's messages, fields, enums, enum values, services and methods against
+// the rules in Lint. Entities without comments are not checked (see Coverage for those). Findings are ordered by file,
+// entity and then rule.
+func (t *Template) LintFindings() []*LintFinding {
+	config := t.Lint
+	if config == nil {
+		config = new(LintConfig)
+	}
+
+	rules := config.Rules
+	if len(rules) == 0 {
+		rules = DefaultLintRules
+	}
+
+	findings := make([]*LintFinding, 0)
+	for _, f := range t.Files {
+		walkEntities(f, func(kind, name, fullName, description string) {
+			if description == "" {
+				return
+			}
+
+			for _, rule := range rules {
+				check, ok := lintRules[rule]
+				if !ok {
+					continue
+				}
+
+				if message := check(config, name, description); message != "" {
+					findings = append(findings, &LintFinding{
+						File:    f.Name,
+						Kind:    kind,
+						Name:    fullName,
+						Rule:    rule,
+						Message: message,
+					})
+				}
+			}
+		})
+	}
+
+	return findings
+}
+
+func lintStartsWithName(_ *LintConfig, name, description string) string {
+	if strings.HasPrefix(description, name) {
+		rest := strings.TrimPrefix(description, name)
+		if r, _ := utf8.DecodeRuneInString(rest); rest == "" || !isWordRune(r) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("Comment should start with %q", name)
+}
+
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME)\b`)
+
+func lintNoTodo(_ *LintConfig, _, description string) string {
+	if match := todoPattern.FindString(description); match != "" {
+		return fmt.Sprintf("Comment contains %s", match)
+	}
+
+	return ""
+}
+
+func lintMaxLineLength(config *LintConfig, _, description string) string {
+	max := config.MaxLineLength
+	if max <= 0 {
+		max = DefaultLintMaxLineLength
+	}
+
+	for i, line := range strings.Split(description, "\n") {
+		if length := utf8.RuneCountInString(line); length > max {
+			return fmt.Sprintf("Line %d is %d characters long (maximum is %d)", i+1, length, max)
+		}
+	}
+
+	return ""
+}
+
+func lintRequireSince(_ *LintConfig, _, description string) string {
+	if strings.Contains(description, "@since") {
+		return ""
+	}
+
+	return "Comment is missing an @since tag"
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
+
+// lintRenderer renders lint findings, either as plain text or as JSON.
+type lintRenderer struct {
+	json bool
+}
+
+func (r *lintRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *lintRenderer) ApplyTo(w io.Writer, template *Template) error {
+	findings := template.LintFindings()
+
+	if r.json {
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(data)
+		return err
+	}
+
+	for _, finding := range findings {
+		_, err := fmt.Fprintf(w, "%s: %s %s: %s (%s)\n", finding.File, finding.Kind, finding.Name, finding.Message, finding.Rule)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkLint fails when any of the template's comments violate a lint rule.
+func checkLint(template *Template) error {
+	findings := template.LintFindings()
+	if len(findings) == 0 {
+		return nil
+	}
+
+	first := findings[0]
+	return fmt.Errorf("Found %d documentation lint issue(s), the first being %s: %s %s: %s (%s)",
+		len(findings), first.File, first.Kind, first.Name, first.Message, first.Rule)
+}
@@ -0,0 +1,110 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func lintTemplate(config *LintConfig) *Template {
+	return &Template{
+		Files: []*File{{
+			Name: "Booking.proto",
+			Messages: []*Message{{
+				Name:        "Booking",
+				FullName:    "com.example.Booking",
+				Description: "Booking represents a booking.\n@since 1.2",
+				Fields: []*MessageField{
+					{Name: "id", Description: "id of the booking. TODO: use a UUID"},
+					{Name: "status", Description: "statuses are ignored"},
+					{Name: "notes"},
+				},
+			}},
+			Services: []*Service{{
+				Name:        "BookingService",
+				FullName:    "com.example.BookingService",
+				Description: "BookingService books vehicles.\nThis second line is rather long.",
+			}},
+		}},
+		Lint: config,
+	}
+}
+
+func TestLintFindings(t *testing.T) {
+	findings := lintTemplate(nil).LintFindings()
+	require.Equal(t, []*LintFinding{
+		{
+			File:    "Booking.proto",
+			Kind:    "field",
+			Name:    "com.example.Booking.id",
+			Rule:    LintRuleNoTodo,
+			Message: "Comment contains TODO",
+		},
+		{
+			File:    "Booking.proto",
+			Kind:    "field",
+			Name:    "com.example.Booking.status",
+			Rule:    LintRuleStartsWithName,
+			Message: `Comment should start with "status"`,
+		},
+	}, findings)
+}
+
+func TestLintFindingsWithRules(t *testing.T) {
+	findings := lintTemplate(&LintConfig{
+		Rules:         []string{LintRuleMaxLineLength, LintRuleRequireSince},
+		MaxLineLength: 30,
+	}).LintFindings()
+
+	require.Len(t, findings, 5)
+	require.Equal(t, &LintFinding{
+		File:    "Booking.proto",
+		Kind:    "field",
+		Name:    "com.example.Booking.id",
+		Rule:    LintRuleMaxLineLength,
+		Message: "Line 1 is 35 characters long (maximum is 30)",
+	}, findings[0])
+	require.Equal(t, LintRuleRequireSince, findings[1].Rule)
+	require.Equal(t, "com.example.Booking.id", findings[1].Name)
+	require.Equal(t, "com.example.Booking.status", findings[2].Name)
+	require.Equal(t, "com.example.BookingService", findings[3].Name)
+	require.Equal(t, "Line 2 is 32 characters long (maximum is 30)", findings[3].Message)
+	require.Equal(t, LintRuleRequireSince, findings[4].Rule)
+}
+
+func TestLintRenderers(t *testing.T) {
+	template := lintTemplate(nil)
+
+	output, err := RenderTemplate(RenderTypeLint, template, "")
+	require.NoError(t, err)
+	require.Equal(t,
+		"Booking.proto: field com.example.Booking.id: Comment contains TODO (no_todo)\n"+
+			`Booking.proto: field com.example.Booking.status: Comment should start with "status" (starts_with_name)`+"\n",
+		string(output))
+
+	output, err = RenderTemplate(RenderTypeLintJSON, template, "")
+	require.NoError(t, err)
+
+	var findings []*LintFinding
+	require.NoError(t, json.Unmarshal(output, &findings))
+	require.Equal(t, template.LintFindings(), findings)
+}
+
+func TestLintFindingsUseParsedComments(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{
+		LintRules:             []string{LintRuleNoTodo},
+		ExcludeDirectives:     []string{"@exclude"},
+		ExcludeLineDirectives: []string{"@exclude-line"},
+	})
+
+	require.Equal(t, &LintConfig{Rules: []string{LintRuleNoTodo}}, template.Lint)
+	require.Empty(t, template.LintFindings())
+}
@@ -0,0 +1,548 @@
+package gendoc
+
+import (
+	"sort"
+)
+
+// DefaultLocale is the locale the built-in templates are written in.
+const DefaultLocale = "en"
+
+// Catalog maps the (English) strings used by the built-in templates to their translations. Strings that are missing
+// from a catalog are rendered untranslated.
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	DefaultLocale: {},
+	"de": {
+		"(default package)":          "(Standardpaket)",
+		".proto Type":                ".proto-Typ",
+		"Allowed types:":             "Erlaubte Typen:",
+		"Back to the home page":      "Zurück zur Startseite",
+		"Base":                       "Basis",
+		"Body":                       "Body",
+		"Breadcrumb":                 "Pfadnavigation",
+		"Code":                       "Code",
+		"Constraints":                "Einschränkungen",
+		"Copied":                     "Kopiert",
+		"Copy":                       "Kopieren",
+		"Copy code":                  "Code kopieren",
+		"Copy link":                  "Link kopieren",
+		"Dark":                       "Dunkel",
+		"Default:":                   "Standard:",
+		"Deprecated.":                "Veraltet.",
+		"Description":                "Beschreibung",
+		"Edit this comment":          "Diesen Kommentar bearbeiten",
+		"Example":                    "Beispiel",
+		"Example:":                   "Beispiel:",
+		"Extension":                  "Erweiterung",
+		"Features:":                  "Features:",
+		"Field":                      "Feld",
+		"Fields":                     "Felder",
+		"Fields with %s option":      "Felder mit Option %s",
+		"File-level Extensions":      "Erweiterungen auf Dateiebene",
+		"Full Name":                  "Vollständiger Name",
+		"Generated by":               "Erzeugt von",
+		"Generated for release":      "Erstellt für Release",
+		"High contrast":              "Hoher Kontrast",
+		"Home":                       "Startseite",
+		"Imported Types":             "Importierte Typen",
+		"Index":                      "Index",
+		"JSON:":                      "JSON:",
+		"Kind":                       "Art",
+		"Label":                      "Label",
+		"Light":                      "Hell",
+		"Link to this section":       "Link zu diesem Abschnitt",
+		"Metadata:":                  "Metadaten:",
+		"Method":                     "Methode",
+		"Method Errors":              "Methodenfehler",
+		"Method Name":                "Methodenname",
+		"Methods":                    "Methoden",
+		"Methods with %s option":     "Methoden mit Option %s",
+		"Methods with HTTP bindings": "Methoden mit HTTP-Bindungen",
+		"Name":                       "Name",
+		"Nested Extensions":          "Verschachtelte Erweiterungen",
+		"Notes":                      "Hinweise",
+		"Number":                     "Nummer",
+		"Option":                     "Option",
+		"Owner:":                     "Verantwortlich:",
+		"Package Overview":           "Paketübersicht",
+		"Packages":                   "Pakete",
+		"Page not found":             "Seite nicht gefunden",
+		"Parameters:":                "Parameter:",
+		"Pattern":                    "Muster",
+		"Patterns:":                  "Muster:",
+		"Protocol Documentation":     "Protokolldokumentation",
+		"Request":                    "Anfrage",
+		"Request Fields":             "Anfragefelder",
+		"Request Type":               "Anfragetyp",
+		"Resource reference:":        "Ressourcenreferenz:",
+		"Resource:":                  "Ressource:",
+		"Response":                   "Antwort",
+		"Response Fields":            "Antwortfelder",
+		"Response Type":              "Antworttyp",
+		"Response:":                  "Antwort:",
+		"Returns:":                   "Rückgabe:",
+		"Scalar Value Types":         "Skalare Werttypen",
+		"See also:":                  "Siehe auch:",
+		"Since":                      "Seit",
+		"Skip to content":            "Zum Inhalt springen",
+		"Source":                     "Quelltext",
+		"System":                     "System",
+		"Table of Contents":          "Inhaltsverzeichnis",
+		"Theme":                      "Farbschema",
+		"This page doesn't exist.":   "Diese Seite existiert nicht.",
+		"Top":                        "Nach oben",
+		"Type":                       "Typ",
+		"Used by:":                   "Verwendet von:",
+		"Validated Fields":           "Validierte Felder",
+		"Validations":                "Validierungen",
+		"Values":                     "Werte",
+		"Version":                    "Version",
+		"View source":                "Quelltext anzeigen",
+		"alpha":                      "Alpha",
+		"beta":                       "Beta",
+		"enum":                       "Aufzählung",
+		"enum value":                 "Aufzählungswert",
+		"explicit presence":          "explizite Präsenz",
+		"field":                      "Feld",
+		"message":                    "Nachricht",
+		"method":                     "Methode",
+		"parent of":                  "übergeordnet zu",
+		"request":                    "Anfrage",
+		"response":                   "Antwort",
+		"service":                    "Dienst",
+		"stable":                     "stabil",
+	},
+	"es": {
+		"(default package)":          "(paquete predeterminado)",
+		".proto Type":                "Tipo .proto",
+		"Allowed types:":             "Tipos permitidos:",
+		"Back to the home page":      "Volver a la página de inicio",
+		"Base":                       "Base",
+		"Body":                       "Cuerpo",
+		"Breadcrumb":                 "Ruta de navegación",
+		"Code":                       "Código",
+		"Constraints":                "Restricciones",
+		"Copied":                     "Copiado",
+		"Copy":                       "Copiar",
+		"Copy code":                  "Copiar código",
+		"Copy link":                  "Copiar enlace",
+		"Dark":                       "Oscuro",
+		"Default:":                   "Predeterminado:",
+		"Deprecated.":                "Obsoleto.",
+		"Description":                "Descripción",
+		"Edit this comment":          "Editar este comentario",
+		"Example":                    "Ejemplo",
+		"Example:":                   "Ejemplo:",
+		"Extension":                  "Extensión",
+		"Features:":                  "Características:",
+		"Field":                      "Campo",
+		"Fields":                     "Campos",
+		"Fields with %s option":      "Campos con la opción %s",
+		"File-level Extensions":      "Extensiones a nivel de archivo",
+		"Full Name":                  "Nombre completo",
+		"Generated by":               "Generado por",
+		"Generated for release":      "Generado para la versión",
+		"High contrast":              "Alto contraste",
+		"Home":                       "Inicio",
+		"Imported Types":             "Tipos importados",
+		"Index":                      "Índice",
+		"JSON:":                      "JSON:",
+		"Kind":                       "Clase",
+		"Label":                      "Etiqueta",
+		"Light":                      "Claro",
+		"Link to this section":       "Enlace a esta sección",
+		"Metadata:":                  "Metadatos:",
+		"Method":                     "Método",
+		"Method Errors":              "Errores de los métodos",
+		"Method Name":                "Nombre del método",
+		"Methods":                    "Métodos",
+		"Methods with %s option":     "Métodos con la opción %s",
+		"Methods with HTTP bindings": "Métodos con enlaces HTTP",
+		"Name":                       "Nombre",
+		"Nested Extensions":          "Extensiones anidadas",
+		"Notes":                      "Notas",
+		"Number":                     "Número",
+		"Option":                     "Opción",
+		"Owner:":                     "Responsable:",
+		"Package Overview":           "Resumen de paquetes",
+		"Packages":                   "Paquetes",
+		"Page not found":             "Página no encontrada",
+		"Parameters:":                "Parámetros:",
+		"Pattern":                    "Patrón",
+		"Patterns:":                  "Patrones:",
+		"Protocol Documentation":     "Documentación del protocolo",
+		"Request":                    "Solicitud",
+		"Request Fields":             "Campos de la solicitud",
+		"Request Type":               "Tipo de solicitud",
+		"Resource reference:":        "Referencia de recurso:",
+		"Resource:":                  "Recurso:",
+		"Response":                   "Respuesta",
+		"Response Fields":            "Campos de la respuesta",
+		"Response Type":              "Tipo de respuesta",
+		"Response:":                  "Respuesta:",
+		"Returns:":                   "Devuelve:",
+		"Scalar Value Types":         "Tipos de valores escalares",
+		"See also:":                  "Véase también:",
+		"Since":                      "Desde",
+		"Skip to content":            "Saltar al contenido",
+		"Source":                     "Código fuente",
+		"System":                     "Sistema",
+		"Table of Contents":          "Índice",
+		"Theme":                      "Tema",
+		"This page doesn't exist.":   "Esta página no existe.",
+		"Top":                        "Inicio",
+		"Type":                       "Tipo",
+		"Used by:":                   "Usado por:",
+		"Validated Fields":           "Campos validados",
+		"Validations":                "Validaciones",
+		"Values":                     "Valores",
+		"Version":                    "Versión",
+		"View source":                "Ver código fuente",
+		"alpha":                      "alfa",
+		"beta":                       "beta",
+		"enum":                       "enumeración",
+		"enum value":                 "valor de enumeración",
+		"explicit presence":          "presencia explícita",
+		"field":                      "campo",
+		"message":                    "mensaje",
+		"method":                     "método",
+		"parent of":                  "padre de",
+		"request":                    "solicitud",
+		"response":                   "respuesta",
+		"service":                    "servicio",
+		"stable":                     "estable",
+	},
+	"fr": {
+		"(default package)":          "(paquet par défaut)",
+		".proto Type":                "Type .proto",
+		"Allowed types:":             "Types autorisés :",
+		"Back to the home page":      "Retour à la page d'accueil",
+		"Base":                       "Base",
+		"Body":                       "Corps",
+		"Breadcrumb":                 "Fil d’Ariane",
+		"Code":                       "Code",
+		"Constraints":                "Contraintes",
+		"Copied":                     "Copié",
+		"Copy":                       "Copier",
+		"Copy code":                  "Copier le code",
+		"Copy link":                  "Copier le lien",
+		"Dark":                       "Sombre",
+		"Default:":                   "Par défaut :",
+		"Deprecated.":                "Obsolète.",
+		"Description":                "Description",
+		"Edit this comment":          "Modifier ce commentaire",
+		"Example":                    "Exemple",
+		"Example:":                   "Exemple :",
+		"Extension":                  "Extension",
+		"Features:":                  "Fonctionnalités :",
+		"Field":                      "Champ",
+		"Fields":                     "Champs",
+		"Fields with %s option":      "Champs avec l'option %s",
+		"File-level Extensions":      "Extensions au niveau du fichier",
+		"Full Name":                  "Nom complet",
+		"Generated by":               "Généré par",
+		"Generated for release":      "Généré pour la version",
+		"High contrast":              "Contraste élevé",
+		"Home":                       "Accueil",
+		"Imported Types":             "Types importés",
+		"Index":                      "Index",
+		"JSON:":                      "JSON :",
+		"Kind":                       "Nature",
+		"Label":                      "Étiquette",
+		"Light":                      "Clair",
+		"Link to this section":       "Lien vers cette section",
+		"Metadata:":                  "Métadonnées :",
+		"Method":                     "Méthode",
+		"Method Errors":              "Erreurs des méthodes",
+		"Method Name":                "Nom de la méthode",
+		"Methods":                    "Méthodes",
+		"Methods with %s option":     "Méthodes avec l'option %s",
+		"Methods with HTTP bindings": "Méthodes avec liaisons HTTP",
+		"Name":                       "Nom",
+		"Nested Extensions":          "Extensions imbriquées",
+		"Notes":                      "Remarques",
+		"Number":                     "Numéro",
+		"Option":                     "Option",
+		"Owner:":                     "Responsable :",
+		"Package Overview":           "Aperçu des paquets",
+		"Packages":                   "Paquets",
+		"Page not found":             "Page introuvable",
+		"Parameters:":                "Paramètres :",
+		"Pattern":                    "Motif",
+		"Patterns:":                  "Modèles :",
+		"Protocol Documentation":     "Documentation du protocole",
+		"Request":                    "Requête",
+		"Request Fields":             "Champs de la requête",
+		"Request Type":               "Type de requête",
+		"Resource reference:":        "Référence de ressource :",
+		"Resource:":                  "Ressource :",
+		"Response":                   "Réponse",
+		"Response Fields":            "Champs de la réponse",
+		"Response Type":              "Type de réponse",
+		"Response:":                  "Réponse :",
+		"Returns:":                   "Retourne :",
+		"Scalar Value Types":         "Types de valeurs scalaires",
+		"See also:":                  "Voir aussi :",
+		"Since":                      "Depuis",
+		"Skip to content":            "Aller au contenu",
+		"Source":                     "Source",
+		"System":                     "Système",
+		"Table of Contents":          "Table des matières",
+		"Theme":                      "Thème",
+		"This page doesn't exist.":   "Cette page n'existe pas.",
+		"Top":                        "Haut",
+		"Type":                       "Type",
+		"Used by:":                   "Utilisé par :",
+		"Validated Fields":           "Champs validés",
+		"Validations":                "Validations",
+		"Values":                     "Valeurs",
+		"Version":                    "Version",
+		"View source":                "Voir la source",
+		"alpha":                      "alpha",
+		"beta":                       "bêta",
+		"enum":                       "énumération",
+		"enum value":                 "valeur d'énumération",
+		"explicit presence":          "présence explicite",
+		"field":                      "champ",
+		"message":                    "message",
+		"method":                     "méthode",
+		"parent of":                  "parent de",
+		"request":                    "requête",
+		"response":                   "réponse",
+		"service":                    "service",
+		"stable":                     "stable",
+	},
+	"ja": {
+		"(default package)":          "(デフォルトパッケージ)",
+		".proto Type":                ".proto 型",
+		"Allowed types:":             "許可される型:",
+		"Back to the home page":      "ホームページに戻る",
+		"Base":                       "拡張対象",
+		"Body":                       "ボディ",
+		"Breadcrumb":                 "パンくずリスト",
+		"Code":                       "コード",
+		"Constraints":                "制約",
+		"Copied":                     "コピーしました",
+		"Copy":                       "コピー",
+		"Copy code":                  "コードをコピー",
+		"Copy link":                  "リンクをコピー",
+		"Dark":                       "ダーク",
+		"Default:":                   "デフォルト:",
+		"Deprecated.":                "非推奨。",
+		"Description":                "説明",
+		"Edit this comment":          "このコメントを編集",
+		"Example":                    "例",
+		"Example:":                   "例:",
+		"Extension":                  "拡張",
+		"Features:":                  "機能:",
+		"Field":                      "フィールド",
+		"Fields":                     "フィールド",
+		"Fields with %s option":      "%s オプションを持つフィールド",
+		"File-level Extensions":      "ファイルレベルの拡張",
+		"Full Name":                  "完全名",
+		"Generated by":               "生成元",
+		"Generated for release":      "生成対象のリリース:",
+		"High contrast":              "ハイコントラスト",
+		"Home":                       "ホーム",
+		"Imported Types":             "インポートされた型",
+		"Index":                      "索引",
+		"JSON:":                      "JSON:",
+		"Kind":                       "種類",
+		"Label":                      "ラベル",
+		"Light":                      "ライト",
+		"Link to this section":       "このセクションへのリンク",
+		"Metadata:":                  "メタデータ:",
+		"Method":                     "メソッド",
+		"Method Errors":              "メソッドのエラー",
+		"Method Name":                "メソッド名",
+		"Methods":                    "メソッド",
+		"Methods with %s option":     "%s オプションを持つメソッド",
+		"Methods with HTTP bindings": "HTTP バインディングを持つメソッド",
+		"Name":                       "名前",
+		"Nested Extensions":          "ネストされた拡張",
+		"Notes":                      "備考",
+		"Number":                     "番号",
+		"Option":                     "オプション",
+		"Owner:":                     "担当者:",
+		"Package Overview":           "パッケージ概要",
+		"Packages":                   "パッケージ",
+		"Page not found":             "ページが見つかりません",
+		"Parameters:":                "パラメーター:",
+		"Pattern":                    "パターン",
+		"Patterns:":                  "パターン:",
+		"Protocol Documentation":     "プロトコルドキュメント",
+		"Request":                    "リクエスト",
+		"Request Fields":             "リクエストのフィールド",
+		"Request Type":               "リクエスト型",
+		"Resource reference:":        "リソース参照:",
+		"Resource:":                  "リソース:",
+		"Response":                   "レスポンス",
+		"Response Fields":            "レスポンスのフィールド",
+		"Response Type":              "レスポンス型",
+		"Response:":                  "レスポンス:",
+		"Returns:":                   "戻り値:",
+		"Scalar Value Types":         "スカラー値型",
+		"See also:":                  "関連項目:",
+		"Since":                      "導入バージョン",
+		"Skip to content":            "コンテンツへスキップ",
+		"Source":                     "ソース",
+		"System":                     "システム",
+		"Table of Contents":          "目次",
+		"Theme":                      "テーマ",
+		"This page doesn't exist.":   "このページは存在しません。",
+		"Top":                        "トップ",
+		"Type":                       "型",
+		"Used by:":                   "使用箇所:",
+		"Validated Fields":           "検証されるフィールド",
+		"Validations":                "検証",
+		"Values":                     "値",
+		"Version":                    "バージョン",
+		"View source":                "ソースを表示",
+		"alpha":                      "アルファ",
+		"beta":                       "ベータ",
+		"enum":                       "列挙型",
+		"enum value":                 "列挙値",
+		"explicit presence":          "明示的な存在",
+		"field":                      "フィールド",
+		"message":                    "メッセージ",
+		"method":                     "メソッド",
+		"parent of":                  "親リソース:",
+		"request":                    "リクエスト",
+		"response":                   "レスポンス",
+		"service":                    "サービス",
+		"stable":                     "安定版",
+	},
+	"zh": {
+		"(default package)":          "(默认包)",
+		".proto Type":                ".proto 类型",
+		"Allowed types:":             "允许的类型:",
+		"Back to the home page":      "返回首页",
+		"Base":                       "扩展目标",
+		"Body":                       "请求体",
+		"Breadcrumb":                 "面包屑导航",
+		"Code":                       "代码",
+		"Constraints":                "约束",
+		"Copied":                     "已复制",
+		"Copy":                       "复制",
+		"Copy code":                  "复制代码",
+		"Copy link":                  "复制链接",
+		"Dark":                       "深色",
+		"Default:":                   "默认值:",
+		"Deprecated.":                "已弃用。",
+		"Description":                "描述",
+		"Edit this comment":          "编辑此注释",
+		"Example":                    "示例",
+		"Example:":                   "示例:",
+		"Extension":                  "扩展",
+		"Features:":                  "特性:",
+		"Field":                      "字段",
+		"Fields":                     "字段",
+		"Fields with %s option":      "带有 %s 选项的字段",
+		"File-level Extensions":      "文件级扩展",
+		"Full Name":                  "全名",
+		"Generated by":               "生成工具",
+		"Generated for release":      "生成对应的版本",
+		"High contrast":              "高对比度",
+		"Home":                       "首页",
+		"Imported Types":             "导入的类型",
+		"Index":                      "索引",
+		"JSON:":                      "JSON:",
+		"Kind":                       "种类",
+		"Label":                      "标签",
+		"Light":                      "浅色",
+		"Link to this section":       "指向此部分的链接",
+		"Metadata:":                  "元数据：",
+		"Method":                     "方法",
+		"Method Errors":              "方法错误",
+		"Method Name":                "方法名",
+		"Methods":                    "方法",
+		"Methods with %s option":     "带有 %s 选项的方法",
+		"Methods with HTTP bindings": "带有 HTTP 绑定的方法",
+		"Name":                       "名称",
+		"Nested Extensions":          "嵌套扩展",
+		"Notes":                      "说明",
+		"Number":                     "编号",
+		"Option":                     "选项",
+		"Owner:":                     "负责人:",
+		"Package Overview":           "包概览",
+		"Packages":                   "包",
+		"Page not found":             "页面未找到",
+		"Parameters:":                "参数:",
+		"Pattern":                    "路径模式",
+		"Patterns:":                  "模式：",
+		"Protocol Documentation":     "协议文档",
+		"Request":                    "请求",
+		"Request Fields":             "请求字段",
+		"Request Type":               "请求类型",
+		"Resource reference:":        "资源引用：",
+		"Resource:":                  "资源：",
+		"Response":                   "响应",
+		"Response Fields":            "响应字段",
+		"Response Type":              "响应类型",
+		"Response:":                  "响应：",
+		"Returns:":                   "返回:",
+		"Scalar Value Types":         "标量值类型",
+		"See also:":                  "另请参阅:",
+		"Since":                      "起始版本",
+		"Skip to content":            "跳到内容",
+		"Source":                     "源码",
+		"System":                     "跟随系统",
+		"Table of Contents":          "目录",
+		"Theme":                      "主题",
+		"This page doesn't exist.":   "此页面不存在。",
+		"Top":                        "顶部",
+		"Type":                       "类型",
+		"Used by:":                   "使用者：",
+		"Validated Fields":           "校验字段",
+		"Validations":                "校验规则",
+		"Values":                     "值",
+		"Version":                    "版本",
+		"View source":                "查看源代码",
+		"alpha":                      "内测",
+		"beta":                       "公测",
+		"enum":                       "枚举",
+		"enum value":                 "枚举值",
+		"explicit presence":          "显式存在",
+		"field":                      "字段",
+		"message":                    "消息",
+		"method":                     "方法",
+		"parent of":                  "父资源：",
+		"request":                    "请求",
+		"response":                   "响应",
+		"service":                    "服务",
+		"stable":                     "稳定",
+	},
+}
+
+// RegisterLocale makes a catalog available to the `locale` option, replacing any catalog previously registered under
+// the same name. This must be called before options are parsed (e.g. in an init function).
+func RegisterLocale(name string, catalog Catalog) {
+	catalogs[name] = catalog
+}
+
+// Locales returns the names of all registered locales in alphabetical order.
+func Locales() []string {
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Translate returns the translation of s in the given locale. If the locale is unknown or has no translation for s,
+// s is returned as is.
+func Translate(locale, s string) string {
+	if translation, ok := catalogs[locale][s]; ok {
+		return translation
+	}
+
+	return s
+}
+
+func hasLocale(name string) bool {
+	_, ok := catalogs[name]
+	return ok
+}
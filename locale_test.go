@@ -0,0 +1,67 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	require.Equal(t, "Felder", Translate("de", "Fields"))
+	require.Equal(t, "Fields", Translate(DefaultLocale, "Fields"))
+	require.Equal(t, "Fields", Translate("unknown", "Fields"))
+	require.Equal(t, "Not in any catalog", Translate("de", "Not in any catalog"))
+}
+
+func TestRegisterLocale(t *testing.T) {
+	RegisterLocale("x-pirate", Catalog{"Table of Contents": "Treasure Map"})
+	require.Contains(t, Locales(), "x-pirate")
+	require.Equal(t, "Treasure Map", Translate("x-pirate", "Table of Contents"))
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{Locale: "x-pirate"})
+
+	output, err := RenderTemplate(RenderTypeMarkdown, template, "")
+	require.NoError(t, err)
+	require.Contains(t, string(output), "## Treasure Map\n")
+	require.Contains(t, string(output), "| Field | Type | Label | Description |")
+}
+
+func TestLocales(t *testing.T) {
+	locales := Locales()
+	require.Contains(t, locales, DefaultLocale)
+	require.Contains(t, locales, "de")
+	require.IsIncreasing(t, locales)
+}
+
+func TestBuiltinTemplatesAreTranslated(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{Locale: "de"})
+
+	expected := map[RenderType][]string{
+		RenderTypeDocBook: {"<title>Protokolldokumentation</title>", "<entry>Beschreibung</entry>"},
+		RenderTypeHTML: {
+			`<html lang="de"`, `<h2 id="toc-heading">Inhaltsverzeichnis</h2>`, `<th scope="col">Beschreibung</th>`,
+			"Zum Inhalt springen",
+		},
+		RenderTypeMarkdown: {"# Protokolldokumentation", "| Feld | Typ | Label | Beschreibung |"},
+	}
+
+	for kind, snippets := range expected {
+		output, err := RenderTemplate(kind, template, "")
+		require.NoError(t, err)
+
+		for _, snippet := range snippets {
+			require.Contains(t, string(output), snippet)
+		}
+	}
+}
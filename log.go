@@ -0,0 +1,105 @@
+package gendoc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Values of the log_level option. Each level includes the ones after it.
+const (
+	// LogLevelDebug logs the files being documented or excluded, and how long each output took to render.
+	LogLevelDebug = "debug"
+	// LogLevelInfo logs a summary of each run.
+	LogLevelInfo = "info"
+	// LogLevelWarn logs problems that don't stop docs from being generated.
+	LogLevelWarn = "warn"
+	// LogLevelError logs nothing, as errors are returned to protoc instead.
+	LogLevelError = "error"
+)
+
+var logLevels = map[string]int{LogLevelDebug: 0, LogLevelInfo: 1, LogLevelWarn: 2, LogLevelError: 3}
+
+// LogOutput is where the logs enabled by the log_level option are written. Each entry is a single line of key=value
+// pairs (logfmt), e.g. `time=2021-01-02T15:04:05.000Z level=debug msg="excluded file" file=google/api/http.proto`.
+// The summary written by `timings=stderr` goes here too.
+var LogOutput io.Writer = os.Stderr
+
+// isLogLevel returns whether level is a valid value of the log_level option.
+func isLogLevel(level string) bool {
+	_, ok := logLevels[level]
+	return ok
+}
+
+// logger writes structured log entries of at least a minimum level to LogOutput. It's safe for concurrent use.
+type logger struct {
+	level int
+	mu    sync.Mutex
+}
+
+func newLogger(level string) *logger {
+	l, ok := logLevels[level]
+	if !ok {
+		l = logLevels[LogLevelWarn]
+	}
+
+	return &logger{level: l}
+}
+
+func (l *logger) debug(msg string, keyValues ...interface{}) {
+	l.log(LogLevelDebug, msg, keyValues)
+}
+
+func (l *logger) info(msg string, keyValues ...interface{}) {
+	l.log(LogLevelInfo, msg, keyValues)
+}
+
+func (l *logger) warn(msg string, keyValues ...interface{}) {
+	l.log(LogLevelWarn, msg, keyValues)
+}
+
+// enabled returns whether entries of level are written. Useful to skip collecting expensive values.
+func (l *logger) enabled(level string) bool {
+	return logLevels[level] >= l.level
+}
+
+// log writes an entry with the given message and alternating keys and values.
+func (l *logger) log(level, msg string, keyValues []interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "time=%s level=%s msg=%s", time.Now().UTC().Format("2006-01-02T15:04:05.000Z07:00"), level,
+		logValue(msg))
+
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		fmt.Fprintf(&b, " %v=%s", keyValues[i], logValue(keyValues[i+1]))
+	}
+	b.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(LogOutput, b.String())
+}
+
+// logValue formats a value for a log entry, quoting it when needed.
+func logValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case time.Duration:
+		s = v.Round(time.Microsecond).String()
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\\\t\r\n") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,66 @@
+package gendoc_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func captureLogs(t *testing.T, parameter string) []string {
+	var buf bytes.Buffer
+	LogOutput = &buf
+	defer func() { LogOutput = os.Stderr }()
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String(parameter)
+
+	_, err = new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestDebugLogs(t *testing.T) {
+	logs := captureLogs(t, "markdown,docs.md:log_level=debug,exclude_patterns=nested/.*")
+	require.Len(t, logs, 6)
+
+	for _, entry := range logs {
+		require.Regexp(t, `^time=\S+ level=(debug|info) msg=`, entry)
+	}
+
+	require.Contains(t, logs[0], `level=debug msg="generating docs" parameter="markdown,docs.md:log_level=debug,exclude_patterns=nested/.*" files=3`)
+	require.Contains(t, logs[1], `level=debug msg="excluded file" file=nested/Book.proto pattern=nested/.*`)
+	require.Contains(t, logs[2], `level=debug msg="documenting file" dir=./ file=Booking.proto package=com.example messages=3 enums=2 services=1 extensions=1`)
+	require.Contains(t, logs[3], `msg="documenting file" dir=./ file=Vehicle.proto`)
+	require.Regexp(t, `level=debug msg="rendered output" dir=./ files=2 bytes=\d+ duration=\S+$`, logs[4])
+	require.Regexp(t, `level=info msg="generated docs" files=2 excluded=1 outputs=1 duration=\S+$`, logs[5])
+}
+
+func TestInfoLogs(t *testing.T) {
+	logs := captureLogs(t, "markdown,docs.md:log_level=info")
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], `level=info msg="generated docs" files=3 excluded=0 outputs=1`)
+}
+
+func TestWarnLogs(t *testing.T) {
+	require.Empty(t, captureLogs(t, "markdown,docs.md"))
+
+	logs := captureLogs(t, "json,docs.json:index=true")
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], `level=warn msg="the index option is ignored by this format" option=index`)
+
+	require.Empty(t, captureLogs(t, "json,docs.json:index=true,log_level=error"))
+}
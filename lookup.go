@@ -0,0 +1,42 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// Lookup returns the message (*Message), enum (*Enum) or service (*Service) with the given full name (a leading dot is
+// allowed), wherever it's documented in the template, or nil when none is.
+//
+// Templates use it as `{{lookup "com.example.Error"}}`, e.g. to inline a shared message in the docs of others with
+// `{{with lookup "com.example.Error"}}{{template "gendoc/default/field-table" .}}{{end}}`.
+func (t *Template) Lookup(fullName string) interface{} {
+	return lookupEntity(t.entities(), fullName)
+}
+
+// entities returns the messages, enums and services documented in the template, keyed by full name.
+func (t *Template) entities() map[string]interface{} {
+	entities := make(map[string]interface{})
+	for _, f := range t.Files {
+		for _, m := range f.AllMessages() {
+			entities[m.FullName] = m
+		}
+		for _, e := range f.AllEnums() {
+			entities[e.FullName] = e
+		}
+		for _, s := range f.Services {
+			entities[s.FullName] = s
+		}
+	}
+
+	return entities
+}
+
+// lookupEntity returns the entity with the given full name, as an untyped nil when there's none so that templates
+// treat it as empty.
+func lookupEntity(entities map[string]interface{}, fullName string) interface{} {
+	if entity, ok := entities[strings.TrimPrefix(fullName, ".")]; ok {
+		return entity
+	}
+
+	return nil
+}
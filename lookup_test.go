@@ -0,0 +1,43 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	template := newBookingTemplate(t)
+
+	message, ok := template.Lookup("com.example.BookingStatus").(*Message)
+	require.True(t, ok)
+	require.Equal(t, "com.example.BookingStatus", message.FullName)
+
+	enum, ok := template.Lookup(".com.example.BookingStatus.StatusCode").(*Enum)
+	require.True(t, ok)
+	require.Equal(t, "StatusCode", enum.Name)
+
+	service, ok := template.Lookup("com.example.BookingService").(*Service)
+	require.True(t, ok)
+	require.Equal(t, "BookingService", service.Name)
+
+	require.Nil(t, template.Lookup("com.example.Missing"))
+	require.Nil(t, template.Lookup("int32"))
+}
+
+func TestLookupInTemplate(t *testing.T) {
+	output, err := RenderTemplate(RenderTypeHTML, newBookingTemplate(t),
+		`{{with lookup "com.example.BookingStatus"}}<h3>{{.LongName}}</h3>{{template "gendoc/default/field-table" .}}`+
+			`{{end}}{{with lookup "com.example.Missing"}}missing{{else}}none{{end}}`)
+	require.NoError(t, err)
+	require.Contains(t, string(output), "<h3>BookingStatus</h3>\n            <table class=\"field-table\">")
+	require.Contains(t, string(output), `<td><a href="#int32">int32</a></td>`)
+	require.Contains(t, string(output), "none")
+	require.NotContains(t, string(output), "missing")
+
+	output, err = RenderTemplate(RenderTypeMarkdown, newBookingTemplate(t),
+		`{{range (lookup "com.example.BookingService").Methods}}{{.Name}} {{end}}`)
+	require.NoError(t, err)
+	require.Contains(t, string(output), "BookVehicle ")
+}
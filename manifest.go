@@ -0,0 +1,107 @@
+package gendoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pseudomuto/protokit"
+)
+
+// Manifest lists the files written by a run (see the manifest option), so that publishing pipelines can tell which of
+// them changed since the previous one.
+type Manifest struct {
+	// The version of protoc-gen-doc that wrote the files.
+	GeneratorVersion string `json:"generatorVersion"`
+	// The files written, ordered by name. The manifest itself isn't included.
+	Files []*ManifestFile `json:"files"`
+}
+
+// ManifestFile describes a file listed in a Manifest.
+type ManifestFile struct {
+	// The path of the file, relative to the output directory.
+	Name string `json:"name"`
+	// The hex-encoded SHA-256 checksum of the file's content.
+	SHA256 string `json:"sha256"`
+	// The names of the proto files the file was generated from, ordered by name. Files generated from every documented
+	// proto file, e.g. index pages and sitemaps, list all of them, and static assets none.
+	Sources []string `json:"sources,omitempty"`
+	// A hash of everything the file depends on (its proto files, the options, the template, etc.), compared with the
+	// previous_manifest option to find the files that would stay the same. Only set for the files documenting protos.
+	Key string `json:"key,omitempty"`
+}
+
+// readManifest reads the manifest written by a previous run, returning its files by name. A missing file is taken as
+// an empty manifest, so that the first run can pass the same options as the following ones.
+func readManifest(name string) (map[string]*ManifestFile, error) {
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return map[string]*ManifestFile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := new(Manifest)
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("Invalid previous_manifest %s: %v", name, err)
+	}
+
+	files := make(map[string]*ManifestFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files[file.Name] = file
+	}
+
+	return files, nil
+}
+
+// sha256Hex returns the hex-encoded SHA-256 checksum of content.
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// protoNames returns the sorted names of fds.
+func protoNames(fds []*protokit.FileDescriptor) []string {
+	names := make([]string, 0, len(fds))
+	for _, fd := range fds {
+		names = append(names, fd.GetName())
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// renderManifest renders the JSON manifest of files, along with the unchanged files that were skipped. The sources and
+// key of each file are looked up by its name, and files without sources are taken to be generated from every proto
+// file in all.
+func renderManifest(files []OutputFile, unchanged []*ManifestFile, sources map[string][]string,
+	keys map[string]string, all []string) (string, error) {
+	manifest := &Manifest{GeneratorVersion: VERSION, Files: make([]*ManifestFile, 0, len(files)+len(unchanged))}
+	for _, file := range files {
+		fileSources, ok := sources[file.Name]
+		if !ok {
+			fileSources = all
+		}
+
+		manifest.Files = append(manifest.Files, &ManifestFile{
+			Name:    file.Name,
+			SHA256:  sha256Hex(file.Content),
+			Sources: fileSources,
+			Key:     keys[file.Name],
+		})
+	}
+	manifest.Files = append(manifest.Files, unchanged...)
+
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].Name < manifest.Files[j].Name })
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,160 @@
+package gendoc_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func generateManifest(t *testing.T, parameter string) (*pluginpb.CodeGeneratorResponse, *Manifest) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String(parameter)
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	for _, f := range resp.File {
+		if f.GetName() == "manifest.json" {
+			manifest := new(Manifest)
+			require.NoError(t, json.Unmarshal([]byte(f.GetContent()), manifest))
+			return resp, manifest
+		}
+	}
+
+	require.Fail(t, "manifest.json wasn't generated")
+	return nil, nil
+}
+
+func TestParseOptionsForManifest(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:manifest=manifest.json"))
+	require.NoError(t, err)
+	require.Equal(t, "manifest.json", options.Manifest)
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:manifest="))
+	require.EqualError(t, err, "Invalid manifest value: ")
+}
+
+func TestManifest(t *testing.T) {
+	resp, manifest := generateManifest(t, "html,index.html,source_relative:manifest=manifest.json,assets=external")
+	require.Equal(t, VERSION, manifest.GeneratorVersion)
+
+	contents := make(map[string]string)
+	for _, f := range resp.File {
+		contents[f.GetName()] = f.GetContent()
+	}
+
+	names := make([]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		sum := sha256.Sum256([]byte(contents[file.Name]))
+		require.Equal(t, hex.EncodeToString(sum[:]), file.SHA256, file.Name)
+		names = append(names, file.Name)
+	}
+	require.Equal(t, []string{StylesheetAsset, "index.html", "nested/index.html"}, names)
+
+	require.Empty(t, manifest.Files[0].Sources)
+	require.Equal(t, []string{"Booking.proto", "Vehicle.proto"}, manifest.Files[1].Sources)
+	require.Equal(t, []string{"nested/Book.proto"}, manifest.Files[2].Sources)
+}
+
+func TestManifestForAggregateFiles(t *testing.T) {
+	_, manifest := generateManifest(t, "html,index.html,source_relative:manifest=manifest.json,index=true,"+
+		"site_url=https://docs.example.com")
+
+	sources := make(map[string][]string)
+	for _, file := range manifest.Files {
+		sources[file.Name] = file.Sources
+	}
+
+	all := []string{"Booking.proto", "Vehicle.proto", "nested/Book.proto"}
+	require.Equal(t, all, sources["glossary.html"])
+	require.Equal(t, all, sources["sitemap.xml"])
+	require.Equal(t, []string{"nested/Book.proto"}, sources["nested/index.html"])
+	require.NotContains(t, sources, "manifest.json")
+}
+
+func TestPreviousManifest(t *testing.T) {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	previous := filepath.Join(dir, "manifest.json")
+	parameter := "markdown,docs.md,source_relative:manifest=manifest.json,reproducible=true," +
+		"previous_manifest=" + previous
+	generate := func(set *descriptorpb.FileDescriptorSet) []string {
+		req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+		req.Parameter = proto.String(parameter)
+
+		resp, err := new(Plugin).Generate(req)
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(resp.File))
+		for _, f := range resp.File {
+			names = append(names, f.GetName())
+			if f.GetName() == "manifest.json" {
+				require.NoError(t, os.WriteFile(previous, []byte(f.GetContent()), 0644))
+			}
+		}
+
+		return names
+	}
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	// the previous manifest doesn't exist yet, so everything is generated
+	require.ElementsMatch(t, []string{"docs.md", "nested/docs.md", "manifest.json"}, generate(set))
+	first, err := os.ReadFile(previous)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"manifest.json"}, generate(set))
+	second, err := os.ReadFile(previous)
+	require.NoError(t, err)
+	require.JSONEq(t, string(first), string(second))
+
+	for _, fd := range set.GetFile() {
+		if fd.GetName() == "nested/Book.proto" {
+			fd.MessageType[0].Field[0].Name = proto.String("renamed")
+		}
+	}
+	// templates can read any file of the request, so changing one renders every output again
+	require.ElementsMatch(t, []string{"docs.md", "nested/docs.md", "manifest.json"}, generate(set))
+	require.Equal(t, []string{"manifest.json"}, generate(set))
+
+	_, manifest := generateManifest(t, parameter)
+	require.Len(t, manifest.Files, 2)
+	for _, file := range manifest.Files {
+		require.NotEmpty(t, file.Key)
+		require.NotEmpty(t, file.SHA256)
+		require.NotEmpty(t, file.Sources)
+	}
+}
+
+func TestParseOptionsForPreviousManifest(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:previous_manifest=missing/manifest.json"))
+	require.NoError(t, err)
+	require.Equal(t, "missing/manifest.json", options.PreviousManifest)
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:previous_manifest="))
+	require.EqualError(t, err, "Invalid previous_manifest value: ")
+
+	f, err := os.CreateTemp("", "manifest-*.json")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, f.Close())
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:previous_manifest="+f.Name()))
+	require.Error(t, err)
+}
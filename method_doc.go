@@ -0,0 +1,126 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// The directives documenting the parts of a method in its comment, e.g. `@param isbn The ISBN of the book.`,
+// `@returns The book.` or `@deprecated Use GetBookV2 instead.`. `@return` is accepted for `@returns`.
+const (
+	paramDirective      = "@param"
+	returnsDirective    = "@returns"
+	returnDirective     = "@return"
+	deprecatedDirective = "@deprecated"
+)
+
+// MethodDoc holds the structured documentation of a method, given with @param, @returns and @deprecated directives in
+// its comment. Each description continues on the following lines up to the end of the paragraph.
+type MethodDoc struct {
+	// The request fields documented with `@param NAME description`, in order.
+	Params []*MethodParam `json:"params,omitempty"`
+	// The description of the response, given with `@returns description`.
+	Returns string `json:"returns,omitempty"`
+	// Whether the method is marked with `@deprecated`, optionally followed by the reason.
+	Deprecated        bool   `json:"deprecated,omitempty"`
+	DeprecationReason string `json:"deprecationReason,omitempty"`
+}
+
+// MethodParam is a request field documented with an @param directive.
+type MethodParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// directiveArgs returns the rest of line when it starts with directive (followed by a space or nothing), and whether it
+// does.
+func directiveArgs(line, directive string) (string, bool) {
+	rest := strings.TrimPrefix(line, directive)
+	if rest == line || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+
+	return strings.TrimSpace(rest), true
+}
+
+// extractMethodDoc removes the @param, @returns and @deprecated directives from a method description, returning the
+// remaining description and the structured documentation, which is nil when there are no directives.
+func extractMethodDoc(description string) (string, *MethodDoc) {
+	if !strings.Contains(description, paramDirective) && !strings.Contains(description, returnDirective) &&
+		!strings.Contains(description, deprecatedDirective) {
+		return description, nil
+	}
+
+	var doc *MethodDoc
+	// the description being continued by the following lines of the paragraph
+	var current *string
+	paragraphs := make([]string, 0)
+	paragraph := make([]string, 0)
+
+	endParagraph := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraph, "\n"))
+			paragraph = paragraph[:0]
+		}
+		current = nil
+	}
+	startDirective := func() {
+		if doc == nil {
+			doc = new(MethodDoc)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			endParagraph()
+			continue
+		}
+
+		if args, ok := directiveArgs(trimmed, paramDirective); ok {
+			startDirective()
+			fields := strings.Fields(args)
+			if len(fields) == 0 {
+				continue
+			}
+
+			param := &MethodParam{Name: fields[0], Description: strings.Join(fields[1:], " ")}
+			doc.Params = append(doc.Params, param)
+			current = &param.Description
+			continue
+		}
+
+		args, ok := directiveArgs(trimmed, returnsDirective)
+		if !ok {
+			args, ok = directiveArgs(trimmed, returnDirective)
+		}
+		if ok {
+			startDirective()
+			doc.Returns = args
+			current = &doc.Returns
+			continue
+		}
+
+		if args, ok := directiveArgs(trimmed, deprecatedDirective); ok {
+			startDirective()
+			doc.Deprecated = true
+			doc.DeprecationReason = args
+			current = &doc.DeprecationReason
+			continue
+		}
+
+		// other directives (like @error) end the description too
+		if strings.HasPrefix(trimmed, "@") {
+			current = nil
+		}
+		if current != nil {
+			*current = joinLines(*current, trimmed)
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	endParagraph()
+
+	return strings.Join(paragraphs, "\n\n"), doc
+}
@@ -0,0 +1,106 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const getShelfComment = ` Returns a shelf.
+
+ @param name The name of
+   the shelf.
+ @param view
+ @param title 本棚の
+   名前。
+ @error NOT_FOUND The shelf doesn't exist.
+ @returns The shelf, with [Book] entries.
+ @deprecated Use GetShelfV2.
+
+ Shelves are cached.
+`
+
+func newMethodDocRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"library.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("library.proto"),
+			Package: proto.String("library"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("Book")},
+				{Name: proto.String("Shelf")},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Library"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("GetShelf"), InputType: proto.String(".library.Shelf"),
+						OutputType: proto.String(".library.Shelf")},
+					{Name: proto.String("GetBook"), InputType: proto.String(".library.Book"),
+						OutputType: proto.String(".library.Book")},
+				},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{6, 0, 2, 0}, Span: []int32{1, 0, 1}, LeadingComments: proto.String(getShelfComment)},
+				{Path: []int32{6, 0, 2, 1}, Span: []int32{2, 0, 1}, LeadingComments: proto.String(
+					" Returns a book.\n @return The book.\n @deprecated\n")},
+			}},
+		}},
+	}
+}
+
+func TestMethodDoc(t *testing.T) {
+	req := newMethodDocRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	service := findService("Library", NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0])
+
+	method := findServiceMethod("GetShelf", service)
+	require.Equal(t, "Returns a shelf.\n\nShelves are cached.", method.Description)
+	require.Equal(t, &MethodDoc{
+		Params: []*MethodParam{
+			{Name: "name", Description: "The name of the shelf."},
+			{Name: "view", Description: ""},
+			// lines of Japanese text are joined without a space
+			{Name: "title", Description: "本棚の名前。"},
+		},
+		Returns:           "The shelf, with [Book] entries.",
+		Deprecated:        true,
+		DeprecationReason: "Use GetShelfV2.",
+	}, method.Doc)
+
+	// directives end the descriptions of each other
+	require.Len(t, method.Errors, 1)
+	require.Equal(t, "The shelf doesn't exist.", method.Errors[0].Description)
+
+	method = findServiceMethod("GetBook", service)
+	require.Equal(t, "Returns a book.", method.Description)
+	require.Equal(t, &MethodDoc{Returns: "The book.", Deprecated: true}, method.Doc)
+}
+
+func TestMethodDocOutput(t *testing.T) {
+	resp, err := new(Plugin).Generate(newMethodDocRequest("html,index.html"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "<dt>Parameters:</dt>\n"+
+		"                      <dd><code>name</code> The name of the shelf.</dd>")
+	require.Contains(t, content, `<dd>The shelf, with <a href="#library.Book">Book</a> entries.</dd>`)
+	require.Contains(t, content, "<dt>Deprecated.</dt>\n                      <dd>Use GetShelfV2.</dd>")
+
+	resp, err = new(Plugin).Generate(newMethodDocRequest("markdown,README.md"))
+	require.NoError(t, err)
+
+	content = resp.File[0].GetContent()
+	require.Contains(t, content, "| Returns a shelf.<br><br>Shelves are cached.<br>**Parameters:**"+
+		"<br>`name` The name of the shelf.<br>`view` <br>`title` 本棚の名前。<br>**Returns:** The shelf, with "+
+		"[Book](#library-Book) entries.<br>**Deprecated.** Use GetShelfV2. |")
+	require.Contains(t, content, "| Returns a book.<br>**Returns:** The book.<br>**Deprecated.** |")
+}
@@ -0,0 +1,125 @@
+package gendoc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// errorDirective starts a line documenting an error a method may return, e.g. `@error NOT_FOUND The book doesn't exist.`
+const errorDirective = "@error"
+
+// MethodError describes an error a method may return, documented with an `@error CODE description` line in the
+// method's comment. The description continues on the following lines up to the end of the paragraph.
+type MethodError struct {
+	// The status code, e.g. `NOT_FOUND`. Canonical gRPC codes are normalized to their upper case name, any other code is
+	// kept as written.
+	Code string `json:"code"`
+	// The number of a canonical gRPC code, e.g. 5 for `NOT_FOUND`. 0 for other codes.
+	Number int `json:"number,omitempty"`
+	// The HTTP status the code maps to (see google/rpc/code.proto), e.g. 404 for `NOT_FOUND`. 0 for other codes.
+	HTTPStatus  int    `json:"httpStatus,omitempty"`
+	Description string `json:"description"`
+}
+
+// grpcCodes are the canonical gRPC status codes, indexed by number, along with the HTTP status each maps to.
+var grpcCodes = []struct {
+	name       string
+	httpStatus int
+}{
+	{"OK", 200},
+	{"CANCELLED", 499},
+	{"UNKNOWN", 500},
+	{"INVALID_ARGUMENT", 400},
+	{"DEADLINE_EXCEEDED", 504},
+	{"NOT_FOUND", 404},
+	{"ALREADY_EXISTS", 409},
+	{"PERMISSION_DENIED", 403},
+	{"RESOURCE_EXHAUSTED", 429},
+	{"FAILED_PRECONDITION", 400},
+	{"ABORTED", 409},
+	{"OUT_OF_RANGE", 400},
+	{"UNIMPLEMENTED", 501},
+	{"INTERNAL", 500},
+	{"UNAVAILABLE", 503},
+	{"DATA_LOSS", 500},
+	{"UNAUTHENTICATED", 401},
+}
+
+// newMethodError returns the error documented with code. Canonical codes may be given by name (in any case, with or
+// without underscores, e.g. `NotFound`) or by number.
+func newMethodError(code, description string) *MethodError {
+	normalized := strings.ToUpper(strings.ReplaceAll(code, "_", ""))
+	for number, c := range grpcCodes {
+		if normalized == strings.ReplaceAll(c.name, "_", "") || code == strconv.Itoa(number) {
+			return &MethodError{Code: c.name, Number: number, HTTPStatus: c.httpStatus, Description: description}
+		}
+	}
+
+	return &MethodError{Code: code, Description: description}
+}
+
+// extractErrors removes the @error directives from a method description, returning the remaining description and the
+// documented errors in order.
+func extractErrors(description string) (string, []*MethodError) {
+	if !strings.Contains(description, errorDirective) {
+		return description, nil
+	}
+
+	var errors []*MethodError
+	var current *MethodError
+	paragraphs := make([]string, 0)
+	paragraph := make([]string, 0)
+
+	endParagraph := func() {
+		if len(paragraph) > 0 {
+			paragraphs = append(paragraphs, strings.Join(paragraph, "\n"))
+			paragraph = paragraph[:0]
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(description, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			endParagraph()
+		case isErrorDirective(trimmed):
+			fields := strings.Fields(strings.TrimPrefix(trimmed, errorDirective))
+			if len(fields) == 0 {
+				current = nil
+				continue
+			}
+
+			current = newMethodError(fields[0], strings.Join(fields[1:], " "))
+			errors = append(errors, current)
+		case current != nil && !strings.HasPrefix(trimmed, "@"):
+			current.Description = joinLines(current.Description, trimmed)
+		default:
+			// other directives (like @param) end the description of an error
+			current = nil
+			paragraph = append(paragraph, line)
+		}
+	}
+	endParagraph()
+
+	return strings.Join(paragraphs, "\n\n"), errors
+}
+
+func isErrorDirective(line string) bool {
+	rest := strings.TrimPrefix(line, errorDirective)
+	return rest != line && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// MethodsWithErrors returns the methods of this service that document the errors they may return.
+func (s Service) MethodsWithErrors() []*ServiceMethod {
+	methods := make([]*ServiceMethod, 0, len(s.Methods))
+	for _, method := range s.Methods {
+		if len(method.Errors) > 0 {
+			methods = append(methods, method)
+		}
+	}
+	if len(methods) > 0 {
+		return methods
+	}
+	return nil
+}
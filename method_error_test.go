@@ -0,0 +1,70 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newErrorsRequest returns a request documenting a service whose methods document their errors with @error directives.
+func newErrorsRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "errors.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "library.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestMethodErrors(t *testing.T) {
+	tmpl := NewTemplate(protokit.ParseCodeGenRequest(newErrorsRequest(t, "")), new(PluginOptions))
+	service := findService("Library", tmpl.Files[0])
+
+	method := findServiceMethod("GetBook", service)
+	require.Equal(t, "Returns a book.\n\nBooks are cached for a minute.", method.Description)
+	require.Equal(t, []*MethodError{
+		{Code: "NOT_FOUND", Number: 5, HTTPStatus: 404, Description: "The book doesn't exist."},
+		{Code: "PERMISSION_DENIED", Number: 7, HTTPStatus: 403, Description: "The caller can't read books on this shelf."},
+		{Code: "UNAVAILABLE", Number: 14, HTTPStatus: 503, Description: "Try again later."},
+		{Code: "BOOK_CHECKED_OUT", Description: "Someone else has it."},
+	}, method.Errors)
+
+	method = findServiceMethod("UpdateBook", service)
+	require.Equal(t, "Updates a book.", method.Description)
+	require.Nil(t, method.Errors)
+
+	require.Len(t, service.MethodsWithErrors(), 1)
+}
+
+func TestRenderMethodErrors(t *testing.T) {
+	expected := map[string][]string{
+		"html": {
+			`<h4>Method Errors</h4>`,
+			"<td>GetBook</td>\n                  <td><code>NOT_FOUND</code></td>\n                  <td><p>The book doesn&#39;t exist.</p></td>",
+		},
+		"markdown": {
+			"#### Method Errors",
+			"| GetBook | `PERMISSION_DENIED` | The caller can't read books on this shelf. |",
+		},
+		"docbook": {
+			`<title>Method Errors</title>`,
+			"<entry>GetBook</entry>\n              <entry><literal>BOOK_CHECKED_OUT</literal></entry>",
+		},
+	}
+
+	for format, snippets := range expected {
+		resp, err := new(Plugin).Generate(newErrorsRequest(t, format+",docs"))
+		require.NoError(t, err)
+
+		content := resp.File[0].GetContent()
+		for _, snippet := range snippets {
+			require.Contains(t, content, snippet, format)
+		}
+		require.NotContains(t, content, "@error", format)
+	}
+}
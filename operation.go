@@ -0,0 +1,103 @@
+package gendoc
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// operationInfoField is the field number of the google.longrunning.operation_info method option. The option is read
+// from the wire format, so that it's found whether or not the google.longrunning package is linked into the binary.
+const operationInfoField = 1049
+
+// OperationInfo describes the result of a long-running operation, i.e. a method returning google.longrunning.Operation
+// (see https://google.aip.dev/151). It's declared with the google.longrunning.operation_info method option.
+type OperationInfo struct {
+	// The type the operation eventually responds with.
+	ResponseType     string `json:"responseType"`
+	ResponseLongType string `json:"responseLongType"`
+	ResponseFullType string `json:"responseFullType"`
+	// The type of the metadata reported while the operation runs. Empty when not declared.
+	MetadataType     string `json:"metadataType,omitempty"`
+	MetadataLongType string `json:"metadataLongType,omitempty"`
+	MetadataFullType string `json:"metadataFullType,omitempty"`
+}
+
+// parseOperationInfo returns the operation info declared by the options of a method in pkg, or nil.
+func parseOperationInfo(opts proto.Message, pkg string) *OperationInfo {
+	data, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+
+	var info *OperationInfo
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil
+		}
+		data = data[n:]
+
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return nil
+		}
+
+		if num == operationInfoField && typ == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(data[:n])
+			if info == nil {
+				info = new(OperationInfo)
+			}
+			// Like any message, the option may be split across several records that are merged
+			mergeOperationInfo(info, value, pkg)
+		}
+
+		data = data[n:]
+	}
+
+	return info
+}
+
+// mergeOperationInfo merges an encoded google.longrunning.OperationInfo message into info.
+func mergeOperationInfo(info *OperationInfo, data []byte, pkg string) {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return
+		}
+		data = data[n:]
+
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return
+		}
+
+		if typ == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(data[:n])
+			switch num {
+			case 1:
+				info.ResponseType, info.ResponseLongType, info.ResponseFullType = operationType(string(value), pkg)
+			case 2:
+				info.MetadataType, info.MetadataLongType, info.MetadataFullType = operationType(string(value), pkg)
+			}
+		}
+
+		data = data[n:]
+	}
+}
+
+// operationType returns the name, long name and full name of a type named in operation info. Names without a dot are
+// relative to the method's package.
+func operationType(name, pkg string) (string, string, string) {
+	name = strings.TrimPrefix(name, ".")
+	if name == "" {
+		return "", "", ""
+	}
+
+	if !strings.Contains(name, ".") && pkg != "" {
+		name = pkg + "." + name
+	}
+
+	return baseName(name), strings.TrimPrefix(name, pkg+"."), name
+}
@@ -0,0 +1,64 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newOperationsRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "operations.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "library.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestOperationInfo(t *testing.T) {
+	tmpl := NewTemplate(protokit.ParseCodeGenRequest(newOperationsRequest(t, "")), new(PluginOptions))
+	service := findService("Library", tmpl.Files[0])
+
+	method := findServiceMethod("ExportBook", service)
+	require.Equal(t, "google.longrunning.Operation", method.ResponseFullType)
+	require.Equal(t, &OperationInfo{
+		ResponseType:     "Book",
+		ResponseLongType: "Book",
+		ResponseFullType: "library.Book",
+		MetadataType:     "ExportMetadata",
+		MetadataLongType: "ExportMetadata",
+		MetadataFullType: "library.ExportMetadata",
+	}, method.Operation)
+	require.Equal(t, true, method.Option("deprecated"))
+
+	method = findServiceMethod("DeleteBook", service)
+	require.Equal(t, &OperationInfo{
+		ResponseType:     "Empty",
+		ResponseLongType: "google.protobuf.Empty",
+		ResponseFullType: "google.protobuf.Empty",
+	}, method.Operation)
+
+	require.Nil(t, findServiceMethod("GetBook", service).Operation)
+}
+
+func TestRenderOperationInfo(t *testing.T) {
+	expected := map[string]string{
+		"html": `<a href="#google.longrunning.Operation">.google.longrunning.Operation</a><br>Response: ` +
+			`<a href="#library.Book">Book</a><br>Metadata: <a href="#library.ExportMetadata">ExportMetadata</a></td>`,
+		"markdown": `[.google.longrunning.Operation](#google-longrunning-Operation)<br>Response: [Book](#library-Book)` +
+			`<br>Metadata: [ExportMetadata](#library-ExportMetadata) |`,
+		// types that aren't documented aren't linked, so that no link is dangling
+		"docbook": `<entry>.google.longrunning.Operation<para>Response: google.protobuf.Empty</para></entry>`,
+	}
+
+	for format, snippet := range expected {
+		resp, err := new(Plugin).Generate(newOperationsRequest(t, format+",docs"))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), snippet, format)
+	}
+}
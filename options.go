@@ -0,0 +1,227 @@
+package gendoc
+
+import (
+	"github.com/daotl/protoc-gen-doc/extensions"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// NewExtensionTypes returns a resolver for the extensions declared in files, which is used to decode the custom options
+// exposed to templates with TemplateAPIV2. Extensions that aren't declared in files are resolved from the ones linked
+// into the binary (protoregistry.GlobalTypes).
+func NewExtensionTypes(files []*descriptorpb.FileDescriptorProto) (protoregistry.ExtensionTypeResolver, error) {
+	registry, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: files})
+	if err != nil {
+		return nil, err
+	}
+
+	return extensionResolvers{dynamicpb.NewTypes(registry), protoregistry.GlobalTypes}, nil
+}
+
+// extensionResolvers resolves extensions with the first resolver that knows about them.
+type extensionResolvers []protoregistry.ExtensionTypeResolver
+
+func (r extensionResolvers) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	for _, resolver := range r {
+		if xt, err := resolver.FindExtensionByName(field); err == nil {
+			return xt, nil
+		}
+	}
+
+	return nil, protoregistry.NotFound
+}
+
+func (r extensionResolvers) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	for _, resolver := range r {
+		if xt, err := resolver.FindExtensionByNumber(message, field); err == nil {
+			return xt, nil
+		}
+	}
+
+	return nil, protoregistry.NotFound
+}
+
+// entityOptions returns the options of an entity for templates: whether it's deprecated, the idempotency level of
+// methods and the extensions with a registered transformer (see extensions.SetTransformer). With TemplateAPIV2, all
+// other options set on the entity are included too (see decodeOptions).
+func entityOptions(opts commonOptions, optionExtensions map[string]interface{}, pluginOptions *PluginOptions) map[string]interface{} {
+	if templateAPI(pluginOptions) != TemplateAPIV2 {
+		return mergeOptions(extractOptions(opts), extensions.Transform(optionExtensions))
+	}
+
+	return mergeOptions(extractOptions(opts), extensions.Transform(optionExtensions),
+		decodeOptions(opts.(proto.Message), pluginOptions))
+}
+
+// decodeOptions returns all options set in opts, keyed by field name for standard options (e.g. `java_package`) and by
+// full name for custom ones (e.g. `google.api.http`). Custom options are resolved with pluginOptions.ExtensionTypes,
+// falling back to the extensions linked into the binary; unknown ones are skipped. Enum values are given by name, and
+// messages as maps keyed by field name.
+func decodeOptions(opts proto.Message, pluginOptions *PluginOptions) map[string]interface{} {
+	resolver := pluginOptions.ExtensionTypes
+	if resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+
+	return decodeOptionsWith(opts, resolver)
+}
+
+// decodeOptionsWith returns all options set in opts like decodeOptions, resolving custom options with resolver.
+func decodeOptionsWith(opts proto.Message, resolver protoregistry.ExtensionTypeResolver) map[string]interface{} {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return nil
+	}
+
+	// Extensions that weren't known when the request was parsed are kept as unknown fields, so decode them again with
+	// the resolver
+	data, err := proto.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+
+	decoded := opts.ProtoReflect().New()
+	if err := (proto.UnmarshalOptions{Resolver: resolver}).Unmarshal(data, decoded.Interface()); err != nil {
+		return nil
+	}
+
+	if options := messageValue(decoded); len(options) > 0 {
+		return options
+	}
+
+	return nil
+}
+
+// messageValue converts m to a map of its fields, keyed by name (full name for extensions).
+func messageValue(m protoreflect.Message) map[string]interface{} {
+	out := make(map[string]interface{})
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		if fd.IsExtension() {
+			name = string(fd.FullName())
+		}
+
+		switch {
+		case fd.IsList():
+			list := make([]interface{}, 0, v.List().Len())
+			for i := 0; i < v.List().Len(); i++ {
+				list = append(list, fieldValue(fd, v.List().Get(i)))
+			}
+			out[name] = list
+		case fd.IsMap():
+			entries := make(map[string]interface{}, v.Map().Len())
+			v.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+				entries[key.String()] = fieldValue(fd.MapValue(), value)
+				return true
+			})
+			out[name] = entries
+		default:
+			out[name] = fieldValue(fd, v)
+		}
+
+		return true
+	})
+
+	return out
+}
+
+// fieldValue converts a single value of fd to a plain Go value.
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageValue(v.Message())
+	}
+
+	return v.Interface()
+}
+
+// optionTypes returns the resolver the option and hasOption template functions decode custom options with: the
+// ExtensionTypes option when set, and otherwise the extensions declared in the request's files, falling back to the
+// ones linked into the binary.
+func optionTypes(pluginOptions *PluginOptions) protoregistry.ExtensionTypeResolver {
+	switch {
+	case pluginOptions.ExtensionTypes != nil:
+		return pluginOptions.ExtensionTypes
+	case pluginOptions.descriptors != nil:
+		return extensionResolvers{dynamicpb.NewTypes(pluginOptions.descriptors), protoregistry.GlobalTypes}
+	default:
+		return protoregistry.GlobalTypes
+	}
+}
+
+// entityDescriptor returns the protoreflect descriptor of a documented file or entity, or nil when it has none.
+// Descriptors are returned as they are.
+func entityDescriptor(entity interface{}) protoreflect.Descriptor {
+	var desc protoreflect.Descriptor
+	switch e := entity.(type) {
+	case *File:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case *Message:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case *MessageField:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case *Enum:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case *EnumValue:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case *Service:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case *ServiceMethod:
+		if e != nil {
+			desc = e.Descriptor
+		}
+	case protoreflect.Descriptor:
+		desc = e
+	}
+
+	return desc
+}
+
+// optionLookup looks up the options of documented entities for the option and hasOption template functions, decoding
+// the options of each entity once.
+type optionLookup struct {
+	resolver protoregistry.ExtensionTypeResolver
+	decoded  map[protoreflect.Descriptor]map[string]interface{}
+}
+
+// get returns the option of entity with the given name: the field name of a standard option (e.g. `java_package`) or
+// the full name of a custom one (e.g. `acme.owner`), as returned by decodeOptions. The second result reports whether
+// the option is set.
+func (l *optionLookup) get(entity interface{}, name string) (interface{}, bool) {
+	desc := entityDescriptor(entity)
+	if desc == nil {
+		return nil, false
+	}
+
+	options, ok := l.decoded[desc]
+	if !ok {
+		options = decodeOptionsWith(desc.Options(), l.resolver)
+		if l.decoded == nil {
+			l.decoded = make(map[protoreflect.Descriptor]map[string]interface{})
+		}
+		l.decoded[desc] = options
+	}
+
+	value, ok := options[name]
+	return value, ok
+}
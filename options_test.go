@@ -0,0 +1,99 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newAnnotatedRequest returns a request documenting a file whose message and fields use the custom options of
+// org/annotations.proto, like an organization's own annotations. They aren't linked into the binary, so they are
+// unknown fields like they are when protoc invokes the plugin.
+func newAnnotatedRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "annotated.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "org/user.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestTemplateAPIV2IncludesStandardOptions(t *testing.T) {
+	file := newTemplateWithAPI(t, "fileset.pb", TemplateAPIV2, "google/protobuf/descriptor.proto").Files[0]
+	require.Equal(t, "com.google.protobuf", file.Option("java_package"))
+	require.Equal(t, "google.golang.org/protobuf/types/descriptorpb", file.Option("go_package"))
+	require.Equal(t, "SPEED", file.Option("optimize_for"))
+	require.Equal(t, true, file.Option("cc_enable_arenas"))
+
+	file = newTemplateWithAPI(t, "fileset.pb", TemplateAPIV1, "google/protobuf/descriptor.proto").Files[0]
+	require.Empty(t, file.Options)
+}
+
+func TestTemplateAPIV2KeepsTransformedOptions(t *testing.T) {
+	file := newTemplateWithAPI(t, "fileset.pb", TemplateAPIV2, "Booking.proto").Files[0]
+	require.Equal(t, proto.Bool(true), file.Option("com.pseudomuto.protokit.v1.extend_file"))
+
+	field := findField("color_preference", findMessage("Booking", file))
+	require.Equal(t, true, field.Option("deprecated"))
+}
+
+func TestTemplateAPIV2DecodesCustomOptions(t *testing.T) {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-options")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	templateFile := filepath.Join(dir, "custom.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(
+		`{{range .Files}}{{range .Messages}}{{.Name}}: {{with .Option "org.owner"}}{{.team}} {{index .emails 0}}{{end}}`+
+			`{{range .Fields}}, {{.Name}}{{if .Option "org.sensitive"}} (sensitive){{end}}{{end}}{{end}}{{end}}`), 0644))
+
+	resp, err := new(Plugin).Generate(newAnnotatedRequest(t, templateFile+",out.txt:template_api=v2"))
+	require.NoError(t, err)
+	require.Equal(t, "User: identity identity@example.com, id, password (sensitive)", resp.File[0].GetContent())
+
+	// v1 only includes options with a registered transformer
+	resp, err = new(Plugin).Generate(newAnnotatedRequest(t, templateFile+",out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "User: , id, password", resp.File[0].GetContent())
+}
+
+func TestNewExtensionTypesWithMissingDependency(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "annotated.pb")
+	require.NoError(t, err)
+
+	// org/annotations.proto without google/protobuf/descriptor.proto
+	_, err = NewExtensionTypes(set.File[1:2])
+	require.Error(t, err)
+}
+
+func TestOptionFuncs(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{range .Messages}}`+
+		`{{.Name}}: {{with option . "org.owner"}}{{.team}} {{index .emails 0}}{{end}} {{hasOption . "deprecated"}}`+
+		`{{range .Fields}}, {{.Name}}: {{hasOption . "org.sensitive"}} {{option . "deprecated"}}{{end}}`+
+		`{{end}}{{end}}`)
+
+	// the options are decoded without the v2 template API or any option asking for them
+	resp, err := new(Plugin).Generate(newAnnotatedRequest(t, templateFile+",output.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "User: identity identity@example.com false, id: false <no value>, password: true true",
+		resp.File[0].GetContent())
+}
+
+func TestOptionFuncsForDescriptors(t *testing.T) {
+	templateFile := writeTemplate(t, `{{range .Files}}{{option .Descriptor "go_package"}} `+
+		`{{hasOption .Descriptor "java_package"}}{{end}}`)
+
+	req := newAnnotatedRequest(t, templateFile+",output.txt")
+	req.ProtoFile[2].Options = &descriptorpb.FileOptions{GoPackage: proto.String("example.com/org")}
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Equal(t, "example.com/org false", resp.File[0].GetContent())
+}
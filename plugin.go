@@ -1,79 +1,764 @@
 package gendoc
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	html_template "html/template"
+	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/golang/protobuf/proto"
-	plugin_go "github.com/golang/protobuf/protoc-gen-go/plugin"
 	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
 // PluginOptions encapsulates options for the plugin. The type of renderer, template file, and the name of the output
 // file are included.
 type PluginOptions struct {
-	Type                RenderType
-	TemplateFile         string
-	OutputFile           string
-	ExcludePatterns      []*regexp.Regexp
-	SourceRelative       bool
-	CamelCaseFields      bool
-	ExcludeDirectives    []string // Directives for paragraph/block exclusion (default: ["@exclude"])
+	Type                  RenderType
+	TemplateFile          string
+	OutputFile            string
+	ExcludePatterns       []*regexp.Regexp
+	ExcludePackages       []*regexp.Regexp // Packages whose files are left out, matched against whole package names
+	ExcludeOptions        []ExcludeOption  // Options whose value leaves out the file or entity they're set on
+	SourceRelative        bool
+	CamelCaseFields       bool
+	ExcludeDirectives     []string // Directives for paragraph/block exclusion (default: ["@exclude"])
 	ExcludeLineDirectives []string // Directives for line-level exclusion (default: ["@exclude-line"])
+	IncludeFileSource     bool     // Append the reconstructed proto source to each file's section
+	PackageOverview       bool     // Start the built-in templates with an overview of each package's file comments
+	ShowSyntax            bool     // Show the syntax or edition (and features) of each file in the built-in templates
+	Theme                 string   // Color scheme of the HTML template: light, dark or auto (default: auto)
+	HTMLTemplate          string   // Layout of the built-in HTML template, see BuiltinTemplates (default: default)
+	Dir                   string   // Direction of the HTML page's text: ltr, rtl or auto (default: unset, i.e. ltr)
+	CSSFile               string   // Stylesheet inlined after the HTML template's default styles
+	Logo                  string   // URL of an image shown next to the HTML page title
+	HeaderFile            string   // HTML or Markdown inserted at the top of the page
+	FooterFile            string   // HTML or Markdown inserted at the bottom of the page
+	SnippetsDir           string   // Directory of the snippets inserted with @snippet directives in comments
+	ExternalAssets        bool     // Write the HTML template's stylesheet to a separate file instead of inlining it
+	AssetsURL             string   // Base URL the HTML template's stylesheet is loaded from (nothing is written)
+	Locale                string   // Language of the built-in templates' strings (default: en)
+	Parallelism           int      // Maximum number of output files rendered concurrently (default: number of CPUs)
+	CacheDir              string   // Directory used to cache rendered output between runs (disabled when empty)
+	ProfileDir            string   // Directory CPU and heap profiles of the run are written to (disabled when empty)
+	Index                 bool     // Include an alphabetical index of all entities (a separate page with source_relative)
+	CoverageThreshold     float64  // Minimum documentation coverage percentage, below which generation fails
+	LintRules             []string // Rules checked by the lint render types (default: DefaultLintRules)
+	LintMaxLineLength     int      // Maximum comment line length for the max_line_length lint rule
+	LintFail              bool     // Fail generation when comments violate any lint rule
+	Title                 string   // Title of the generated docs (default: Protocol Documentation, translated)
+	Description           string   // Description of the generated docs, shown below the title
+	Version               string   // Version of the documented API, shown below the title
+	VersionStamp          string   // Release embedded into every output, e.g. a Git tag (default: $VersionStampEnv)
+	Reproducible          bool     // Leave the generation time out of the outputs
+	MetaFile              string   // JSON file providing the title, description and version not set by options
+	CodeOwnersFile        string   // CODEOWNERS file the owners shown for each proto file are read from
+	SiteURL               string   // Base URL the HTML docs are hosted at, used for link previews and sitemap.xml
+	SourceURLFormat       string   // URL of a proto source line, with {file}, {line} and {col} placeholders
+	EditURLFormat         string   // URL editing a proto source line on the Git host, with the same placeholders
+	SanitizeHTML          string   // How markup in comments is treated: false (kept), true (escaped) or allowlist
+	TemplateSandbox       bool     // Restrict templates to functions without access to the environment or network
+	TemplateAPI           string   // Version of the data passed to custom templates: v1 or v2 (default: v1)
+	LogLevel              string   // Minimum level of the structured logs written to LogOutput (default: warn)
+	Timings               string   // Where to write a timing summary: stderr or the name of a JSON output file
+	Manifest              string   // Name of a JSON output file listing the checksum and sources of every output
+	PreviousManifest      string   // Manifest of a previous run: outputs that would stay the same are left out
+	PublishURL            string   // Endpoint the output files are posted to after rendering (see PublishAuthEnv)
+	Site                  bool     // Write the HTML docs as a static site, with a page per package and a 404 page
+	DocSearch             string   // Name of a JSON output file of records indexing the HTML docs for Algolia DocSearch
+	PublishBucket         string   // s3:// or gs:// URL the output files are uploaded below, e.g. s3://docs/{version}
+	PublishRetries        int      // Number of times publishing is retried after a transient failure (default: 3)
+	ExpandMethodTypes     bool     // Inline the fields of each method's request and response messages
+	EnumNumberFormat      string   // How enum value numbers are shown: decimal, hex or both (default: decimal)
+	IncludeImports        bool     // Also document the files imported by the files to generate, in a section of their own
+	Redact                bool     // Show excluded messages, fields and enum values as «redacted» placeholders
+	EventOption           string   // Option designating the messages published as events, e.g. company.event
+	StabilityOption       string   // Option giving the stability level of entities, e.g. company.stability
+	MinStability          string   // Least stable level included (alpha, beta or stable; default: all are included)
+	CommentLinks          bool     // Link references to other entities in comments, e.g. [Booking] (default: true)
+	StripAsterisks        bool     // Remove the leading asterisks of block comment lines from descriptions
+
+	// Processes the descriptions of the documented entities before they're rendered, e.g. a spell checker. Set with the
+	// comment_hook option, or through Options when using Generate.
+	CommentHook CommentHook
+
+	// Renders the output files in place of the format or custom template, e.g. in another language. Set with the exec:
+	// and wasm: formats (see ExecRenderPrefix and WasmRenderPrefix), or through Options when using Generate.
+	Renderer Processor
+
+	// Lines (matched from their start) and paragraphs of comments left out of descriptions, e.g. `Next id:` lines and
+	// license boilerplate.
+	StripCommentLines      []*regexp.Regexp
+	StripCommentParagraphs []*regexp.Regexp
+
+	// The DocBook documents the types of other packages are documented in, linked to with olinks (see the olink option).
+	OlinkTargets []OlinkTarget
+
+	// The descriptor sets of the released versions, from the oldest to the newest, that the version each entity first
+	// appeared in is computed from (see the since_descriptor_set option).
+	SinceDescriptorSets []SinceDescriptorSet
+
+	// The text shown for the required, optional and repeated field labels, keyed by label (see the label option).
+	Labels map[string]string
+
+	// The field options the units and ranges of fields are read from, given as the fieldMeta of the meta_file.
+	FieldMeta *FieldMetaMapping
+
+	// How the field tables show types, keyed by full name, given as the typeDisplay of the meta_file.
+	TypeDisplays map[string]*TypeDisplay
+
+	// The generation time shown in the outputs, instead of the current time. Read from $SourceDateEpochEnv by
+	// ParseOptions.
+	GeneratedAt time.Time
+
+	// The protoreflect descriptors of all files in the request, including the imported ones.
+	descriptors *protoregistry.Files
+
+	// The files of the PreviousManifest, by name.
+	previousManifest map[string]*ManifestFile
+
+	// The first version of the SinceDescriptorSets each entity appeared in, by full name.
+	sinceVersions map[string]string
+
+	// The rules of the CodeOwnersFile, in the order they're written in it.
+	codeOwners []codeOwnersRule
+
+	// The descriptions changed by the CommentHook, keyed by commentKey.
+	commentDescriptions map[string]string
+
+	// Resolves the custom options exposed to templates with template_api=v2. The plugin uses the extensions declared in
+	// the request's files (see NewExtensionTypes), and NewTemplate the ones linked into the binary when nil.
+	ExtensionTypes protoregistry.ExtensionTypeResolver
 }
 
+// VersionStampEnv is the environment variable the version stamp is read from when the version_stamp option isn't set,
+// e.g. set to the Git tag by the CI job releasing the API.
+const VersionStampEnv = "PROTOC_GEN_DOC_VERSION_STAMP"
+
+// StylesheetAsset is the path (relative to the output root) of the stylesheet written when using assets=external.
+const StylesheetAsset = "assets/protoc-gen-doc.css"
+
 // SupportedFeatures describes a flag setting for supported features.
-var SupportedFeatures = uint64(plugin_go.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+var SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
 
 // Plugin describes a protoc code generate plugin. It's an implementation of Plugin from github.com/pseudomuto/protokit
 type Plugin struct{}
 
 // Generate compiles the documentation and generates the CodeGeneratorResponse to send back to protoc. It does this
-// by rendering a template based on the options parsed from the CodeGeneratorRequest.
-func (p *Plugin) Generate(r *plugin_go.CodeGeneratorRequest) (*plugin_go.CodeGeneratorResponse, error) {
+// by rendering a template based on the options parsed from the CodeGeneratorRequest. With the reproducible option or a
+// fixed $SourceDateEpochEnv, the same request always produces byte-identical output, with files ordered by their
+// directory.
+func (p *Plugin) Generate(r *pluginpb.CodeGeneratorRequest) (*pluginpb.CodeGeneratorResponse, error) {
 	options, err := ParseOptions(r)
 	if err != nil {
 		return nil, err
 	}
+	defer closeWasmModules(options.Renderer, options.CommentHook)
+
+	files, err := generateFiles(r, options)
+	if err != nil {
+		return nil, err
+	}
 
-	result := excludeUnwantedProtos(protokit.ParseCodeGenRequest(r), options.ExcludePatterns)
+	if err := publishOutputs(files, options); err != nil {
+		return nil, err
+	}
+
+	resp := new(pluginpb.CodeGeneratorResponse)
+	for _, file := range files {
+		resp.File = append(resp.File, &pluginpb.CodeGeneratorResponse_File{
+			Name:    proto.String(file.Name),
+			Content: proto.String(file.Content),
+		})
+	}
+
+	resp.SupportedFeatures = proto.Uint64(SupportedFeatures)
+	resp.MinimumEdition = proto.Int32(900)  // Edition_EDITION_LEGACY
+	resp.MaximumEdition = proto.Int32(1001) // Edition_EDITION_2024
+
+	return resp, nil
+}
+
+// generateFiles renders the documentation for the files of req, using the options parsed from its parameter.
+func generateFiles(req *pluginpb.CodeGeneratorRequest, options *PluginOptions) ([]OutputFile, error) {
+	start := time.Now()
+	parameter := req.GetParameter()
+	fds := protokit.ParseCodeGenRequest(req)
+	parsed := time.Since(start)
+
+	log := newLogger(options.LogLevel)
+	log.debug("generating docs", "parameter", parameter, "files", len(fds))
+
+	if options.ProfileDir != "" {
+		stop, err := startProfiling(options.ProfileDir)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := stop(); err != nil {
+				log.warn("unable to write the profiles", "error", err)
+			}
+		}()
+	}
+	warnIgnoredOptions(log, options)
+
+	if options.ExtensionTypes == nil && (templateAPI(options) == TemplateAPIV2 || len(options.ExcludeOptions) > 0 ||
+		options.FieldMeta != nil || options.EventOption != "" || options.StabilityOption != "") {
+		types, err := NewExtensionTypes(req.GetProtoFile())
+		if err != nil {
+			log.warn("only the custom options linked into the binary can be decoded", "error", err)
+		} else {
+			options.ExtensionTypes = types
+		}
+	}
+
+	descriptors, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: req.GetProtoFile()})
+	if err != nil {
+		log.warn("the types imported by the documented files can't be resolved in templates", "error", err)
+	} else {
+		options.descriptors = descriptors
+	}
+
+	result := excludeUnwantedProtos(log, fds, options)
+
+	if options.CoverageThreshold > 0 || options.LintFail {
+		template := NewTemplate(result, options)
+		if options.CoverageThreshold > 0 {
+			if err := checkCoverage(template, options.CoverageThreshold); err != nil {
+				return nil, err
+			}
+		}
+
+		if options.LintFail {
+			if err := checkLint(template); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	customTemplate := ""
 
 	if options.TemplateFile != "" {
-		data, err := ioutil.ReadFile(options.TemplateFile)
+		data, err := os.ReadFile(options.TemplateFile)
 		if err != nil {
 			return nil, err
 		}
 
 		customTemplate = string(data)
+	} else if customTemplate, err = options.Type.registeredTemplate(); err != nil {
+		return nil, err
 	}
 
-	resp := new(plugin_go.CodeGeneratorResponse)
-	fdsGroup := groupProtosByDirectory(result, options.SourceRelative)
-	for dir, fds := range fdsGroup {
-		template := NewTemplate(fds, options)
+	snippets, err := readSnippets(options)
+	if err != nil {
+		return nil, err
+	}
+
+	themeCSS := ""
 
-		output, err := RenderTemplate(options.Type, template, customTemplate)
+	if options.CSSFile != "" {
+		data, err := os.ReadFile(options.CSSFile)
 		if err != nil {
 			return nil, err
 		}
 
-		resp.File = append(resp.File, &plugin_go.CodeGeneratorResponse_File{
-			Name:    proto.String(filepath.Join(dir, options.OutputFile)),
-			Content: proto.String(string(output)),
-		})
+		themeCSS = string(data)
 	}
 
-	resp.SupportedFeatures = proto.Uint64(SupportedFeatures)
-	resp.MinimumEdition = proto.Int32(900)  // Edition_EDITION_LEGACY
-	resp.MaximumEdition = proto.Int32(1001) // Edition_EDITION_2024
+	documented := result
+	var imported map[string]bool
+	if options.IncludeImports {
+		imports := excludeUnwantedProtos(log, parseImportedFiles(req), options)
+		imported = make(map[string]bool, len(imports))
+		for _, f := range imports {
+			imported[f.GetName()] = true
+		}
+		documented = append(documented, imports...)
+	}
 
-	return resp, nil
+	var commentFindings []*LintFinding
+	if options.CommentHook != nil {
+		commentFindings, err = applyCommentHook(NewTemplate(documented, options), options)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, finding := range commentFindings {
+			log.warn(finding.Message, "file", finding.File, "kind", finding.Kind, "name", finding.Name)
+		}
+	}
+
+	var fdsGroup map[string][]*protokit.FileDescriptor
+	if hasWiki(options) {
+		fdsGroup = groupProtosByPackage(documented)
+	} else if hasSite(options) {
+		fdsGroup = groupProtosBySitePackage(documented)
+	} else {
+		fdsGroup = groupProtosByDirectory(documented, options.SourceRelative)
+	}
+	dirs := sortedDirectories(fdsGroup)
+	outputs := make([]string, len(dirs))
+
+	groups := &groupRenderer{
+		options:        options,
+		parameter:      parameter,
+		customTemplate: customTemplate,
+		themeCSS:       themeCSS,
+		snippets:       snippets,
+		imported:       imported,
+		log:            log,
+	}
+	if options.Timings != "" {
+		groups.timings = new(timingCollector)
+	}
+	if options.CacheDir != "" {
+		groups.cache = &outputCache{dir: options.CacheDir}
+	}
+	if options.CacheDir != "" || options.Manifest != "" || options.previousManifest != nil {
+		if groups.protoFiles, err = protoFilesKey(req.GetProtoFile()); err != nil {
+			return nil, err
+		}
+	}
+	if hasWiki(options) {
+		groups.wiki = newWikiSite(fdsGroup, options)
+	} else if options.SourceRelative {
+		groups.pages = typePages(fdsGroup, options)
+	}
+	if hasSite(options) {
+		groups.site = newSite(fdsGroup, groups.pages, options)
+	}
+
+	keys := make([]string, len(dirs))
+	unchanged := make([]*ManifestFile, len(dirs))
+	err = forEachParallel(len(dirs), options.Parallelism, func(i int) error {
+		if options.Manifest != "" || options.previousManifest != nil {
+			key, err := groups.key(dirs[i], fdsGroup[dirs[i]])
+			if err != nil {
+				return err
+			}
+			keys[i] = key
+
+			previous := options.previousManifest[outputName(options, dirs[i])]
+			if previous != nil && previous.Key == key {
+				log.debug("skipping unchanged output", "dir", dirs[i])
+				unchanged[i] = previous
+				return nil
+			}
+		}
+
+		output, err := groups.render(dirs[i], fdsGroup[dirs[i]])
+		outputs[i] = output
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]OutputFile, 0, len(dirs)+2)
+	sources := make(map[string][]string, len(dirs))
+	outputKeys := make(map[string]string, len(dirs))
+	skipped := make([]*ManifestFile, 0)
+	for i, dir := range dirs {
+		if unchanged[i] != nil {
+			skipped = append(skipped, unchanged[i])
+			continue
+		}
+
+		name := outputName(options, dir)
+		files = append(files, OutputFile{Name: name, Content: outputs[i]})
+		sources[name] = protoNames(fdsGroup[dir])
+		outputKeys[name] = keys[i]
+	}
+
+	if hasAvro(options) {
+		for _, dir := range dirs {
+			schemas, err := avroSchemaFiles(NewTemplate(fdsGroup[dir], options), dir)
+			if err != nil {
+				return nil, err
+			}
+			for _, schema := range schemas {
+				sources[schema.Name] = protoNames(fdsGroup[dir])
+			}
+			files = append(files, schemas...)
+		}
+	}
+
+	if hasIndex(options) && options.SourceRelative {
+		output, err := groups.renderIndexPage(fdsGroup, dirs)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, OutputFile{Name: indexPageName(options.OutputFile), Content: output})
+	}
+
+	if groups.wiki != nil {
+		files = append(files,
+			OutputFile{Name: options.OutputFile, Content: groups.wiki.renderHome()},
+			OutputFile{Name: wikiPageFile(WikiSidebarPage), Content: groups.wiki.renderSidebar()},
+			OutputFile{Name: wikiPageFile(WikiFooterPage), Content: groups.wiki.renderFooter()},
+		)
+	}
+
+	if hasTocTree(options) {
+		files = append(files, OutputFile{Name: TocTreeFile, Content: renderTocTree(options, files)})
+	}
+
+	if groups.site != nil {
+		output, err := groups.renderSitePage(groups.site.Home, false)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, OutputFile{Name: groups.site.Home, Content: output})
+	}
+
+	if hasSitemap(options) {
+		files = append(files, OutputFile{Name: SitemapFile, Content: renderSitemap(options, files)})
+	}
+
+	if hasDocSearch(options) {
+		content, err := renderDocSearch(docSearchRecords(fdsGroup, dirs, options))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, OutputFile{Name: options.DocSearch, Content: content})
+	}
+
+	if groups.site != nil {
+		output, err := groups.renderSitePage(SiteNotFoundPage, true)
+		if err != nil {
+			return nil, err
+		}
+
+		vercel, err := renderVercelConfig(groups.site)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files,
+			OutputFile{Name: SiteNotFoundPage, Content: output},
+			OutputFile{Name: NetlifyRedirectsFile, Content: renderNetlifyRedirects(groups.site)},
+			OutputFile{Name: VercelConfigFile, Content: vercel},
+		)
+	}
+
+	if options.ExternalAssets && options.AssetsURL == "" {
+		files = append(files, OutputFile{Name: StylesheetAsset, Content: string(htmlCSS)})
+		sources[StylesheetAsset] = nil
+	}
+
+	if len(commentFindings) > 0 {
+		content, err := json.MarshalIndent(commentFindings, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, OutputFile{Name: CommentFindingsFile, Content: string(content)})
+	}
+
+	if groups.timings != nil {
+		timings := groups.timings.summary(parsed, time.Since(start))
+		if options.Timings == TimingsStderr {
+			if err := writeTimings(LogOutput, timings); err != nil {
+				return nil, err
+			}
+		} else {
+			content, err := renderTimings(timings)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, OutputFile{Name: options.Timings, Content: content})
+		}
+	}
+
+	if options.Manifest != "" {
+		content, err := renderManifest(files, skipped, sources, outputKeys, protoNames(documented))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, OutputFile{Name: options.Manifest, Content: content})
+	}
+
+	log.info("generated docs", "files", len(result), "excluded", len(fds)-len(result), "outputs", len(files),
+		"duration", time.Since(start))
+	return files, nil
+}
+
+// warnIgnoredOptions logs the options that have no effect on the selected output format.
+func warnIgnoredOptions(log *logger, options *PluginOptions) {
+	if options.Index && !hasIndex(options) {
+		log.warn("the index option is ignored by this format", "option", "index")
+	}
+
+	if options.SiteURL != "" && !hasSitemap(options) {
+		log.warn("the site_url option is ignored by this format", "option", "site_url")
+	}
+
+	if options.DocSearch != "" && !hasDocSearch(options) {
+		log.warn("the docsearch option is ignored by this format", "option", "docsearch")
+	}
+
+	if options.HTMLTemplate != "" && (options.Type != RenderTypeHTML || options.TemplateFile != "") {
+		log.warn("the template option only applies to the built-in HTML template", "option", "template")
+	}
+
+	if options.Dir != "" && (options.Type != RenderTypeHTML || options.TemplateFile != "") {
+		log.warn("the dir option only applies to the built-in HTML template", "option", "dir")
+	}
+
+	if options.HTMLTemplate != "" && options.HTMLTemplate != HTMLTemplateDefault &&
+		(options.ExternalAssets || options.AssetsURL != "") {
+		log.warn("the assets options only apply to the default HTML template", "option", "assets")
+	}
+
+	if (options.HeaderFile != "" || options.FooterFile != "" || options.SnippetsDir != "") && !hasSnippets(options) {
+		log.warn("the header_file, footer_file and snippets_dir options only apply to the HTML and Markdown formats",
+			"option", "snippets")
+	}
+
+	if options.Site && !hasSite(options) {
+		log.warn("the site option only applies to the built-in HTML template", "option", "site")
+	}
+
+	if options.SourceRelative && hasWiki(options) {
+		log.warn("the source_relative flag is ignored by this format", "option", "source_relative")
+	}
+
+	if options.TemplateAPI == TemplateAPIV2 && options.TemplateFile == "" {
+		log.warn("the template_api option is ignored by built-in formats", "option", "template_api")
+	}
+}
+
+// groupRenderer renders the output file for a group of files written to the same directory.
+type groupRenderer struct {
+	options        *PluginOptions
+	parameter      string
+	customTemplate string
+	themeCSS       string
+	snippets       *Snippets
+	imported       map[string]bool
+	wiki           *wikiSite
+	pages          map[string]string
+	site           *Site
+	cache          *outputCache
+	protoFiles     string // the key of all the files of the request, when outputs are keyed
+	log            *logger
+	timings        *timingCollector
+}
+
+// key returns the key of the output rendered for a group of files, which changes whenever anything the output depends
+// on does.
+func (g *groupRenderer) key(dir string, fds []*protokit.FileDescriptor) (string, error) {
+	inputs := []string{g.parameter, g.customTemplate, g.themeCSS, g.snippets.key(), g.protoFiles,
+		fmt.Sprintf("%+v", g.options.FieldMeta)}
+	if g.wiki != nil {
+		inputs = append(inputs, g.wiki.key())
+	}
+	if g.pages != nil {
+		inputs = append(inputs, stringMapKey(g.pages))
+	}
+	if g.site != nil {
+		inputs = append(inputs, g.site.key())
+	}
+	if g.options.commentDescriptions != nil {
+		inputs = append(inputs, stringMapKey(g.options.commentDescriptions))
+	}
+	if g.options.TypeDisplays != nil {
+		displays, _ := json.Marshal(g.options.TypeDisplays)
+		inputs = append(inputs, string(displays))
+	}
+	if g.options.MetaFile != "" {
+		// the title, description and version may come from the meta_file rather than the parameter
+		inputs = append(inputs, g.options.Title, g.options.Description, g.options.Version)
+	}
+	if g.options.VersionStamp != "" {
+		// the stamp may come from the environment rather than the parameter
+		inputs = append(inputs, g.options.VersionStamp)
+	}
+	if !g.options.GeneratedAt.IsZero() {
+		// a fixed generation time may come from the environment as well. The current time is left out, so cached and
+		// skipped outputs keep the time they were first generated at (see the reproducible option)
+		inputs = append(inputs, g.options.GeneratedAt.UTC().Format(time.RFC3339))
+	}
+	if g.options.codeOwners != nil {
+		inputs = append(inputs, codeOwnersKey(g.options.codeOwners))
+	}
+	if g.options.sinceVersions != nil {
+		// the descriptor sets may change without the parameter changing
+		inputs = append(inputs, stringMapKey(g.options.sinceVersions))
+	}
+	if r, ok := g.options.Renderer.(*execRenderer); ok {
+		// the command may be rebuilt at the same path
+		inputs = append(inputs, r.hash)
+	}
+	if r, ok := g.options.Renderer.(*wasmRenderer); ok {
+		// the module may be rebuilt at the same path
+		inputs = append(inputs, r.module.hash)
+	}
+	if h, ok := g.options.CommentHook.(*wasmCommentHook); ok {
+		inputs = append(inputs, h.module.hash)
+	}
+
+	return outputKey(dir, fds, inputs...)
+}
+
+func (g *groupRenderer) render(dir string, fds []*protokit.FileDescriptor) (string, error) {
+	name := outputName(g.options, dir)
+	cacheKey := ""
+	if g.cache != nil {
+		key, err := g.key(dir, fds)
+		if err != nil {
+			return "", err
+		}
+
+		if output, ok := g.cache.get(key); ok {
+			g.log.debug("using cached output", "dir", dir)
+			g.timings.addOutput(&OutputTiming{Name: name, Files: len(fds), Cached: true})
+			return string(output), nil
+		}
+		cacheKey = key
+	}
+
+	start := time.Now()
+	template := newTemplate(fds, g.options, g.timings.fileObserver(name))
+	template.markImported(g.imported)
+	if g.wiki != nil {
+		template.WikiPages = g.wiki.pages
+	}
+	template.URL = pageURL(g.options, name)
+	template.Page = filepath.ToSlash(name)
+	template.Pages = g.pages
+	template.Site = g.site
+	if hasIndex(g.options) && !g.options.SourceRelative {
+		template.Index = template.index("")
+	}
+	built := time.Since(start)
+
+	if g.log.enabled(LogLevelDebug) {
+		for _, f := range template.Files {
+			g.log.debug("documenting file", "dir", dir, "file", f.Name, "package", f.Package, "messages", len(f.AllMessages()),
+				"enums", len(f.AllEnums()), "services", len(f.Services), "extensions", len(f.Extensions))
+		}
+	}
+
+	output, err := g.renderTemplate(dir, template)
+	if err != nil {
+		return "", err
+	}
+	g.log.debug("rendered output", "dir", dir, "files", len(template.Files), "bytes", len(output),
+		"duration", time.Since(start))
+	g.timings.addOutput(&OutputTiming{
+		Name:   name,
+		Files:  len(fds),
+		Build:  milliseconds(built),
+		Render: milliseconds(time.Since(start) - built),
+	})
+
+	if g.cache != nil {
+		if err := g.cache.put(cacheKey, []byte(output)); err != nil {
+			return "", err
+		}
+	}
+
+	return output, nil
+}
+
+// renderIndexPage renders the separate index page written to the output root in source_relative mode. The page uses
+// the same template as all other pages, but has no files of its own.
+func (g *groupRenderer) renderIndexPage(fdsGroup map[string][]*protokit.FileDescriptor, dirs []string) (string, error) {
+	start := time.Now()
+	files := 0
+	entries := make([]*IndexEntry, 0)
+	for _, dir := range dirs {
+		page := path.Join(filepath.ToSlash(dir), g.options.OutputFile)
+		entries = append(entries, NewTemplate(fdsGroup[dir], g.options).index(page)...)
+		files += len(fdsGroup[dir])
+	}
+	sortIndex(entries)
+
+	name := indexPageName(g.options.OutputFile)
+	template := NewTemplate(nil, g.options)
+	template.URL = pageURL(g.options, name)
+	template.Page = filepath.ToSlash(name)
+	template.Pages = g.pages
+	template.Site = g.site
+	template.Index = entries
+	built := time.Since(start)
+
+	output, err := g.renderTemplate("./", template)
+	g.timings.addOutput(&OutputTiming{
+		Name:   name,
+		Files:  files,
+		Build:  milliseconds(built),
+		Render: milliseconds(time.Since(start) - built),
+	})
+
+	return output, err
+}
+
+// renderSitePage renders the home page or the 404 page of the site, written to name. The pages use the same template
+// as all other pages, but have no files of their own.
+func (g *groupRenderer) renderSitePage(name string, notFound bool) (string, error) {
+	start := time.Now()
+	site := *g.site
+	site.NotFound = notFound
+
+	template := NewTemplate(nil, g.options)
+	template.URL = pageURL(g.options, name)
+	template.Page = filepath.ToSlash(name)
+	template.Pages = g.pages
+	template.Site = &site
+
+	output, err := g.renderTemplate("./", template)
+	g.timings.addOutput(&OutputTiming{Name: name, Render: milliseconds(time.Since(start))})
+
+	return output, err
+}
+
+// renderTemplate applies the page settings for an output file written to dir and renders template.
+func (g *groupRenderer) renderTemplate(dir string, template *Template) (string, error) {
+	template.Theme.CSS = html_template.CSS(g.themeCSS)
+	template.Snippets = g.snippets
+	if g.options.ExternalAssets && g.options.AssetsURL == "" {
+		template.Assets.StylesheetURL = relativeAssetURL(dir, StylesheetAsset)
+	}
+
+	// Render straight into the string that ends up in the response to avoid copying (potentially huge) outputs.
+	var output strings.Builder
+	if g.options.Renderer != nil {
+		if err := renderWith(&output, g.options.Renderer, template); err != nil {
+			return "", err
+		}
+
+		return output.String(), nil
+	}
+
+	if err := RenderTemplateTo(&output, g.options.Type, template, g.customTemplate); err != nil {
+		var templateErr *TemplateError
+		if errors.As(err, &templateErr) && templateErr.Name == "" && g.options.TemplateFile != "" {
+			templateErr.Name = g.options.TemplateFile
+		}
+
+		return "", err
+	}
+
+	return output.String(), nil
 }
 
 func groupProtosByDirectory(fds []*protokit.FileDescriptor, sourceRelative bool) map[string][]*protokit.FileDescriptor {
@@ -92,17 +777,91 @@ func groupProtosByDirectory(fds []*protokit.FileDescriptor, sourceRelative bool)
 	return fdsGroup
 }
 
-func excludeUnwantedProtos(fds []*protokit.FileDescriptor, excludePatterns []*regexp.Regexp) []*protokit.FileDescriptor {
+// sortedDirectories returns the directories of a grouping in alphabetical order so that output files are always
+// generated (and returned to protoc) in the same order.
+func sortedDirectories(fdsGroup map[string][]*protokit.FileDescriptor) []string {
+	dirs := make([]string, 0, len(fdsGroup))
+	for dir := range fdsGroup {
+		dirs = append(dirs, dir)
+	}
+
+	sort.Strings(dirs)
+	return dirs
+}
+
+// forEachParallel calls fn for every index in [0, n) using at most workers goroutines (runtime.NumCPU() when workers
+// is not positive). If any call fails, the error of the lowest failing index is returned.
+func forEachParallel(n, workers int, fn func(i int) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > n {
+		workers = n
+	}
+
+	errs := make([]error, n)
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relativeAssetURL returns the URL of an asset (relative to the output root) as seen from a page written to dir.
+func relativeAssetURL(dir, asset string) string {
+	rel, err := filepath.Rel(dir, filepath.FromSlash(asset))
+	if err != nil {
+		return asset
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func excludeUnwantedProtos(log *logger, fds []*protokit.FileDescriptor, options *PluginOptions) []*protokit.FileDescriptor {
 	descs := make([]*protokit.FileDescriptor, 0)
 
 OUTER:
 	for _, d := range fds {
-		for _, p := range excludePatterns {
+		for _, p := range options.ExcludePatterns {
 			if p.MatchString(d.GetName()) {
+				log.debug("excluded file", "file", d.GetName(), "pattern", p.String())
+				continue OUTER
+			}
+		}
+
+		for _, p := range options.ExcludePackages {
+			if p.MatchString(d.GetPackage()) {
+				log.debug("excluded file", "file", d.GetName(), "package", d.GetPackage())
 				continue OUTER
 			}
 		}
 
+		if excludedByOption(d.GetOptions(), options) {
+			log.debug("excluded file", "file", d.GetName(), "reason", "option")
+			continue
+		}
+
 		descs = append(descs, d)
 	}
 
@@ -114,18 +873,30 @@ OUTER:
 //
 // The parameter (`--doc_opt`) must be of the format <TYPE|TEMPLATE_FILE>,<OUTPUT_FILE>[,default|source_relative]:<OPTION>,<OPTION>*.
 // The file will be written to the directory specified with the `--doc_out` argument to protoc.
-func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
+func ParseOptions(req *pluginpb.CodeGeneratorRequest) (*PluginOptions, error) {
 	options := &PluginOptions{
-		Type:                RenderTypeHTML,
-		TemplateFile:         "",
-		OutputFile:           "index.html",
-		SourceRelative:       false,
-		CamelCaseFields:      false,
-		ExcludeDirectives:    []string{"@exclude"},
+		Type:                  RenderTypeHTML,
+		TemplateFile:          "",
+		OutputFile:            "index.html",
+		SourceRelative:        false,
+		CamelCaseFields:       false,
+		ExcludeDirectives:     []string{"@exclude"},
 		ExcludeLineDirectives: []string{"@exclude-line"},
+		Theme:                 "auto",
+		Locale:                DefaultLocale,
+		SanitizeHTML:          SanitizeHTMLOff,
+		TemplateAPI:           TemplateAPIV1,
+		LogLevel:              LogLevelWarn,
+		CommentLinks:          true,
+		PublishRetries:        DefaultPublishRetries,
 	}
 
+	var err error
 	params := strings.Split(req.GetParameter(), "\n")[0]
+	external := strings.HasPrefix(params, ExecRenderPrefix)
+	params = strings.TrimPrefix(params, ExecRenderPrefix)
+	wasm := strings.HasPrefix(params, WasmRenderPrefix)
+	params = strings.TrimPrefix(params, WasmRenderPrefix)
 	colonParts := strings.SplitN(params, ":", 2)
 	fileParams := colonParts[0]
 	if len(colonParts) == 2 {
@@ -143,14 +914,227 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 				currentOption = key
 				switch key {
 				case "camel_case_fields":
+					if options.CamelCaseFields, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "comment_links":
+					if options.CommentLinks, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "include_file_source":
+					if options.IncludeFileSource, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "package_overview":
+					if options.PackageOverview, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "show_syntax":
+					if options.ShowSyntax, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "theme":
 					switch value {
-					case "true":
-						options.CamelCaseFields = true
-					case "false":
-						options.CamelCaseFields = false
+					case "light", "dark", "auto":
+						options.Theme = value
 					default:
-						return nil, fmt.Errorf("Invalid camel_case_fields value: %v", value)
+						return nil, fmt.Errorf("Invalid theme value: %v", value)
+					}
+				case "template":
+					if !isHTMLTemplate(value) {
+						return nil, fmt.Errorf("Invalid template value: %v", value)
+					}
+					options.HTMLTemplate = value
+				case "dir":
+					if value != "ltr" && value != "rtl" && value != "auto" {
+						return nil, fmt.Errorf("Invalid dir value: %v", value)
 					}
+					options.Dir = value
+				case "css_file":
+					options.CSSFile = value
+				case "logo":
+					options.Logo = value
+				case "header_file":
+					options.HeaderFile = value
+				case "footer_file":
+					options.FooterFile = value
+				case "snippets_dir":
+					options.SnippetsDir = value
+				case "assets":
+					switch value {
+					case "inline":
+						options.ExternalAssets = false
+					case "external":
+						options.ExternalAssets = true
+					default:
+						return nil, fmt.Errorf("Invalid assets value: %v", value)
+					}
+				case "assets_url":
+					options.AssetsURL = value
+				case "parallelism":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 1 {
+						return nil, fmt.Errorf("Invalid parallelism value: %v", value)
+					}
+					options.Parallelism = n
+				case "cache_dir":
+					options.CacheDir = value
+				case "profile_dir":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid profile_dir value: %v", value)
+					}
+					options.ProfileDir = value
+				case "expand_method_types":
+					if options.ExpandMethodTypes, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "enum_number_format":
+					if !isEnumNumberFormat(value) {
+						return nil, fmt.Errorf("Invalid enum_number_format value: %v", value)
+					}
+					options.EnumNumberFormat = value
+				case "label":
+					label, text, err := parseLabelOption(value)
+					if err != nil {
+						return nil, err
+					}
+					if options.Labels == nil {
+						options.Labels = make(map[string]string)
+					}
+					options.Labels[label] = text
+				case "olink":
+					target, err := parseOlinkTarget(value)
+					if err != nil {
+						return nil, err
+					}
+					options.OlinkTargets = append(options.OlinkTargets, target)
+				case "since_descriptor_set":
+					set, err := parseSinceDescriptorSet(value)
+					if err != nil {
+						return nil, err
+					}
+					options.SinceDescriptorSets = append(options.SinceDescriptorSets, set)
+				case "include_imports":
+					if options.IncludeImports, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "index":
+					if options.Index, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "site":
+					if options.Site, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "coverage_threshold":
+					threshold, err := strconv.ParseFloat(value, 64)
+					if err != nil || threshold < 0 || threshold > 100 {
+						return nil, fmt.Errorf("Invalid coverage_threshold value: %v", value)
+					}
+					options.CoverageThreshold = threshold
+				case "lint_rule":
+					if !isLintRule(value) {
+						return nil, fmt.Errorf("Invalid lint_rule value: %v", value)
+					}
+					options.LintRules = append(options.LintRules, value)
+				case "lint_max_line_length":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 1 {
+						return nil, fmt.Errorf("Invalid lint_max_line_length value: %v", value)
+					}
+					options.LintMaxLineLength = n
+				case "lint_fail":
+					if options.LintFail, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "title":
+					options.Title = value
+				case "description":
+					options.Description = value
+				case "version":
+					options.Version = value
+				case "version_stamp":
+					options.VersionStamp = value
+				case "reproducible":
+					if options.Reproducible, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "meta_file":
+					options.MetaFile = value
+				case "codeowners_file":
+					options.CodeOwnersFile = value
+				case "site_url":
+					options.SiteURL = value
+				case "source_url_format":
+					if !isSourceURLFormat(value) {
+						return nil, fmt.Errorf("Invalid source_url_format value: %v", value)
+					}
+					options.SourceURLFormat = value
+				case "edit_url_format":
+					if !isSourceURLFormat(value) {
+						return nil, fmt.Errorf("Invalid edit_url_format value: %v", value)
+					}
+					options.EditURLFormat = value
+				case "manifest":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid manifest value: %v", value)
+					}
+					options.Manifest = value
+				case "docsearch":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid docsearch value: %v", value)
+					}
+					options.DocSearch = value
+				case "previous_manifest":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid previous_manifest value: %v", value)
+					}
+					options.PreviousManifest = value
+				case "publish_url":
+					if !isPublishURL(value) {
+						return nil, fmt.Errorf("Invalid publish_url value: %v", value)
+					}
+					options.PublishURL = value
+				case "publish_bucket":
+					if !isBucketURL(value) {
+						return nil, fmt.Errorf("Invalid publish_bucket value: %v", value)
+					}
+					options.PublishBucket = value
+				case "publish_retries":
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 0 {
+						return nil, fmt.Errorf("Invalid publish_retries value: %v", value)
+					}
+					options.PublishRetries = n
+				case "timings":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid timings value: %v", value)
+					}
+					options.Timings = value
+				case "log_level":
+					if !isLogLevel(value) {
+						return nil, fmt.Errorf("Invalid log_level value: %v", value)
+					}
+					options.LogLevel = value
+				case "template_sandbox":
+					if options.TemplateSandbox, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "template_api":
+					if !isTemplateAPI(value) {
+						return nil, fmt.Errorf("Invalid template_api value: %v", value)
+					}
+					options.TemplateAPI = value
+				case "sanitize_html":
+					if !isSanitizeHTMLMode(value) {
+						return nil, fmt.Errorf("Invalid sanitize_html value: %v", value)
+					}
+					options.SanitizeHTML = value
+				case "locale":
+					if !hasLocale(value) {
+						return nil, fmt.Errorf("Invalid locale value: %v", value)
+					}
+					options.Locale = value
 				case "exclude_patterns":
 					if value != "" {
 						r, err := regexp.Compile(value)
@@ -159,6 +1143,68 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 						}
 						options.ExcludePatterns = append(options.ExcludePatterns, r)
 					}
+				case "exclude_packages":
+					if value != "" {
+						r, err := compilePackagePattern(value)
+						if err != nil {
+							return nil, err
+						}
+						options.ExcludePackages = append(options.ExcludePackages, r)
+					}
+				case "exclude_option":
+					excludeOption, err := parseExcludeOption(value)
+					if err != nil {
+						return nil, err
+					}
+					options.ExcludeOptions = append(options.ExcludeOptions, excludeOption)
+				case "event_option":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid event_option value: %v", value)
+					}
+					options.EventOption = value
+				case "stability_option":
+					if value == "" {
+						return nil, fmt.Errorf("Invalid stability_option value: %v", value)
+					}
+					options.StabilityOption = value
+				case "min_stability":
+					if !isStability(value) {
+						return nil, fmt.Errorf("Invalid min_stability value: %v", value)
+					}
+					options.MinStability = value
+				case "redact":
+					if options.Redact, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "comment_hook":
+					if strings.TrimSpace(value) == "" {
+						return nil, fmt.Errorf("Invalid comment_hook value: %v", value)
+					}
+					if strings.HasPrefix(value, WasmRenderPrefix) {
+						module, err := loadWasmModule(strings.TrimPrefix(value, WasmRenderPrefix))
+						if err != nil {
+							return nil, err
+						}
+						options.CommentHook = &wasmCommentHook{module: module}
+					} else {
+						options.CommentHook = &commandCommentHook{command: value}
+					}
+				case "strip_asterisks":
+					if options.StripAsterisks, err = parseBoolOption(key, value); err != nil {
+						return nil, err
+					}
+				case "strip_comment_lines":
+					r, err := compileCommentLinePattern(value)
+					if err != nil || value == "" {
+						return nil, fmt.Errorf("Invalid strip_comment_lines value: %v", value)
+					}
+					options.StripCommentLines = append(options.StripCommentLines, r)
+				case "strip_comment_paragraphs":
+					r, err := regexp.Compile(value)
+					if err != nil || value == "" {
+						return nil, fmt.Errorf("Invalid strip_comment_paragraphs value: %v", value)
+					}
+					options.StripCommentParagraphs = append(options.StripCommentParagraphs, r)
 				case "exclude_directive":
 					if value != "" {
 						options.ExcludeDirectives = append(options.ExcludeDirectives, value)
@@ -180,10 +1226,49 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 				options.ExcludePatterns = append(options.ExcludePatterns, r)
 				continue
 			}
+			if currentOption == "exclude_packages" {
+				r, err := compilePackagePattern(token)
+				if err != nil {
+					return nil, err
+				}
+				options.ExcludePackages = append(options.ExcludePackages, r)
+				continue
+			}
 			return nil, fmt.Errorf("Invalid option: %v", token)
 		}
 	}
+	if options.MetaFile != "" {
+		if err = readMetaFile(options); err != nil {
+			return nil, err
+		}
+	}
+	if options.VersionStamp == "" {
+		options.VersionStamp = os.Getenv(VersionStampEnv)
+	}
+	if epoch := os.Getenv(SourceDateEpochEnv); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s value: %v", SourceDateEpochEnv, epoch)
+		}
+		options.GeneratedAt = time.Unix(seconds, 0)
+	}
+	if options.CodeOwnersFile != "" {
+		if options.codeOwners, err = readCodeOwners(options.CodeOwnersFile); err != nil {
+			return nil, err
+		}
+	}
+	if options.PreviousManifest != "" {
+		if options.previousManifest, err = readManifest(options.PreviousManifest); err != nil {
+			return nil, err
+		}
+	}
+	if len(options.SinceDescriptorSets) > 0 {
+		if options.sinceVersions, err = readSinceVersions(options.SinceDescriptorSets); err != nil {
+			return nil, err
+		}
+	}
 	if fileParams == "" {
+		applySiteDefaults(options)
 		return options, nil
 	}
 
@@ -210,11 +1295,67 @@ func ParseOptions(req *plugin_go.CodeGeneratorRequest) (*PluginOptions, error) {
 	}
 	options.SourceRelative = len(parts) > 2 && parts[2] == "source_relative"
 
-	renderType, err := NewRenderType(options.TemplateFile)
-	if err == nil {
+	if external {
+		if strings.TrimSpace(options.TemplateFile) == "" {
+			return nil, fmt.Errorf("Invalid parameter: %s", fileParams)
+		}
+		options.Renderer = newExecRenderer(options.TemplateFile)
+		options.TemplateFile = ""
+	} else if wasm {
+		module, err := loadWasmModule(options.TemplateFile)
+		if err != nil {
+			return nil, err
+		}
+		options.Renderer = &wasmRenderer{module: module}
+		options.TemplateFile = ""
+	} else if renderType, err := NewRenderType(options.TemplateFile); err == nil {
 		options.Type = renderType
 		options.TemplateFile = ""
 	}
+	applySiteDefaults(options)
 
 	return options, nil
 }
+
+// readMetaFile fills in the title, description and version from options.MetaFile, unless they were set by options,
+// the field options mapping of its fieldMeta key and the type displays of its typeDisplay key.
+func readMetaFile(options *PluginOptions) error {
+	data, err := os.ReadFile(options.MetaFile)
+	if err != nil {
+		return err
+	}
+
+	meta := new(struct {
+		Meta
+		FieldMeta    *FieldMetaMapping       `json:"fieldMeta"`
+		TypeDisplays map[string]*TypeDisplay `json:"typeDisplay"`
+	})
+	if err := json.Unmarshal(data, meta); err != nil {
+		return fmt.Errorf("Invalid meta_file %s: %v", options.MetaFile, err)
+	}
+
+	if options.Title == "" {
+		options.Title = meta.Title
+	}
+	if options.Description == "" {
+		options.Description = meta.Description
+	}
+	if options.Version == "" {
+		options.Version = meta.Version
+	}
+	options.FieldMeta = meta.FieldMeta
+	options.TypeDisplays = normalizeTypeDisplays(meta.TypeDisplays)
+
+	return nil
+}
+
+func parseBoolOption(key, value string) (bool, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("Invalid %s value: %v", key, value)
+}
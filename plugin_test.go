@@ -1,14 +1,15 @@
 package gendoc_test
 
 import (
+	"os"
 	"regexp"
 	"testing"
 
 	. "github.com/daotl/protoc-gen-doc"
-	"github.com/golang/protobuf/proto"
-	plugin_go "github.com/golang/protobuf/protoc-gen-go/plugin"
 	"github.com/pseudomuto/protokit/utils"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
 func TestParseOptionsForBuiltinTemplates(t *testing.T) {
@@ -17,10 +18,11 @@ func TestParseOptionsForBuiltinTemplates(t *testing.T) {
 		"html":     "output.html",
 		"json":     "output.json",
 		"markdown": "output.md",
+		"coverage": "coverage.txt",
 	}
 
 	for kind, file := range results {
-		req := new(plugin_go.CodeGeneratorRequest)
+		req := new(pluginpb.CodeGeneratorRequest)
 		req.Parameter = proto.String(kind + "," + file)
 
 		options, err := ParseOptions(req)
@@ -36,7 +38,7 @@ func TestParseOptionsForBuiltinTemplates(t *testing.T) {
 }
 
 func TestParseOptionsForSourceRelative(t *testing.T) {
-	req := new(plugin_go.CodeGeneratorRequest)
+	req := new(pluginpb.CodeGeneratorRequest)
 	req.Parameter = proto.String("markdown,index.md,source_relative")
 	options, err := ParseOptions(req)
 	require.NoError(t, err)
@@ -54,7 +56,7 @@ func TestParseOptionsForSourceRelative(t *testing.T) {
 }
 
 func TestParseOptionsForCustomTemplate(t *testing.T) {
-	req := new(plugin_go.CodeGeneratorRequest)
+	req := new(pluginpb.CodeGeneratorRequest)
 	req.Parameter = proto.String("/path/to/template.tmpl,/base/name/only/output.md")
 
 	options, err := ParseOptions(req)
@@ -66,7 +68,7 @@ func TestParseOptionsForCustomTemplate(t *testing.T) {
 }
 
 func TestParseOptionsForExcludePatterns(t *testing.T) {
-	req := new(plugin_go.CodeGeneratorRequest)
+	req := new(pluginpb.CodeGeneratorRequest)
 	req.Parameter = proto.String(":exclude_patterns=google/*,notgoogle/*")
 
 	options, err := ParseOptions(req)
@@ -79,6 +81,202 @@ func TestParseOptionsForExcludePatterns(t *testing.T) {
 	require.Equal(t, pattern1.String(), options.ExcludePatterns[1].String())
 }
 
+func TestParseOptionsForIncludeFileSource(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:include_file_source=true")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.True(t, options.IncludeFileSource)
+
+	req.Parameter = proto.String("html,index.html")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.False(t, options.IncludeFileSource)
+}
+
+func TestParseOptionsForTheme(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:theme=dark,css_file=brand.css,logo=https://example.com/logo.png")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "dark", options.Theme)
+	require.Equal(t, "brand.css", options.CSSFile)
+	require.Equal(t, "https://example.com/logo.png", options.Logo)
+
+	req.Parameter = proto.String("html,index.html")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "auto", options.Theme)
+	require.Empty(t, options.CSSFile)
+	require.Empty(t, options.Logo)
+}
+
+func TestParseOptionsForAssets(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:assets=external")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.True(t, options.ExternalAssets)
+	require.Empty(t, options.AssetsURL)
+
+	req.Parameter = proto.String("html,index.html:assets_url=https://cdn.example.com/docs/")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.False(t, options.ExternalAssets)
+	require.Equal(t, "https://cdn.example.com/docs/", options.AssetsURL)
+}
+
+func TestParseOptionsForLocale(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:locale=fr")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "fr", options.Locale)
+
+	req.Parameter = proto.String("html,index.html")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, DefaultLocale, options.Locale)
+}
+
+func TestParseOptionsForParallelism(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:parallelism=4")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, 4, options.Parallelism)
+
+	req.Parameter = proto.String("html,index.html")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Zero(t, options.Parallelism)
+}
+
+func TestParseOptionsForCoverageThreshold(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("coverage,coverage.txt:coverage_threshold=87.5")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, RenderTypeCoverage, options.Type)
+	require.Equal(t, 87.5, options.CoverageThreshold)
+}
+
+func TestParseOptionsForLint(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("lint_json,lint.json:lint_rule=no_todo,lint_rule=require_since,lint_max_line_length=80,lint_fail=true")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, RenderTypeLintJSON, options.Type)
+	require.Equal(t, []string{"no_todo", "require_since"}, options.LintRules)
+	require.Equal(t, 80, options.LintMaxLineLength)
+	require.True(t, options.LintFail)
+}
+
+func TestParseOptionsForMeta(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:title=Booking API,description=Books vehicles.,version=1.2.0")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "Booking API", options.Title)
+	require.Equal(t, "Books vehicles.", options.Description)
+	require.Equal(t, "1.2.0", options.Version)
+
+	req.Parameter = proto.String("html,index.html:site_url=https://docs.example.com")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "https://docs.example.com", options.SiteURL)
+}
+
+func TestParseOptionsForMetaFile(t *testing.T) {
+	meta, err := os.CreateTemp("", "meta-*.json")
+	require.NoError(t, err)
+	defer os.Remove(meta.Name())
+
+	_, err = meta.WriteString(`{"title": "Booking API", "description": "Books vehicles, quickly.", "version": "1.2.0"}`)
+	require.NoError(t, err)
+	require.NoError(t, meta.Close())
+
+	// options take precedence over the file, regardless of their order
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:version=2.0.0,meta_file=" + meta.Name())
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "Booking API", options.Title)
+	require.Equal(t, "Books vehicles, quickly.", options.Description)
+	require.Equal(t, "2.0.0", options.Version)
+}
+
+func TestParseOptionsWithInvalidMetaFile(t *testing.T) {
+	meta, err := os.CreateTemp("", "meta-*.json")
+	require.NoError(t, err)
+	defer os.Remove(meta.Name())
+
+	_, err = meta.WriteString("title: Booking API")
+	require.NoError(t, err)
+	require.NoError(t, meta.Close())
+
+	for _, path := range []string{meta.Name(), "does/not/exist.json"} {
+		req := new(pluginpb.CodeGeneratorRequest)
+		req.Parameter = proto.String("html,index.html:meta_file=" + path)
+
+		_, err := ParseOptions(req)
+		require.Error(t, err)
+	}
+}
+
+func TestParseOptionsForSanitizeHTML(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, SanitizeHTMLOff, options.SanitizeHTML)
+
+	for _, mode := range []string{SanitizeHTMLOff, SanitizeHTMLEscape, SanitizeHTMLAllowlist} {
+		req.Parameter = proto.String("html,index.html:sanitize_html=" + mode)
+		options, err = ParseOptions(req)
+		require.NoError(t, err)
+		require.Equal(t, mode, options.SanitizeHTML)
+	}
+}
+
+func TestParseOptionsForTemplateSandbox(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("custom.tmpl,index.txt:template_sandbox=true")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.True(t, options.TemplateSandbox)
+
+	req.Parameter = proto.String("custom.tmpl,index.txt")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.False(t, options.TemplateSandbox)
+}
+
+func TestParseOptionsForLogLevel(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, LogLevelWarn, options.LogLevel)
+
+	req.Parameter = proto.String("html,index.html:log_level=debug")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, LogLevelDebug, options.LogLevel)
+}
+
 func TestParseOptionsWithInvalidValues(t *testing.T) {
 	badValues := []string{
 		"markdown",
@@ -88,11 +286,29 @@ func TestParseOptionsWithInvalidValues(t *testing.T) {
 		"markdown,index.md,unknown",
 		"markdown,index.md:unknown=1",
 		"markdown,index.md:camel_case_fields=maybe",
+		"markdown,index.md:include_file_source=maybe",
+		"html,index.html:theme=purple",
+		"html,index.html:dir=up",
+		"html,index.html:assets=cdn",
+		"html,index.html:locale=klingon",
+		"html,index.html:sanitize_html=strict",
+		"custom.tmpl,index.txt:template_sandbox=maybe",
+		"custom.tmpl,index.txt:template_api=v3",
+		"html,index.html:log_level=trace",
+		"html,index.html:parallelism=0",
+		"html,index.html:parallelism=many",
+		"html,index.html:index=maybe",
+		"html,index.html:coverage_threshold=101",
+		"html,index.html:coverage_threshold=-1",
+		"html,index.html:coverage_threshold=most",
+		"lint,lint.txt:lint_rule=no_typos",
+		"lint,lint.txt:lint_max_line_length=0",
+		"lint,lint.txt:lint_fail=maybe",
 		"markdown,index.md:exclude_patterns",
 	}
 
 	for _, value := range badValues {
-		req := new(plugin_go.CodeGeneratorRequest)
+		req := new(pluginpb.CodeGeneratorRequest)
 		req.Parameter = proto.String(value)
 
 		_, err := ParseOptions(req)
@@ -126,8 +342,101 @@ func TestRunPluginForCustomTemplate(t *testing.T) {
 	require.NotEmpty(t, resp.File[0].GetContent())
 }
 
+func TestRunPluginWithBrokenCustomTemplate(t *testing.T) {
+	tmpl, err := os.CreateTemp("", "broken-*.tmpl")
+	require.NoError(t, err)
+	defer os.Remove(tmpl.Name())
+
+	_, err = tmpl.WriteString("{{range .Files}}\n{{.Title}}\n{{end}}")
+	require.NoError(t, err)
+	require.NoError(t, tmpl.Close())
+
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String(tmpl.Name() + ",output.txt")
+
+	plugin := new(Plugin)
+	_, err = plugin.Generate(req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), tmpl.Name()+":2:3: executing <.Title>: can't evaluate field Title")
+	require.Contains(t, err.Error(), "> 2 | {{.Title}}")
+}
+
+func TestRunPluginWithTheme(t *testing.T) {
+	css, err := os.CreateTemp("", "theme-*.css")
+	require.NoError(t, err)
+	defer os.Remove(css.Name())
+
+	_, err = css.WriteString(":root { --link-color: #ff6600; }")
+	require.NoError(t, err)
+	require.NoError(t, css.Close())
+
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("html,index.html:theme=auto,css_file=" + css.Name() + ",logo=logo.png")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<html lang="en" data-theme="auto">`)
+	require.Contains(t, content, ":root { --link-color: #ff6600; }")
+	require.Contains(t, content, `<img class="logo" src="logo.png" alt=""/>`)
+}
+
+func TestRunPluginWithDir(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("html,index.html:dir=rtl")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	// the stylesheet mirrors the layout, while proto source stays left to right
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<html lang="en" dir="rtl" data-theme="auto">`)
+	require.Contains(t, content, "inset-inline-start: 0;")
+	require.Contains(t, content, "unicode-bidi: plaintext;")
+	require.Contains(t, content, ".proto-source {\n  direction: ltr;")
+
+	req.Parameter = proto.String("html,index.html:dir=auto,template=minimal")
+	resp, err = new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), `<html lang="en" dir="auto">`)
+}
+
+func TestRunPluginWithMeta(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("html,index.html:title=Booking API,description=Books vehicles.,version=1.2.0")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "<title>Booking API</title>")
+	require.Contains(t, content, `<meta name="description" content="Books vehicles.">`)
+	require.Contains(t, content, `<h1 id="title">Booking API</h1>`)
+	require.Contains(t, content, `<p class="version">Version 1.2.0</p>`)
+	require.NotContains(t, content, "Protocol Documentation")
+}
+
+func TestRunPluginWithMissingCSSFile(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("html,index.html:css_file=does/not/exist.css")
+
+	plugin := new(Plugin)
+	_, err := plugin.Generate(req)
+	require.Error(t, err)
+}
+
 func TestRunPluginWithInvalidOptions(t *testing.T) {
-	req := new(plugin_go.CodeGeneratorRequest)
+	req := new(pluginpb.CodeGeneratorRequest)
 	req.Parameter = proto.String("html")
 
 	plugin := new(Plugin)
@@ -153,3 +462,184 @@ func TestRunPluginForSourceRelative(t *testing.T) {
 	require.NotEmpty(t, resp.File[0].GetContent())
 	require.NotEmpty(t, resp.File[1].GetContent())
 }
+
+func TestRunPluginWithSiteURL(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String("html,index.html,source_relative:site_url=https://docs.example.com/api/,index=true,description=Bookings.")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 4)
+	require.Equal(t, "nested/index.html", resp.File[1].GetName())
+	require.Equal(t, "glossary.html", resp.File[2].GetName())
+	require.Equal(t, "sitemap.xml", resp.File[3].GetName())
+
+	page := resp.File[1].GetContent()
+	require.Contains(t, page, `<meta property="og:title" content="Protocol Documentation">`)
+	require.Contains(t, page, `<meta property="og:description" content="Bookings.">`)
+	require.Contains(t, page, `<meta name="twitter:card" content="summary">`)
+	require.Contains(t, page, `<meta property="og:url" content="https://docs.example.com/api/nested/index.html">`)
+	require.Contains(t, resp.File[0].GetContent(), `<link rel="canonical" href="https://docs.example.com/api/index.html">`)
+
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://docs.example.com/api/index.html</loc>
+  </url>
+  <url>
+    <loc>https://docs.example.com/api/nested/index.html</loc>
+  </url>
+  <url>
+    <loc>https://docs.example.com/api/glossary.html</loc>
+  </url>
+</urlset>
+`, resp.File[3].GetContent())
+}
+
+func TestRunPluginWithoutSiteURL(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+
+	for _, parameter := range []string{"html,index.html", "markdown,index.md:site_url=https://docs.example.com"} {
+		req.Parameter = proto.String(parameter)
+
+		plugin := new(Plugin)
+		resp, err := plugin.Generate(req)
+		require.NoError(t, err)
+		require.Len(t, resp.File, 1)
+		require.NotContains(t, resp.File[0].GetContent(), "og:url")
+	}
+}
+
+func TestRunPluginWithExternalAssets(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String("html,index.html,source_relative:assets=external")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 3)
+
+	files := make(map[string]string)
+	for _, f := range resp.File {
+		files[f.GetName()] = f.GetContent()
+	}
+
+	require.Contains(t, files[StylesheetAsset], "--link-color")
+	require.Contains(t, files["index.html"], `<link rel="stylesheet" type="text/css" href="assets/protoc-gen-doc.css"/>`)
+	require.Contains(t, files["nested/index.html"], `<link rel="stylesheet" type="text/css" href="../assets/protoc-gen-doc.css"/>`)
+	require.NotContains(t, files["index.html"], "--link-color")
+}
+
+func TestRunPluginWithAssetsURL(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("html,index.html:assets_url=https://cdn.example.com/docs/")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<link rel="stylesheet" type="text/css" href="https://cdn.example.com/docs/protoc-gen-doc.css"/>`)
+	require.NotContains(t, content, "--link-color")
+}
+
+func TestRunPluginIsDeterministic(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+
+	for _, kind := range []string{"docbook", "html", "json", "markdown"} {
+		var expected []byte
+
+		for i := 0; i < 10; i++ {
+			req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+			req.Parameter = proto.String(kind +
+				",output,source_relative:include_file_source=true,assets=external,reproducible=true")
+
+			plugin := new(Plugin)
+			resp, err := plugin.Generate(req)
+			require.NoError(t, err)
+
+			data, err := proto.Marshal(resp)
+			require.NoError(t, err)
+
+			if expected == nil {
+				expected = data
+				continue
+			}
+
+			require.Equal(t, expected, data, "output of %s differs between runs", kind)
+		}
+	}
+}
+
+func TestRunPluginSortsOutputFiles(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "nested/Book.proto", "Booking.proto", "Vehicle.proto")
+	req.Parameter = proto.String("markdown,index.md,source_relative")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 2)
+	require.Equal(t, "index.md", resp.File[0].GetName())
+	require.Equal(t, "nested/index.md", resp.File[1].GetName())
+}
+
+func TestRunPluginWithCoverageThreshold(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	req.Parameter = proto.String("html,index.html:coverage_threshold=75")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	req = utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String("coverage,coverage.txt:coverage_threshold=80")
+
+	_, err = plugin.Generate(req)
+	require.EqualError(t, err,
+		"Documentation coverage of 63.81% is below the threshold of 80.00% (38 of 105 entities are undocumented)")
+}
+
+func TestRunPluginWithLintFail(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("html,index.html:lint_rule=no_todo,lint_fail=true")
+
+	plugin := new(Plugin)
+	resp, err := plugin.Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	req.Parameter = proto.String("html,index.html:lint_rule=require_since,lint_fail=true")
+	_, err = plugin.Generate(req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "documentation lint issue(s), the first being Booking.proto: message com.example.Booking")
+}
+
+func TestRunPluginWithParallelism(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	outputs := make(map[string]*pluginpb.CodeGeneratorResponse)
+
+	for _, parallelism := range []string{"1", "2", "8"} {
+		req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+		req.Parameter = proto.String("markdown,index.md,source_relative:reproducible=true,parallelism=" + parallelism)
+
+		plugin := new(Plugin)
+		resp, err := plugin.Generate(req)
+		require.NoError(t, err)
+		require.Len(t, resp.File, 2)
+
+		outputs[parallelism] = resp
+	}
+
+	require.True(t, proto.Equal(outputs["1"], outputs["2"]))
+	require.True(t, proto.Equal(outputs["1"], outputs["8"]))
+}
@@ -0,0 +1,264 @@
+package gendoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	httpext "github.com/daotl/protoc-gen-doc/extensions/google_api_http"
+)
+
+// PostmanSchema is the schema of the collections written with the postman format. Insomnia imports them as well.
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// postmanBaseURL is the default value of the baseUrl variable that the request URLs start with.
+const postmanBaseURL = "http://localhost:8080"
+
+// postmanPathVariable matches a variable of an HTTP rule's path template, e.g. `{name=shelves/*}`.
+var postmanPathVariable = regexp.MustCompile(`\{([^}=]+)(=[^}]*)?\}`)
+
+type postmanCollection struct {
+	Info     postmanInfo        `json:"info"`
+	Item     []*postmanItem     `json:"item"`
+	Variable []*postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// postmanItem is either a folder of requests (with Item) or a request (with Request).
+type postmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []*postmanItem  `json:"item,omitempty"`
+	Request     *postmanRequest `json:"request,omitempty"`
+}
+
+type postmanRequest struct {
+	Method      string             `json:"method"`
+	Header      []*postmanVariable `json:"header"`
+	URL         *postmanURL        `json:"url"`
+	Body        *postmanBody       `json:"body,omitempty"`
+	Description string             `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string             `json:"raw"`
+	Host     []string           `json:"host"`
+	Path     []string           `json:"path"`
+	Query    []*postmanVariable `json:"query,omitempty"`
+	Variable []*postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string             `json:"mode"`
+	Raw     string             `json:"raw"`
+	Options postmanBodyOptions `json:"options"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// postmanRenderer writes a Postman collection with a folder per service and a request per HTTP binding of its methods,
+// declared with the google.api.http option. Request bodies are filled in with an example of the request message (see
+// Template.Example), and the fields that aren't bound to the path or the body are listed as disabled query parameters.
+type postmanRenderer struct{}
+
+func (r *postmanRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *postmanRenderer) ApplyTo(w io.Writer, template *Template) error {
+	collection := &postmanCollection{
+		Info: postmanInfo{
+			Name:        template.Meta.Title,
+			Description: template.Meta.Description,
+			Version:     template.Meta.Version,
+			Schema:      PostmanSchema,
+		},
+		Item:     make([]*postmanItem, 0),
+		Variable: []*postmanVariable{{Key: "baseUrl", Value: postmanBaseURL}},
+	}
+	if collection.Info.Name == "" {
+		collection.Info.Name = Translate(template.Locale, "Protocol Documentation")
+	}
+
+	messages := messagesByName(template.Files)
+	enums := make(map[string]bool)
+	for _, file := range template.Files {
+		for _, enum := range file.AllEnums() {
+			enums[enum.FullName] = true
+		}
+	}
+
+	for _, file := range template.Files {
+		for _, service := range file.Services {
+			folder := &postmanItem{Name: service.FullName, Description: service.Description}
+
+			for _, method := range service.Methods {
+				rules, ok := method.Option("google.api.http").(httpext.HTTPExtension)
+				if !ok {
+					continue
+				}
+
+				for i, rule := range rules.Rules {
+					message := messages[method.RequestFullType]
+					request, err := postmanMethodRequest(template, method, rule, message, enums)
+					if err != nil {
+						return err
+					}
+
+					name := method.Name
+					if i > 0 {
+						name = fmt.Sprintf("%s (%d)", method.Name, i+1)
+					}
+					folder.Item = append(folder.Item, &postmanItem{Name: name, Request: request})
+				}
+			}
+
+			if len(folder.Item) > 0 {
+				collection.Item = append(collection.Item, folder)
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// postmanMethodRequest returns the request of an HTTP binding of method. The request message is nil when it isn't
+// documented in the same output, and enums holds the full names of the documented enums.
+func postmanMethodRequest(
+	template *Template, method *ServiceMethod, rule httpext.HTTPRule, message *Message, enums map[string]bool,
+) (*postmanRequest, error) {
+	request := &postmanRequest{
+		Method:      rule.Method,
+		Header:      make([]*postmanVariable, 0),
+		URL:         &postmanURL{Host: []string{"{{baseUrl}}"}},
+		Description: method.Description,
+	}
+
+	bound := make(map[string]bool)
+	pattern := postmanPathVariable.ReplaceAllStringFunc(rule.Pattern, func(variable string) string {
+		name := postmanPathVariable.FindStringSubmatch(variable)[1]
+		bound[strings.SplitN(name, ".", 2)[0]] = true
+		request.URL.Variable = append(request.URL.Variable, &postmanVariable{
+			Key:         name,
+			Value:       "",
+			Description: postmanFieldDescription(message, name),
+		})
+		return ":" + name
+	})
+	request.URL.Raw = "{{baseUrl}}" + pattern
+	request.URL.Path = strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+
+	if rule.Body != "" {
+		body, err := postmanExampleBody(template, method, rule.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		request.Header = append(request.Header, &postmanVariable{Key: "Content-Type", Value: "application/json"})
+		request.Body = &postmanBody{Mode: "raw", Raw: body}
+		request.Body.Options.Raw.Language = "json"
+		bound[rule.Body] = true
+	}
+
+	if message != nil && rule.Body != "*" {
+		request.URL.Query = postmanQuery(message, bound, enums)
+	}
+
+	return request, nil
+}
+
+// postmanExampleBody returns an example of the body of an HTTP binding of method: the whole request message for `*`,
+// or the value of the named field.
+func postmanExampleBody(template *Template, method *ServiceMethod, field string) (string, error) {
+	example, err := template.Example(method.RequestFullType)
+	if err != nil || field == "*" {
+		return example, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(example), &members); err != nil {
+		return "", err
+	}
+
+	value, ok := members[field]
+	if !ok {
+		return "{}", nil
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, value, "", "  "); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// postmanQuery returns the query parameters of the scalar and enum fields of message that aren't bound to the path or
+// the body. They're disabled, so that only the ones a request needs have to be enabled.
+func postmanQuery(message *Message, bound, enums map[string]bool) []*postmanVariable {
+	query := make([]*postmanVariable, 0)
+	for _, field := range message.Fields {
+		if field.Redacted || field.IsMap || bound[field.Name] {
+			continue
+		}
+		if _, ok := exampleScalars[field.FullType]; !ok && !enums[field.FullType] {
+			continue
+		}
+
+		name := field.JSONName
+		if name == "" {
+			name = lowerCamelCase(field.Name)
+		}
+		query = append(query, &postmanVariable{
+			Key:         name,
+			Value:       "",
+			Description: field.Description,
+			Disabled:    true,
+		})
+	}
+
+	if len(query) == 0 {
+		return nil
+	}
+	return query
+}
+
+// postmanFieldDescription returns the description of the field of message a path variable is bound to.
+func postmanFieldDescription(message *Message, name string) string {
+	if message == nil {
+		return ""
+	}
+
+	for _, field := range message.Fields {
+		if field.Name == name {
+			return field.Description
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,149 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	httpext "github.com/daotl/protoc-gen-doc/extensions/google_api_http"
+	"github.com/stretchr/testify/require"
+)
+
+func newLibraryTemplate() *Template {
+	bindings := func(rules ...httpext.HTTPRule) map[string]interface{} {
+		return map[string]interface{}{"google.api.http": httpext.HTTPExtension{Rules: rules}}
+	}
+
+	book := &Message{Name: "Book", LongName: "Book", FullName: "library.Book", Fields: []*MessageField{
+		{Name: "name", FullType: "string", Description: "The resource name of the book."},
+		{Name: "title", FullType: "string"},
+	}}
+	getBook := &Message{Name: "GetBookRequest", LongName: "GetBookRequest", FullName: "library.GetBookRequest",
+		Fields: []*MessageField{
+			{Name: "name", FullType: "string", Description: "The name of the book."},
+			{Name: "read_mask", JSONName: "readMask", FullType: "string"},
+			{Name: "view", FullType: "library.BookView"},
+			{Name: "book", FullType: "library.Book"},
+		}}
+	updateBook := &Message{Name: "UpdateBookRequest", LongName: "UpdateBookRequest",
+		FullName: "library.UpdateBookRequest", Fields: []*MessageField{
+			{Name: "book", FullType: "library.Book"},
+			{Name: "update_mask", JSONName: "updateMask", FullType: "string"},
+		}}
+
+	return &Template{
+		Meta: Meta{Title: "Library", Version: "1.0"},
+		Files: []*File{{
+			Name:     "library.proto",
+			Package:  "library",
+			Messages: []*Message{book, getBook, updateBook},
+			Enums: []*Enum{{Name: "BookView", LongName: "BookView", FullName: "library.BookView",
+				Values: []*EnumValue{{Name: "BASIC"}, {Name: "FULL"}}}},
+			Services: []*Service{{
+				Name:     "LibraryService",
+				FullName: "library.LibraryService",
+				Methods: []*ServiceMethod{
+					{
+						Name:            "GetBook",
+						Description:     "Gets a book.",
+						RequestFullType: "library.GetBookRequest",
+						Options: bindings(
+							httpext.HTTPRule{Method: "GET", Pattern: "/v1/{name=shelves/*/books/*}"},
+						),
+					},
+					{
+						Name:            "UpdateBook",
+						RequestFullType: "library.UpdateBookRequest",
+						Options: bindings(
+							httpext.HTTPRule{Method: "PATCH", Pattern: "/v1/{book.name=shelves/*}", Body: "book"},
+							httpext.HTTPRule{Method: "POST", Pattern: "/v1/books:update", Body: "*"},
+						),
+					},
+					{Name: "DeleteBook", RequestFullType: "library.GetBookRequest"},
+				},
+			}},
+		}},
+	}
+}
+
+func TestRenderPostman(t *testing.T) {
+	output, err := RenderTemplate(RenderTypePostman, newLibraryTemplate(), "")
+	require.NoError(t, err)
+
+	var collection map[string]interface{}
+	require.NoError(t, json.Unmarshal(output, &collection))
+	require.Equal(t, map[string]interface{}{"name": "Library", "version": "1.0", "schema": PostmanSchema},
+		collection["info"])
+	require.Equal(t, []interface{}{map[string]interface{}{"key": "baseUrl", "value": "http://localhost:8080"}},
+		collection["variable"])
+
+	folders := collection["item"].([]interface{})
+	require.Len(t, folders, 1)
+	folder := folders[0].(map[string]interface{})
+	require.Equal(t, "library.LibraryService", folder["name"])
+
+	// methods without HTTP bindings are left out, additional bindings are numbered
+	requests := folder["item"].([]interface{})
+	require.Len(t, requests, 3)
+
+	get := requests[0].(map[string]interface{})
+	require.Equal(t, "GetBook", get["name"])
+	request := get["request"].(map[string]interface{})
+	require.Equal(t, "GET", request["method"])
+	require.Equal(t, "Gets a book.", request["description"])
+	require.NotContains(t, request, "body")
+	require.Equal(t, map[string]interface{}{
+		"raw":  "{{baseUrl}}/v1/:name",
+		"host": []interface{}{"{{baseUrl}}"},
+		"path": []interface{}{"v1", ":name"},
+		"variable": []interface{}{
+			map[string]interface{}{"key": "name", "value": "", "description": "The name of the book."},
+		},
+		"query": []interface{}{
+			map[string]interface{}{"key": "readMask", "value": "", "disabled": true},
+			map[string]interface{}{"key": "view", "value": "", "disabled": true},
+		},
+	}, request["url"])
+
+	patch := requests[1].(map[string]interface{})
+	require.Equal(t, "UpdateBook", patch["name"])
+	request = patch["request"].(map[string]interface{})
+	require.Equal(t, "{{baseUrl}}/v1/:book.name", request["url"].(map[string]interface{})["raw"])
+	require.Equal(t, []interface{}{map[string]interface{}{"key": "updateMask", "value": "", "disabled": true}},
+		request["url"].(map[string]interface{})["query"])
+	require.Equal(t, []interface{}{map[string]interface{}{"key": "Content-Type", "value": "application/json"}},
+		request["header"])
+	body := request["body"].(map[string]interface{})
+	require.Equal(t, "raw", body["mode"])
+	require.JSONEq(t, `{"name": "string", "title": "string"}`, body["raw"].(string))
+
+	post := requests[2].(map[string]interface{})
+	require.Equal(t, "UpdateBook (2)", post["name"])
+	request = post["request"].(map[string]interface{})
+	require.NotContains(t, request["url"], "query")
+	require.JSONEq(t, `{"book": {"name": "string", "title": "string"}, "update_mask": "string"}`,
+		request["body"].(map[string]interface{})["raw"].(string))
+}
+
+func TestRenderPostmanWithoutBindings(t *testing.T) {
+	template := newLibraryTemplate()
+	template.Meta = Meta{}
+	template.Files[0].Services[0].Methods = template.Files[0].Services[0].Methods[2:]
+
+	output, err := RenderTemplate(RenderTypePostman, template, "")
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "info": {
+    "name": "Protocol Documentation",
+    "schema": "`+PostmanSchema+`"
+  },
+  "item": [],
+  "variable": [
+    {
+      "key": "baseUrl",
+      "value": "http://localhost:8080"
+    }
+  ]
+}
+`, string(output))
+}
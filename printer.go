@@ -0,0 +1,730 @@
+package gendoc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// tag numbers used to build source code info paths. See descriptor.proto for details.
+const (
+	filePackagePath    = 2
+	fileDependencyPath = 3
+	fileMessagePath    = 4
+	fileEnumPath       = 5
+	fileServicePath    = 6
+	fileExtensionPath  = 7
+	fileSyntaxPath     = 12
+	fileEditionPath    = 14
+
+	messageFieldPath          = 2
+	messageNestedPath         = 3
+	messageEnumPath           = 4
+	messageExtensionRangePath = 5
+	messageExtensionPath      = 6
+	messageOneofPath          = 8
+	messageReservedRangePath  = 9
+	messageReservedNamePath   = 10
+
+	enumValuePath         = 2
+	enumReservedRangePath = 4
+	enumReservedNamePath  = 5
+
+	serviceMethodPath = 2
+)
+
+// PrintProto reconstructs idiomatic .proto source text for the supplied file from its descriptor. Comments are taken
+// from the source code info (when present) and declarations are emitted in the order they appeared in the original
+// file. Options are included, though custom (extension) options are only printed when their extension has been
+// registered.
+//
+// Example: printing a file from a CodeGeneratorRequest
+//
+//	for _, fd := range protokit.ParseCodeGenRequest(req) {
+//		source, err := gendoc.PrintProto(fd)
+//		...
+//	}
+func PrintProto(fd *protokit.FileDescriptor) (string, error) {
+	if fd == nil || fd.FileDescriptorProto == nil {
+		return "", errors.New("Unable to print a nil file descriptor")
+	}
+
+	p := &protoPrinter{
+		file:      fd.FileDescriptorProto,
+		locations: make(map[string]*descriptorpb.SourceCodeInfo_Location),
+	}
+
+	for _, loc := range fd.GetSourceCodeInfo().GetLocation() {
+		key := pathKey(loc.GetPath())
+		if _, ok := p.locations[key]; !ok {
+			p.locations[key] = loc
+		}
+	}
+
+	p.printFile()
+	return p.buf.String(), nil
+}
+
+type protoPrinter struct {
+	buf       bytes.Buffer
+	file      *descriptorpb.FileDescriptorProto
+	locations map[string]*descriptorpb.SourceCodeInfo_Location
+	indent    int
+}
+
+// nestedType is a message nested within another one, along with its source code info path.
+type nestedType struct {
+	msg  *descriptorpb.DescriptorProto
+	path []int32
+}
+
+// declaration is a single printable element of a file or message body. Blocks (messages, enums, oneofs, etc.) are
+// separated from their siblings by a blank line.
+type declaration struct {
+	path  []int32
+	block bool
+	print func()
+}
+
+func pathKey(path []int32) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(int(p))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func childPath(path []int32, elems ...int32) []int32 {
+	out := make([]int32, 0, len(path)+len(elems))
+	out = append(out, path...)
+	return append(out, elems...)
+}
+
+func (p *protoPrinter) line(format string, args ...interface{}) {
+	if format == "" {
+		p.buf.WriteString("\n")
+		return
+	}
+
+	p.buf.WriteString(strings.Repeat("  ", p.indent))
+	p.buf.WriteString(fmt.Sprintf(format, args...))
+	p.buf.WriteString("\n")
+}
+
+// startLine returns the line on which the element at path starts, or -1 when no source info is available.
+func (p *protoPrinter) startLine(path []int32) int32 {
+	if loc, ok := p.locations[pathKey(path)]; ok && len(loc.GetSpan()) > 0 {
+		return loc.GetSpan()[0]
+	}
+
+	return -1
+}
+
+// sortDeclarations orders declarations by where they appeared in the source. Without source info the original
+// (descriptor) ordering is kept.
+func (p *protoPrinter) sortDeclarations(decls []declaration) {
+	sort.SliceStable(decls, func(i, j int) bool {
+		li, lj := p.startLine(decls[i].path), p.startLine(decls[j].path)
+		if li < 0 || lj < 0 {
+			return false
+		}
+
+		return li < lj
+	})
+}
+
+// printComment prints the comment text as line comments. The leading `*` of doc-style block comments (`/** ... */`) is
+// dropped.
+func (p *protoPrinter) printComment(text string) {
+	text = strings.TrimPrefix(text, "*")
+	text = strings.Trim(text, "\n")
+	for _, l := range strings.Split(text, "\n") {
+		p.line("%s", commentLine(l))
+	}
+}
+
+func commentLine(text string) string {
+	text = strings.TrimRight(text, " \t")
+	if text != "" && !strings.HasPrefix(text, " ") && !strings.HasPrefix(text, "/") {
+		text = " " + text
+	}
+
+	return "//" + text
+}
+
+func (p *protoPrinter) printLeadingComments(path []int32) {
+	loc, ok := p.locations[pathKey(path)]
+	if !ok {
+		return
+	}
+
+	for _, detached := range loc.GetLeadingDetachedComments() {
+		p.printComment(detached)
+		p.line("")
+	}
+
+	if loc.GetLeadingComments() != "" {
+		p.printComment(loc.GetLeadingComments())
+	}
+}
+
+func (p *protoPrinter) trailingComment(path []int32) string {
+	loc, ok := p.locations[pathKey(path)]
+	if !ok {
+		return ""
+	}
+
+	return loc.GetTrailingComments()
+}
+
+// printStatement prints a single-line statement along with any comments attached to it.
+func (p *protoPrinter) printStatement(path []int32, statement string) {
+	p.printLeadingComments(path)
+
+	trailing := p.trailingComment(path)
+	if trailing == "" {
+		p.line("%s", statement)
+		return
+	}
+
+	trailing = strings.Trim(strings.TrimPrefix(trailing, "*"), "\n")
+	if !strings.Contains(trailing, "\n") {
+		p.line("%s %s", statement, commentLine(trailing))
+		return
+	}
+
+	p.line("%s", statement)
+	p.printComment(trailing)
+}
+
+// openBlock prints the opening line of a block (message, enum, etc.) along with its comments.
+func (p *protoPrinter) openBlock(path []int32, header string) {
+	p.printLeadingComments(path)
+	p.line("%s {", header)
+	p.indent++
+
+	if trailing := p.trailingComment(path); trailing != "" {
+		p.printComment(trailing)
+	}
+}
+
+func (p *protoPrinter) closeBlock() {
+	p.indent--
+	p.line("}")
+}
+
+func (p *protoPrinter) printFile() {
+	f := p.file
+
+	if f.GetSyntax() == "editions" {
+		edition := strings.TrimPrefix(f.GetEdition().String(), "EDITION_")
+		p.printStatement([]int32{fileEditionPath}, fmt.Sprintf("edition = %q;", edition))
+	} else {
+		syntax := f.GetSyntax()
+		if syntax == "" {
+			syntax = "proto2"
+		}
+		p.printStatement([]int32{fileSyntaxPath}, fmt.Sprintf("syntax = %q;", syntax))
+	}
+
+	if f.GetPackage() != "" {
+		p.line("")
+		p.printStatement([]int32{filePackagePath}, fmt.Sprintf("package %s;", f.GetPackage()))
+	}
+
+	if len(f.GetDependency()) > 0 {
+		p.line("")
+		for i, dep := range f.GetDependency() {
+			modifier := ""
+			if containsInt32(f.GetPublicDependency(), int32(i)) {
+				modifier = "public "
+			} else if containsInt32(f.GetWeakDependency(), int32(i)) {
+				modifier = "weak "
+			}
+			p.printStatement([]int32{fileDependencyPath, int32(i)}, fmt.Sprintf("import %s%q;", modifier, dep))
+		}
+	}
+
+	if opts := formatOptions(f.GetOptions()); len(opts) > 0 {
+		p.line("")
+		for _, opt := range opts {
+			p.line("option %s;", opt)
+		}
+	}
+
+	decls := make([]declaration, 0)
+	for i, m := range f.GetMessageType() {
+		m, path := m, []int32{fileMessagePath, int32(i)}
+		decls = append(decls, declaration{path, true, func() { p.printMessage(m, path) }})
+	}
+	for i, e := range f.GetEnumType() {
+		e, path := e, []int32{fileEnumPath, int32(i)}
+		decls = append(decls, declaration{path, true, func() { p.printEnum(e, path) }})
+	}
+	for i, s := range f.GetService() {
+		s, path := s, []int32{fileServicePath, int32(i)}
+		decls = append(decls, declaration{path, true, func() { p.printService(s, path) }})
+	}
+	decls = append(decls, p.extensionDeclarations(f.GetExtension(), []int32{fileExtensionPath}, nil)...)
+
+	p.sortDeclarations(decls)
+	for _, d := range decls {
+		p.line("")
+		d.print()
+	}
+}
+
+func (p *protoPrinter) printMessage(m *descriptorpb.DescriptorProto, path []int32) {
+	p.openBlock(path, "message "+m.GetName())
+	defer p.closeBlock()
+
+	for _, opt := range formatOptions(m.GetOptions()) {
+		p.line("option %s;", opt)
+	}
+
+	nested := make(map[string]nestedType)
+	for i, n := range m.GetNestedType() {
+		nested[n.GetName()] = nestedType{n, childPath(path, messageNestedPath, int32(i))}
+	}
+
+	// Groups and map entries are printed inline with the field that uses them.
+	inlined := make(map[string]bool)
+	decls := make([]declaration, 0)
+	printedOneofs := make(map[int32]bool)
+
+	for i, field := range m.GetField() {
+		field, fieldPath := field, childPath(path, messageFieldPath, int32(i))
+
+		if entry := p.mapEntry(field, nested); entry != nil {
+			inlined[entry.GetName()] = true
+		}
+		if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP {
+			inlined[baseName(field.GetTypeName())] = true
+		}
+
+		if field.OneofIndex != nil && !field.GetProto3Optional() {
+			idx := field.GetOneofIndex()
+			if printedOneofs[idx] {
+				continue
+			}
+			printedOneofs[idx] = true
+
+			oneofPath := childPath(path, messageOneofPath, idx)
+			decls = append(decls, declaration{fieldPath, true, func() { p.printOneof(m, idx, oneofPath, nested) }})
+			continue
+		}
+
+		group := field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP
+		decls = append(decls, declaration{fieldPath, group, func() { p.printField(field, fieldPath, nested) }})
+	}
+
+	for i, n := range m.GetNestedType() {
+		if inlined[n.GetName()] {
+			continue
+		}
+		n, nestedPath := n, childPath(path, messageNestedPath, int32(i))
+		decls = append(decls, declaration{nestedPath, true, func() { p.printMessage(n, nestedPath) }})
+	}
+
+	for i, e := range m.GetEnumType() {
+		e, enumPath := e, childPath(path, messageEnumPath, int32(i))
+		decls = append(decls, declaration{enumPath, true, func() { p.printEnum(e, enumPath) }})
+	}
+
+	decls = append(decls, p.extensionDeclarations(m.GetExtension(), childPath(path, messageExtensionPath), nested)...)
+
+	if len(m.GetExtensionRange()) > 0 {
+		ranges := make([]string, 0, len(m.GetExtensionRange()))
+		for _, r := range m.GetExtensionRange() {
+			ranges = append(ranges, formatRange(r.GetStart(), r.GetEnd(), true))
+		}
+		rangePath := childPath(path, messageExtensionRangePath)
+		decls = append(decls, declaration{rangePath, false, func() {
+			p.printStatement(rangePath, fmt.Sprintf("extensions %s;", strings.Join(ranges, ", ")))
+		}})
+	}
+
+	decls = append(decls, p.reservedDeclarations(m, path)...)
+
+	p.sortDeclarations(decls)
+	for i, d := range decls {
+		if i > 0 && (d.block || decls[i-1].block) {
+			p.line("")
+		}
+		d.print()
+	}
+}
+
+func (p *protoPrinter) reservedDeclarations(m *descriptorpb.DescriptorProto, path []int32) []declaration {
+	decls := make([]declaration, 0, 2)
+
+	if len(m.GetReservedRange()) > 0 {
+		ranges := make([]string, 0, len(m.GetReservedRange()))
+		for _, r := range m.GetReservedRange() {
+			ranges = append(ranges, formatRange(r.GetStart(), r.GetEnd(), true))
+		}
+		rangePath := childPath(path, messageReservedRangePath)
+		decls = append(decls, declaration{rangePath, false, func() {
+			p.printStatement(rangePath, fmt.Sprintf("reserved %s;", strings.Join(ranges, ", ")))
+		}})
+	}
+
+	if len(m.GetReservedName()) > 0 {
+		decls = append(decls, p.reservedNames(m.GetReservedName(), childPath(path, messageReservedNamePath)))
+	}
+
+	return decls
+}
+
+func (p *protoPrinter) reservedNames(names []string, path []int32) declaration {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		if p.file.GetSyntax() == "editions" {
+			quoted = append(quoted, n)
+		} else {
+			quoted = append(quoted, strconv.Quote(n))
+		}
+	}
+
+	return declaration{path, false, func() {
+		p.printStatement(path, fmt.Sprintf("reserved %s;", strings.Join(quoted, ", ")))
+	}}
+}
+
+func (p *protoPrinter) printOneof(m *descriptorpb.DescriptorProto, idx int32, path []int32, nested map[string]nestedType) {
+	oneof := m.GetOneofDecl()[idx]
+	p.openBlock(path, "oneof "+oneof.GetName())
+	defer p.closeBlock()
+
+	for _, opt := range formatOptions(oneof.GetOptions()) {
+		p.line("option %s;", opt)
+	}
+
+	msgPath := path[:len(path)-2]
+	for i, field := range m.GetField() {
+		if field.OneofIndex == nil || field.GetOneofIndex() != idx {
+			continue
+		}
+		p.printField(field, childPath(msgPath, messageFieldPath, int32(i)), nested)
+	}
+}
+
+func (p *protoPrinter) printField(field *descriptorpb.FieldDescriptorProto, path []int32,
+	nested map[string]nestedType) {
+	label := p.fieldLabel(field)
+	options := p.fieldOptions(field)
+
+	if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP {
+		p.openBlock(path, fmt.Sprintf("%sgroup %s = %d%s", label, baseName(field.GetTypeName()), field.GetNumber(), options))
+		defer p.closeBlock()
+
+		if group, ok := nested[baseName(field.GetTypeName())]; ok {
+			groupNested := make(map[string]nestedType)
+			for i, n := range group.msg.GetNestedType() {
+				groupNested[n.GetName()] = nestedType{n, childPath(group.path, messageNestedPath, int32(i))}
+			}
+			for i, f := range group.msg.GetField() {
+				p.printField(f, childPath(group.path, messageFieldPath, int32(i)), groupNested)
+			}
+		}
+		return
+	}
+
+	typeName := p.typeName(field)
+	if entry := p.mapEntry(field, nested); entry != nil && len(entry.GetField()) == 2 {
+		label = ""
+		typeName = fmt.Sprintf("map<%s, %s>", p.typeName(entry.GetField()[0]), p.typeName(entry.GetField()[1]))
+	}
+
+	p.printStatement(path, fmt.Sprintf("%s%s %s = %d%s;", label, typeName, field.GetName(), field.GetNumber(), options))
+}
+
+func (p *protoPrinter) fieldLabel(field *descriptorpb.FieldDescriptorProto) string {
+	switch field.GetLabel() {
+	case descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+		return "repeated "
+	case descriptorpb.FieldDescriptorProto_LABEL_REQUIRED:
+		return "required "
+	}
+
+	switch p.file.GetSyntax() {
+	case "proto3":
+		if field.GetProto3Optional() {
+			return "optional "
+		}
+		return ""
+	case "editions":
+		return ""
+	}
+
+	if field.OneofIndex != nil {
+		return ""
+	}
+
+	return "optional "
+}
+
+func (p *protoPrinter) fieldOptions(field *descriptorpb.FieldDescriptorProto) string {
+	opts := make([]string, 0)
+
+	if field.DefaultValue != nil {
+		value := field.GetDefaultValue()
+		switch field.GetType() {
+		case descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+			value = strconv.Quote(value)
+		}
+		opts = append(opts, "default = "+value)
+	}
+
+	if field.JsonName != nil && field.GetJsonName() != jsonName(field.GetName()) {
+		opts = append(opts, fmt.Sprintf("json_name = %q", field.GetJsonName()))
+	}
+
+	opts = append(opts, formatOptions(field.GetOptions())...)
+	if len(opts) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(opts, ", ") + "]"
+}
+
+// typeName returns the name of the field's type relative to the file's package.
+func (p *protoPrinter) typeName(field *descriptorpb.FieldDescriptorProto) string {
+	if field.GetTypeName() == "" {
+		return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+	}
+
+	name := strings.TrimPrefix(field.GetTypeName(), ".")
+	if pkg := p.file.GetPackage(); pkg != "" && strings.HasPrefix(name, pkg+".") {
+		return strings.TrimPrefix(name, pkg+".")
+	}
+
+	return name
+}
+
+func (p *protoPrinter) mapEntry(field *descriptorpb.FieldDescriptorProto, nested map[string]nestedType) *descriptorpb.DescriptorProto {
+	if field.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE ||
+		field.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return nil
+	}
+
+	entry, ok := nested[baseName(field.GetTypeName())]
+	if !ok || !entry.msg.GetOptions().GetMapEntry() {
+		return nil
+	}
+
+	return entry.msg
+}
+
+// extensionDeclarations groups consecutive extensions of the same type into `extend` blocks.
+func (p *protoPrinter) extensionDeclarations(exts []*descriptorpb.FieldDescriptorProto, path []int32, nested map[string]nestedType) []declaration {
+	decls := make([]declaration, 0)
+
+	for start := 0; start < len(exts); {
+		end := start + 1
+		for end < len(exts) && exts[end].GetExtendee() == exts[start].GetExtendee() {
+			end++
+		}
+
+		group, first := exts[start:end], start
+		decls = append(decls, declaration{childPath(path, int32(first)), true, func() {
+			p.line("extend %s {", p.typeName(&descriptorpb.FieldDescriptorProto{TypeName: group[0].Extendee}))
+			p.indent++
+			for i, ext := range group {
+				p.printField(ext, childPath(path, int32(first+i)), nested)
+			}
+			p.closeBlock()
+		}})
+
+		start = end
+	}
+
+	return decls
+}
+
+func (p *protoPrinter) printEnum(e *descriptorpb.EnumDescriptorProto, path []int32) {
+	p.openBlock(path, "enum "+e.GetName())
+	defer p.closeBlock()
+
+	for _, opt := range formatOptions(e.GetOptions()) {
+		p.line("option %s;", opt)
+	}
+
+	for i, v := range e.GetValue() {
+		options := ""
+		if opts := formatOptions(v.GetOptions()); len(opts) > 0 {
+			options = " [" + strings.Join(opts, ", ") + "]"
+		}
+
+		valuePath := childPath(path, enumValuePath, int32(i))
+		p.printStatement(valuePath, fmt.Sprintf("%s = %d%s;", v.GetName(), v.GetNumber(), options))
+	}
+
+	if len(e.GetReservedRange()) > 0 {
+		ranges := make([]string, 0, len(e.GetReservedRange()))
+		for _, r := range e.GetReservedRange() {
+			// enum reserved ranges are inclusive on both ends
+			ranges = append(ranges, formatRange(r.GetStart(), r.GetEnd(), false))
+		}
+		p.printStatement(childPath(path, enumReservedRangePath), fmt.Sprintf("reserved %s;", strings.Join(ranges, ", ")))
+	}
+
+	if len(e.GetReservedName()) > 0 {
+		p.reservedNames(e.GetReservedName(), childPath(path, enumReservedNamePath)).print()
+	}
+}
+
+func (p *protoPrinter) printService(s *descriptorpb.ServiceDescriptorProto, path []int32) {
+	p.openBlock(path, "service "+s.GetName())
+	defer p.closeBlock()
+
+	for _, opt := range formatOptions(s.GetOptions()) {
+		p.line("option %s;", opt)
+	}
+
+	for i, m := range s.GetMethod() {
+		methodPath := childPath(path, serviceMethodPath, int32(i))
+
+		input := p.typeName(&descriptorpb.FieldDescriptorProto{TypeName: m.InputType})
+		if m.GetClientStreaming() {
+			input = "stream " + input
+		}
+
+		output := p.typeName(&descriptorpb.FieldDescriptorProto{TypeName: m.OutputType})
+		if m.GetServerStreaming() {
+			output = "stream " + output
+		}
+
+		signature := fmt.Sprintf("rpc %s(%s) returns (%s)", m.GetName(), input, output)
+		opts := formatOptions(m.GetOptions())
+		if len(opts) == 0 {
+			p.printStatement(methodPath, signature+";")
+			continue
+		}
+
+		p.openBlock(methodPath, signature)
+		for _, opt := range opts {
+			p.line("option %s;", opt)
+		}
+		p.closeBlock()
+	}
+}
+
+// formatOptions returns the set options of a descriptor as `name = value` strings, ordered by field number. Extension
+// options are only included when the extension has been registered.
+func formatOptions(opts proto.Message) []string {
+	if opts == nil {
+		return nil
+	}
+
+	msg := opts.ProtoReflect()
+	if !msg.IsValid() {
+		return nil
+	}
+
+	fields := make([]protoreflect.FieldDescriptor, 0)
+	values := make(map[protoreflect.FieldDescriptor]protoreflect.Value)
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		fields = append(fields, fd)
+		values[fd] = v
+		return true
+	})
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Number() < fields[j].Number() })
+
+	out := make([]string, 0, len(fields))
+	for _, fd := range fields {
+		name := string(fd.Name())
+		if fd.IsExtension() {
+			name = "(" + string(fd.FullName()) + ")"
+		}
+
+		if fd.IsList() {
+			list := values[fd].List()
+			for i := 0; i < list.Len(); i++ {
+				out = append(out, name+" = "+formatOptionValue(fd, list.Get(i)))
+			}
+			continue
+		}
+
+		out = append(out, name+" = "+formatOptionValue(fd, values[fd]))
+	}
+
+	return out
+}
+
+func formatOptionValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.StringKind:
+		return strconv.Quote(v.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(v.Bytes()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		b, err := prototext.MarshalOptions{}.Marshal(v.Message().Interface())
+		if err != nil {
+			return "{}"
+		}
+		return "{ " + strings.Join(strings.Fields(string(b)), " ") + " }"
+	}
+
+	return v.String()
+}
+
+func formatRange(start, end int32, exclusive bool) string {
+	if exclusive {
+		end--
+	}
+
+	switch {
+	case start == end:
+		return strconv.Itoa(int(start))
+	case end >= 536870911:
+		return fmt.Sprintf("%d to max", start)
+	}
+
+	return fmt.Sprintf("%d to %d", start, end)
+}
+
+// jsonName returns the JSON name protoc would assign to a field by default.
+func jsonName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func containsInt32(values []int32, v int32) bool {
+	for _, val := range values {
+		if val == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,195 @@
+package gendoc_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestPrintProtoFromSource(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Vehicle.proto")
+	source, err := PrintProto(protokit.ParseCodeGenRequest(req)[0])
+	require.NoError(t, err)
+
+	require.Contains(t, source, "// Messages describing manufacturers / vehicles.\nsyntax = \"proto3\";\n")
+	require.Contains(t, source, "import \"github.com/pseudomuto/protokit/fixtures/extend.proto\";\n")
+	require.Contains(t, source, "  rpc GetModels(EmptyMessage) returns (stream Model);\n")
+	require.Contains(t, source, "  rpc AddModels(stream Model) returns (stream Model); // creates models\n")
+	require.Contains(t, source, "  map<string, string> properties = 7; // bag of properties related to the vehicle.\n")
+	require.Contains(t, source, "  oneof travel {\n    int32 kilometers = 8;\n    int64 lightyears = 10;\n  }\n")
+	require.NotContains(t, source, "PropertiesEntry")
+
+	// declarations keep their original ordering rather than being grouped by kind
+	require.Less(t, strings.Index(source, "service VehicleService"), strings.Index(source, "message FindVehicleById"))
+	require.Less(t, strings.Index(source, "message ExcludedMessage"), strings.Index(source, "enum Type"))
+	require.Less(t, strings.Index(source, "enum Type"), strings.Index(source, "message Manufacturer"))
+}
+
+func TestPrintProtoWithoutSourceInfo(t *testing.T) {
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("thing.proto"),
+		Package:    proto.String("com.example"),
+		Syntax:     proto.String("proto2"),
+		Dependency: []string{"other.proto"},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/thing")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Thing"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:         proto.String("id"),
+					Number:       proto.Int32(1),
+					Label:        descriptorpb.FieldDescriptorProto_LABEL_REQUIRED.Enum(),
+					Type:         descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+					DefaultValue: proto.String("5"),
+				},
+				{
+					Name:     proto.String("result"),
+					Number:   proto.Int32(2),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_GROUP.Enum(),
+					TypeName: proto.String(".com.example.Thing.Result"),
+				},
+				{
+					Name:     proto.String("tags"),
+					Number:   proto.Int32(3),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".com.example.Thing.TagsEntry"),
+				},
+				{
+					Name:       proto.String("name"),
+					Number:     proto.Int32(4),
+					Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:       descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					OneofIndex: proto.Int32(0),
+				},
+				{
+					Name:       proto.String("color"),
+					Number:     proto.Int32(5),
+					Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:       descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+					TypeName:   proto.String(".com.example.Color"),
+					OneofIndex: proto.Int32(0),
+					Options:    &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)},
+				},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("Result"),
+					Field: []*descriptorpb.FieldDescriptorProto{{
+						Name:   proto.String("url"),
+						Number: proto.Int32(3),
+						Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					}},
+				},
+				{
+					Name: proto.String("TagsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{
+							Name:   proto.String("key"),
+							Number: proto.Int32(1),
+							Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+						},
+						{
+							Name:   proto.String("value"),
+							Number: proto.Int32(2),
+							Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+							Type:   descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+						},
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				},
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("choice")}},
+			ReservedRange: []*descriptorpb.DescriptorProto_ReservedRange{
+				{Start: proto.Int32(10), End: proto.Int32(12)},
+			},
+			ReservedName: []string{"old"},
+		}},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Color"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("RED"), Number: proto.Int32(0)},
+				{
+					Name:    proto.String("GREEN"),
+					Number:  proto.Int32(1),
+					Options: &descriptorpb.EnumValueOptions{Deprecated: proto.Bool(true)},
+				},
+			},
+			ReservedRange: []*descriptorpb.EnumDescriptorProto_EnumReservedRange{{Start: proto.Int32(5), End: proto.Int32(6)}},
+		}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Things"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:            proto.String("Watch"),
+				InputType:       proto.String(".com.example.Thing"),
+				OutputType:      proto.String(".other.Event"),
+				ServerStreaming: proto.Bool(true),
+				Options:         &descriptorpb.MethodOptions{Deprecated: proto.Bool(true)},
+			}},
+		}},
+	}
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"thing.proto"},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	}
+
+	source, err := PrintProto(protokit.ParseCodeGenRequest(req)[0])
+	require.NoError(t, err)
+	require.Equal(t, `syntax = "proto2";
+
+package com.example;
+
+import "other.proto";
+
+option go_package = "example.com/thing";
+
+message Thing {
+  required int32 id = 1 [default = 5];
+
+  optional group Result = 2 {
+    optional string url = 3;
+  }
+
+  map<string, int64> tags = 3;
+
+  oneof choice {
+    string name = 4;
+    Color color = 5 [deprecated = true];
+  }
+
+  reserved 10 to 11;
+  reserved "old";
+}
+
+enum Color {
+  RED = 0;
+  GREEN = 1 [deprecated = true];
+  reserved 5 to 6;
+}
+
+service Things {
+  rpc Watch(Thing) returns (stream other.Event) {
+    option deprecated = true;
+  }
+}
+`, source)
+}
+
+func TestPrintProtoWithNilDescriptor(t *testing.T) {
+	_, err := PrintProto(nil)
+	require.Error(t, err)
+}
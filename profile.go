@@ -0,0 +1,54 @@
+package gendoc
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+const (
+	// CPUProfileFile is the name of the CPU profile written to the profile_dir directory.
+	CPUProfileFile = "cpu.pprof"
+	// HeapProfileFile is the name of the heap profile written to the profile_dir directory.
+	HeapProfileFile = "heap.pprof"
+)
+
+// startProfiling starts a CPU profile written to dir, returning a function that stops it and writes a heap profile
+// next to it. Both can be inspected with `go tool pprof`. Only one profile can be taken at a time in a process.
+func startProfiling(dir string) (func() error, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	cpu, err := os.Create(filepath.Join(dir, CPUProfileFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, err
+	}
+
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := cpu.Close(); err != nil {
+			return err
+		}
+
+		heap, err := os.Create(filepath.Join(dir, HeapProfileFile))
+		if err != nil {
+			return err
+		}
+
+		// collect garbage first, so that the profile shows the memory that's still in use
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(heap); err != nil {
+			heap.Close()
+			return err
+		}
+
+		return heap.Close()
+	}, nil
+}
@@ -0,0 +1,36 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionsForProfileDir(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:profile_dir=profiles"))
+	require.NoError(t, err)
+	require.Equal(t, "profiles", options.ProfileDir)
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:profile_dir="))
+	require.EqualError(t, err, "Invalid profile_dir value: ")
+}
+
+func TestProfileDir(t *testing.T) {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	profiles := filepath.Join(dir, "profiles")
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:profile_dir="+profiles))
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	for _, name := range []string{CPUProfileFile, HeapProfileFile} {
+		info, err := os.Stat(filepath.Join(profiles, name))
+		require.NoError(t, err, name)
+		require.NotZero(t, info.Size(), name)
+	}
+}
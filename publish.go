@@ -0,0 +1,130 @@
+package gendoc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// PublishAuthEnv is the environment variable holding the Authorization header sent along with the files posted to the
+// publish_url, e.g. `Bearer <token>`.
+const PublishAuthEnv = "PROTOC_GEN_DOC_PUBLISH_AUTH"
+
+// DefaultPublishRetries is the number of times publishing the files is retried after a network error, a server error
+// or a 429 (Too Many Requests) response, unless set with the publish_retries option.
+const DefaultPublishRetries = 3
+
+// publishBackoff is the delay before the first retry of a failed publication, which doubles with each retry.
+var publishBackoff = 500 * time.Millisecond
+
+var publishClient = &http.Client{Timeout: time.Minute}
+
+// isPublishURL returns whether value is an absolute http or https URL.
+func isPublishURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// publishOutputs publishes the output files of the plugin once they're rendered. It's only called by Plugin.Generate,
+// so docs generated with Generate, previewed with the serve command or fetched through server reflection are never
+// published.
+func publishOutputs(files []OutputFile, options *PluginOptions) error {
+	log := newLogger(options.LogLevel)
+	if options.PublishURL != "" {
+		if err := publishFiles(files, options, log); err != nil {
+			return err
+		}
+	}
+
+	if options.PublishBucket != "" {
+		if err := publishToBucket(files, options, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishFiles posts the output files to options.PublishURL as a multipart/form-data request, with a `file` part named
+// after the path of each file.
+func publishFiles(files []OutputFile, options *PluginOptions, log *logger) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, f := range files {
+		part, err := form.CreateFormFile("file", f.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(part, f.Content); err != nil {
+			return err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	err := sendWithRetries(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, options.PublishURL, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		if auth := os.Getenv(PublishAuthEnv); auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		return req, nil
+	}, options, log)
+	if err != nil {
+		return err
+	}
+
+	log.info("published docs", "url", options.PublishURL, "files", len(files))
+	return nil
+}
+
+// sendWithRetries sends the request returned by newRequest, which is called again for each retry of a transient
+// failure (see DefaultPublishRetries).
+func sendWithRetries(newRequest func() (*http.Request, error), options *PluginOptions, log *logger) error {
+	delay := publishBackoff
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return err
+		}
+
+		retry, err := send(req)
+		if err == nil || !retry || attempt >= options.PublishRetries {
+			return err
+		}
+
+		log.warn("retrying to publish docs", "url", req.URL.String(), "error", err, "delay", delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// send sends a request publishing docs. When it fails, the first result reports whether it's worth retrying.
+func send(req *http.Request) (bool, error) {
+	req.Header.Set("User-Agent", "protoc-gen-doc/"+VERSION)
+	resp, err := publishClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("Couldn't publish to %s: %v", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("Couldn't publish to %s: %s: %s", req.URL, resp.Status,
+			strings.TrimSpace(string(message)))
+	}
+
+	return false, nil
+}
@@ -0,0 +1,106 @@
+package gendoc_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionsForPublish(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "markdown,docs.md"))
+	require.NoError(t, err)
+	require.Empty(t, options.PublishURL)
+	require.Equal(t, DefaultPublishRetries, options.PublishRetries)
+
+	options, err = ParseOptions(newBookingRequest(t,
+		"markdown,docs.md:publish_url=https://docs.example.com/upload?project=booking,publish_retries=0"))
+	require.NoError(t, err)
+	require.Equal(t, "https://docs.example.com/upload?project=booking", options.PublishURL)
+	require.Zero(t, options.PublishRetries)
+
+	_, err = ParseOptions(newBookingRequest(t, "markdown,docs.md:publish_url=docs.example.com"))
+	require.EqualError(t, err, "Invalid publish_url value: docs.example.com")
+
+	_, err = ParseOptions(newBookingRequest(t, "markdown,docs.md:publish_retries=-1"))
+	require.EqualError(t, err, "Invalid publish_retries value: -1")
+}
+
+func TestPublish(t *testing.T) {
+	t.Setenv(PublishAuthEnv, "Bearer secret")
+
+	var requests int32
+	published := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt fails, and is retried
+		if atomic.AddInt32(&requests, 1) == 1 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		for _, header := range r.MultipartForm.File["file"] {
+			f, err := header.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(f)
+			require.NoError(t, err)
+			published[header.Filename] = string(content)
+		}
+	}))
+	defer server.Close()
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t,
+		"markdown,docs.md:publish_url="+server.URL+",manifest=m.json"))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, requests)
+	require.Len(t, published, 2)
+	require.Equal(t, resp.File[0].GetContent(), published["docs.md"])
+	require.Equal(t, resp.File[1].GetContent(), published["m.json"])
+}
+
+func TestPublishFailure(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "unknown project", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// client errors aren't retried
+	_, err := new(Plugin).Generate(newBookingRequest(t, "markdown,docs.md:publish_url="+server.URL))
+	require.EqualError(t, err, "Couldn't publish to "+server.URL+": 404 Not Found: unknown project")
+	require.EqualValues(t, 1, requests)
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	})
+	_, err = new(Plugin).Generate(newBookingRequest(t,
+		"markdown,docs.md:publish_url="+server.URL+",publish_retries=0"))
+	require.EqualError(t, err, "Couldn't publish to "+server.URL+": 503 Service Unavailable: down for maintenance")
+	require.EqualValues(t, 2, requests)
+}
+
+func TestGenerateDoesNotPublish(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	// only the protoc plugin publishes, so that previewing docs (e.g. with the serve command) never does
+	files, err := Generate(set, Options{Parameter: "markdown,docs.md:publish_url=" + server.URL})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Zero(t, atomic.LoadInt32(&requests))
+}
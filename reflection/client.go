@@ -0,0 +1,436 @@
+// Package reflection fetches file descriptors from a running gRPC server through the server reflection service, so that
+// services can be documented without access to their proto files.
+//
+// Only the small subset of gRPC needed to talk to the reflection service is implemented (unary-style calls over
+// HTTP/2), which keeps this package free of a dependency on grpc-go.
+package reflection
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	reflectionV1Path      = "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"
+	reflectionV1AlphaPath = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
+
+	grpcStatusOK            = "0"
+	grpcStatusUnimplemented = "12"
+)
+
+// Field numbers of grpc.reflection.v1.ServerReflectionRequest and ServerReflectionResponse.
+const (
+	requestFileByFilename       protowire.Number = 3
+	requestFileContainingSymbol protowire.Number = 4
+	requestListServices         protowire.Number = 7
+
+	responseFileDescriptor protowire.Number = 4
+	responseListServices   protowire.Number = 6
+	responseError          protowire.Number = 7
+)
+
+// Client fetches descriptors from a server implementing grpc.reflection.v1 (or v1alpha) ServerReflection. A Client is
+// not safe for concurrent use. Its connections are reused across calls, and should be closed with Close once done.
+type Client struct {
+	// Address of the server, e.g. "localhost:50051".
+	Address string
+	// Plaintext connects without TLS (HTTP/2 with prior knowledge).
+	Plaintext bool
+	// TLSConfig is used for TLS connections. The default configuration is used when nil.
+	TLSConfig *tls.Config
+	// HTTPClient, if set, is used instead of a client derived from Plaintext and TLSConfig. It must speak HTTP/2.
+	HTTPClient *http.Client
+
+	path   string
+	client *http.Client
+}
+
+// Close closes the connections the client opened. A closed client can still be used, opening new connections.
+func (c *Client) Close() {
+	if c.client != nil {
+		c.client.CloseIdleConnections()
+	}
+}
+
+// ListServices returns the fully qualified names of the services exposed by the server, in alphabetical order.
+func (c *Client) ListServices(ctx context.Context) ([]string, error) {
+	resp, err := c.call(ctx, requestListServices, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(resp.services)
+	return resp.services, nil
+}
+
+// FileDescriptorSet returns the files defining the server's services (except the reflection service itself) along
+// with all of their dependencies. Files are ordered so that dependencies always come before the files importing them.
+func (c *Client) FileDescriptorSet(ctx context.Context) (*descriptorpb.FileDescriptorSet, error) {
+	services, err := c.ListServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, service := range services {
+		if strings.HasPrefix(service, "grpc.reflection.") {
+			continue
+		}
+
+		resp, err := c.call(ctx, requestFileContainingSymbol, service)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := addFiles(files, resp.files); err != nil {
+			return nil, err
+		}
+	}
+
+	// servers aren't required to send dependencies along, so fetch whatever is still missing
+	for missing := missingDependencies(files); len(missing) > 0; missing = missingDependencies(files) {
+		for _, name := range missing {
+			resp, err := c.call(ctx, requestFileByFilename, name)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := addFiles(files, resp.files); err != nil {
+				return nil, err
+			}
+
+			if _, ok := files[name]; !ok {
+				return nil, fmt.Errorf("Server did not return file %s", name)
+			}
+		}
+	}
+
+	return &descriptorpb.FileDescriptorSet{File: sortFiles(files)}, nil
+}
+
+type response struct {
+	files    [][]byte
+	services []string
+}
+
+// call sends a single ServerReflectionRequest and returns the decoded response.
+func (c *Client) call(ctx context.Context, field protowire.Number, value string) (*response, error) {
+	var msg []byte
+	msg = protowire.AppendTag(msg, field, protowire.BytesType)
+	msg = protowire.AppendString(msg, value)
+
+	if c.path != "" {
+		return c.send(ctx, c.path, msg)
+	}
+
+	resp, err := c.send(ctx, reflectionV1Path, msg)
+	if errors.Is(err, errUnimplemented) {
+		c.path = reflectionV1AlphaPath
+		return c.send(ctx, c.path, msg)
+	}
+
+	if err == nil {
+		c.path = reflectionV1Path
+	}
+
+	return resp, err
+}
+
+var errUnimplemented = errors.New("Server reflection is not implemented")
+
+func (c *Client) send(ctx context.Context, path string, msg []byte) (*response, error) {
+	client := c.httpClient()
+	scheme := "https"
+	if c.Plaintext {
+		scheme = "http"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, scheme+"://"+c.Address+path, bytes.NewReader(frame(msg)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	httpResp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected HTTP status from %s: %s", c.Address, httpResp.Status)
+	}
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := grpcStatus(httpResp); err != nil {
+		return nil, err
+	}
+
+	messages, err := unframe(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, errors.New("Server sent no reflection response")
+	}
+
+	return decodeResponse(messages[0])
+}
+
+// httpClient returns the HTTPClient, or the client derived from Plaintext and TLSConfig, which is built by the first
+// call and reused by the following ones.
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+
+	if c.client == nil {
+		if c.Plaintext {
+			c.client = &http.Client{Transport: plaintextTransport()}
+		} else {
+			c.client = &http.Client{Transport: &http.Transport{TLSClientConfig: c.TLSConfig, ForceAttemptHTTP2: true}}
+		}
+	}
+
+	return c.client
+}
+
+// grpcStatus converts the grpc-status of a response (sent as a trailer, or as a header for trailers-only responses)
+// into an error.
+func grpcStatus(resp *http.Response) error {
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+
+	switch status {
+	case grpcStatusOK:
+		return nil
+	case grpcStatusUnimplemented:
+		return errUnimplemented
+	case "":
+		return errors.New("Server response is missing grpc-status")
+	}
+
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+
+	return fmt.Errorf("Server reflection failed with status %s: %s", status, message)
+}
+
+// frame wraps a message in the gRPC length-prefixed message format (uncompressed).
+func frame(msg []byte) []byte {
+	out := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(out[1:], uint32(len(msg)))
+	return append(out, msg...)
+}
+
+// unframe splits a gRPC response body into its messages.
+func unframe(body []byte) ([][]byte, error) {
+	var messages [][]byte
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		if body[0] != 0 {
+			return nil, errors.New("Compressed reflection responses are not supported")
+		}
+
+		size := binary.BigEndian.Uint32(body[1:5])
+		if uint64(len(body)-5) < uint64(size) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		messages = append(messages, body[5:5+size])
+		body = body[5+size:]
+	}
+
+	return messages, nil
+}
+
+func decodeResponse(msg []byte) (*response, error) {
+	resp := new(response)
+	err := consumeFields(msg, func(num protowire.Number, value []byte) error {
+		switch num {
+		case responseFileDescriptor:
+			return consumeFields(value, func(num protowire.Number, value []byte) error {
+				if num == 1 {
+					resp.files = append(resp.files, value)
+				}
+				return nil
+			})
+		case responseListServices:
+			return consumeFields(value, func(num protowire.Number, value []byte) error {
+				if num != 1 {
+					return nil
+				}
+
+				return consumeFields(value, func(num protowire.Number, value []byte) error {
+					if num == 1 {
+						resp.services = append(resp.services, string(value))
+					}
+					return nil
+				})
+			})
+		case responseError:
+			return decodeError(value)
+		}
+
+		return nil
+	})
+
+	return resp, err
+}
+
+func decodeError(msg []byte) error {
+	code, message := int64(0), ""
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(msg)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			code, msg = int64(int32(v)), msg[n:]
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(msg)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			message, msg = v, msg[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, msg)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			msg = msg[n:]
+		}
+	}
+
+	return fmt.Errorf("Server reflection failed with status %d: %s", code, message)
+}
+
+// consumeFields calls fn for every length-delimited field of msg, skipping fields of other wire types.
+func consumeFields(msg []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		if typ != protowire.BytesType {
+			n := protowire.ConsumeFieldValue(num, typ, msg)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			msg = msg[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addFiles(files map[string]*descriptorpb.FileDescriptorProto, serialized [][]byte) error {
+	for _, data := range serialized {
+		fd := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(data, fd); err != nil {
+			return err
+		}
+
+		files[fd.GetName()] = fd
+	}
+
+	return nil
+}
+
+func missingDependencies(files map[string]*descriptorpb.FileDescriptorProto) []string {
+	missing := make(map[string]bool)
+	for _, fd := range files {
+		for _, dep := range fd.GetDependency() {
+			if _, ok := files[dep]; !ok {
+				missing[dep] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// sortFiles orders files alphabetically, except that dependencies are always placed before their importers.
+func sortFiles(files map[string]*descriptorpb.FileDescriptorProto) []*descriptorpb.FileDescriptorProto {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]*descriptorpb.FileDescriptorProto, 0, len(files))
+	visited := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		fd, ok := files[name]
+		if !ok || visited[name] {
+			return
+		}
+
+		visited[name] = true
+		for _, dep := range fd.GetDependency() {
+			visit(dep)
+		}
+
+		sorted = append(sorted, fd)
+	}
+
+	for _, name := range names {
+		visit(name)
+	}
+
+	return sorted
+}
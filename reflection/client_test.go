@@ -0,0 +1,198 @@
+package reflection_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc/reflection"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// fakeServer implements just enough of grpc.reflection to exercise the client. Like some real servers, it only returns
+// the requested file without its dependencies.
+type fakeServer struct {
+	files     map[string]*descriptorpb.FileDescriptorProto
+	alphaOnly bool
+}
+
+func newFakeServer(t *testing.T, alphaOnly bool) (*fakeServer, *httptest.Server) {
+	set, err := utils.LoadDescriptorSet("..", "fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	s := &fakeServer{files: make(map[string]*descriptorpb.FileDescriptorProto), alphaOnly: alphaOnly}
+	for _, fd := range set.GetFile() {
+		s.files[fd.GetName()] = fd
+	}
+
+	srv := httptest.NewUnstartedServer(s)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	return s, srv
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+
+	if r.ProtoMajor != 2 || (s.alphaOnly && !strings.Contains(r.URL.Path, "v1alpha")) {
+		w.Header().Set("Grpc-Status", "12")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	num, _, n := protowire.ConsumeTag(body[5:])
+	value, _ := protowire.ConsumeString(body[5+n:])
+
+	var resp []byte
+	switch num {
+	case 7: // list_services
+		var services []byte
+		for _, fd := range s.files {
+			for _, service := range fd.GetService() {
+				var entry []byte
+				entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+				entry = protowire.AppendString(entry, fd.GetPackage()+"."+service.GetName())
+
+				services = protowire.AppendTag(services, 1, protowire.BytesType)
+				services = protowire.AppendBytes(services, entry)
+			}
+		}
+
+		resp = protowire.AppendTag(resp, 6, protowire.BytesType)
+		resp = protowire.AppendBytes(resp, services)
+	case 3, 4: // file_by_filename, file_containing_symbol
+		fd := s.find(num, value)
+		if fd == nil {
+			var notFound []byte
+			notFound = protowire.AppendTag(notFound, 1, protowire.VarintType)
+			notFound = protowire.AppendVarint(notFound, 5)
+			notFound = protowire.AppendTag(notFound, 2, protowire.BytesType)
+			notFound = protowire.AppendString(notFound, value+" not found")
+
+			resp = protowire.AppendTag(resp, 7, protowire.BytesType)
+			resp = protowire.AppendBytes(resp, notFound)
+			break
+		}
+
+		data, _ := proto.Marshal(fd)
+		var files []byte
+		files = protowire.AppendTag(files, 1, protowire.BytesType)
+		files = protowire.AppendBytes(files, data)
+
+		resp = protowire.AppendTag(resp, 4, protowire.BytesType)
+		resp = protowire.AppendBytes(resp, files)
+	}
+
+	frame := make([]byte, 5)
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(resp)))
+	w.Write(append(frame, resp...))
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", "0")
+}
+
+func (s *fakeServer) find(num protowire.Number, value string) *descriptorpb.FileDescriptorProto {
+	if num == 3 {
+		return s.files[value]
+	}
+
+	for _, fd := range s.files {
+		for _, service := range fd.GetService() {
+			if fd.GetPackage()+"."+service.GetName() == value {
+				return fd
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestListServices(t *testing.T) {
+	_, srv := newFakeServer(t, false)
+	defer srv.Close()
+
+	client := &Client{Address: srv.Listener.Addr().String(), HTTPClient: srv.Client()}
+	services, err := client.ListServices(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"com.book.BookService", "com.example.BookingService", "com.example.VehicleService"}, services)
+}
+
+func TestListServicesWithPlaintext(t *testing.T) {
+	s, _ := newFakeServer(t, false)
+	var connections int32
+	srv := httptest.NewUnstartedServer(h2c.NewHandler(s, new(http2.Server)))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client := &Client{Address: srv.Listener.Addr().String(), Plaintext: true}
+	defer client.Close()
+
+	// the connection is reused by the following calls
+	for i := 0; i < 3; i++ {
+		services, err := client.ListServices(context.Background())
+		require.NoError(t, err)
+		require.Len(t, services, 3)
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&connections))
+}
+
+func TestFileDescriptorSet(t *testing.T) {
+	for _, alphaOnly := range []bool{false, true} {
+		_, srv := newFakeServer(t, alphaOnly)
+
+		client := &Client{Address: srv.Listener.Addr().String(), HTTPClient: srv.Client()}
+		set, err := client.FileDescriptorSet(context.Background())
+		srv.Close()
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(set.GetFile()))
+		for _, fd := range set.GetFile() {
+			names = append(names, fd.GetName())
+		}
+
+		require.Equal(t, []string{
+			"google/protobuf/descriptor.proto",
+			"github.com/pseudomuto/protokit/fixtures/extend.proto",
+			"Booking.proto",
+			"Vehicle.proto",
+			"nested/Book.proto",
+		}, names)
+	}
+}
+
+func TestFileDescriptorSetWithMissingDependency(t *testing.T) {
+	s, srv := newFakeServer(t, false)
+	defer srv.Close()
+	delete(s.files, "google/protobuf/descriptor.proto")
+
+	client := &Client{Address: srv.Listener.Addr().String(), HTTPClient: srv.Client()}
+	_, err := client.FileDescriptorSet(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "google/protobuf/descriptor.proto not found")
+}
+
+func TestUnreachableServer(t *testing.T) {
+	_, srv := newFakeServer(t, false)
+	address := srv.Listener.Addr().String()
+	srv.Close()
+
+	client := &Client{Address: address}
+	_, err := client.ListServices(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,22 @@
+package reflection
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+)
+
+// plaintextTransport returns a transport speaking HTTP/2 over cleartext TCP (h2c with prior knowledge), which is what
+// gRPC servers expect when TLS is disabled.
+func plaintextTransport() http.RoundTripper {
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}
+}
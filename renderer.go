@@ -4,7 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	html_template "html/template"
+	"io"
+	"math"
+	"reflect"
 	text_template "text/template"
 
 	"github.com/Masterminds/sprig"
@@ -20,6 +24,24 @@ const (
 	RenderTypeHTML
 	RenderTypeJSON
 	RenderTypeMarkdown
+	RenderTypeCoverage
+	RenderTypeCoverageJSON
+	RenderTypeLint
+	RenderTypeLintJSON
+	RenderTypeDot
+	RenderTypeMermaid
+	RenderTypeRTF
+	RenderTypeRST
+	RenderTypeGFM
+	RenderTypeWiki
+	RenderTypeCSV
+	RenderTypeTSV
+	RenderTypeSQL
+	RenderTypeGraphQL
+	RenderTypeAvro
+	RenderTypeTypeScript
+	RenderTypePostman
+	RenderTypeAsyncAPI
 )
 
 // NewRenderType creates a RenderType from the supplied string. If the type is not known, (0, error) is returned. It is
@@ -34,51 +56,311 @@ func NewRenderType(renderType string) (RenderType, error) {
 		return RenderTypeJSON, nil
 	case "markdown":
 		return RenderTypeMarkdown, nil
+	case "coverage":
+		return RenderTypeCoverage, nil
+	case "coverage_json":
+		return RenderTypeCoverageJSON, nil
+	case "lint":
+		return RenderTypeLint, nil
+	case "lint_json":
+		return RenderTypeLintJSON, nil
+	case "dot":
+		return RenderTypeDot, nil
+	case "mermaid":
+		return RenderTypeMermaid, nil
+	case "rtf":
+		return RenderTypeRTF, nil
+	case "rst":
+		return RenderTypeRST, nil
+	case "gfm":
+		return RenderTypeGFM, nil
+	case "wiki":
+		return RenderTypeWiki, nil
+	case "csv":
+		return RenderTypeCSV, nil
+	case "tsv":
+		return RenderTypeTSV, nil
+	case "sql":
+		return RenderTypeSQL, nil
+	case "graphql":
+		return RenderTypeGraphQL, nil
+	case "avro":
+		return RenderTypeAvro, nil
+	case "typescript":
+		return RenderTypeTypeScript, nil
+	case "postman":
+		return RenderTypePostman, nil
+	case "asyncapi":
+		return RenderTypeAsyncAPI, nil
+	}
+
+	for i, registered := range registeredRenderTypes {
+		if registered.name == renderType {
+			return renderTypeRegistered + RenderType(i), nil
+		}
 	}
 
 	return 0, errors.New("Invalid render type")
 }
 
-func (rt RenderType) renderer() (Processor, error) {
-	tmpl, err := rt.template()
+// TemplateResolver returns the Go template of a format registered with RegisterRenderType, given the name of the
+// format. A resolver can serve several formats, e.g. from an embed.FS.
+type TemplateResolver func(name string) (string, error)
+
+// registeredRenderType is a format registered with RegisterRenderType.
+type registeredRenderType struct {
+	name     string
+	resolver TemplateResolver
+}
+
+// renderTypeRegistered is the RenderType of the first format registered with RegisterRenderType. The following ones
+// are numbered in the order they were registered.
+const renderTypeRegistered = RenderTypeAsyncAPI + 1
+
+var registeredRenderTypes []registeredRenderType
+
+// RegisterRenderType makes a format rendered with a Go template available by name, like the built-in ones, e.g. for the
+// organization-specific formats of a program embedding the plugin. The template is resolved whenever docs are generated
+// in that format, and rendered like a custom template file. Registering a name again replaces its resolver, while the
+// names of the built-in formats take precedence over registered ones. This must be called before options are parsed
+// (e.g. in an init function).
+func RegisterRenderType(name string, resolver TemplateResolver) {
+	for i, registered := range registeredRenderTypes {
+		if registered.name == name {
+			registeredRenderTypes[i].resolver = resolver
+			return
+		}
+	}
+
+	if int(renderTypeRegistered)+len(registeredRenderTypes) > math.MaxInt8 {
+		panic("Too many registered render types")
+	}
+
+	registeredRenderTypes = append(registeredRenderTypes, registeredRenderType{name: name, resolver: resolver})
+}
+
+// registered returns the format rt was registered as with RegisterRenderType. The second result is false for the
+// built-in formats.
+func (rt RenderType) registered() (registeredRenderType, bool) {
+	i := int(rt - renderTypeRegistered)
+	if rt < renderTypeRegistered || i >= len(registeredRenderTypes) {
+		return registeredRenderType{}, false
+	}
+
+	return registeredRenderTypes[i], true
+}
+
+// registeredTemplate returns the template of rt when it's a format registered with RegisterRenderType, and "" for the
+// built-in formats.
+func (rt RenderType) registeredTemplate() (string, error) {
+	registered, ok := rt.registered()
+	if !ok {
+		return "", nil
+	}
+
+	tmpl, err := registered.resolver(registered.name)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("Couldn't resolve template of render type %s: %v", registered.name, err)
 	}
 
+	return tmpl, nil
+}
+
+// renderer returns the processor of rt. Template based formats use their built-in template in the given layout (see
+// the template option).
+func (rt RenderType) renderer(layout string) (Processor, error) {
 	switch rt {
-	case RenderTypeDocBook:
-		return &textRenderer{string(tmpl)}, nil
-	case RenderTypeHTML:
-		return &htmlRenderer{string(tmpl)}, nil
 	case RenderTypeJSON:
 		return new(jsonRenderer), nil
-	case RenderTypeMarkdown:
-		return &htmlRenderer{string(tmpl)}, nil
+	case RenderTypeCoverage:
+		return new(coverageRenderer), nil
+	case RenderTypeCoverageJSON:
+		return &coverageRenderer{json: true}, nil
+	case RenderTypeLint:
+		return new(lintRenderer), nil
+	case RenderTypeLintJSON:
+		return &lintRenderer{json: true}, nil
+	case RenderTypeDot:
+		return new(dotRenderer), nil
+	case RenderTypeMermaid:
+		return new(mermaidRenderer), nil
+	case RenderTypeCSV:
+		return new(csvRenderer), nil
+	case RenderTypeTSV:
+		return &csvRenderer{tsv: true}, nil
+	case RenderTypeSQL:
+		return new(sqlRenderer), nil
+	case RenderTypeGraphQL:
+		return new(graphqlRenderer), nil
+	case RenderTypeAvro:
+		return new(avroRenderer), nil
+	case RenderTypeTypeScript:
+		return new(typescriptRenderer), nil
+	case RenderTypePostman:
+		return new(postmanRenderer), nil
+	case RenderTypeAsyncAPI:
+		return new(asyncapiRenderer), nil
 	}
 
-	return nil, errors.New("Unable to create a processor")
-}
+	if registered, ok := rt.registered(); ok {
+		tmpl, err := rt.registeredTemplate()
+		if err != nil {
+			return nil, err
+		}
+		return &textRenderer{tmpl, registered.name}, nil
+	}
+
+	builtin := builtinTemplate(rt, layout)
+	if builtin == nil {
+		return nil, errors.New("Couldn't find template for render type")
+	}
+	tmpl := string(builtin.Source())
 
-func (rt RenderType) template() ([]byte, error) {
 	switch rt {
-	case RenderTypeDocBook:
-		return docbookTmpl, nil
-	case RenderTypeHTML:
-		return htmlTmpl, nil
-	case RenderTypeJSON:
-		return nil, nil
-	case RenderTypeMarkdown:
-		return markdownTmpl, nil
+	case RenderTypeDocBook, RenderTypeRTF, RenderTypeRST:
+		return &textRenderer{tmpl, builtin.file}, nil
+	case RenderTypeHTML, RenderTypeMarkdown, RenderTypeWiki:
+		return &htmlRenderer{tmpl, builtin.file, false}, nil
+	case RenderTypeGFM:
+		return &htmlRenderer{tmpl, builtin.file, true}, nil
 	}
 
-	return nil, errors.New("Couldn't find template for render type")
+	return nil, errors.New("Unable to create a processor")
 }
 
 var funcMap = map[string]interface{}{
-	"p":      PFilter,
-	"para":   ParaFilter,
-	"nobr":   NoBrFilter,
-	"anchor": AnchorFilter,
+	"p":          PFilter,
+	"para":       ParaFilter,
+	"nobr":       NoBrFilter,
+	"anchor":     AnchorFilter,
+	"highlight":  HighlightFilter,
+	"display":    DisplayFilter,
+	"rtf":        RtfFilter,
+	"rst":        RstFilter,
+	"rstHeading": RstHeadingFilter,
+	// Whether the Markdown template renders GitHub Flavored Markdown. Only the gfm format sets it, see htmlRenderer.
+	"gfm": func() bool { return false },
+}
+
+// sandboxDeniedFuncs are the sprig functions unavailable in sandbox mode, in addition to the non-hermetic ones (which
+// read environment variables, resolve host names, or depend on the current time or randomness). They're either CPU
+// intensive or allocate arbitrary amounts of memory.
+var sandboxDeniedFuncs = []string{
+	"buildCustomCert",
+	"decryptAES",
+	"derivePassword",
+	"encryptAES",
+	"genCA",
+	"genPrivateKey",
+	"genSelfSignedCert",
+	"genSignedCert",
+	"repeat",
+	"until",
+	"untilStep",
+}
+
+// sprigFuncMap returns the sprig functions available to the template being rendered, which are restricted to a safe
+// subset in sandbox mode.
+func (t *Template) sprigFuncMap() map[string]interface{} {
+	if !t.Sandbox {
+		return sprig.GenericFuncMap()
+	}
+
+	funcs := sprig.HermeticTxtFuncMap()
+	for _, name := range sandboxDeniedFuncs {
+		delete(funcs, name)
+	}
+
+	return funcs
+}
+
+// funcMap returns the functions that depend on the template being rendered.
+func (t *Template) funcMap() map[string]interface{} {
+	funcs := map[string]interface{}{
+		"t":             func(s string) string { return Translate(t.Locale, s) },
+		"expand":        t.Expand,
+		"example":       t.Example,
+		"enumNumber":    func(v *EnumValue) string { return FormatEnumNumber(v.Number, t.EnumNumberFormat) },
+		"label":         func(label string) string { return FormatLabel(label, t.Labels) },
+		"wellKnownType": LookupWellKnownType,
+		"snippet":       func(name string) (html_template.HTML, error) { return t.Snippets.Snippet(name) },
+	}
+
+	var docbookIDs map[string]bool
+	funcs["docbookLink"] = func(fullName, text string) string {
+		if docbookIDs == nil {
+			docbookIDs = t.docbookIDs()
+		}
+		return docbookLink(docbookIDs, t, fullName, text)
+	}
+
+	var wikiTypes map[string]bool
+	funcs["wikiLink"] = func(fullName, text string) string {
+		if wikiTypes == nil {
+			wikiTypes = t.documentedTypes()
+		}
+		return wikiLink(wikiTypes, t, fullName, text)
+	}
+
+	var types map[string]bool
+	documented := func() map[string]bool {
+		if types == nil {
+			types = t.documentedTypes()
+		}
+		return types
+	}
+	var entities map[string]interface{}
+	funcs["lookup"] = func(fullName string) interface{} {
+		if entities == nil {
+			entities = t.entities()
+		}
+		return lookupEntity(entities, fullName)
+	}
+
+	options := &optionLookup{resolver: t.optionTypes}
+	funcs["option"] = func(entity interface{}, name string) interface{} {
+		value, _ := options.get(entity, name)
+		return value
+	}
+	funcs["hasOption"] = func(entity interface{}, name string) bool {
+		_, ok := options.get(entity, name)
+		return ok
+	}
+
+	funcs["relpath"] = t.relativePath
+	funcs["typeurl"] = func(fullName string) string { return t.typeURL(documented(), fullName) }
+	funcs["link"] = func(fullName, text string) html_template.HTML { return t.typeLink(documented(), fullName, text) }
+
+	var targets *commentTargets
+	links := func(content string) string {
+		if !t.CommentLinks {
+			return content
+		}
+		if targets == nil {
+			targets = t.commentTargets(documented())
+		}
+		return t.commentLinks(targets, documented(), content)
+	}
+	funcs["refs"] = func(content string) html_template.HTML {
+		return html_template.HTML(links(html_template.HTMLEscapeString(content)))
+	}
+
+	var rstTypes map[string]bool
+	funcs["rstRef"] = func(fullName, text string) string {
+		if rstTypes == nil {
+			rstTypes = t.documentedTypes()
+		}
+		return rstRef(rstTypes, t, fullName, text)
+	}
+
+	sanitize := sanitizer(t.SanitizeHTML)
+	if sanitize == nil {
+		sanitize = func(content string) string { return content }
+	}
+	funcs["p"] = func(content string) html_template.HTML { return PFilter(links(sanitize(content))) }
+	funcs["nobr"] = func(content string) html_template.HTML { return NoBrFilter(links(sanitize(content))) }
+
+	return funcs
 }
 
 // Processor is an interface that is satisfied by all built-in processors (text, html, and json).
@@ -86,66 +368,220 @@ type Processor interface {
 	Apply(template *Template) ([]byte, error)
 }
 
+// StreamingProcessor is implemented by processors that can write their output incrementally rather than building the
+// whole document in memory first. All built-in processors implement it.
+type StreamingProcessor interface {
+	ApplyTo(w io.Writer, template *Template) error
+}
+
 // RenderTemplate renders the template based on the render type. It supports overriding the default input templates by
 // supplying a non-empty string as the last parameter.
 //
 // Example: generating an HTML template (assuming you've got a Template object)
-//     data, err := RenderTemplate(RenderTypeHTML, &template, "")
+//
+//	data, err := RenderTemplate(RenderTypeHTML, &template, "")
 //
 // Example: generating a custom template (assuming you've got a Template object)
-//     data, err := RenderTemplate(RenderTypeHTML, &template, "{{range .Files}}{{.Name}}{{end}}")
+//
+//	data, err := RenderTemplate(RenderTypeHTML, &template, "{{range .Files}}{{.Name}}{{end}}")
 func RenderTemplate(kind RenderType, template *Template, inputTemplate string) ([]byte, error) {
-	if inputTemplate != "" {
-		processor := &textRenderer{inputTemplate}
-		return processor.Apply(template)
+	var buf bytes.Buffer
+	if err := RenderTemplateTo(&buf, kind, template, inputTemplate); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderTemplateTo is like RenderTemplate, but writes the output to w as it's being rendered. This keeps memory usage
+// down for very large outputs (e.g. JSON for huge descriptor sets). If rendering fails, w may have received partial
+// output.
+func RenderTemplateTo(w io.Writer, kind RenderType, template *Template, inputTemplate string) error {
+	var processor Processor = &textRenderer{inputTemplate: inputTemplate}
+	if inputTemplate == "" {
+		var err error
+		layout := ""
+		if kind == RenderTypeHTML && template.Theme != nil {
+			layout = template.Theme.Layout
+		}
+
+		if processor, err = kind.renderer(layout); err != nil {
+			return err
+		}
+	}
+
+	return renderWith(w, processor, template)
+}
+
+// renderWith writes the output of processor to w, as it's being rendered when the processor is a StreamingProcessor.
+func renderWith(w io.Writer, processor Processor, template *Template) error {
+	if streaming, ok := processor.(StreamingProcessor); ok {
+		return streaming.ApplyTo(w, template)
 	}
 
-	processor, err := kind.renderer()
+	data, err := processor.Apply(template)
 	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// applyBuffered implements Processor.Apply in terms of StreamingProcessor.ApplyTo.
+func applyBuffered(processor StreamingProcessor, template *Template) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := processor.ApplyTo(&buf, template); err != nil {
 		return nil, err
 	}
 
-	return processor.Apply(template)
+	return buf.Bytes(), nil
 }
 
 type textRenderer struct {
 	inputTemplate string
+	name          string
 }
 
 func (mr *textRenderer) Apply(template *Template) ([]byte, error) {
-	tmpl, err := text_template.New("Text Template").Funcs(funcMap).Funcs(sprig.TxtFuncMap()).Parse(mr.inputTemplate)
+	return applyBuffered(mr, template)
+}
+
+func (mr *textRenderer) ApplyTo(w io.Writer, template *Template) error {
+	tmpl, err := mr.parse(template)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var buf bytes.Buffer
-	if err = tmpl.Execute(&buf, template); err != nil {
+	if err := tmpl.Execute(w, template); err != nil {
+		return newTemplateError(mr.name, mr.inputTemplate, err)
+	}
+
+	return nil
+}
+
+// parse compiles the input template with the functions available to template, and the blocks of the built-in
+// templates.
+func (mr *textRenderer) parse(template *Template) (*text_template.Template, error) {
+	tmpl := text_template.New("Text Template").
+		Funcs(funcMap).
+		Funcs(template.sprigFuncMap()).
+		Funcs(template.funcMap())
+	if _, err := tmpl.New(TemplateBlocksPrefix + "blocks").Parse(blocksTemplate); err != nil {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	if _, err := tmpl.Parse(mr.inputTemplate); err != nil {
+		return nil, newTemplateError(mr.name, mr.inputTemplate, err)
+	}
+
+	return tmpl, nil
 }
 
 type htmlRenderer struct {
 	inputTemplate string
+	name          string
+	// Whether the Markdown template renders GitHub Flavored Markdown, which the template checks with the gfm function.
+	gfm bool
 }
 
 func (mr *htmlRenderer) Apply(template *Template) ([]byte, error) {
-	tmpl, err := html_template.New("Text Template").Funcs(funcMap).Funcs(sprig.HtmlFuncMap()).Parse(mr.inputTemplate)
-	if err != nil {
-		return nil, err
+	return applyBuffered(mr, template)
+}
+
+func (mr *htmlRenderer) ApplyTo(w io.Writer, template *Template) error {
+	tmpl := html_template.New("Text Template").
+		Funcs(funcMap).
+		Funcs(template.sprigFuncMap()).
+		Funcs(template.funcMap()).
+		Funcs(html_template.FuncMap{"gfm": func() bool { return mr.gfm }})
+	if _, err := tmpl.New(TemplateBlocksPrefix + "blocks").Parse(blocksTemplate); err != nil {
+		return err
 	}
 
-	var buf bytes.Buffer
-	if err = tmpl.Execute(&buf, template); err != nil {
-		return nil, err
+	if _, err := tmpl.Parse(mr.inputTemplate); err != nil {
+		return newTemplateError(mr.name, mr.inputTemplate, err)
 	}
 
-	return buf.Bytes(), nil
+	if err := tmpl.Execute(w, template); err != nil {
+		return newTemplateError(mr.name, mr.inputTemplate, err)
+	}
+
+	return nil
 }
 
 type jsonRenderer struct{}
 
 func (r *jsonRenderer) Apply(template *Template) ([]byte, error) {
-	return json.MarshalIndent(template, "", "  ")
+	return applyBuffered(r, template)
+}
+
+// ApplyTo writes the same document as json.MarshalIndent(template, "", "  "), but encodes one file at a time so that
+// the whole document never has to be held in memory.
+func (r *jsonRenderer) ApplyTo(w io.Writer, template *Template) error {
+	if _, err := io.WriteString(w, "{\n  \"files\": "); err != nil {
+		return err
+	}
+
+	if err := writeJSONArray(w, template.Files); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, ",\n  \"scalarValueTypes\": "); err != nil {
+		return err
+	}
+
+	if err := writeJSONArray(w, template.Scalars); err != nil {
+		return err
+	}
+
+	meta, err := json.MarshalIndent(template.Meta, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, ",\n  \"meta\": %s\n}", meta)
+	return err
+}
+
+// writeJSONArray writes a slice as an indented JSON array nested one level deep within an object, encoding one element
+// at a time.
+func writeJSONArray(w io.Writer, slice interface{}) error {
+	elements := reflect.ValueOf(slice)
+	if elements.IsNil() {
+		_, err := io.WriteString(w, "null")
+		return err
+	}
+
+	if elements.Len() == 0 {
+		_, err := io.WriteString(w, "[]")
+		return err
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i := 0; i < elements.Len(); i++ {
+		data, err := json.MarshalIndent(elements.Index(i).Interface(), "    ", "  ")
+		if err != nil {
+			return err
+		}
+
+		separator := "\n    "
+		if i > 0 {
+			separator = ",\n    "
+		}
+
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "\n  ]")
+	return err
 }
@@ -1,7 +1,11 @@
 package gendoc_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	. "github.com/daotl/protoc-gen-doc"
@@ -31,15 +35,230 @@ func TestRenderers(t *testing.T) {
 	}
 }
 
+func TestHTMLNavigation(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	result := protokit.ParseCodeGenRequest(req)
+
+	output, err := RenderTemplate(RenderTypeHTML, NewTemplate(result, &PluginOptions{PackageOverview: true}), "")
+	require.NoError(t, err)
+
+	html := string(output)
+	require.Contains(t, html, `<nav id="sidebar" aria-labelledby="toc-heading">`)
+	require.Contains(t, html, `<summary aria-expanded="true">com.book</summary>`)
+	require.Contains(t, html, `<summary aria-expanded="false"><a href="#Booking.proto">Booking.proto</a></summary>`)
+	require.Contains(t, html, `<span>com.example</span>
+          <span class="separator">/</span>
+          <a href="#Booking.proto">Booking.proto</a>
+          <span class="separator">/</span>
+          <span>BookingStatus</span>`)
+	require.Contains(t, html, `<li><a href="#package-overview">Package Overview</a></li>`)
+	require.Contains(t, html, `<h3 id="com.example-package">com.example<a class="permalink"`)
+	require.NotContains(t, html, `id="com.book-package"`)
+
+	// the overview is left out unless the package_overview option is enabled
+	output, err = RenderTemplate(RenderTypeHTML, NewTemplate(result, new(PluginOptions)), "")
+	require.NoError(t, err)
+	require.NotContains(t, string(output), `package-overview`)
+}
+
+func TestHTMLAccessibility(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	output, err := RenderTemplate(RenderTypeHTML, template, "")
+	require.NoError(t, err)
+
+	html := string(output)
+	require.Contains(t, html, "<body>\n    <a class=\"skip-link\" href=\"#content\">Skip to content</a>")
+	require.Contains(t, html, `<main id="content" tabindex="-1">`)
+	require.Contains(t, html, `<button id="contrast-toggle" type="button" aria-pressed="false">High contrast</button>`)
+	require.Contains(t, html, `<nav class="breadcrumb" aria-label="Breadcrumb">`)
+
+	// table headers are header cells of their column
+	require.Contains(t, html, `<tr><th scope="col">Field</th><th scope="col">Type</th><th scope="col">Label</th>`)
+	require.NotContains(t, html, "<thead>\n                <tr><td>")
+
+	// badges are announced as the kind of the entity
+	require.Contains(t, html, `<a href="#com.example.Booking"><span class="badge" aria-hidden="true">M</span>`+
+		`<span class="visually-hidden">message </span>Booking</a>`)
+}
+
+func TestHTMLPageSettings(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	output, err := RenderTemplate(RenderTypeHTML, template, "")
+	require.NoError(t, err)
+
+	// the scheme follows the system by default, and readers' choices are applied before the page is drawn
+	html := string(output)
+	require.Contains(t, html, `<html lang="en" data-theme="auto">`)
+	require.Less(t, strings.Index(html, `localStorage.getItem("protoc-gen-doc-theme")`), strings.Index(html, "</head>"))
+	require.Contains(t, html, `<div id="page-settings" hidden>
+        <label for="theme-select">Theme</label>
+        <select id="theme-select">
+          <option value="auto">System</option>
+          <option value="light">Light</option>
+          <option value="dark">Dark</option>
+        </select>`)
+}
+
+func TestHTMLCopyButtons(t *testing.T) {
+	for _, layout := range []string{HTMLTemplateDefault, HTMLTemplateSlate} {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:locale=de,template="+layout))
+		require.NoError(t, err)
+
+		html := resp.File[0].GetContent()
+		require.Contains(t, html, `data-copy="com.example.Booking"`, layout)
+		require.Contains(t, html, `button.textContent = "Kopieren";`, layout)
+		require.Contains(t, html, `document.querySelectorAll("pre")`, layout)
+
+		if layout == HTMLTemplateDefault {
+			require.Contains(t, html, `<h2 id="Booking.proto" data-copy="#Booking.proto">Booking.proto<a `)
+		}
+	}
+}
+
+func TestHTMLPermalinks(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:locale=de"))
+	require.NoError(t, err)
+
+	html := resp.File[0].GetContent()
+	require.Contains(t, html, `<h3 id="com.example.Booking" data-copy="com.example.Booking">Booking`+
+		`<a class="permalink" href="#com.example.Booking" aria-label="Link zu diesem Abschnitt">¶</a></h3>`)
+
+	// the rows of fields, enum values and methods can be linked to
+	require.Contains(t, html, `<tr id="com.example.Booking.vehicle_id">`)
+	require.Contains(t, html, `<tr id="com.example.BookingStatus.StatusCode.BAD_REQUEST">`)
+	require.Contains(t, html, `<tr id="com.example.BookingService.BookVehicle">`)
+}
+
+func TestMarkdownRowAnchors(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "markdown,README.md"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `| <a name="com-example-Booking-vehicle_id"></a> vehicle_id | [int32](#int32) |`)
+	require.Contains(t, content, `| <a name="com-example-BookingType-FUTURE"></a> FUTURE | 101 |`)
+	require.Contains(t, content, `| <a name="com-example-BookingService-BookVehicle"></a> BookVehicle |`)
+}
+
+func TestRenderTemplateTo(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+
+	for _, r := range []RenderType{
+		RenderTypeDocBook,
+		RenderTypeHTML,
+		RenderTypeJSON,
+		RenderTypeMarkdown,
+	} {
+		expected, err := RenderTemplate(r, template, "")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, RenderTemplateTo(&buf, r, template, ""))
+		require.Equal(t, string(expected), buf.String())
+	}
+}
+
+func TestTemplateSandbox(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{TemplateSandbox: true})
+
+	// the built-in templates only use functions available in the sandbox
+	for _, r := range []RenderType{RenderTypeDocBook, RenderTypeHTML, RenderTypeMarkdown, RenderTypeRTF, RenderTypeRST,
+		RenderTypeGFM, RenderTypeWiki} {
+		_, err := RenderTemplate(r, template, "")
+		require.NoError(t, err)
+	}
+
+	for _, layout := range []string{HTMLTemplateSlate, HTMLTemplateMinimal, HTMLTemplatePrint} {
+		template.Theme.Layout = layout
+		_, err = RenderTemplate(RenderTypeHTML, template, "")
+		require.NoError(t, err, layout)
+	}
+	template.Theme.Layout = ""
+
+	output, err := RenderTemplate(RenderTypeHTML, template, `{{range .Files}}{{.Name | upper}}{{end}}`)
+	require.NoError(t, err)
+	require.Equal(t, "BOOKING.PROTO", string(output))
+
+	for _, fn := range []string{`env "HOME"`, `getHostByName "example.com"`, "now", `repeat 1000000 "x"`, "until 1000000", "genPrivateKey \"rsa\""} {
+		_, err := RenderTemplate(RenderTypeHTML, template, "{{"+fn+"}}")
+		require.Error(t, err, fn)
+		require.Contains(t, err.Error(), "not defined", fn)
+	}
+
+	template.Sandbox = false
+	_, err = RenderTemplate(RenderTypeHTML, template, `{{env "HOME"}}`)
+	require.NoError(t, err)
+}
+
+func TestJSONStreamingMatchesMarshalIndent(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	templates := []*Template{
+		NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{IncludeFileSource: true}),
+		NewTemplate(nil, new(PluginOptions)),
+		new(Template),
+	}
+
+	for _, template := range templates {
+		expected, err := json.MarshalIndent(template, "", "  ")
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, RenderTemplateTo(&buf, RenderTypeJSON, template, ""))
+		require.Equal(t, string(expected), buf.String())
+	}
+}
+
 func TestNewRenderType(t *testing.T) {
 	expected := []RenderType{
 		RenderTypeDocBook,
 		RenderTypeHTML,
 		RenderTypeJSON,
 		RenderTypeMarkdown,
+		RenderTypeCoverage,
+		RenderTypeCoverageJSON,
+		RenderTypeLint,
+		RenderTypeLintJSON,
+		RenderTypeDot,
+		RenderTypeMermaid,
+		RenderTypeRTF,
+		RenderTypeRST,
+		RenderTypeGFM,
+		RenderTypeWiki,
+		RenderTypeCSV,
+		RenderTypeTSV,
+		RenderTypeSQL,
+		RenderTypeGraphQL,
+		RenderTypeAvro,
+		RenderTypeTypeScript,
+		RenderTypePostman,
+		RenderTypeAsyncAPI,
 	}
 
-	supplied := []string{"docbook", "html", "json", "markdown"}
+	supplied := []string{
+		"docbook", "html", "json", "markdown", "coverage", "coverage_json", "lint", "lint_json", "dot", "mermaid", "rtf",
+		"rst", "gfm", "wiki", "csv", "tsv", "sql", "graphql", "avro", "typescript", "postman", "asyncapi",
+	}
 
 	for idx, input := range supplied {
 		rt, err := NewRenderType(input)
@@ -53,3 +272,48 @@ func TestNewRenderTypeUnknown(t *testing.T) {
 	require.Zero(t, rt)
 	require.Error(t, err)
 }
+
+func TestRegisterRenderType(t *testing.T) {
+	RegisterRenderType("x-changelog", func(name string) (string, error) {
+		return name + ":{{range .Files}} {{.Name}}{{end}}", nil
+	})
+	RegisterRenderType("x-broken", func(name string) (string, error) {
+		return "", errors.New("no such template")
+	})
+
+	rt, err := NewRenderType("x-changelog")
+	require.NoError(t, err)
+
+	// built-in formats keep their render type
+	html, err := NewRenderType("html")
+	require.NoError(t, err)
+	require.Equal(t, RenderTypeHTML, html)
+
+	options, err := ParseOptions(newBookingRequest(t, "x-changelog,changes.txt"))
+	require.NoError(t, err)
+	require.Equal(t, rt, options.Type)
+	require.Empty(t, options.TemplateFile)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "x-changelog,changes.txt"))
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+	require.Equal(t, "x-changelog: Booking.proto", resp.File[0].GetContent())
+
+	template := NewTemplate(protokit.ParseCodeGenRequest(newBookingRequest(t, "")), new(PluginOptions))
+	data, err := RenderTemplate(rt, template, "")
+	require.NoError(t, err)
+	require.Equal(t, "x-changelog: Booking.proto", string(data))
+
+	// registering a name again replaces its template
+	RegisterRenderType("x-changelog", func(string) (string, error) { return "replaced", nil })
+	again, err := NewRenderType("x-changelog")
+	require.NoError(t, err)
+	require.Equal(t, rt, again)
+
+	data, err = RenderTemplate(rt, template, "")
+	require.NoError(t, err)
+	require.Equal(t, "replaced", string(data))
+
+	_, err = new(Plugin).Generate(newBookingRequest(t, "x-broken,out.txt"))
+	require.EqualError(t, err, "Couldn't resolve template of render type x-broken: no such template")
+}
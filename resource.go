@@ -0,0 +1,150 @@
+package gendoc
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+)
+
+// Resource describes a resource of an AIP-style API (see https://google.aip.dev/123), declared with the
+// google.api.resource message option or the google.api.resource_definition file option.
+type Resource struct {
+	// The resource type, e.g. `pubsub.googleapis.com/Topic`.
+	Type string `json:"type"`
+	// The patterns of the resource's names, e.g. `projects/{project}/topics/{topic}`.
+	Patterns  []string `json:"patterns"`
+	NameField string   `json:"nameField,omitempty"`
+	Plural    string   `json:"plural,omitempty"`
+	Singular  string   `json:"singular,omitempty"`
+}
+
+// ResourceReference describes the resource a field refers to, declared with the google.api.resource_reference field
+// option. Either Type or ChildType is set.
+type ResourceReference struct {
+	// The type of the resource referred to. `*` means any resource.
+	Type string `json:"type,omitempty"`
+	// The type of a resource whose parent is referred to (e.g. the parent field of a List request).
+	ChildType string `json:"childType,omitempty"`
+	// The name patterns of the resource referred to. Only set when its type is declared in the same output.
+	Patterns []string `json:"patterns,omitempty"`
+	// The full name of the message declaring the resource referred to, which is what templates link to. Only set when
+	// the message is documented in the same output.
+	Anchor string `json:"anchor,omitempty"`
+}
+
+// ResourceType returns the type of the resource referred to, or of its child.
+func (r ResourceReference) ResourceType() string {
+	if r.ChildType != "" {
+		return r.ChildType
+	}
+
+	return r.Type
+}
+
+func newResource(rd *annotations.ResourceDescriptor) *Resource {
+	return &Resource{
+		Type:      rd.GetType(),
+		Patterns:  rd.GetPattern(),
+		NameField: rd.GetNameField(),
+		Plural:    rd.GetPlural(),
+		Singular:  rd.GetSingular(),
+	}
+}
+
+// parseResource returns the resource declared by the options of a message, or nil.
+func parseResource(opts proto.Message) *Resource {
+	if !proto.HasExtension(opts, annotations.E_Resource) {
+		return nil
+	}
+
+	return newResource(proto.GetExtension(opts, annotations.E_Resource).(*annotations.ResourceDescriptor))
+}
+
+// parseResourceDefinitions returns the resources declared by the options of a file.
+func parseResourceDefinitions(opts proto.Message) []*Resource {
+	if !proto.HasExtension(opts, annotations.E_ResourceDefinition) {
+		return nil
+	}
+
+	definitions := proto.GetExtension(opts, annotations.E_ResourceDefinition).([]*annotations.ResourceDescriptor)
+	resources := make([]*Resource, 0, len(definitions))
+	for _, rd := range definitions {
+		resources = append(resources, newResource(rd))
+	}
+
+	return resources
+}
+
+// parseResourceReference returns the resource referred to by the options of a field, or nil.
+func parseResourceReference(opts proto.Message) *ResourceReference {
+	if !proto.HasExtension(opts, annotations.E_ResourceReference) {
+		return nil
+	}
+
+	ref := proto.GetExtension(opts, annotations.E_ResourceReference).(*annotations.ResourceReference)
+	return &ResourceReference{Type: ref.GetType(), ChildType: ref.GetChildType()}
+}
+
+// resolveResourceReferences fills in the patterns and anchors of the resource references of the fields in files, using
+// the resources declared in files.
+func resolveResourceReferences(files []*File) {
+	resources := make(map[string]*Resource)
+	anchors := make(map[string]string)
+
+	for _, f := range files {
+		for _, r := range f.ResourceDefinitions {
+			resources[r.Type] = r
+		}
+		for _, m := range f.AllMessages() {
+			if m.Resource != nil {
+				resources[m.Resource.Type] = m.Resource
+				anchors[m.Resource.Type] = m.FullName
+			}
+		}
+	}
+
+	for _, f := range files {
+		for _, m := range f.AllMessages() {
+			for _, field := range m.Fields {
+				ref := field.ResourceReference
+				if ref == nil {
+					continue
+				}
+
+				resource, ok := resources[ref.ResourceType()]
+				if !ok {
+					continue
+				}
+
+				ref.Anchor = anchors[resource.Type]
+				ref.Patterns = resource.Patterns
+				if ref.ChildType != "" {
+					ref.Patterns = parentPatterns(resource.Patterns)
+				}
+			}
+		}
+	}
+}
+
+// parentPatterns returns the patterns of the parents of a resource, e.g. `projects/{project}` for
+// `projects/{project}/topics/{topic}`. Top-level patterns have no parent.
+func parentPatterns(patterns []string) []string {
+	parents := make([]string, 0, len(patterns))
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		segments := strings.Split(pattern, "/")
+		if len(segments) <= 2 {
+			continue
+		}
+
+		parent := strings.Join(segments[:len(segments)-2], "/")
+		if !seen[parent] {
+			seen[parent] = true
+			parents = append(parents, parent)
+		}
+	}
+
+	return parents
+}
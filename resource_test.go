@@ -0,0 +1,97 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newLibraryRequest returns a request documenting an AIP-style library API, with shelves containing books.
+func newLibraryRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "resources.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "library.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+func TestResources(t *testing.T) {
+	tmpl := NewTemplate(protokit.ParseCodeGenRequest(newLibraryRequest(t, "")), new(PluginOptions))
+	file := tmpl.Files[0]
+
+	require.Len(t, file.ResourceDefinitions, 1)
+	require.Equal(t, "library.googleapis.com/Publisher", file.ResourceDefinitions[0].Type)
+	require.Equal(t, []string{"publishers/{publisher}"}, file.ResourceDefinitions[0].Patterns)
+
+	book := findMessage("Book", file)
+	require.NotNil(t, book.Resource)
+	require.Equal(t, "library.googleapis.com/Book", book.Resource.Type)
+	require.Equal(t, []string{"shelves/{shelf}/books/{book}", "publishers/{publisher}/books/{book}"},
+		book.Resource.Patterns)
+	require.Nil(t, findMessage("GetBookRequest", file).Resource)
+	require.Nil(t, findField("name", book).ResourceReference)
+}
+
+func TestResourceReferences(t *testing.T) {
+	tmpl := NewTemplate(protokit.ParseCodeGenRequest(newLibraryRequest(t, "")), new(PluginOptions))
+	file := tmpl.Files[0]
+
+	ref := findField("publisher", findMessage("Book", file)).ResourceReference
+	require.Equal(t, "library.googleapis.com/Publisher", ref.ResourceType())
+	require.Equal(t, []string{"publishers/{publisher}"}, ref.Patterns)
+	require.Empty(t, ref.Anchor)
+
+	ref = findField("name", findMessage("GetBookRequest", file)).ResourceReference
+	require.Equal(t, "library.googleapis.com/Book", ref.Type)
+	require.Equal(t, "library.Book", ref.Anchor)
+	require.Equal(t, []string{"shelves/{shelf}/books/{book}", "publishers/{publisher}/books/{book}"}, ref.Patterns)
+
+	request := findMessage("ListBooksRequest", file)
+	ref = findField("parent", request).ResourceReference
+	require.Empty(t, ref.Type)
+	require.Equal(t, "library.googleapis.com/Book", ref.ResourceType())
+	require.Equal(t, "library.Book", ref.Anchor)
+	require.Equal(t, []string{"shelves/{shelf}", "publishers/{publisher}"}, ref.Patterns)
+
+	// resources declared elsewhere can't be resolved
+	ref = findField("author", request).ResourceReference
+	require.Equal(t, "library.googleapis.com/Author", ref.Type)
+	require.Empty(t, ref.Patterns)
+	require.Empty(t, ref.Anchor)
+}
+
+func TestRenderResources(t *testing.T) {
+	expected := map[string][]string{
+		"html": {
+			`Resource: <code>library.googleapis.com/Book</code><br>Patterns: <code>shelves/{shelf}/books/{book}</code>, <code>publishers/{publisher}/books/{book}</code>`,
+			`Resource: <code>library.googleapis.com/Publisher</code>`,
+			`Resource reference: <a href="#library.Book"><code>library.googleapis.com/Book</code></a> (<code>shelves/{shelf}/books/{book}</code>, <code>publishers/{publisher}/books/{book}</code>)`,
+			`Resource reference: parent of <a href="#library.Book"><code>library.googleapis.com/Book</code></a> (<code>shelves/{shelf}</code>, <code>publishers/{publisher}</code>)`,
+			`Resource reference: <code>library.googleapis.com/Author</code></p>`,
+		},
+		"markdown": {
+			"Resource: `library.googleapis.com/Book`<br>Patterns: `shelves/{shelf}/books/{book}`, `publishers/{publisher}/books/{book}`",
+			"Resource: `library.googleapis.com/Publisher`",
+			"Resource reference: parent of [`library.googleapis.com/Book`](#library-Book) (`shelves/{shelf}`, `publishers/{publisher}`) |",
+		},
+		"docbook": {
+			`<para>Resource: <literal>library.googleapis.com/Book</literal></para>`,
+			`<para>Resource reference: <link linkend="library.Book"><literal>library.googleapis.com/Book</literal></link>`,
+		},
+	}
+
+	for format, snippets := range expected {
+		resp, err := new(Plugin).Generate(newLibraryRequest(t, format+",docs"))
+		require.NoError(t, err)
+
+		for _, snippet := range snippets {
+			require.Contains(t, resp.File[0].GetContent(), snippet, format)
+		}
+	}
+}
@@ -1,16 +1,20 @@
 package gendoc
 
 import (
-	_ "embed" // for including embedded resources
+	"embed"
 )
 
 var (
-	//go:embed resources/docbook.tmpl
-	docbookTmpl []byte
-	//go:embed resources/html.tmpl
-	htmlTmpl []byte
-	//go:embed resources/markdown.tmpl
-	markdownTmpl []byte
+	// The built-in templates, see builtinTemplates.
+	//go:embed resources/*.tmpl
+	templatesFS embed.FS
+	// The blocks shared by the built-in and custom templates, see TemplateBlocksPrefix.
+	//go:embed resources/blocks.tmpl
+	blocksTemplate string
+	//go:embed resources/html.css
+	htmlCSS []byte
 	//go:embed resources/scalars.json
 	scalarsJSON []byte
+	//go:embed resources/well_known_types.json
+	wellKnownTypesJSON []byte
 )
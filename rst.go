@@ -0,0 +1,89 @@
+package gendoc
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// TocTreeFile is the path (relative to the output root) of the toctree stub written for reStructuredText docs. Sphinx
+// projects include it (e.g. with `.. include:: toctree.rst`) to add the generated documents to their navigation.
+const TocTreeFile = "toctree.rst"
+
+// rstSpecialChars are escaped by RstFilter, since they start inline markup (and references, for underscores).
+var rstSpecialChars = strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "|", `\|`, "_", `\_`)
+
+// RstFilter escapes the characters that start reStructuredText inline markup, so that content is shown as written.
+func RstFilter(content string) string {
+	return rstSpecialChars.Replace(content)
+}
+
+// RstHeadingFilter returns title underlined with char, as a reStructuredText section heading. The underline has to be
+// at least as wide as the title, so characters from U+1100 on (where East Asian wide characters start) count as two
+// columns: a longer underline is fine.
+func RstHeadingFilter(char, title string) string {
+	width := 0
+	for _, r := range title {
+		width++
+		if r >= 0x1100 {
+			width++
+		}
+	}
+
+	return title + "\n" + strings.Repeat(char, width)
+}
+
+// rstLabel returns the Sphinx label of the section documenting the type fullName, or "" when it isn't documented in
+// the template. Scalar value types are labeled with a `protobuf-` prefix to avoid clashing with other labels.
+func rstLabel(types map[string]bool, t *Template, fullName string) string {
+	fullName = strings.TrimPrefix(fullName, ".")
+	if types[fullName] {
+		return fullName
+	}
+
+	for _, s := range t.Scalars {
+		if s.ProtoType == fullName {
+			return "protobuf-" + fullName
+		}
+	}
+
+	return ""
+}
+
+// rstRef returns a Sphinx cross-reference to the type fullName, shown as text, or the text as literal when the type
+// isn't documented in the template.
+func rstRef(types map[string]bool, t *Template, fullName, text string) string {
+	if label := rstLabel(types, t, fullName); label != "" {
+		return fmt.Sprintf(":ref:`%s <%s>`", text, label)
+	}
+
+	return "``" + text + "``"
+}
+
+// hasTocTree returns whether a toctree stub should be written.
+func hasTocTree(pluginOptions *PluginOptions) bool {
+	return pluginOptions.Type == RenderTypeRST
+}
+
+// renderTocTree renders a toctree directive listing the documents written to pages (relative to the output root).
+func renderTocTree(pluginOptions *PluginOptions, pages []OutputFile) string {
+	caption := pluginOptions.Title
+	if caption == "" {
+		locale := pluginOptions.Locale
+		if locale == "" {
+			locale = DefaultLocale
+		}
+		caption = Translate(locale, "Protocol Documentation")
+	}
+
+	var b strings.Builder
+	b.WriteString(".. toctree::\n   :maxdepth: 2\n   :caption: " + caption + "\n\n")
+
+	for _, page := range pages {
+		name := filepath.ToSlash(filepath.Clean(page.Name))
+		b.WriteString("   " + strings.TrimSuffix(name, path.Ext(name)) + "\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRstFilter(t *testing.T) {
+	require.Equal(t, "plain text", RstFilter("plain text"))
+	require.Equal(t, `field\_name \*not emphasis\* \`+"`"+`code\`+"`"+` a\|b C:\\dir`,
+		RstFilter("field_name *not emphasis* `code` a|b C:\\dir"))
+}
+
+func TestRstHeadingFilter(t *testing.T) {
+	require.Equal(t, "Booking\n~~~~~~~", RstHeadingFilter("~", "Booking"))
+	require.Equal(t, "协议文档\n========", RstHeadingFilter("=", "协议文档"))
+}
+
+func TestRenderRST(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "rst,docs.rst"))
+	require.NoError(t, err)
+	require.Len(t, resp.File, 2)
+	require.Equal(t, "docs.rst", resp.File[0].GetName())
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "Protocol Documentation\n======================\n")
+	require.Contains(t, content, "Booking.proto\n-------------\n")
+	require.Contains(t, content, ".. _com.example.Booking:\n\nBooking\n~~~~~~~\n")
+	require.Contains(t, content, "   * - status\n     - :ref:`BookingStatus <com.example.BookingStatus>`\n"+
+		"     - required\n     -\n       Status of the booking.\n")
+	require.Contains(t, content, "     - :ref:`int32 <protobuf-int32>`\n")
+	require.Contains(t, content, "   * - .. _protobuf-int32:\n\n       int32\n")
+
+	require.Equal(t, TocTreeFile, resp.File[1].GetName())
+	require.Equal(t, ".. toctree::\n   :maxdepth: 2\n   :caption: Protocol Documentation\n\n   docs\n",
+		resp.File[1].GetContent())
+}
+
+func TestRenderRSTUndocumentedTypes(t *testing.T) {
+	resp, err := new(Plugin).Generate(newOperationsRequest(t, "rst,docs.rst"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, "     - ``.google.longrunning.Operation``\n")
+	require.Contains(t, content, "     - :ref:`Request <library.Request>`\n")
+}
+
+func TestTocTreeForSourceRelative(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String("rst,api.rst,source_relative:title=Shop API")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 3)
+	require.Equal(t, "api.rst", resp.File[0].GetName())
+	require.Equal(t, "nested/api.rst", resp.File[1].GetName())
+	require.Equal(t, ".. toctree::\n   :maxdepth: 2\n   :caption: Shop API\n\n   api\n   nested/api\n",
+		resp.File[2].GetContent())
+}
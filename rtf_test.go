@@ -0,0 +1,42 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderRTF(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "rtf,docs.rtf"))
+	require.NoError(t, err)
+	require.Equal(t, "docs.rtf", resp.File[0].GetName())
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `{\rtf1\ansi`)
+	require.Contains(t, content, `\s1\outlinelevel0\sb360\sa120\b\fs36 Booking.proto\par`)
+	require.Contains(t, content, `\s2\outlinelevel1\sb240\sa120\b\fs30 BookingService\par`)
+	require.Contains(t, content, `\pard\plain\intbl\b\fs20 Field\cell Type\cell Label\cell Description\cell\row`)
+	require.Contains(t, content, `\pard\plain\intbl\fs20 vehicle_id\cell int32\cell required\cell ID of booked vehicle.`)
+
+	// braces must balance for the document to open
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			require.GreaterOrEqual(t, depth, 0)
+		}
+	}
+	require.Zero(t, depth)
+}
+
+func TestRenderRTFTranslated(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "rtf,docs.rtf:locale=ja"))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), Translate("ja", "Description"))
+}
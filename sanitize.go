@@ -0,0 +1,177 @@
+package gendoc
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Values of the sanitize_html option, which controls how comments are included in HTML output.
+const (
+	// SanitizeHTMLOff includes comments as is, so any markup in them ends up in the output.
+	SanitizeHTMLOff = "false"
+	// SanitizeHTMLEscape escapes all markup in comments.
+	SanitizeHTMLEscape = "true"
+	// SanitizeHTMLAllowlist keeps a safe subset of markup in comments (see SanitizeFilter) and escapes the rest.
+	SanitizeHTMLAllowlist = "allowlist"
+)
+
+var (
+	tagPattern       = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^<>]*?)?)\s*/?>`)
+	attributePattern = regexp.MustCompile(`([a-zA-Z_:][-a-zA-Z0-9_:.]*)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?`)
+	entityPattern    = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+
+	// The tags kept by SanitizeFilter, along with their allowed attributes.
+	allowedTags = map[string]map[string]bool{
+		"a":          {"href": true, "title": true},
+		"abbr":       {"title": true},
+		"b":          {},
+		"blockquote": {},
+		"br":         {},
+		"code":       {},
+		"dd":         {},
+		"del":        {},
+		"dl":         {},
+		"dt":         {},
+		"em":         {},
+		"hr":         {},
+		"i":          {},
+		"img":        {"src": true, "alt": true, "title": true, "width": true, "height": true},
+		"ins":        {},
+		"kbd":        {},
+		"li":         {},
+		"ol":         {},
+		"p":          {},
+		"pre":        {},
+		"q":          {},
+		"s":          {},
+		"samp":       {},
+		"small":      {},
+		"strong":     {},
+		"sub":        {},
+		"sup":        {},
+		"table":      {},
+		"tbody":      {},
+		"td":         {"colspan": true, "rowspan": true},
+		"th":         {"colspan": true, "rowspan": true},
+		"thead":      {},
+		"tr":         {},
+		"u":          {},
+		"ul":         {},
+		"var":        {},
+	}
+
+	urlAttributes = map[string]bool{"href": true, "src": true}
+	safeSchemes   = map[string]bool{"http": true, "https": true, "mailto": true}
+)
+
+// isSanitizeHTMLMode returns whether mode is a valid value of the sanitize_html option.
+func isSanitizeHTMLMode(mode string) bool {
+	switch mode {
+	case SanitizeHTMLOff, SanitizeHTMLEscape, SanitizeHTMLAllowlist:
+		return true
+	}
+
+	return false
+}
+
+// sanitizer returns the function comments are passed through before being included as HTML, or nil when they're
+// included as is.
+func sanitizer(mode string) func(string) string {
+	switch mode {
+	case SanitizeHTMLEscape:
+		return template.HTMLEscapeString
+	case SanitizeHTMLAllowlist:
+		return SanitizeFilter
+	}
+
+	return nil
+}
+
+// SanitizeFilter keeps a safe subset of HTML in content and escapes everything else. Allowed tags are basic formatting,
+// lists, tables, links and images. All attributes except a few harmless ones (e.g. href, src and alt) are dropped,
+// and links and images must use http, https or mailto URLs (or relative ones). Entities are kept as is.
+func SanitizeFilter(content string) string {
+	var b strings.Builder
+	last := 0
+
+	for _, loc := range tagPattern.FindAllStringSubmatchIndex(content, -1) {
+		name := strings.ToLower(content[loc[4]:loc[5]])
+		attributes, ok := allowedTags[name]
+		if !ok {
+			continue
+		}
+
+		b.WriteString(escapeText(content[last:loc[0]]))
+		last = loc[1]
+
+		if loc[3] > loc[2] {
+			b.WriteString("</" + name + ">")
+			continue
+		}
+
+		b.WriteString("<" + name)
+		for _, attr := range attributePattern.FindAllStringSubmatch(content[loc[6]:loc[7]], -1) {
+			key := strings.ToLower(attr[1])
+			if !attributes[key] {
+				continue
+			}
+
+			value := html.UnescapeString(strings.Trim(attr[2], `"'`))
+			if urlAttributes[key] && !isSafeURL(value) {
+				continue
+			}
+
+			b.WriteString(" " + key + `="` + template.HTMLEscapeString(value) + `"`)
+		}
+		b.WriteString(">")
+	}
+
+	b.WriteString(escapeText(content[last:]))
+	return b.String()
+}
+
+// escapeText escapes the text between tags, leaving valid entities intact.
+func escapeText(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '&':
+			if entity := entityPattern.FindString(text[i:]); entity != "" {
+				b.WriteString(entity)
+				i += len(entity) - 1
+			} else {
+				b.WriteString("&amp;")
+			}
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&#34;")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// isSafeURL returns whether url is relative or uses one of the safe schemes. Browsers ignore whitespace and control
+// characters within schemes, so these are removed before checking.
+func isSafeURL(url string) bool {
+	stripped := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, url)
+
+	colon := strings.IndexByte(stripped, ':')
+	if colon < 0 || strings.ContainsAny(stripped[:colon], "/?#") {
+		return true
+	}
+
+	return safeSchemes[strings.ToLower(stripped[:colon])]
+}
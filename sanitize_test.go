@@ -0,0 +1,76 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeFilter(t *testing.T) {
+	tests := map[string]string{
+		"Plain text.":                                  "Plain text.",
+		"Use <b>bold</b> and <CODE>code</CODE>.":       "Use <b>bold</b> and <code>code</code>.",
+		"Line one<br/>line two":                        "Line one<br>line two",
+		"1 < 2 && 3 > 2":                               "1 &lt; 2 &amp;&amp; 3 &gt; 2",
+		"Tom &amp; Jerry &copy; &#169; &#xA9;":         "Tom &amp; Jerry &copy; &#169; &#xA9;",
+		`<script>alert("hi")</script>`:                 "&lt;script&gt;alert(&#34;hi&#34;)&lt;/script&gt;",
+		`<img src="x.png" onerror="alert(1)">`:         `<img src="x.png">`,
+		`<a href="https://example.com" target=_blank>`: `<a href="https://example.com">`,
+		`<a href='docs/index.html#top' title="Docs">`:  `<a href="docs/index.html#top" title="Docs">`,
+		`<a href="javascript:alert(1)">x</a>`:          `<a>x</a>`,
+		`<a href="java	script:alert(1)">x</a>`:         `<a>x</a>`,
+		`<a href="javascript&#58;alert(1)">x</a>`:      `<a>x</a>`,
+		`<a href="mailto:me@example.com">`:             `<a href="mailto:me@example.com">`,
+		`<p style="color: red">Red</p>`:                "<p>Red</p>",
+		"<!-- hidden -->":                              "&lt;!-- hidden --&gt;",
+		`<a title="a>b">`:                              `<a title="">b&#34;&gt;`,
+	}
+
+	for input, output := range tests {
+		require.Equal(t, output, SanitizeFilter(input), input)
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	template.Files[0].Description = `Bookings <em>only</em>.<script>alert(1)</script>`
+	template.Files[0].Messages[0].Fields[0].Description = `<i>Unique</i> <img src=x onerror=alert(1)>`
+
+	tests := map[string][]string{
+		"": {
+			`<p>Bookings <em>only</em>.<script>alert(1)</script></p>`,
+			`<i>Unique</i> <img src=x onerror=alert(1)>`,
+		},
+		SanitizeHTMLOff: {
+			`<p>Bookings <em>only</em>.<script>alert(1)</script></p>`,
+			`<i>Unique</i> <img src=x onerror=alert(1)>`,
+		},
+		SanitizeHTMLEscape: {
+			`<p>Bookings &lt;em&gt;only&lt;/em&gt;.&lt;script&gt;alert(1)&lt;/script&gt;</p>`,
+			`&lt;i&gt;Unique&lt;/i&gt; &lt;img src=x onerror=alert(1)&gt;`,
+		},
+		SanitizeHTMLAllowlist: {
+			`<p>Bookings <em>only</em>.&lt;script&gt;alert(1)&lt;/script&gt;</p>`,
+			`<i>Unique</i> <img src="x">`,
+		},
+	}
+
+	for mode, expected := range tests {
+		template.SanitizeHTML = mode
+
+		output, err := RenderTemplate(RenderTypeHTML, template, "")
+		require.NoError(t, err)
+		require.Contains(t, string(output), expected[0], mode)
+
+		output, err = RenderTemplate(RenderTypeMarkdown, template, "")
+		require.NoError(t, err)
+		require.Contains(t, string(output), expected[1], mode)
+	}
+}
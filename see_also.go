@@ -0,0 +1,77 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// The directives adding an entry to the "See also" list of an entity: `@see com.example.Booking` refers to a type (by
+// full name) and `@link https://example.com/booking Booking guide` to a page. Both may be followed by the text shown
+// for the entry.
+const (
+	seeDirective  = "@see"
+	linkDirective = "@link"
+)
+
+// SeeAlso is an entry of the "See also" list of a message, enum, service or method, given with an @see or @link
+// directive in its comment.
+type SeeAlso struct {
+	// The full name of the type referred to with @see. Empty for links.
+	Type string `json:"type,omitempty"`
+	// The URL given with @link (or with @see, when the directive names a URL rather than a type). Empty for types.
+	URL string `json:"url,omitempty"`
+	// The text shown for the entry, following the type or URL in the directive. Defaults to the full name of the type
+	// or the URL.
+	Text string `json:"text"`
+}
+
+// parseSeeAlso returns the entry described by the line of a comment, or nil when the line isn't an @see or @link
+// directive.
+func parseSeeAlso(line string) *SeeAlso {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || (fields[0] != seeDirective && fields[0] != linkDirective) {
+		return nil
+	}
+
+	target, text := fields[1], strings.Join(fields[2:], " ")
+	if fields[0] == seeDirective && !strings.Contains(target, "://") {
+		target = strings.TrimPrefix(target, ".")
+		if text == "" {
+			text = target
+		}
+		return &SeeAlso{Type: target, Text: text}
+	}
+
+	if text == "" {
+		text = target
+	}
+	return &SeeAlso{URL: target, Text: text}
+}
+
+// extractSeeAlso removes the @see and @link directives from a description, returning the remaining description and the
+// entries in order.
+func extractSeeAlso(description string) (string, []*SeeAlso) {
+	if !strings.Contains(description, seeDirective) && !strings.Contains(description, linkDirective) {
+		return description, nil
+	}
+
+	var entries []*SeeAlso
+	lines := make([]string, 0)
+	for _, line := range strings.Split(description, "\n") {
+		if entry := parseSeeAlso(line); entry != nil {
+			entries = append(entries, entry)
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	// drop the paragraphs that only held directives
+	paragraphs := make([]string, 0)
+	for _, paragraph := range strings.Split(strings.Join(lines, "\n"), "\n\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			paragraphs = append(paragraphs, strings.Trim(paragraph, "\n"))
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n"), entries
+}
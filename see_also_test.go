@@ -0,0 +1,84 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newSeeAlsoRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"library.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("library.proto"),
+			Package: proto.String("library"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{Name: proto.String("Book")},
+				{Name: proto.String("Shelf")},
+			},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: proto.String("Library"),
+				Method: []*descriptorpb.MethodDescriptorProto{{
+					Name:       proto.String("GetBook"),
+					InputType:  proto.String(".library.Shelf"),
+					OutputType: proto.String(".library.Book"),
+				}},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+				{Path: []int32{4, 0}, Span: []int32{1, 0, 1}, LeadingComments: proto.String(
+					" A book.\n\n @see .library.Shelf The shelves\n @link https://example.com/books Book guide\n" +
+						" @see https://example.com\n")},
+				{Path: []int32{6, 0, 2, 0}, Span: []int32{2, 0, 1}, LeadingComments: proto.String(
+					" Gets a book.\n @see library.Book\n")},
+			}},
+		}},
+	}
+}
+
+func TestSeeAlso(t *testing.T) {
+	req := newSeeAlsoRequest("html,index.html")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), options)
+	file := template.Files[0]
+
+	book := findMessage("Book", file)
+	require.Equal(t, "A book.", book.Description)
+	require.Equal(t, []*SeeAlso{
+		{Type: "library.Shelf", Text: "The shelves"},
+		{URL: "https://example.com/books", Text: "Book guide"},
+		{URL: "https://example.com", Text: "https://example.com"},
+	}, book.SeeAlso)
+	require.Nil(t, findMessage("Shelf", file).SeeAlso)
+
+	method := findServiceMethod("GetBook", findService("Library", file))
+	require.Equal(t, "Gets a book.", method.Description)
+	require.Equal(t, []*SeeAlso{{Type: "library.Book", Text: "library.Book"}}, method.SeeAlso)
+}
+
+func TestSeeAlsoOutput(t *testing.T) {
+	resp, err := new(Plugin).Generate(newSeeAlsoRequest("html,index.html"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<li><a href="#library.Shelf">The shelves</a></li>`)
+	require.Contains(t, content, `<li><a href="https://example.com/books">Book guide</a></li>`)
+	require.Contains(t, content, `<strong>See also:</strong>`)
+	require.Contains(t, content, `<li><a href="#library.Book">library.Book</a></li>`)
+
+	resp, err = new(Plugin).Generate(newSeeAlsoRequest("markdown,README.md"))
+	require.NoError(t, err)
+
+	content = resp.File[0].GetContent()
+	require.Contains(t, content, "See also:\n\n- [The shelves](#library-Shelf)\n"+
+		"- [Book guide](https://example.com/books)\n- [https://example.com](https://example.com)\n")
+	require.Contains(t, content, "| Gets a book.<br>See also: [library.Book](#library-Book) |")
+}
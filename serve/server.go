@@ -0,0 +1,231 @@
+// Package serve previews generated documentation in a browser. A Server regenerates the documentation whenever its
+// source files change and tells open pages to reload, so doc comments can be checked while they're being written.
+package serve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	gendoc "github.com/daotl/protoc-gen-doc"
+)
+
+// ReloadPath is the URL path of the event stream pages listen on for reload notifications.
+const ReloadPath = "/_protoc-gen-doc/reload"
+
+// DefaultInterval is how often source files are checked for changes when Server.Interval isn't set.
+const DefaultInterval = 500 * time.Millisecond
+
+// reloadScript is injected into every HTML page. The browser reconnects the event source on its own, e.g. after the
+// server was restarted.
+const reloadScript = `<script>new EventSource("` + ReloadPath + `").onmessage = function() { location.reload(); };</script>`
+
+// Server serves the documentation generated from Source and rebuilds it when any of the source files change. It
+// implements http.Handler.
+type Server struct {
+	// Source provides the descriptors to document.
+	Source Source
+	// Options are passed to gendoc.Generate. FilesToGenerate defaults to every file in the set.
+	Options gendoc.Options
+	// Interval is how often source files are checked for changes. Defaults to DefaultInterval.
+	Interval time.Duration
+
+	mu      sync.Mutex
+	files   map[string]string
+	index   string
+	err     error
+	watched map[string]fileState
+	changed chan struct{}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Build (re)generates the documentation. A failed build is served as an error page until the next successful one.
+func (s *Server) Build() error {
+	set, watched, err := s.Source.Load()
+
+	var files []gendoc.OutputFile
+	if err == nil {
+		files, err = gendoc.Generate(set, s.Options)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.watched = stat(watched)
+	s.err = err
+	if err == nil {
+		s.files = make(map[string]string, len(files))
+		s.index = ""
+		for _, file := range files {
+			s.files[file.Name] = file.Content
+			if s.index == "" {
+				s.index = file.Name
+			}
+		}
+	}
+
+	if s.changed != nil {
+		close(s.changed)
+	}
+	s.changed = make(chan struct{})
+
+	return err
+}
+
+// Run builds the documentation and then rebuilds it whenever a watched file changes, until ctx is done. Build errors
+// don't stop the server; they're reported through onError (if not nil) and shown in the browser instead.
+func (s *Server) Run(ctx context.Context, onError func(error)) error {
+	if err := s.Build(); err != nil && onError != nil {
+		onError(err)
+	}
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if !s.modified() {
+				continue
+			}
+
+			if err := s.Build(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// modified reports whether any watched file changed since the last build.
+func (s *Server) modified() bool {
+	s.mu.Lock()
+	watched := s.watched
+	s.mu.Unlock()
+
+	paths := make([]string, 0, len(watched))
+	for path := range watched {
+		paths = append(paths, path)
+	}
+
+	current := stat(paths)
+	for path, state := range watched {
+		if current[path] != state {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stat(paths []string) map[string]fileState {
+	states := make(map[string]fileState, len(paths))
+	for _, path := range paths {
+		var state fileState
+		if info, err := os.Stat(path); err == nil {
+			state = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+
+		states[path] = state
+	}
+
+	return states
+}
+
+// ServeHTTP serves the generated files, with the live reload script injected into HTML pages.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == ReloadPath {
+		s.serveReload(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	files, index, err := s.files, s.index, s.err
+	s.mu.Unlock()
+
+	if err != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "<!DOCTYPE html>\n<html><body><h1>Unable to generate documentation</h1><pre>%s</pre>%s</body></html>\n",
+			html.EscapeString(err.Error()), reloadScript)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if name == "" {
+		name = index
+	}
+
+	content, ok := files[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType([]byte(content))
+	}
+
+	if strings.HasPrefix(contentType, "text/html") {
+		content = injectReloadScript(content)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprint(w, content)
+}
+
+// serveReload holds the connection open as a server-sent event stream and sends a single event once the documentation
+// has been rebuilt.
+func (s *Server) serveReload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	changed := s.changed
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	select {
+	case <-r.Context().Done():
+	case <-changed:
+		fmt.Fprint(w, "data: reload\n\n")
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+func injectReloadScript(content string) string {
+	if i := strings.LastIndex(content, "</body>"); i >= 0 {
+		var buf bytes.Buffer
+		buf.WriteString(content[:i])
+		buf.WriteString(reloadScript)
+		buf.WriteString(content[i:])
+		return buf.String()
+	}
+
+	return content + reloadScript
+}
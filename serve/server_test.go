@@ -0,0 +1,150 @@
+package serve_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gendoc "github.com/daotl/protoc-gen-doc"
+	. "github.com/daotl/protoc-gen-doc/serve"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func writeDescriptorSet(t *testing.T, path string, names ...string) {
+	set, err := utils.LoadDescriptorSet("..", "fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	keep := make(map[string]bool)
+	for _, name := range names {
+		keep[name] = true
+	}
+
+	subset := new(descriptorpb.FileDescriptorSet)
+	for _, fd := range set.GetFile() {
+		if len(names) == 0 || keep[fd.GetName()] {
+			subset.File = append(subset.File, fd)
+		}
+	}
+
+	data, err := proto.Marshal(subset)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+}
+
+func get(t *testing.T, url string) (*http.Response, string) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestServe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fileset.pb")
+	writeDescriptorSet(t, path)
+
+	s := &Server{Source: &DescriptorSetFile{Path: path}, Options: gendoc.Options{Parameter: "html,index.html"}}
+	require.NoError(t, s.Build())
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, body := get(t, srv.URL+"/")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	require.Contains(t, body, "Booking.proto")
+	require.Contains(t, body, ReloadPath+`").onmessage`)
+
+	_, indexBody := get(t, srv.URL+"/index.html")
+	require.Equal(t, body, indexBody)
+
+	resp, _ = get(t, srv.URL+"/missing.html")
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServeNonHTML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fileset.pb")
+	writeDescriptorSet(t, path)
+
+	s := &Server{Source: &DescriptorSetFile{Path: path}, Options: gendoc.Options{Parameter: "markdown,docs.md"}}
+	require.NoError(t, s.Build())
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	_, body := get(t, srv.URL+"/docs.md")
+	require.Contains(t, body, "Booking.proto")
+	require.NotContains(t, body, ReloadPath)
+}
+
+func TestServeBuildError(t *testing.T) {
+	s := &Server{Source: &DescriptorSetFile{Path: filepath.Join(t.TempDir(), "missing.pb")}}
+	require.Error(t, s.Build())
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, body := get(t, srv.URL+"/")
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	require.Contains(t, body, "Unable to generate documentation")
+	require.Contains(t, body, "missing.pb")
+	require.Contains(t, body, ReloadPath)
+}
+
+func TestLiveReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fileset.pb")
+	writeDescriptorSet(t, path, "nested/Book.proto")
+
+	s := &Server{
+		Source:   &DescriptorSetFile{Path: path},
+		Options:  gendoc.Options{Parameter: "html,index.html"},
+		Interval: 10 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- s.Run(ctx, nil) }()
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	require.Eventually(t, func() bool {
+		resp, _ := get(t, srv.URL+"/")
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	_, body := get(t, srv.URL+"/")
+	require.NotContains(t, body, "Vehicle.proto")
+
+	resp, err := http.Get(srv.URL + ReloadPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	writeDescriptorSet(t, path)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "data: reload\n", line)
+
+	_, body = get(t, srv.URL+"/")
+	require.Contains(t, body, "Vehicle.proto")
+
+	cancel()
+	require.Equal(t, context.Canceled, <-done)
+}
@@ -0,0 +1,117 @@
+package serve
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Source loads the descriptors to document. Along with the descriptors it returns the paths of the files they were
+// loaded from, which are watched for changes. The paths are returned even when loading fails, so that fixing the error
+// triggers a rebuild.
+type Source interface {
+	Load() (*descriptorpb.FileDescriptorSet, []string, error)
+}
+
+// DescriptorSetFile loads a FileDescriptorSet written by `protoc --descriptor_set_out`. Pass `--include_imports` and
+// `--include_source_info` to protoc to have dependencies and comments documented.
+type DescriptorSetFile struct {
+	Path string
+}
+
+// Load reads and parses the descriptor set.
+func (s *DescriptorSetFile) Load() (*descriptorpb.FileDescriptorSet, []string, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, []string{s.Path}, err
+	}
+
+	set := new(descriptorpb.FileDescriptorSet)
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, []string{s.Path}, err
+	}
+
+	return set, []string{s.Path}, nil
+}
+
+// Protoc compiles proto files with protoc. Every file that ends up in the descriptor set and can be found in one of the
+// import paths is watched, so editing an imported file triggers a rebuild too.
+type Protoc struct {
+	// Command is the protoc executable. Defaults to "protoc".
+	Command string
+	// ImportPaths are passed to protoc as `--proto_path` arguments.
+	ImportPaths []string
+	// Files are the proto files to compile.
+	Files []string
+}
+
+// Load runs protoc and parses the resulting descriptor set.
+func (s *Protoc) Load() (*descriptorpb.FileDescriptorSet, []string, error) {
+	out, err := os.CreateTemp("", "protoc-gen-doc-*.pb")
+	if err != nil {
+		return nil, s.Files, err
+	}
+	out.Close()
+	defer os.Remove(out.Name())
+
+	command := s.Command
+	if command == "" {
+		command = "protoc"
+	}
+
+	args := []string{"--include_imports", "--include_source_info", "--descriptor_set_out=" + out.Name()}
+	for _, path := range s.ImportPaths {
+		args = append(args, "--proto_path="+path)
+	}
+
+	cmd := exec.Command(command, append(args, s.Files...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return nil, s.Files, errors.New(strings.TrimSpace(string(output)))
+		}
+
+		return nil, s.Files, err
+	}
+
+	set, _, err := (&DescriptorSetFile{Path: out.Name()}).Load()
+	if err != nil {
+		return nil, s.Files, err
+	}
+
+	return set, s.watchedFiles(set), nil
+}
+
+func (s *Protoc) watchedFiles(set *descriptorpb.FileDescriptorSet) []string {
+	watched := append([]string(nil), s.Files...)
+	seen := make(map[string]bool, len(watched))
+	for _, path := range watched {
+		seen[path] = true
+	}
+
+	importPaths := s.ImportPaths
+	if len(importPaths) == 0 {
+		importPaths = []string{"."}
+	}
+
+	for _, fd := range set.GetFile() {
+		for _, dir := range importPaths {
+			path := filepath.Join(dir, filepath.FromSlash(fd.GetName()))
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+
+			if !seen[path] {
+				seen[path] = true
+				watched = append(watched, path)
+			}
+			break
+		}
+	}
+
+	return watched
+}
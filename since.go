@@ -0,0 +1,148 @@
+package gendoc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// SinceDescriptorSet is a released version of the documented protos, given by a descriptor set of them (e.g. written
+// by `protoc --descriptor_set_out` at the version's Git tag). See the since_descriptor_set option.
+type SinceDescriptorSet struct {
+	// The version the descriptor set was built for, e.g. `v1.2.0`.
+	Version string
+	// The path of the descriptor set.
+	File string
+}
+
+// parseSinceDescriptorSet parses the value of the since_descriptor_set option: `version=file`.
+func parseSinceDescriptorSet(value string) (SinceDescriptorSet, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return SinceDescriptorSet{}, fmt.Errorf("Invalid since_descriptor_set value: %v", value)
+	}
+
+	return SinceDescriptorSet{Version: parts[0], File: parts[1]}, nil
+}
+
+// readSinceVersions reads the descriptor sets of sets, given from the oldest version to the newest, and returns the
+// first version each message, field, enum, enum value, service and method appeared in, keyed by full name. Fields,
+// enum values and methods are qualified by their parent, like in the index.
+func readSinceVersions(sets []SinceDescriptorSet) (map[string]string, error) {
+	versions := make(map[string]string)
+	for _, set := range sets {
+		data, err := os.ReadFile(set.File)
+		if err != nil {
+			return nil, err
+		}
+
+		fds := new(descriptorpb.FileDescriptorSet)
+		if err := proto.Unmarshal(data, fds); err != nil {
+			return nil, fmt.Errorf("Invalid since_descriptor_set %s: %v", set.File, err)
+		}
+
+		for _, f := range fds.GetFile() {
+			walkDescriptorNames(f, func(fullName string) {
+				if _, ok := versions[fullName]; !ok {
+					versions[fullName] = set.Version
+				}
+			})
+		}
+	}
+
+	return versions, nil
+}
+
+// walkDescriptorNames calls fn with the full name of every message, field, enum, enum value, service and method
+// declared in f.
+func walkDescriptorNames(f *descriptorpb.FileDescriptorProto, fn func(fullName string)) {
+	qualify := func(scope, name string) string {
+		if scope == "" {
+			return name
+		}
+		return scope + "." + name
+	}
+
+	walkEnum := func(scope string, e *descriptorpb.EnumDescriptorProto) {
+		enum := qualify(scope, e.GetName())
+		fn(enum)
+		for _, value := range e.GetValue() {
+			fn(enum + "." + value.GetName())
+		}
+	}
+
+	var walkMessage func(scope string, m *descriptorpb.DescriptorProto)
+	walkMessage = func(scope string, m *descriptorpb.DescriptorProto) {
+		message := qualify(scope, m.GetName())
+		fn(message)
+		for _, field := range m.GetField() {
+			fn(message + "." + field.GetName())
+		}
+		for _, e := range m.GetEnumType() {
+			walkEnum(message, e)
+		}
+		for _, nested := range m.GetNestedType() {
+			walkMessage(message, nested)
+		}
+	}
+
+	for _, m := range f.GetMessageType() {
+		walkMessage(f.GetPackage(), m)
+	}
+	for _, e := range f.GetEnumType() {
+		walkEnum(f.GetPackage(), e)
+	}
+	for _, s := range f.GetService() {
+		service := qualify(f.GetPackage(), s.GetName())
+		fn(service)
+		for _, method := range s.GetMethod() {
+			fn(service + "." + method.GetName())
+		}
+	}
+}
+
+// sinceVersion returns the first version the entity with the given full name appeared in. Entities missing from all
+// descriptor sets are new in the documented version (see the version option), if any.
+func sinceVersion(fullName string, pluginOptions *PluginOptions) string {
+	if version, ok := pluginOptions.sinceVersions[fullName]; ok {
+		return version
+	}
+
+	return pluginOptions.Version
+}
+
+// applySince sets the version the entities of file appeared in, from the descriptor sets of the since_descriptor_set
+// option.
+func applySince(file *File, pluginOptions *PluginOptions) {
+	for _, m := range file.AllMessages() {
+		if m.Redacted {
+			continue
+		}
+
+		m.Since = sinceVersion(m.FullName, pluginOptions)
+		for _, field := range m.Fields {
+			if !field.Redacted {
+				field.Since = sinceVersion(m.FullName+"."+field.Name, pluginOptions)
+			}
+		}
+	}
+
+	for _, e := range file.AllEnums() {
+		e.Since = sinceVersion(e.FullName, pluginOptions)
+		for _, value := range e.Values {
+			if !value.Redacted {
+				value.Since = sinceVersion(e.FullName+"."+value.Name, pluginOptions)
+			}
+		}
+	}
+
+	for _, s := range file.Services {
+		s.Since = sinceVersion(s.FullName, pluginOptions)
+		for _, method := range s.Methods {
+			method.Since = sinceVersion(s.FullName+"."+method.Name, pluginOptions)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// writeSinceDescriptorSets writes the descriptor sets of two older versions of Booking.proto: v1.0 without the
+// BookingService and with the first three fields of Booking, and v1.1 adding the service. It returns their paths.
+func writeSinceDescriptorSets(t *testing.T) (string, string) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	v11 := proto.Clone(utils.FindDescriptor(set, "Booking.proto")).(*descriptorpb.FileDescriptorProto)
+	for _, m := range v11.GetMessageType() {
+		if m.GetName() == "Booking" {
+			m.Field = m.Field[:3]
+		}
+	}
+
+	v10 := proto.Clone(v11).(*descriptorpb.FileDescriptorProto)
+	v10.Service = nil
+
+	dir := t.TempDir()
+	write := func(name string, f *descriptorpb.FileDescriptorProto) string {
+		data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{f}})
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+		return path
+	}
+
+	return write("v1.0.pb", v10), write("v1.1.pb", v11)
+}
+
+func TestParseOptionsForSinceDescriptorSet(t *testing.T) {
+	v10, v11 := writeSinceDescriptorSets(t)
+
+	options, err := ParseOptions(newBookingRequest(t,
+		"html,index.html:since_descriptor_set=v1.0="+v10+",since_descriptor_set=v1.1="+v11))
+	require.NoError(t, err)
+	require.Equal(t, []SinceDescriptorSet{{Version: "v1.0", File: v10}, {Version: "v1.1", File: v11}},
+		options.SinceDescriptorSets)
+
+	for _, value := range []string{"v1.0", "=" + v10, "v1.0="} {
+		req := &pluginpb.CodeGeneratorRequest{Parameter: proto.String("html,index.html:since_descriptor_set=" + value)}
+		_, err = ParseOptions(req)
+		require.Error(t, err)
+	}
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:since_descriptor_set=v1.0=missing.pb"))
+	require.Error(t, err)
+}
+
+func TestSince(t *testing.T) {
+	v10, v11 := writeSinceDescriptorSets(t)
+
+	options, err := ParseOptions(newBookingRequest(t,
+		"html,index.html:since_descriptor_set=v1.0="+v10+",since_descriptor_set=v1.1="+v11+",version=v2.0"))
+	require.NoError(t, err)
+
+	fds := protokit.ParseCodeGenRequest(newBookingRequest(t, ""))
+	template := NewTemplate(fds, options)
+	file := template.Files[0]
+
+	booking := findMessage("Booking", file)
+	require.Equal(t, "v1.0", booking.Since)
+	require.Equal(t, "v1.0", findField("vehicle_id", booking).Since)
+	require.Equal(t, "v2.0", findField("confirmation_sent", booking).Since)
+
+	status := findEnum("BookingStatus.StatusCode", file)
+	require.Equal(t, "v1.0", status.Since)
+	require.Equal(t, "OK", status.Values[0].Name)
+	require.Equal(t, "v1.0", status.Values[0].Since)
+
+	service := findService("BookingService", file)
+	require.Equal(t, "v1.1", service.Since)
+	require.Equal(t, "v1.1", findServiceMethod("BookVehicle", service).Since)
+
+	// without the option, no versions are computed
+	template = NewTemplate(fds, &PluginOptions{Version: "v2.0"})
+	require.Empty(t, findMessage("Booking", template.Files[0]).Since)
+}
+
+func TestSinceInHTML(t *testing.T) {
+	v10, v11 := writeSinceDescriptorSets(t)
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t,
+		"html,index.html:since_descriptor_set=v1.0="+v10+",since_descriptor_set=v1.1="+v11))
+	require.NoError(t, err)
+
+	page := resp.File[0].GetContent()
+	require.Contains(t, page, `<td>vehicle_id <span class="since-badge">Since v1.0</span></td>`)
+	require.Contains(t, page, `<td>BookVehicle <span class="since-badge">Since v1.1</span></td>`)
+	// entities missing from all descriptor sets have no version without the version option
+	require.Contains(t, page, `<td>confirmation_sent</td>`)
+}
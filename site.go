@@ -0,0 +1,189 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+)
+
+// The files written along with the package pages, home page (the output file), glossary and stylesheet of the site
+// option. Netlify and Vercel serve 404.html for missing pages, and read their redirects from _redirects and
+// vercel.json.
+const (
+	SiteNotFoundPage     = "404.html"
+	NetlifyRedirectsFile = "_redirects"
+	VercelConfigFile     = "vercel.json"
+)
+
+// siteDefaultDir is the directory of the page documenting the files without a package.
+const siteDefaultDir = "default/"
+
+// Site describes the static site the docs are written as with the site option. It's set on every page of the site.
+type Site struct {
+	// The path of the home page and of the glossary, relative to the site root.
+	Home     string
+	Glossary string
+	// The pages documenting each package, sorted by package name.
+	Packages []*SitePackage
+	// The page (relative to the site root) each type is documented on, keyed by full name. The home and 404 pages
+	// redirect the anchors of single page docs (e.g. index.html#com.example.Booking) and the paths of the redirects
+	// (e.g. /com.example.Booking) to them.
+	Pages map[string]string
+	// The path the site is served from: the path of site_url, or / without it.
+	BasePath string
+	// Whether the page being rendered is the 404 page, whose links are relative to BasePath wherever it's served.
+	NotFound bool
+}
+
+// SitePackage is a package documented by a page of the site.
+type SitePackage struct {
+	// The name of the package, empty for files without one.
+	Name string
+	// The overview of the package (see Package).
+	Description string
+	// The path of the page, relative to the site root.
+	Page string
+}
+
+// hasSite returns whether the docs are written as a static site. Sites are made of the pages of the built-in HTML
+// template.
+func hasSite(pluginOptions *PluginOptions) bool {
+	return pluginOptions.Site && pluginOptions.Type == RenderTypeHTML && pluginOptions.TemplateFile == ""
+}
+
+// sitePackageDir returns the directory of the page documenting pkg, e.g. com/example/ for com.example.
+func sitePackageDir(pkg string) string {
+	if pkg == "" {
+		return siteDefaultDir
+	}
+
+	return strings.ReplaceAll(pkg, ".", "/") + "/"
+}
+
+// groupProtosBySitePackage groups files by the directory of the site page documenting their package.
+func groupProtosBySitePackage(fds []*protokit.FileDescriptor) map[string][]*protokit.FileDescriptor {
+	fdsGroup := make(map[string][]*protokit.FileDescriptor)
+	for _, fd := range fds {
+		dir := sitePackageDir(fd.GetPackage())
+		fdsGroup[dir] = append(fdsGroup[dir], fd)
+	}
+
+	return fdsGroup
+}
+
+// newSite describes the site of the package pages of fdsGroup, documenting the types of pages.
+func newSite(fdsGroup map[string][]*protokit.FileDescriptor, pages map[string]string,
+	pluginOptions *PluginOptions) *Site {
+	site := &Site{
+		Home:     path.Clean(pluginOptions.OutputFile),
+		Glossary: indexPageName(pluginOptions.OutputFile),
+		Packages: make([]*SitePackage, 0, len(fdsGroup)),
+		Pages:    make(map[string]string, len(pages)),
+		BasePath: "/",
+	}
+
+	if u, err := url.Parse(pluginOptions.SiteURL); err == nil && pluginOptions.SiteURL != "" {
+		site.BasePath = strings.TrimSuffix(u.Path, "/") + "/"
+	}
+
+	for fullName, page := range pages {
+		site.Pages[strings.TrimPrefix(fullName, ".")] = page
+	}
+
+	for _, dir := range sortedDirectories(fdsGroup) {
+		for _, pkg := range NewTemplate(fdsGroup[dir], pluginOptions).Packages() {
+			site.Packages = append(site.Packages, &SitePackage{
+				Name:        pkg.Name,
+				Description: pkg.Description,
+				Page:        path.Join(dir, pluginOptions.OutputFile),
+			})
+		}
+	}
+	sort.SliceStable(site.Packages, func(i, j int) bool { return site.Packages[i].Name < site.Packages[j].Name })
+
+	return site
+}
+
+// key returns a string describing the site, for the cache keys of its pages.
+func (s *Site) key() string {
+	var b strings.Builder
+	for _, pkg := range s.Packages {
+		fmt.Fprintf(&b, "%s=%s\n", pkg.Name, pkg.Page)
+	}
+
+	return b.String()
+}
+
+// redirects returns the types of the site sorted by full name, with the absolute paths of their redirects and of the
+// sections documenting them.
+func (s *Site) redirects() [][2]string {
+	names := make([]string, 0, len(s.Pages))
+	for fullName := range s.Pages {
+		names = append(names, fullName)
+	}
+	sort.Strings(names)
+
+	redirects := make([][2]string, 0, len(names))
+	for _, fullName := range names {
+		redirects = append(redirects, [2]string{s.BasePath + fullName, s.BasePath + s.Pages[fullName] + "#" + fullName})
+	}
+
+	return redirects
+}
+
+// renderNetlifyRedirects renders the _redirects file of Netlify (see
+// https://docs.netlify.com/routing/redirects/), redirecting the path of each type to the section documenting it.
+func renderNetlifyRedirects(site *Site) string {
+	var b strings.Builder
+	for _, redirect := range site.redirects() {
+		fmt.Fprintf(&b, "%s  %s  301\n", redirect[0], redirect[1])
+	}
+
+	return b.String()
+}
+
+// vercelConfig is the part of the vercel.json configuration written by the site option (see
+// https://vercel.com/docs/projects/project-configuration).
+type vercelConfig struct {
+	Redirects []vercelRedirect `json:"redirects"`
+}
+
+type vercelRedirect struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Permanent   bool   `json:"permanent"`
+}
+
+// renderVercelConfig renders the vercel.json configuration, with the same redirects as the _redirects file of Netlify.
+func renderVercelConfig(site *Site) (string, error) {
+	config := vercelConfig{Redirects: make([]vercelRedirect, 0)}
+	for _, redirect := range site.redirects() {
+		config.Redirects = append(config.Redirects, vercelRedirect{redirect[0], redirect[1], true})
+	}
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(content) + "\n", nil
+}
+
+// applySiteDefaults enables the options a site is made of: an output file per package (like source_relative), the
+// glossary and an external stylesheet (unless assets_url is set).
+func applySiteDefaults(options *PluginOptions) {
+	if !hasSite(options) {
+		return
+	}
+
+	options.SourceRelative = true
+	options.Index = true
+	if options.AssetsURL == "" {
+		options.ExternalAssets = true
+	}
+}
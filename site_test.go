@@ -0,0 +1,108 @@
+package gendoc_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func generateSite(t *testing.T, parameter string) map[string]string {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String(parameter)
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range resp.File {
+		files[f.GetName()] = f.GetContent()
+	}
+	return files
+}
+
+func TestParseOptionsForSite(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:site=true"))
+	require.NoError(t, err)
+	require.True(t, options.Site)
+	require.True(t, options.SourceRelative)
+	require.True(t, options.Index)
+	require.True(t, options.ExternalAssets)
+
+	// the site option only applies to the built-in HTML template
+	options, err = ParseOptions(newBookingRequest(t, "markdown,docs.md:site=true"))
+	require.NoError(t, err)
+	require.False(t, options.SourceRelative)
+
+	_, err = ParseOptions(&pluginpb.CodeGeneratorRequest{Parameter: proto.String("html,index.html:site=yes")})
+	require.Error(t, err)
+}
+
+func TestSite(t *testing.T) {
+	files := generateSite(t, "html,index.html:site=true")
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	require.ElementsMatch(t, []string{
+		"com/example/index.html",
+		"com/book/index.html",
+		"index.html",
+		"glossary.html",
+		StylesheetAsset,
+		SiteNotFoundPage,
+		NetlifyRedirectsFile,
+		VercelConfigFile,
+	}, names)
+
+	// every page links to the home page, the glossary and the page of each package
+	page := files["com/example/index.html"]
+	require.Contains(t, page, `<li><a href="../../index.html">Home</a></li>`)
+	require.Contains(t, page, `<li><a href="../../glossary.html">Index</a></li>`)
+	require.Contains(t, page, `<li><a href="../book/index.html">com.book</a></li>`)
+	require.Contains(t, page, `href="../../assets/protoc-gen-doc.css"`)
+
+	home := files["index.html"]
+	require.Contains(t, home, `<h2 id="packages">Packages`)
+	require.Contains(t, home, `<td><a href="com/example/index.html">com.example</a></td>`)
+	require.Contains(t, home, `"com.example.Booking":"com/example/index.html"`)
+	require.NotContains(t, home, "<base")
+
+	notFound := files[SiteNotFoundPage]
+	require.Contains(t, notFound, `<base href="/">`)
+	require.Contains(t, notFound, `<h2 id="not-found">Page not found</h2>`)
+
+	require.Contains(t, files[NetlifyRedirectsFile],
+		"/com.example.Booking  /com/example/index.html#com.example.Booking  301\n")
+
+	var vercel struct {
+		Redirects []struct {
+			Source      string `json:"source"`
+			Destination string `json:"destination"`
+			Permanent   bool   `json:"permanent"`
+		} `json:"redirects"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(files[VercelConfigFile]), &vercel))
+	require.NotEmpty(t, vercel.Redirects)
+	require.Equal(t, "/com.book.Book", vercel.Redirects[0].Source)
+	require.Equal(t, "/com/book/index.html#com.book.Book", vercel.Redirects[0].Destination)
+	require.True(t, vercel.Redirects[0].Permanent)
+}
+
+func TestSiteWithSiteURL(t *testing.T) {
+	files := generateSite(t, "html,index.html:site=true,site_url=https://example.com/docs/")
+	require.Contains(t, files[SiteNotFoundPage], `<base href="/docs/">`)
+	require.Contains(t, files[NetlifyRedirectsFile],
+		"/docs/com.example.Booking  /docs/com/example/index.html#com.example.Booking  301\n")
+	require.Contains(t, files[SitemapFile], "<loc>https://example.com/docs/index.html</loc>")
+	require.Contains(t, files[SitemapFile], "<loc>https://example.com/docs/com/book/index.html</loc>")
+	require.NotContains(t, files[SitemapFile], SiteNotFoundPage)
+}
@@ -0,0 +1,42 @@
+package gendoc
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+)
+
+// SitemapFile is the path (relative to the output root) of the sitemap written for HTML docs when site_url is set.
+const SitemapFile = "sitemap.xml"
+
+// hasSitemap returns whether a sitemap should be written. Sitemaps list absolute URLs, so they require site_url.
+func hasSitemap(pluginOptions *PluginOptions) bool {
+	return pluginOptions.SiteURL != "" && pluginOptions.Type == RenderTypeHTML
+}
+
+// pageURL returns the absolute URL of the page written to name (relative to the output root), or an empty string when
+// site_url isn't set.
+func pageURL(pluginOptions *PluginOptions, name string) string {
+	if pluginOptions.SiteURL == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(pluginOptions.SiteURL, "/") + "/" + filepath.ToSlash(filepath.Clean(name))
+}
+
+// renderSitemap renders a sitemap (see https://www.sitemaps.org/protocol.html) listing the URLs of pages. No
+// modification dates are included so that the output stays reproducible.
+func renderSitemap(pluginOptions *PluginOptions, pages []OutputFile) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n")
+
+	for _, page := range pages {
+		b.WriteString("  <url>\n    <loc>")
+		xml.EscapeText(&b, []byte(pageURL(pluginOptions, page.Name)))
+		b.WriteString("</loc>\n  </url>\n")
+	}
+
+	b.WriteString("</urlset>\n")
+	return b.String()
+}
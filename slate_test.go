@@ -0,0 +1,58 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestParseOptionsForHTMLTemplate(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:template=slate")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, HTMLTemplateSlate, options.HTMLTemplate)
+
+	req.Parameter = proto.String("html,index.html:template=redoc")
+	_, err = ParseOptions(req)
+	require.EqualError(t, err, "Invalid template value: redoc")
+}
+
+func TestRenderSlate(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:template=slate"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<div class="dark-box"></div>`)
+	require.Contains(t, content, `<li><a href="#com-example-BookingService">BookingService</a></li>`)
+	require.Contains(t, content, `<h3 id="com-example-BookingService-BookVehicle">BookVehicle</h3>
+        <blockquote><p>Request</p></blockquote>
+        <pre><code>{
+  &#34;vehicle_id&#34;: 0,`)
+	require.Contains(t, content, `<blockquote><p>Response</p></blockquote>
+        <pre><code>{
+  &#34;id&#34;: 0,
+  &#34;description&#34;: &#34;string&#34;,
+  &#34;status_code&#34;: &#34;OK&#34;
+}</code></pre>`)
+	require.Contains(t, content, `<tr><th>Request Type</th><td><a href="#com-example-Booking">Booking</a></td></tr>`)
+	require.Contains(t, content, `<tr id="int32">`)
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, "html,index.html:template=default"))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), "dark-box")
+}
+
+func TestRenderSlateTranslated(t *testing.T) {
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:template=slate,locale=de"))
+	require.NoError(t, err)
+
+	content := resp.File[0].GetContent()
+	require.Contains(t, content, `<html lang="de">`)
+	require.Contains(t, content, `<blockquote><p>Anfrage</p></blockquote>`)
+	require.Contains(t, content, `<blockquote><p>Beispiel</p></blockquote>`)
+}
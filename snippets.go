@@ -0,0 +1,137 @@
+package gendoc
+
+import (
+	"fmt"
+	html_template "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// snippetDirective inserts a snippet after the description of a message, enum, service or method, e.g.
+// `@snippet beta-banner.html`.
+const snippetDirective = "@snippet"
+
+// Snippets are the HTML or Markdown inserted into the built-in HTML and Markdown templates as is: a header, a footer,
+// and named snippets referenced with @snippet directives in comments. See the header_file, footer_file and snippets_dir
+// options.
+type Snippets struct {
+	// Inserted at the top of the page.
+	Header html_template.HTML
+	// Inserted at the bottom of the page.
+	Footer html_template.HTML
+	// The snippets @snippet directives refer to, keyed by name.
+	Named map[string]html_template.HTML
+}
+
+// Snippet returns the named snippet, or an error if there's none.
+func (s *Snippets) Snippet(name string) (html_template.HTML, error) {
+	snippet, ok := s.Named[name]
+	if !ok {
+		return "", fmt.Errorf("Unknown snippet: %v", name)
+	}
+
+	return snippet, nil
+}
+
+// key returns a string identifying the snippets, for caching outputs.
+func (s *Snippets) key() string {
+	names := make([]string, 0, len(s.Named))
+	for name := range s.Named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q\n%q\n", s.Header, s.Footer)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%q=%q\n", name, s.Named[name])
+	}
+
+	return b.String()
+}
+
+// hasSnippets returns whether the format inserts the snippets into the page: the built-in HTML and Markdown templates,
+// and custom templates.
+func hasSnippets(options *PluginOptions) bool {
+	switch options.Type {
+	case RenderTypeHTML, RenderTypeMarkdown, RenderTypeGFM:
+		return true
+	}
+
+	return options.TemplateFile != ""
+}
+
+// readSnippets reads the files of the header_file, footer_file and snippets_dir options. Every file in the snippets
+// directory is a snippet named after the file, e.g. `beta-banner.html`.
+func readSnippets(options *PluginOptions) (*Snippets, error) {
+	snippets := &Snippets{Named: make(map[string]html_template.HTML)}
+
+	if options.HeaderFile != "" {
+		data, err := os.ReadFile(options.HeaderFile)
+		if err != nil {
+			return nil, err
+		}
+		snippets.Header = html_template.HTML(data)
+	}
+
+	if options.FooterFile != "" {
+		data, err := os.ReadFile(options.FooterFile)
+		if err != nil {
+			return nil, err
+		}
+		snippets.Footer = html_template.HTML(data)
+	}
+
+	if options.SnippetsDir != "" {
+		entries, err := os.ReadDir(options.SnippetsDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			data, err := os.ReadFile(filepath.Join(options.SnippetsDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			snippets.Named[entry.Name()] = html_template.HTML(data)
+		}
+	}
+
+	return snippets, nil
+}
+
+// extractSnippets removes the @snippet directives from a description, returning the remaining description and the
+// names of the snippets in order.
+func extractSnippets(description string) (string, []string) {
+	if !strings.Contains(description, snippetDirective) {
+		return description, nil
+	}
+
+	var names []string
+	lines := make([]string, 0)
+	for _, line := range strings.Split(description, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == snippetDirective {
+			names = append(names, fields[1])
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	// drop the paragraphs that only held directives
+	paragraphs := make([]string, 0)
+	for _, paragraph := range strings.Split(strings.Join(lines, "\n"), "\n\n") {
+		if strings.TrimSpace(paragraph) != "" {
+			paragraphs = append(paragraphs, strings.Trim(paragraph, "\n"))
+		}
+	}
+
+	return strings.Join(paragraphs, "\n\n"), names
+}
@@ -0,0 +1,111 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newSnippetsRequest returns a request documenting a message, enum, service and method with @snippet directives.
+func newSnippetsRequest(t *testing.T, parameter string) *pluginpb.CodeGeneratorRequest {
+	set, err := utils.LoadDescriptorSet("fixtures", "snippets.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "library.proto")
+	req.Parameter = proto.String(parameter)
+	return req
+}
+
+// writeSnippets writes a header, a footer and a directory of snippets to a temporary directory.
+func writeSnippets(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-snippets")
+	require.NoError(t, err)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "snippets"), os.ModePerm))
+	files := map[string]string{
+		"header.html":         `<div class="banner">Beta docs</div>`,
+		"footer.html":         `<p>&copy; Example Inc.</p>`,
+		"snippets/beta.html":  `<aside>In beta.</aside>`,
+		"snippets/legal.html": `<small>Terms apply.</small>`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func TestParseOptionsForSnippets(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html:header_file=header.html,footer_file=footer.html,snippets_dir=snippets")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "header.html", options.HeaderFile)
+	require.Equal(t, "footer.html", options.FooterFile)
+	require.Equal(t, "snippets", options.SnippetsDir)
+}
+
+func TestSnippetDirectives(t *testing.T) {
+	tmpl := NewTemplate(protokit.ParseCodeGenRequest(newSnippetsRequest(t, "")), new(PluginOptions))
+	file := tmpl.Files[0]
+
+	message := findMessage("Book", file)
+	require.Equal(t, "A book.\n\nBooks have pages.", message.Description)
+	require.Equal(t, []string{"beta.html"}, message.Snippets)
+
+	enum := findEnum("Genre", file)
+	require.Empty(t, enum.Description)
+	require.Equal(t, []string{"beta.html"}, enum.Snippets)
+
+	service := findService("Library", file)
+	require.Equal(t, "The library.", service.Description)
+	require.Equal(t, []string{"legal.html"}, service.Snippets)
+
+	method := findServiceMethod("GetBook", service)
+	require.Equal(t, "Returns a book.", method.Description)
+	require.Equal(t, []string{"beta.html", "legal.html"}, method.Snippets)
+}
+
+func TestRenderSnippets(t *testing.T) {
+	dir := writeSnippets(t)
+	defer os.RemoveAll(dir)
+
+	options := "header_file=" + filepath.Join(dir, "header.html") + ",footer_file=" + filepath.Join(dir, "footer.html") +
+		",snippets_dir=" + filepath.Join(dir, "snippets")
+
+	for _, format := range []string{"html,index.html:", "html,index.html:template=slate,",
+		"html,index.html:template=minimal,", "html,index.html:template=print,", "markdown,docs.md:", "gfm,docs.md:"} {
+		parameter := format + options
+		resp, err := new(Plugin).Generate(newSnippetsRequest(t, parameter))
+		require.NoError(t, err, parameter)
+
+		content := resp.File[0].GetContent()
+		require.Contains(t, content, `<div class="banner">Beta docs</div>`, format)
+		require.Contains(t, content, `<p>&copy; Example Inc.</p>`, format)
+		require.Contains(t, content, `<aside>In beta.</aside>`, format)
+		require.Contains(t, content, `<small>Terms apply.</small>`, format)
+		require.NotContains(t, content, "@snippet", format)
+	}
+}
+
+func TestRenderUnknownSnippet(t *testing.T) {
+	_, err := new(Plugin).Generate(newSnippetsRequest(t, "markdown,docs.md"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Unknown snippet: beta.html")
+}
+
+func TestReadSnippetsFailsForMissingFiles(t *testing.T) {
+	_, err := new(Plugin).Generate(newSnippetsRequest(t, "html,index.html:header_file=does-not-exist.html"))
+	require.Error(t, err)
+
+	_, err = new(Plugin).Generate(newSnippetsRequest(t, "html,index.html:snippets_dir=does-not-exist"))
+	require.Error(t, err)
+}
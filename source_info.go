@@ -0,0 +1,188 @@
+package gendoc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// SourceInfo is where an entity is declared in its proto file.
+type SourceInfo struct {
+	// The name of the proto file, as given to protoc.
+	File string `json:"file"`
+	// The line and column the declaration starts at, from 1.
+	Line int `json:"line"`
+	Col  int `json:"col"`
+	// The URL of the declaration, built with the source_url_format option. Empty without it.
+	URL string `json:"url,omitempty"`
+	// The URL editing the declaration (and its comment) on the Git host, built with the edit_url_format option. Empty
+	// without it.
+	EditURL string `json:"editUrl,omitempty"`
+}
+
+// isSourceURLFormat returns whether the format of the source_url_format or edit_url_format option has a {file}
+// placeholder.
+func isSourceURLFormat(format string) bool {
+	return strings.Contains(format, "{file}")
+}
+
+// formatSourceURL returns the URL of the source location, replacing the {file}, {line} and {col} placeholders of the
+// format.
+func formatSourceURL(format string, info *SourceInfo) string {
+	return strings.NewReplacer(
+		"{file}", info.File,
+		"{line}", strconv.Itoa(info.Line),
+		"{col}", strconv.Itoa(info.Col),
+	).Replace(format)
+}
+
+// newSourceInfo returns the source location at the given line and column (from 0) of the file, with the URLs built
+// with the source_url_format and edit_url_format options.
+func newSourceInfo(file string, line, col int32, pluginOptions *PluginOptions) *SourceInfo {
+	info := &SourceInfo{File: file, Line: int(line) + 1, Col: int(col) + 1}
+	if pluginOptions.SourceURLFormat != "" {
+		info.URL = formatSourceURL(pluginOptions.SourceURLFormat, info)
+	}
+	if pluginOptions.EditURLFormat != "" {
+		info.EditURL = formatSourceURL(pluginOptions.EditURLFormat, info)
+	}
+
+	return info
+}
+
+// sourceLocations returns the source locations of the declarations in the file, keyed by sourceKey. It's empty when
+// protoc left out the source code info.
+func sourceLocations(fd *descriptorpb.FileDescriptorProto, pluginOptions *PluginOptions) map[string]*SourceInfo {
+	spans := make(map[string][]int32)
+	for _, loc := range fd.GetSourceCodeInfo().GetLocation() {
+		key := pathKey(loc.GetPath())
+		if _, ok := spans[key]; !ok && len(loc.GetSpan()) >= 2 {
+			spans[key] = loc.GetSpan()
+		}
+	}
+
+	locations := make(map[string]*SourceInfo)
+	add := func(key string, path []int32) {
+		if span, ok := spans[pathKey(path)]; ok {
+			locations[key] = newSourceInfo(fd.GetName(), span[0], span[1], pluginOptions)
+		}
+	}
+	// child returns a copy of the path extended with the given elements, since appending could share the backing array
+	child := func(path []int32, elements ...int32) []int32 {
+		return append(append(make([]int32, 0, len(path)+len(elements)), path...), elements...)
+	}
+
+	addEnums := func(scope string, path []int32, enums []*descriptorpb.EnumDescriptorProto) {
+		for i, e := range enums {
+			enumPath := child(path, int32(i))
+			fullName := scope + "." + e.GetName()
+			add(sourceKey("enum", fullName), enumPath)
+
+			for j, v := range e.GetValue() {
+				add(sourceKey("enum value", fullName+"."+v.GetName()), child(enumPath, enumValuePath, int32(j)))
+			}
+		}
+	}
+	addExtensions := func(path []int32, extensions []*descriptorpb.FieldDescriptorProto) {
+		for i, ext := range extensions {
+			add(extensionSourceKey(ext.GetExtendee(), int(ext.GetNumber())), child(path, int32(i)))
+		}
+	}
+
+	var addMessages func(scope string, path []int32, messages []*descriptorpb.DescriptorProto)
+	addMessages = func(scope string, path []int32, messages []*descriptorpb.DescriptorProto) {
+		for i, m := range messages {
+			messagePath := child(path, int32(i))
+			fullName := scope + "." + m.GetName()
+			add(sourceKey("message", fullName), messagePath)
+
+			for j, f := range m.GetField() {
+				add(fieldSourceKey(fullName, int(f.GetNumber())), child(messagePath, messageFieldPath, int32(j)))
+			}
+			addMessages(fullName, child(messagePath, messageNestedPath), m.GetNestedType())
+			addEnums(fullName, child(messagePath, messageEnumPath), m.GetEnumType())
+			addExtensions(child(messagePath, messageExtensionPath), m.GetExtension())
+		}
+	}
+
+	pkg := fd.GetPackage()
+	addMessages(pkg, []int32{fileMessagePath}, fd.GetMessageType())
+	addEnums(pkg, []int32{fileEnumPath}, fd.GetEnumType())
+	addExtensions([]int32{fileExtensionPath}, fd.GetExtension())
+
+	for i, s := range fd.GetService() {
+		servicePath := []int32{fileServicePath, int32(i)}
+		fullName := pkg + "." + s.GetName()
+		add(sourceKey("service", fullName), servicePath)
+
+		for j, m := range s.GetMethod() {
+			add(sourceKey("method", fullName+"."+m.GetName()), child(servicePath, serviceMethodPath, int32(j)))
+		}
+	}
+
+	return locations
+}
+
+// sourceKey returns the key of the source location of an entity in the map returned by sourceLocations.
+func sourceKey(kind, fullName string) string {
+	return kind + " " + fullName
+}
+
+// fieldSourceKey returns the key of the source location of a field, named by its number since its name may be changed
+// to camel case.
+func fieldSourceKey(messageFullName string, number int) string {
+	return sourceKey("field", fmt.Sprintf("%s#%d", messageFullName, number))
+}
+
+// extensionSourceKey returns the key of the source location of an extension, named by the message it extends and its
+// number, which are unique unlike the names protokit gives extensions.
+func extensionSourceKey(extendee string, number int) string {
+	return sourceKey("extension", fmt.Sprintf("%s#%d", strings.TrimPrefix(extendee, "."), number))
+}
+
+// applySourceInfo sets the source locations of the file and its entities, with URLs when the source_url_format and
+// edit_url_format options are set.
+func applySourceInfo(f *File, fd *descriptorpb.FileDescriptorProto, pluginOptions *PluginOptions) {
+	locations := sourceLocations(fd, pluginOptions)
+
+	// files are located at their top
+	f.SourceInfo = newSourceInfo(fd.GetName(), 0, 0, pluginOptions)
+
+	for _, m := range f.AllMessages() {
+		if m.Redacted {
+			continue
+		}
+
+		m.SourceInfo = locations[sourceKey("message", m.FullName)]
+		for _, field := range m.Fields {
+			if !field.Redacted {
+				field.SourceInfo = locations[fieldSourceKey(m.FullName, field.Number)]
+			}
+		}
+		for _, ext := range m.Extensions {
+			ext.SourceInfo = locations[extensionSourceKey(ext.ContainingFullType, ext.Number)]
+		}
+	}
+
+	for _, e := range f.AllEnums() {
+		e.SourceInfo = locations[sourceKey("enum", e.FullName)]
+		for _, value := range e.Values {
+			if !value.Redacted {
+				value.SourceInfo = locations[sourceKey("enum value", e.FullName+"."+value.Name)]
+			}
+		}
+	}
+
+	for _, ext := range f.Extensions {
+		ext.SourceInfo = locations[extensionSourceKey(ext.ContainingFullType, ext.Number)]
+	}
+
+	for _, s := range f.Services {
+		s.SourceInfo = locations[sourceKey("service", s.FullName)]
+		for _, method := range s.Methods {
+			method.SourceInfo = locations[sourceKey("method", s.FullName+"."+method.Name)]
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionsForSourceURLFormat(t *testing.T) {
+	req := newBookingRequest(t, "html,index.html:source_url_format=https://example.com/{file}#L{line}")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/{file}#L{line}", options.SourceURLFormat)
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:source_url_format=https://example.com/#L{line}"))
+	require.EqualError(t, err, "Invalid source_url_format value: https://example.com/#L{line}")
+}
+
+func TestSourceInfo(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:camel_case_fields=true"))
+	require.NoError(t, err)
+	file := NewTemplate(protokit.ParseCodeGenRequest(newBookingRequest(t, "")), options).Files[0]
+
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 1, Col: 1}, file.SourceInfo)
+
+	message := findMessage("BookingStatus", file)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 30, Col: 1}, message.SourceInfo)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 39, Col: 3}, findField("id", message).SourceInfo)
+
+	// fields are found whatever their name is shown as
+	field := findField("vehicleId", findMessage("Booking", file))
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 69, Col: 3}, field.SourceInfo)
+
+	enum := findEnum("BookingStatus.StatusCode", file)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 34, Col: 3}, enum.SourceInfo)
+	require.Equal(t, "OK", enum.Values[0].Name)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 35, Col: 5}, enum.Values[0].SourceInfo)
+
+	service := findService("BookingService", file)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 18, Col: 1}, service.SourceInfo)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 22, Col: 3},
+		findServiceMethod("BookVehicle", service).SourceInfo)
+
+	require.Equal(t, "country", file.Extensions[0].Name)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 48, Col: 3}, file.Extensions[0].SourceInfo)
+	require.Equal(t, &SourceInfo{File: "Booking.proto", Line: 84, Col: 5},
+		findMessage("Booking", file).Extensions[0].SourceInfo)
+}
+
+func TestSourceInfoURL(t *testing.T) {
+	req := newBookingRequest(t, "html,index.html:source_url_format=https://example.com/blob/main/{file}#L{line}C{col}")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	file := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0]
+
+	require.Equal(t, "https://example.com/blob/main/Booking.proto#L1C1", file.SourceInfo.URL)
+	require.Equal(t, "https://example.com/blob/main/Booking.proto#L30C1",
+		findMessage("BookingStatus", file).SourceInfo.URL)
+}
+
+func TestRenderSourceLinks(t *testing.T) {
+	const format = "source_url_format=https://example.com/{file}#L{line}"
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:"+format))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(),
+		`<p class="source-link"><a href="https://example.com/Booking.proto#L30">View source</a></p>`)
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t, "markdown,README.md:"+format))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(),
+		"### BookingService\n[View source](https://example.com/Booking.proto#L18)\n")
+
+	// without the option, there's nothing to link to
+	resp, err = new(Plugin).Generate(newBookingRequest(t, "html,index.html"))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), "View source")
+}
+
+func TestRenderEditLinks(t *testing.T) {
+	const format = "edit_url_format=https://example.com/edit/{file}#L{line}"
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:"+format))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(),
+		`<p class="source-link"><a class="edit-link" href="https://example.com/edit/Booking.proto#L30">`+
+			`Edit this comment</a></p>`)
+	require.Contains(t, resp.File[0].GetContent(), `<a class="edit-link" `+
+		`href="https://example.com/edit/Booking.proto#L39" title="Edit this comment" aria-label="Edit this comment">`+
+		`✎</a></p></td>`)
+
+	resp, err = new(Plugin).Generate(newBookingRequest(t,
+		"markdown,README.md:"+format+",source_url_format=https://example.com/{file}#L{line}"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(),
+		"### BookingService\n[View source](https://example.com/Booking.proto#L18)"+
+			" · [Edit this comment](https://example.com/edit/Booking.proto#L18)\n")
+	require.Contains(t, resp.File[0].GetContent(), "OK result. [✎](https://example.com/edit/Booking.proto#L35) |")
+
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:edit_url_format=https://example.com/edit"))
+	require.EqualError(t, err, "Invalid edit_url_format value: https://example.com/edit")
+}
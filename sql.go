@@ -0,0 +1,145 @@
+package gendoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sqlColumnTypes maps scalar value types to the SQL types of their columns. Enum values are stored by name, and
+// messages, repeated fields and maps as JSON.
+var sqlColumnTypes = map[string]string{
+	"double":   "DOUBLE PRECISION",
+	"float":    "REAL",
+	"int32":    "INTEGER",
+	"int64":    "BIGINT",
+	"uint32":   "BIGINT",
+	"uint64":   "NUMERIC(20)",
+	"sint32":   "INTEGER",
+	"sint64":   "BIGINT",
+	"fixed32":  "BIGINT",
+	"fixed64":  "NUMERIC(20)",
+	"sfixed32": "INTEGER",
+	"sfixed64": "BIGINT",
+	"bool":     "BOOLEAN",
+	"string":   "TEXT",
+	"bytes":    "BLOB",
+}
+
+// sqlRenderer writes a CREATE TABLE statement for every message, with a column for each field. Comments describe the
+// tables and columns like a data dictionary: the proto type of each column and its description. Map entry messages,
+// messages without fields and redacted messages and fields are left out.
+type sqlRenderer struct{}
+
+func (r *sqlRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *sqlRenderer) ApplyTo(w io.Writer, template *Template) error {
+	enums := make(map[string]bool)
+	for _, file := range template.Files {
+		for _, enum := range file.AllEnums() {
+			enums[enum.FullName] = true
+		}
+	}
+	mapEntries := mapEntryTypes(template)
+
+	var b strings.Builder
+	for i, file := range template.Files {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "-- %s\n", file.Name)
+
+		for _, message := range file.AllMessages() {
+			fields := make([]*MessageField, 0, len(message.Fields))
+			for _, field := range message.Fields {
+				if !field.Redacted {
+					fields = append(fields, field)
+				}
+			}
+			if message.Redacted || mapEntries[message.FullName] || len(fields) == 0 {
+				continue
+			}
+
+			b.WriteString("\n")
+			writeSQLComment(&b, message.Description)
+			fmt.Fprintf(&b, "CREATE TABLE %s (\n", sqlIdentifier(message.FullName))
+
+			for i, field := range fields {
+				column := fmt.Sprintf("  %s %s", sqlIdentifier(field.Name), sqlColumnType(field, enums))
+				if field.Label == "required" {
+					column += " NOT NULL"
+				}
+				if i < len(fields)-1 {
+					column += ","
+				}
+
+				fmt.Fprintf(&b, "%s -- %s", column, sqlFieldType(field, enums))
+				if field.Option("deprecated") == true {
+					b.WriteString(", deprecated")
+				}
+				if description := strings.Join(strings.Fields(field.Description), " "); description != "" {
+					fmt.Fprintf(&b, ": %s", description)
+				}
+				b.WriteString("\n")
+			}
+
+			b.WriteString(");\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sqlColumnType returns the SQL type of the column for field.
+func sqlColumnType(field *MessageField, enums map[string]bool) string {
+	if field.IsMap || field.Label == "repeated" {
+		return "JSON"
+	}
+	if columnType, ok := sqlColumnTypes[field.FullType]; ok {
+		return columnType
+	}
+	if enums[field.FullType] {
+		return "TEXT"
+	}
+
+	return "JSON"
+}
+
+// sqlFieldType describes the proto type of field in a column comment, e.g. `repeated string` or
+// `enum com.example.BookingStatus`.
+func sqlFieldType(field *MessageField, enums map[string]bool) string {
+	description := field.FullType
+	switch {
+	case field.IsMap:
+		return "map " + description
+	case enums[field.FullType]:
+		description = "enum " + description
+	case sqlColumnTypes[field.FullType] == "":
+		description = "message " + description
+	}
+
+	if field.Label == "repeated" {
+		description = "repeated " + description
+	}
+
+	return description
+}
+
+// sqlIdentifier quotes name as a delimited identifier, which may contain dots and reserved words.
+func sqlIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// writeSQLComment writes text as line comments.
+func writeSQLComment(b *strings.Builder, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		b.WriteString(strings.TrimRight("-- "+line, " ") + "\n")
+	}
+}
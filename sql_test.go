@@ -0,0 +1,66 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSQL(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	output, err := RenderTemplate(RenderTypeSQL, NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions)), "")
+	require.NoError(t, err)
+	content := string(output)
+
+	require.Contains(t, content, `-- Represents the status of a vehicle booking.
+CREATE TABLE "com.example.BookingStatus" (
+  "id" INTEGER NOT NULL, -- int32: Unique booking status ID.
+  "description" TEXT NOT NULL, -- string: Booking status description. E.g. "Active".
+  "status_code" TEXT -- enum com.example.BookingStatus.StatusCode: The status of this status?
+);
+`)
+	require.Contains(t, content, `  "color_preference" TEXT -- string, deprecated: Color preference of the customer.
+);
+`)
+	require.Contains(t, content, `  "model" JSON, -- message com.example.Model: Vehicle model.
+`)
+	require.Contains(t, content, `  "rates" JSON, -- repeated sint32: Doc comments for fields can come before or after`)
+	require.Contains(t, content, `  "properties" JSON, -- map com.example.Vehicle.PropertiesEntry: bag of properties related to the vehicle.
+`)
+
+	// map entries aren't tables of their own
+	require.NotContains(t, content, `CREATE TABLE "com.example.Vehicle.PropertiesEntry"`)
+	require.Contains(t, content, ");\n\n-- Vehicle.proto\n")
+}
+
+func TestRenderSQLOmitsRedactedFields(t *testing.T) {
+	template := &Template{Files: []*File{{
+		Name: "user.proto",
+		Messages: []*Message{
+			{FullName: "org.User", Description: "A user.\n\nUsers sign in.", Fields: []*MessageField{
+				{Name: "id", FullType: "string", Label: "optional"},
+				{Name: "«redacted»", Redacted: true},
+			}},
+			{FullName: "org.Empty", Fields: []*MessageField{{Name: "«redacted»", Redacted: true}}},
+			{FullName: "«redacted»", Redacted: true},
+		},
+	}}}
+
+	output, err := RenderTemplate(RenderTypeSQL, template, "")
+	require.NoError(t, err)
+	require.Equal(t, `-- user.proto
+
+-- A user.
+--
+-- Users sign in.
+CREATE TABLE "org.User" (
+  "id" TEXT -- string
+);
+`, string(output))
+}
@@ -0,0 +1,101 @@
+package gendoc
+
+import (
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// StabilityAlpha is the level of entities that may change or go away at any time.
+	StabilityAlpha = "alpha"
+	// StabilityBeta is the level of entities that are mostly settled, but may still change.
+	StabilityBeta = "beta"
+	// StabilityStable is the level of entities that only change compatibly. Entities without a level are stable.
+	StabilityStable = "stable"
+)
+
+const stabilityDirective = "@stability"
+
+// stabilityRanks orders the stability levels, from the least to the most stable.
+var stabilityRanks = map[string]int{StabilityAlpha: 0, StabilityBeta: 1, StabilityStable: 2}
+
+// isStability returns whether the level is one of StabilityAlpha, StabilityBeta and StabilityStable.
+func isStability(level string) bool {
+	_, ok := stabilityRanks[level]
+	return ok
+}
+
+// normalizeStability returns the stability level written as given, e.g. `Beta` or the enum value `STABILITY_BETA`, or
+// an empty string when it isn't one.
+func normalizeStability(level string) string {
+	level = strings.TrimPrefix(strings.ToLower(level), "stability_")
+	if !isStability(level) {
+		return ""
+	}
+
+	return level
+}
+
+// extractStability removes the @stability directive (e.g. `@stability beta`) from a description, returning the
+// remaining description and the level it gives. The level is empty when there's no directive.
+func extractStability(description string) (string, string) {
+	if !strings.Contains(description, stabilityDirective) {
+		return description, ""
+	}
+
+	stability := ""
+	lines := make([]string, 0)
+	for _, line := range strings.Split(description, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == stabilityDirective && normalizeStability(fields[1]) != "" {
+			stability = normalizeStability(fields[1])
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), stability
+}
+
+// stabilityFromOption returns the stability level opts give an entity with the stability_option option, or an empty
+// string when they don't.
+func stabilityFromOption(opts proto.Message, pluginOptions *PluginOptions) string {
+	if pluginOptions.StabilityOption == "" {
+		return ""
+	}
+
+	if value, ok := decodeOptions(opts, pluginOptions)[pluginOptions.StabilityOption].(string); ok {
+		return normalizeStability(value)
+	}
+
+	return ""
+}
+
+// parseStability removes the @stability directive from the description of an entity, returning the remaining
+// description and the entity's stability level. A directive takes precedence over the stability_option option.
+func parseStability(description string, opts proto.Message, pluginOptions *PluginOptions) (string, string) {
+	description, stability := extractStability(description)
+	if stability == "" {
+		stability = stabilityFromOption(opts, pluginOptions)
+	}
+
+	return description, stability
+}
+
+// excludedByStability reports whether an entity is less stable than the min_stability option allows, given its
+// comment and options.
+func excludedByStability(comment *protokit.Comment, opts proto.Message, pluginOptions *PluginOptions) bool {
+	if pluginOptions.MinStability == "" {
+		return false
+	}
+
+	_, stability := parseStability(descriptionFromComment(comment, pluginOptions), opts, pluginOptions)
+	if stability == "" {
+		return false
+	}
+
+	return stabilityRanks[stability] < stabilityRanks[pluginOptions.MinStability]
+}
@@ -0,0 +1,147 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// newStabilityRequest returns a request documenting entities of each stability level, given with @stability directives.
+func newStabilityRequest(parameter string) *pluginpb.CodeGeneratorRequest {
+	field := func(name string, number int32) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+	value := func(name string, number int32) *descriptorpb.EnumValueDescriptorProto {
+		return &descriptorpb.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+	}
+	method := func(name string) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(name),
+			InputType:  proto.String(".shop.Order"),
+			OutputType: proto.String(".shop.Order"),
+		}
+	}
+	location := func(comment string, path ...int32) *descriptorpb.SourceCodeInfo_Location {
+		return &descriptorpb.SourceCodeInfo_Location{
+			Path: path, Span: []int32{1, 0, 1}, LeadingComments: proto.String(comment),
+		}
+	}
+
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{"shop.proto"},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{{
+			Name:    proto.String("shop.proto"),
+			Package: proto.String("shop"),
+			Syntax:  proto.String("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{
+				{
+					Name:  proto.String("Order"),
+					Field: []*descriptorpb.FieldDescriptorProto{field("id", 1), field("gift_note", 2)},
+				},
+				{Name: proto.String("Draft")},
+			},
+			EnumType: []*descriptorpb.EnumDescriptorProto{{
+				Name:  proto.String("State"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{value("OPEN", 0), value("HELD", 1)},
+			}},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name:   proto.String("Shop"),
+				Method: []*descriptorpb.MethodDescriptorProto{method("GetOrder"), method("PreviewOrder")},
+			}},
+			SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+				Location: []*descriptorpb.SourceCodeInfo_Location{
+					location(" An order.\n @stability stable\n", 4, 0),
+					location(" A note for the recipient.\n\n @stability beta\n", 4, 0, 2, 1),
+					location(" @stability alpha\n An order being written.\n", 4, 1),
+					location(" On hold.\n @stability BETA\n", 5, 0, 2, 1),
+					location(" The shop.\n @stability beta\n", 6, 0),
+					location(" @stability alpha\n", 6, 0, 2, 1),
+				},
+			},
+		}},
+	}
+}
+
+func TestParseOptionsForStability(t *testing.T) {
+	req := newStabilityRequest("html,index.html:min_stability=beta,stability_option=org.stability")
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, StabilityBeta, options.MinStability)
+	require.Equal(t, "org.stability", options.StabilityOption)
+
+	_, err = ParseOptions(newStabilityRequest("html,index.html:min_stability=experimental"))
+	require.EqualError(t, err, "Invalid min_stability value: experimental")
+
+	_, err = ParseOptions(newStabilityRequest("html,index.html:stability_option="))
+	require.EqualError(t, err, "Invalid stability_option value: ")
+}
+
+func TestStability(t *testing.T) {
+	file := NewTemplate(protokit.ParseCodeGenRequest(newStabilityRequest("")), new(PluginOptions)).Files[0]
+
+	order := findMessage("Order", file)
+	require.Equal(t, StabilityStable, order.Stability)
+	require.Equal(t, "An order.", order.Description)
+	require.Empty(t, findField("id", order).Stability)
+	require.Equal(t, StabilityBeta, findField("gift_note", order).Stability)
+	require.Equal(t, "A note for the recipient.", findField("gift_note", order).Description)
+
+	draft := findMessage("Draft", file)
+	require.Equal(t, StabilityAlpha, draft.Stability)
+	require.Equal(t, "An order being written.", draft.Description)
+
+	require.Equal(t, StabilityBeta, findEnum("State", file).Values[1].Stability)
+	require.Equal(t, "On hold.", findEnum("State", file).Values[1].Description)
+
+	service := findService("Shop", file)
+	require.Equal(t, StabilityBeta, service.Stability)
+	require.Empty(t, findServiceMethod("GetOrder", service).Stability)
+	require.Equal(t, StabilityAlpha, findServiceMethod("PreviewOrder", service).Stability)
+	require.Empty(t, findServiceMethod("PreviewOrder", service).Description)
+}
+
+func TestMinStability(t *testing.T) {
+	file := NewTemplate(protokit.ParseCodeGenRequest(newStabilityRequest("")),
+		&PluginOptions{MinStability: StabilityBeta}).Files[0]
+
+	require.NotNil(t, findMessage("Order", file))
+	require.Nil(t, findMessage("Draft", file))
+	require.NotNil(t, findField("gift_note", findMessage("Order", file)))
+	require.Len(t, findEnum("State", file).Values, 2)
+	require.Nil(t, findServiceMethod("PreviewOrder", findService("Shop", file)))
+
+	file = NewTemplate(protokit.ParseCodeGenRequest(newStabilityRequest("")),
+		&PluginOptions{MinStability: StabilityStable}).Files[0]
+
+	require.NotNil(t, findMessage("Order", file))
+	require.NotNil(t, findField("id", findMessage("Order", file)))
+	require.Nil(t, findField("gift_note", findMessage("Order", file)))
+	require.Len(t, findEnum("State", file).Values, 1)
+	require.Nil(t, findService("Shop", file))
+}
+
+func TestRenderStability(t *testing.T) {
+	resp, err := new(Plugin).Generate(newStabilityRequest("html,index.html"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), `<span class="stability-badge stability-alpha">alpha</span></h3>`)
+	require.Contains(t, resp.File[0].GetContent(),
+		`<td>gift_note <span class="stability-badge stability-beta">beta</span></td>`)
+
+	resp, err = new(Plugin).Generate(newStabilityRequest("markdown,README.md:min_stability=beta,locale=de"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "### Shop `Beta`")
+	require.Contains(t, resp.File[0].GetContent(), "</a> gift_note `Beta` |")
+	require.NotContains(t, resp.File[0].GetContent(), "Draft")
+}
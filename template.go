@@ -3,13 +3,17 @@ package gendoc
 import (
 	"encoding/json"
 	"fmt"
+	html_template "html/template"
+	"path"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 
-	"github.com/daotl/protoc-gen-doc/extensions"
-	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/pseudomuto/protokit"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 // Template is a type for encapsulating all the parsed files, messages, fields, enums, services, extensions, etc. into
@@ -19,65 +23,375 @@ type Template struct {
 	Files []*File `json:"files"`
 	// Details about the scalar values and their respective types in supported languages.
 	Scalars []*ScalarValue `json:"scalarValueTypes"`
+	// Presentation settings for the built-in HTML template.
+	Theme *Theme `json:"-"`
+	// The stylesheet of the built-in HTML template, either inlined or referenced by URL.
+	Assets *Assets `json:"-"`
+	// The locale used to translate the strings of the built-in templates.
+	Locale string `json:"-"`
+	// The rules used by LintFindings.
+	Lint *LintConfig `json:"-"`
+	// The alphabetical index of all documented entities, set when the index option is enabled.
+	Index []*IndexEntry `json:"-"`
+	// The title, description and version of the documentation.
+	Meta Meta `json:"meta"`
+	// The absolute URL of the page being rendered. Only set when the site_url option is set.
+	URL string `json:"-"`
+	// How the p and nobr functions treat markup in comments: SanitizeHTMLOff, SanitizeHTMLEscape or
+	// SanitizeHTMLAllowlist. Empty means SanitizeHTMLOff.
+	SanitizeHTML string `json:"-"`
+	// Whether templates are restricted to functions that can't access the environment or the network, and can't be
+	// used to exhaust resources. See the template_sandbox option.
+	Sandbox bool `json:"-"`
+	// The version of the template data: TemplateAPIV1 or TemplateAPIV2. See the template_api option.
+	APIVersion string `json:"-"`
+	// How the enumNumber function formats enum value numbers: EnumNumberFormatDecimal, EnumNumberFormatHex or
+	// EnumNumberFormatBoth. Empty means EnumNumberFormatDecimal.
+	EnumNumberFormat string `json:"-"`
+	// The text the label function shows for field labels, keyed by label. See the label option.
+	Labels map[string]string `json:"-"`
+	// The DocBook documents the types of other packages are documented in, linked to with olinks. See the olink option.
+	OlinkTargets []OlinkTarget `json:"-"`
+	// The wiki page each type is documented on, keyed by full name. Only set for the wiki format.
+	WikiPages map[string]string `json:"-"`
+	// Whether the p, nobr and refs functions link references to other entities in comments. See the comment_links
+	// option.
+	CommentLinks bool `json:"-"`
+	// Whether the built-in templates start with the PackageOverviews. See the package_overview option.
+	PackageOverview bool `json:"-"`
+	// Whether the built-in templates show the syntax or edition of each file, and its features. See the show_syntax
+	// option.
+	ShowSyntax bool `json:"-"`
+	// The path (relative to the output root, with forward slashes) of the output file being rendered.
+	Page string `json:"-"`
+	// The output file (relative to the output root, with forward slashes) each type is documented in, keyed by full
+	// name. Only set when the docs are split into an output file per directory (see the source_relative flag), for the
+	// link, relpath and typeurl functions.
+	Pages map[string]string `json:"-"`
+	// The header, footer and named snippets inserted into the page.
+	Snippets *Snippets `json:"-"`
+	// The static site the page is part of. Only set with the site option.
+	Site *Site `json:"-"`
+
+	// Whether the output is Markdown, which the link and typeurl functions format their links for.
+	markdown bool
+	// The resolver the option and hasOption functions decode custom options with. See optionTypes.
+	optionTypes protoregistry.ExtensionTypeResolver
+	// Whether the sections of the output are named with AnchorFilter rather than by full name. See hasAnchoredSections.
+	anchored bool
+}
+
+// Meta describes the generated documentation as a whole (see the title, description, version and meta_file options).
+// The built-in templates use the translated "Protocol Documentation" when Title is empty.
+type Meta struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+
+	// The release the docs were generated for, e.g. a Git tag, embedded into every output (see the version_stamp
+	// option).
+	VersionStamp string `json:"versionStamp,omitempty"`
+
+	// The version of protoc-gen-doc that generated the docs, and when, as RFC 3339 in UTC. GeneratedAt is empty with
+	// the reproducible option.
+	GeneratorVersion string `json:"generatorVersion"`
+	GeneratedAt      string `json:"generatedAt,omitempty"`
+}
+
+// PackageDocFile is the base name of a file whose comments document its whole package.
+const PackageDocFile = "doc.proto"
+
+// Package groups the files that declare the same proto package.
+type Package struct {
+	Name  string  `json:"name"`
+	Files []*File `json:"files"`
+	// The overview of the package. This is the description of the package's doc.proto file when there is one, and
+	// otherwise the descriptions of all its files (separated by blank lines).
+	Description string `json:"description"`
+}
+
+// Packages returns the template's files grouped by their proto package. Packages are sorted by name and the files
+// within each package keep the order they have in Files.
+func (t *Template) Packages() []*Package {
+	return t.packages(false)
+}
+
+// packages groups either the imported files of the template, or the other files, by package.
+func (t *Template) packages(imported bool) []*Package {
+	packages := make([]*Package, 0)
+	byName := make(map[string]*Package)
+
+	for _, f := range t.Files {
+		if f.Imported != imported {
+			continue
+		}
+
+		pkg, ok := byName[f.Package]
+		if !ok {
+			pkg = &Package{Name: f.Package}
+			byName[f.Package] = pkg
+			packages = append(packages, pkg)
+		}
+
+		pkg.Files = append(pkg.Files, f)
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	for _, pkg := range packages {
+		pkg.Description = packageDescription(pkg.Files)
+	}
+
+	return packages
+}
+
+// PackageOverviews returns the packages that have a description, in the order of Packages.
+func (t *Template) PackageOverviews() []*Package {
+	overviews := make([]*Package, 0)
+	for _, pkg := range t.Packages() {
+		if pkg.Description != "" {
+			overviews = append(overviews, pkg)
+		}
+	}
+
+	return overviews
+}
+
+func packageDescription(files []*File) string {
+	for _, f := range files {
+		if path.Base(f.Name) == PackageDocFile {
+			return f.Description
+		}
+	}
+
+	descriptions := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Description != "" {
+			descriptions = append(descriptions, f.Description)
+		}
+	}
+
+	return strings.Join(descriptions, "\n\n")
+}
+
+// Theme describes how the built-in HTML template should be styled.
+type Theme struct {
+	// The color scheme to use: light, dark or auto (follows the reader's system preference).
+	Name string
+	// Extra CSS inlined after the default styles. Useful for overriding the theme's CSS variables.
+	CSS html_template.CSS
+	// The URL of an image shown next to the page title.
+	Logo string
+	// The layout of the page: one of the HTMLTemplate layouts. Empty means HTMLTemplateDefault.
+	Layout string
+	// The direction of the page's text: ltr, rtl or auto. Empty leaves it to the browser (left to right).
+	Dir string
+}
+
+// Assets describes how the built-in HTML template includes its stylesheet. When StylesheetURL is set the page links to
+// it, otherwise Stylesheet is inlined into the page.
+type Assets struct {
+	// The default styles of the HTML template.
+	Stylesheet html_template.CSS
+	// The URL of an external copy of the default styles (assets=external or assets_url).
+	StylesheetURL string
+}
+
+func newAssets(pluginOptions *PluginOptions) *Assets {
+	if pluginOptions.AssetsURL != "" {
+		return &Assets{StylesheetURL: strings.TrimSuffix(pluginOptions.AssetsURL, "/") + "/" + path.Base(StylesheetAsset)}
+	}
+
+	if pluginOptions.ExternalAssets {
+		return &Assets{StylesheetURL: StylesheetAsset}
+	}
+
+	return &Assets{Stylesheet: html_template.CSS(htmlCSS)}
+}
+
+func newTheme(pluginOptions *PluginOptions) *Theme {
+	theme := &Theme{Name: pluginOptions.Theme, Logo: pluginOptions.Logo, Layout: pluginOptions.HTMLTemplate,
+		Dir: pluginOptions.Dir}
+	if theme.Name == "" {
+		theme.Name = "auto"
+	}
+
+	return theme
 }
 
 // NewTemplate creates a Template object from a set of descriptors.
 func NewTemplate(descs []*protokit.FileDescriptor, pluginOptions *PluginOptions) *Template {
+	return newTemplate(descs, pluginOptions, nil)
+}
+
+// newTemplate is NewTemplate, reporting how long building each file took to onFile when it isn't nil.
+func newTemplate(descs []*protokit.FileDescriptor, pluginOptions *PluginOptions, onFile func(name string, d time.Duration)) *Template {
 	files := make([]*File, 0, len(descs))
+	apiVersion := templateAPI(pluginOptions)
 
 	for _, f := range descs {
+		start := time.Now()
 		file := &File{
-			Name:          f.GetName(),
-			Description:   descriptionFromComment(f.GetSyntaxComments(), pluginOptions),
-			Package:       f.GetPackage(),
-			HasEnums:      len(f.Enums) > 0,
-			HasExtensions: len(f.Extensions) > 0,
-			HasMessages:   len(f.Messages) > 0,
-			HasServices:   len(f.Services) > 0,
-			Enums:         make(orderedEnums, 0, len(f.Enums)),
-			Extensions:    make(orderedExtensions, 0, len(f.Extensions)),
-			Messages:      make(orderedMessages, 0, len(f.Messages)),
-			Services:      make(orderedServices, 0, len(f.Services)),
-			Options: mergeOptions(extractOptions(f.GetOptions()),
-				extensions.Transform(f.OptionExtensions)),
+			Name:        f.GetName(),
+			Description: descriptionFromComment(f.GetSyntaxComments(), pluginOptions),
+			Package:     f.GetPackage(),
+			Enums:       make(orderedEnums, 0, len(f.Enums)),
+			Extensions:  make(orderedExtensions, 0, len(f.Extensions)),
+			Messages:    make(orderedMessages, 0, len(f.Messages)),
+			Services:    make(orderedServices, 0, len(f.Services)),
+			Options:     entityOptions(f.GetOptions(), f.OptionExtensions, pluginOptions),
+		}
+
+		file.Syntax, file.Edition, file.Features = fileSyntax(f.FileDescriptorProto)
+		file.Imports = parseImports(f.FileDescriptorProto)
+		file.ResourceDefinitions = parseResourceDefinitions(f.GetOptions())
+		file.Owners = fileOwners(file.Name, pluginOptions.codeOwners)
+
+		if pluginOptions.IncludeFileSource {
+			if source, err := PrintProto(f); err == nil {
+				file.Source = source
+			}
 		}
 
 		for _, e := range f.Enums {
-			file.Enums = append(file.Enums, parseEnum(e, pluginOptions))
+			if !excludedByOption(e.GetOptions(), pluginOptions) &&
+				!excludedByStability(e.GetComments(), e.GetOptions(), pluginOptions) {
+				file.Enums = append(file.Enums, parseEnum(e, pluginOptions))
+			}
 		}
+		file.HasEnums = len(file.Enums) > 0
 
 		for _, e := range f.Extensions {
-			file.Extensions = append(file.Extensions, parseFileExtension(e, pluginOptions))
+			if !excludedByOption(e.GetOptions(), pluginOptions) {
+				file.Extensions = append(file.Extensions, parseFileExtension(e, pluginOptions))
+			}
+		}
+		file.HasExtensions = len(file.Extensions) > 0
+
+		// Recursively add nested types from messages, to their parent with v2 and to the file otherwise
+		var addFromMessage func(*protokit.Descriptor, *Message)
+		addMessage := func(msg, parent *Message) {
+			if parent != nil {
+				parent.Messages = append(parent.Messages, msg)
+			} else {
+				file.Messages = append(file.Messages, msg)
+			}
 		}
 
-		// Recursively add nested types from messages
-		var addFromMessage func(*protokit.Descriptor)
-		addFromMessage = func(m *protokit.Descriptor) {
-			file.Messages = append(file.Messages, parseMessage(m, pluginOptions))
+		addFromMessage = func(m *protokit.Descriptor, parent *Message) {
+			msg := parseMessage(m, pluginOptions)
+			addMessage(msg, parent)
+
+			if apiVersion == TemplateAPIV2 {
+				parent = msg
+			}
+
 			for _, e := range m.Enums {
-				file.Enums = append(file.Enums, parseEnum(e, pluginOptions))
+				if excludedByOption(e.GetOptions(), pluginOptions) ||
+					excludedByStability(e.GetComments(), e.GetOptions(), pluginOptions) {
+					continue
+				}
+
+				if parent != nil {
+					parent.Enums = append(parent.Enums, parseEnum(e, pluginOptions))
+				} else {
+					file.Enums = append(file.Enums, parseEnum(e, pluginOptions))
+				}
 			}
 			for _, n := range m.Messages {
-				addFromMessage(n)
+				switch {
+				case !excludedByOption(n.GetOptions(), pluginOptions) &&
+					!excludedByStability(n.GetComments(), n.GetOptions(), pluginOptions):
+					addFromMessage(n, parent)
+				case pluginOptions.Redact:
+					addMessage(redactedMessage(), parent)
+				}
 			}
+
+			sort.Sort(msg.Enums)
+			sort.Sort(msg.Messages)
 		}
 		for _, m := range f.Messages {
-			addFromMessage(m)
+			switch {
+			case !excludedByOption(m.GetOptions(), pluginOptions) &&
+				!excludedByStability(m.GetComments(), m.GetOptions(), pluginOptions):
+				addFromMessage(m, nil)
+			case pluginOptions.Redact:
+				addMessage(redactedMessage(), nil)
+			default:
+				continue
+			}
+			file.HasMessages = true
 		}
 
 		for _, s := range f.Services {
-			file.Services = append(file.Services, parseService(s, pluginOptions))
+			if !excludedByOption(s.GetOptions(), pluginOptions) &&
+				!excludedByStability(s.GetComments(), s.GetOptions(), pluginOptions) {
+				file.Services = append(file.Services, parseService(s, pluginOptions))
+			}
 		}
+		file.HasServices = len(file.Services) > 0
 
 		sort.Sort(file.Enums)
 		sort.Sort(file.Extensions)
 		sort.Sort(file.Messages)
 		sort.Sort(file.Services)
 
+		applySourceInfo(file, f.FileDescriptorProto, pluginOptions)
+		applyDescriptors(file, f.FileDescriptorProto, pluginOptions)
+		if len(pluginOptions.commentDescriptions) > 0 {
+			applyCommentDescriptions(file, pluginOptions)
+		}
+		if pluginOptions.sinceVersions != nil {
+			applySince(file, pluginOptions)
+		}
+
 		files = append(files, file)
+		if onFile != nil {
+			onFile(file.Name, time.Since(start))
+		}
 	}
 
-	return &Template{Files: files, Scalars: makeScalars()}
+	addUsages(files)
+	resolveResourceReferences(files)
+	if pluginOptions.ExpandMethodTypes {
+		expandMethodTypes(files)
+	}
+
+	locale := pluginOptions.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	return &Template{
+		Files:            files,
+		Scalars:          makeScalars(),
+		Theme:            newTheme(pluginOptions),
+		Assets:           newAssets(pluginOptions),
+		Snippets:         new(Snippets),
+		Locale:           locale,
+		Lint:             newLintConfig(pluginOptions),
+		SanitizeHTML:     pluginOptions.SanitizeHTML,
+		Sandbox:          pluginOptions.TemplateSandbox,
+		APIVersion:       apiVersion,
+		optionTypes:      optionTypes(pluginOptions),
+		EnumNumberFormat: pluginOptions.EnumNumberFormat,
+		Labels:           pluginOptions.Labels,
+		OlinkTargets:     pluginOptions.OlinkTargets,
+		CommentLinks:     pluginOptions.CommentLinks,
+		PackageOverview:  pluginOptions.PackageOverview,
+		ShowSyntax:       pluginOptions.ShowSyntax,
+		markdown:         isMarkdown(pluginOptions.Type),
+		anchored:         hasAnchoredSections(pluginOptions),
+		Meta: Meta{
+			Title:       pluginOptions.Title,
+			Description: pluginOptions.Description,
+			Version:     pluginOptions.Version,
+
+			VersionStamp:     pluginOptions.VersionStamp,
+			GeneratorVersion: VERSION,
+			GeneratedAt:      generatedAt(pluginOptions),
+		},
+	}
 }
 
 func makeScalars() []*ScalarValue {
@@ -131,7 +445,7 @@ func extractOptions(opts commonOptions) map[string]interface{} {
 		out["deprecated"] = true
 	}
 	switch opts := opts.(type) {
-	case *descriptor.MethodOptions:
+	case *descriptorpb.MethodOptions:
 		if opts != nil && opts.IdempotencyLevel != nil {
 			out["idempotency_level"] = opts.IdempotencyLevel.String()
 		}
@@ -149,6 +463,13 @@ type File struct {
 	Description string `json:"description"`
 	Package     string `json:"package"`
 
+	// The syntax of the file: proto2, proto3 or editions.
+	Syntax string `json:"syntax"`
+	// The edition of editions files, e.g. 2023.
+	Edition string `json:"edition,omitempty"`
+	// The resolved features of editions files (see Features).
+	Features *Features `json:"features,omitempty"`
+
 	HasEnums      bool `json:"hasEnums"`
 	HasExtensions bool `json:"hasExtensions"`
 	HasMessages   bool `json:"hasMessages"`
@@ -160,11 +481,50 @@ type File struct {
 	Services   orderedServices   `json:"services"`
 
 	Options map[string]interface{} `json:"options,omitempty"`
+
+	// The files imported by this file, in the order they are imported.
+	Imports []*FileImport `json:"imports"`
+
+	// The reconstructed proto source of the file. Only set when the include_file_source option is enabled.
+	Source string `json:"source,omitempty"`
+
+	// The resources declared with the google.api.resource_definition option, for use by resource references.
+	ResourceDefinitions []*Resource `json:"resourceDefinitions,omitempty"`
+
+	// Whether the file is only documented because it's imported by the files being documented (see the
+	// include_imports option).
+	Imported bool `json:"imported,omitempty"`
+
+	// The owners of the file, read from the CODEOWNERS file given with the codeowners_file option.
+	Owners []*Owner `json:"owners,omitempty"`
+
+	// The file itself, located at its first line, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the file, giving custom templates access to everything the fields above leave
+	// out, e.g. `{{.Descriptor.Path}}`. Not included in the json output, like the Descriptor of the entities below.
+	Descriptor protoreflect.FileDescriptor `json:"-"`
+}
+
+// FileImport describes an import statement of a file.
+type FileImport struct {
+	Name   string `json:"name"`
+	Public bool   `json:"public"`
+	Weak   bool   `json:"weak"`
 }
 
 // Option returns the named option.
 func (f File) Option(name string) interface{} { return f.Options[name] }
 
+// SourceLines returns the lines of the file's reconstructed proto source.
+func (f File) SourceLines() []string {
+	if f.Source == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(f.Source, "\n"), "\n")
+}
+
 // FileExtension contains details about top-level extensions within a proto(2) file.
 type FileExtension struct {
 	Name               string `json:"name"`
@@ -180,8 +540,16 @@ type FileExtension struct {
 	ContainingType     string `json:"containingType"`
 	ContainingLongType string `json:"containingLongType"`
 	ContainingFullType string `json:"containingFullType"`
+
+	// Where the extension is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	Options map[string]interface{} `json:"options,omitempty"`
 }
 
+// Option returns the named option.
+func (e FileExtension) Option(name string) interface{} { return e.Options[name] }
+
 // Message contains details about a protobuf message.
 //
 // In the case of proto3 files, HasExtensions will always be false, and Extensions will be empty.
@@ -198,6 +566,47 @@ type Message struct {
 	Extensions []*MessageExtension `json:"extensions"`
 	Fields     []*MessageField     `json:"fields"`
 
+	// The oneofs of the message, and the types nested in it. Only set with TemplateAPIV2.
+	Oneofs   []*Oneof        `json:"oneofs,omitempty"`
+	Messages orderedMessages `json:"messages,omitempty"`
+	Enums    orderedEnums    `json:"enums,omitempty"`
+
+	// The fields and methods (within the same output) that use this message.
+	UsedBy []*Usage `json:"usedBy,omitempty"`
+
+	// The resource this message represents, declared with the google.api.resource option.
+	Resource *Resource `json:"resource,omitempty"`
+
+	// The channel the message is published to as an event, designated with an @event directive in the comment or the
+	// event_option option.
+	Channel string `json:"channel,omitempty"`
+
+	// Whether this is a placeholder for an excluded message, shown with the redact option.
+	Redacted bool `json:"redacted,omitempty"`
+
+	// The name of the proto2 group field declaring this message in its parent, if any. The message is described by
+	// the comment of the group when it has none of its own.
+	GroupField string `json:"groupField,omitempty"`
+
+	// The names of the snippets inserted after the description, with @snippet directives in the comment.
+	Snippets []string `json:"snippets,omitempty"`
+
+	// The related types and pages listed after the description, given with @see and @link directives in the comment.
+	SeeAlso []*SeeAlso `json:"seeAlso,omitempty"`
+
+	// Where the message is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the message.
+	Descriptor protoreflect.MessageDescriptor `json:"-"`
+
+	// The stability level (alpha, beta or stable), given with a @stability directive in the comment or the
+	// stability_option option. Empty when it has none, which counts as stable.
+	Stability string `json:"stability,omitempty"`
+	// The first version the entity appeared in, computed from the descriptor sets of the since_descriptor_set option.
+	// Empty without the option.
+	Since string `json:"since,omitempty"`
+
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
@@ -249,11 +658,60 @@ type MessageField struct {
 	Type         string `json:"type"`
 	LongType     string `json:"longType"`
 	FullType     string `json:"fullType"`
+	Number       int    `json:"number"`
+	JSONName     string `json:"jsonName"`
 	IsMap        bool   `json:"ismap"`
 	IsOneof      bool   `json:"isoneof"`
 	OneofDecl    string `json:"oneofdecl"`
 	DefaultValue string `json:"defaultValue"`
 
+	// The resource this field refers to, declared with the google.api.resource_reference option.
+	ResourceReference *ResourceReference `json:"resourceReference,omitempty"`
+
+	// How the type of the field is shown instead of LongType, when the typeDisplay of the meta_file overrides it.
+	TypeDisplay *TypeDisplay `json:"typeDisplay,omitempty"`
+
+	// The types the payload of this google.protobuf.Any field is expected to be, by full name, given with @any-types
+	// directives in the comment.
+	AnyTypes []string `json:"anyTypes,omitempty"`
+
+	// Whether this is a placeholder for an excluded field, shown with the redact option.
+	Redacted bool `json:"redacted,omitempty"`
+
+	// The unit and range of the field's values, read from the options named by the fieldMeta of the meta_file.
+	Meta *FieldMeta `json:"meta,omitempty"`
+
+	// Whether the field tracks presence, i.e. whether a field set to its default value can be told from an unset one.
+	HasPresence bool `json:"hasPresence"`
+	// Whether this is a proto2 group, whose type is the nested message it declares. Templates show it next to the
+	// label, as in the proto source.
+	IsGroup bool `json:"isGroup,omitempty"`
+	// Whether this is a proto3 field declared optional, which tracks presence unlike the other singular proto3 fields.
+	// Templates show it next to the label.
+	Proto3Optional bool `json:"proto3Optional,omitempty"`
+
+	// Whether the field tracks presence (explicit) or not (implicit), resolved from the syntax of its file and the
+	// field_presence feature, which the labels of editions files don't show. Empty for repeated fields.
+	EffectivePresence string `json:"effectivePresence,omitempty"`
+	// Whether the values of this string field are validated as UTF-8, resolved from the utf8_validation feature.
+	EffectiveUTF8Validation bool `json:"effectiveUtf8Validation,omitempty"`
+	// Whether the values of this repeated field are packed on the wire, resolved from the packed option and the
+	// repeated_field_encoding feature.
+	EffectivePacked bool `json:"effectivePacked,omitempty"`
+
+	// Where the field is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the field.
+	Descriptor protoreflect.FieldDescriptor `json:"-"`
+
+	// The stability level (alpha, beta or stable), given with a @stability directive in the comment or the
+	// stability_option option. Empty when it has none, which counts as stable.
+	Stability string `json:"stability,omitempty"`
+	// The first version the entity appeared in, computed from the descriptor sets of the since_descriptor_set option.
+	// Empty without the option.
+	Since string `json:"since,omitempty"`
+
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
@@ -277,6 +735,25 @@ type Enum struct {
 	Description string       `json:"description"`
 	Values      []*EnumValue `json:"values"`
 
+	// The names of the snippets inserted after the description, with @snippet directives in the comment.
+	Snippets []string `json:"snippets,omitempty"`
+
+	// The related types and pages listed after the description, given with @see and @link directives in the comment.
+	SeeAlso []*SeeAlso `json:"seeAlso,omitempty"`
+
+	// Where the enum is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the enum.
+	Descriptor protoreflect.EnumDescriptor `json:"-"`
+
+	// The stability level (alpha, beta or stable), given with a @stability directive in the comment or the
+	// stability_option option. Empty when it has none, which counts as stable.
+	Stability string `json:"stability,omitempty"`
+	// The first version the entity appeared in, computed from the descriptor sets of the since_descriptor_set option.
+	// Empty without the option.
+	Since string `json:"since,omitempty"`
+
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
@@ -323,6 +800,22 @@ type EnumValue struct {
 	Number      string `json:"number"`
 	Description string `json:"description"`
 
+	// Whether this is a placeholder for an excluded value, shown with the redact option.
+	Redacted bool `json:"redacted,omitempty"`
+
+	// Where the value is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the value.
+	Descriptor protoreflect.EnumValueDescriptor `json:"-"`
+
+	// The stability level (alpha, beta or stable), given with a @stability directive in the comment or the
+	// stability_option option. Empty when it has none, which counts as stable.
+	Stability string `json:"stability,omitempty"`
+	// The first version the entity appeared in, computed from the descriptor sets of the since_descriptor_set option.
+	// Empty without the option.
+	Since string `json:"since,omitempty"`
+
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
@@ -337,6 +830,25 @@ type Service struct {
 	Description string           `json:"description"`
 	Methods     []*ServiceMethod `json:"methods"`
 
+	// The names of the snippets inserted after the description, with @snippet directives in the comment.
+	Snippets []string `json:"snippets,omitempty"`
+
+	// The related types and pages listed after the description, given with @see and @link directives in the comment.
+	SeeAlso []*SeeAlso `json:"seeAlso,omitempty"`
+
+	// Where the service is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the service.
+	Descriptor protoreflect.ServiceDescriptor `json:"-"`
+
+	// The stability level (alpha, beta or stable), given with a @stability directive in the comment or the
+	// stability_option option. Empty when it has none, which counts as stable.
+	Stability string `json:"stability,omitempty"`
+	// The first version the entity appeared in, computed from the descriptor sets of the since_descriptor_set option.
+	// Empty without the option.
+	Since string `json:"since,omitempty"`
+
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
@@ -390,6 +902,41 @@ type ServiceMethod struct {
 	ResponseFullType  string `json:"responseFullType"`
 	ResponseStreaming bool   `json:"responseStreaming"`
 
+	// The eventual response and metadata of a long-running operation, declared with the
+	// google.longrunning.operation_info option.
+	Operation *OperationInfo `json:"operation,omitempty"`
+
+	// The errors the method may return, documented with @error directives in its comment.
+	Errors []*MethodError `json:"errors,omitempty"`
+
+	// The parameters, response and deprecation documented with @param, @returns and @deprecated directives in the
+	// comment. nil when there are none.
+	Doc *MethodDoc `json:"doc,omitempty"`
+
+	// The names of the snippets inserted after the description, with @snippet directives in the comment.
+	Snippets []string `json:"snippets,omitempty"`
+
+	// The related types and pages listed after the description, given with @see and @link directives in the comment.
+	SeeAlso []*SeeAlso `json:"seeAlso,omitempty"`
+
+	// The fields of the request and response messages, with expand_method_types. Only set for messages documented in
+	// the same output.
+	RequestFields  []*MessageField `json:"requestFields,omitempty"`
+	ResponseFields []*MessageField `json:"responseFields,omitempty"`
+
+	// Where the method is declared, with a link to it when the source_url_format option is set.
+	SourceInfo *SourceInfo `json:"sourceInfo,omitempty"`
+
+	// The protoreflect descriptor of the method.
+	Descriptor protoreflect.MethodDescriptor `json:"-"`
+
+	// The stability level (alpha, beta or stable), given with a @stability directive in the comment or the
+	// stability_option option. Empty when it has none, which counts as stable.
+	Stability string `json:"stability,omitempty"`
+	// The first version the entity appeared in, computed from the descriptor sets of the since_descriptor_set option.
+	// Empty without the option.
+	Since string `json:"since,omitempty"`
+
 	Options map[string]interface{} `json:"options,omitempty"`
 }
 
@@ -414,22 +961,37 @@ type ScalarValue struct {
 }
 
 func parseEnum(pe *protokit.EnumDescriptor, pluginOptions *PluginOptions) *Enum {
+	description, snippets := extractSnippets(descriptionFromComment(pe.GetComments(), pluginOptions))
+	description, seeAlso := extractSeeAlso(description)
+	description, stability := parseStability(description, pe.GetOptions(), pluginOptions)
 	enum := &Enum{
 		Name:        pe.GetName(),
 		LongName:    pe.GetLongName(),
 		FullName:    pe.GetFullName(),
-		Description: descriptionFromComment(pe.GetComments(), pluginOptions),
-		Options: mergeOptions(extractOptions(pe.GetOptions()),
-			extensions.Transform(pe.OptionExtensions)),
+		Description: description,
+		Snippets:    snippets,
+		SeeAlso:     seeAlso,
+		Stability:   stability,
+		Options:     entityOptions(pe.GetOptions(), pe.OptionExtensions, pluginOptions),
 	}
 
 	for _, val := range pe.GetValues() {
+		if excludedByOption(val.GetOptions(), pluginOptions) || excludedByComment(val.GetComments(), pluginOptions) ||
+			excludedByStability(val.GetComments(), val.GetOptions(), pluginOptions) {
+			if pluginOptions.Redact {
+				enum.Values = append(enum.Values, redactedEnumValue(val.GetNumber()))
+			}
+			continue
+		}
+
+		description, stability := parseStability(descriptionFromComment(val.GetComments(), pluginOptions),
+			val.GetOptions(), pluginOptions)
 		enum.Values = append(enum.Values, &EnumValue{
 			Name:        val.GetName(),
 			Number:      fmt.Sprint(val.GetNumber()),
-			Description: descriptionFromComment(val.GetComments(), pluginOptions),
-			Options: mergeOptions(extractOptions(val.GetOptions()),
-				extensions.Transform(val.OptionExtensions)),
+			Description: description,
+			Stability:   stability,
+			Options:     entityOptions(val.GetOptions(), val.OptionExtensions, pluginOptions),
 		})
 	}
 
@@ -453,30 +1015,73 @@ func parseFileExtension(pe *protokit.ExtensionDescriptor, pluginOptions *PluginO
 		ContainingType:     baseName(pe.GetExtendee()),
 		ContainingLongType: strings.TrimPrefix(pe.GetExtendee(), "."+pe.GetPackage()+"."),
 		ContainingFullType: strings.TrimPrefix(pe.GetExtendee(), "."),
+		Options:            entityOptions(pe.GetOptions(), pe.OptionExtensions, pluginOptions),
 	}
 }
 
 func parseMessage(pm *protokit.Descriptor, pluginOptions *PluginOptions) *Message {
+	groupField := messageGroupField(pm)
+	description := descriptionFromComment(pm.GetComments(), pluginOptions)
+	if description == "" && groupField != nil {
+		// protoc attaches the comments of a group to its field
+		description = descriptionFromComment(groupField.GetComments(), pluginOptions)
+	}
+
+	description, snippets := extractSnippets(description)
+	description, seeAlso := extractSeeAlso(description)
+	description, channel := extractEvent(description, pm.GetFullName())
+	if channel == "" {
+		channel = eventChannelFromOption(pm.GetOptions(), pm.GetFullName(), pluginOptions)
+	}
+	description, stability := parseStability(description, pm.GetOptions(), pluginOptions)
+
 	msg := &Message{
-		Name:          pm.GetName(),
-		LongName:      pm.GetLongName(),
-		FullName:      pm.GetFullName(),
-		Description:   descriptionFromComment(pm.GetComments(), pluginOptions),
-		HasExtensions: len(pm.GetExtensions()) > 0,
-		HasFields:     len(pm.GetMessageFields()) > 0,
-		HasOneofs:     len(pm.GetOneofDecl()) > 0,
-		Extensions:    make([]*MessageExtension, 0, len(pm.Extensions)),
-		Fields:        make([]*MessageField, 0, len(pm.Fields)),
-		Options: mergeOptions(extractOptions(pm.GetOptions()),
-			extensions.Transform(pm.OptionExtensions)),
+		Name:        pm.GetName(),
+		LongName:    pm.GetLongName(),
+		FullName:    pm.GetFullName(),
+		Description: description,
+		Snippets:    snippets,
+		SeeAlso:     seeAlso,
+		HasOneofs:   len(pm.GetOneofDecl()) > 0,
+		Extensions:  make([]*MessageExtension, 0, len(pm.Extensions)),
+		Fields:      make([]*MessageField, 0, len(pm.Fields)),
+		Resource:    parseResource(pm.GetOptions()),
+		Channel:     channel,
+		Stability:   stability,
+		Options:     entityOptions(pm.GetOptions(), pm.OptionExtensions, pluginOptions),
+	}
+
+	if groupField != nil {
+		msg.GroupField = groupField.GetName()
 	}
 
 	for _, ext := range pm.Extensions {
-		msg.Extensions = append(msg.Extensions, parseMessageExtension(ext, pluginOptions))
+		if !excludedByOption(ext.GetOptions(), pluginOptions) {
+			msg.Extensions = append(msg.Extensions, parseMessageExtension(ext, pluginOptions))
+		}
 	}
+	msg.HasExtensions = len(msg.Extensions) > 0
+
+	// parallel to pm.Fields, with nil for the excluded fields
+	fields := make([]*MessageField, len(pm.Fields))
+	for i, f := range pm.Fields {
+		switch {
+		case !excludedByOption(f.GetOptions(), pluginOptions) && !excludedByComment(f.GetComments(), pluginOptions) &&
+			!excludedByStability(f.GetComments(), f.GetOptions(), pluginOptions):
+			fields[i] = parseMessageField(f, pm.GetOneofDecl(), pluginOptions)
+		case pluginOptions.Redact:
+			fields[i] = redactedField(f.GetNumber())
+		default:
+			continue
+		}
+
+		msg.Fields = append(msg.Fields, fields[i])
+	}
+	msg.HasFields = len(msg.Fields) > 0
 
-	for _, f := range pm.Fields {
-		msg.Fields = append(msg.Fields, parseMessageField(f, pm.GetOneofDecl(), pluginOptions))
+	if templateAPI(pluginOptions) == TemplateAPIV2 {
+		msg.Oneofs = parseOneofs(pm, fields, pluginOptions)
+		msg.HasOneofs = len(msg.Oneofs) > 0
 	}
 
 	return msg
@@ -491,9 +1096,15 @@ func parseMessageExtension(pe *protokit.ExtensionDescriptor, pluginOptions *Plug
 	}
 }
 
-func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptor.OneofDescriptorProto, pluginOptions *PluginOptions) *MessageField {
+func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptorpb.OneofDescriptorProto, pluginOptions *PluginOptions) *MessageField {
 	t, lt, ft := parseType(pf)
 
+	description, anyTypes := descriptionFromComment(pf.GetComments(), pluginOptions), []string(nil)
+	if ft == anyFullType {
+		description, anyTypes = extractAnyTypes(description)
+	}
+	description, stability := parseStability(description, pf.GetOptions(), pluginOptions)
+
 	name := pf.GetName()
 	if pluginOptions.CamelCaseFields {
 		name = camelCase(name)
@@ -501,15 +1112,37 @@ func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptor.On
 
 	m := &MessageField{
 		Name:         name,
-		Description:  descriptionFromComment(pf.GetComments(), pluginOptions),
+		Description:  description,
 		Label:        labelName(pf.GetLabel(), pf.IsProto3(), pf.GetProto3Optional()),
 		Type:         t,
 		LongType:     lt,
 		FullType:     ft,
+		Number:       int(pf.GetNumber()),
+		JSONName:     pf.GetJsonName(),
 		DefaultValue: pf.GetDefaultValue(),
-		Options: mergeOptions(extractOptions(pf.GetOptions()),
-			extensions.Transform(pf.OptionExtensions)),
-		IsOneof: pf.OneofIndex != nil,
+		Options:      entityOptions(pf.GetOptions(), pf.OptionExtensions, pluginOptions),
+		IsOneof:      pf.OneofIndex != nil,
+
+		ResourceReference: parseResourceReference(pf.GetOptions()),
+		AnyTypes:          anyTypes,
+		TypeDisplay:       fieldTypeDisplay(ft, lt, pluginOptions.TypeDisplays),
+		Stability:         stability,
+	}
+
+	features := fieldFeatures(pf)
+	m.EffectivePresence = effectivePresence(pf, features)
+	m.EffectiveUTF8Validation = effectiveUTF8Validation(pf, features)
+	m.EffectivePacked = effectivePacked(pf, features)
+	m.HasPresence = m.EffectivePresence == "explicit"
+	m.Proto3Optional = pf.GetProto3Optional()
+	m.IsGroup = isGroupField(pf)
+
+	if pluginOptions.FieldMeta != nil {
+		m.Meta = parseFieldMeta(decodeOptions(pf.GetOptions(), pluginOptions), pluginOptions.FieldMeta)
+	}
+
+	if templateAPI(pluginOptions) == TemplateAPIV2 && pf.GetProto3Optional() {
+		m.IsOneof = false
 	}
 
 	if m.IsOneof {
@@ -517,7 +1150,8 @@ func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptor.On
 	}
 
 	// Check if this is a map.
-	// See https://github.com/golang/protobuf/blob/master/protoc-gen-go/descriptor/descriptor.pb.go#L1556
+	// See the map_entry message option in
+	// https://github.com/protocolbuffers/protobuf-go/blob/master/types/descriptorpb/descriptor.pb.go
 	// for more information
 	if m.Label == "repeated" &&
 		strings.Contains(m.LongType, ".") &&
@@ -530,17 +1164,48 @@ func parseMessageField(pf *protokit.FieldDescriptor, oneofDecls []*descriptor.On
 	return m
 }
 
+func parseImports(fd *descriptorpb.FileDescriptorProto) []*FileImport {
+	imports := make([]*FileImport, 0, len(fd.GetDependency()))
+	for _, dep := range fd.GetDependency() {
+		imports = append(imports, &FileImport{Name: dep})
+	}
+
+	for _, i := range fd.GetPublicDependency() {
+		if int(i) < len(imports) {
+			imports[i].Public = true
+		}
+	}
+
+	for _, i := range fd.GetWeakDependency() {
+		if int(i) < len(imports) {
+			imports[i].Weak = true
+		}
+	}
+
+	return imports
+}
+
 func parseService(ps *protokit.ServiceDescriptor, pluginOptions *PluginOptions) *Service {
+	description, snippets := extractSnippets(descriptionFromComment(ps.GetComments(), pluginOptions))
+	description, seeAlso := extractSeeAlso(description)
+	description, stability := parseStability(description, ps.GetOptions(), pluginOptions)
 	service := &Service{
 		Name:        ps.GetName(),
 		LongName:    ps.GetLongName(),
 		FullName:    ps.GetFullName(),
-		Description: descriptionFromComment(ps.GetComments(), pluginOptions),
-		Options: mergeOptions(extractOptions(ps.GetOptions()),
-			extensions.Transform(ps.OptionExtensions)),
+		Description: description,
+		Snippets:    snippets,
+		SeeAlso:     seeAlso,
+		Stability:   stability,
+		Options:     entityOptions(ps.GetOptions(), ps.OptionExtensions, pluginOptions),
 	}
 
 	for _, sm := range ps.Methods {
+		if excludedByOption(sm.GetOptions(), pluginOptions) || excludedByComment(sm.GetComments(), pluginOptions) ||
+			excludedByStability(sm.GetComments(), sm.GetOptions(), pluginOptions) {
+			continue
+		}
+
 		service.Methods = append(service.Methods, parseServiceMethod(sm, pluginOptions))
 	}
 
@@ -548,9 +1213,15 @@ func parseService(ps *protokit.ServiceDescriptor, pluginOptions *PluginOptions)
 }
 
 func parseServiceMethod(pm *protokit.MethodDescriptor, pluginOptions *PluginOptions) *ServiceMethod {
+	description, errors := extractErrors(descriptionFromComment(pm.GetComments(), pluginOptions))
+	description, doc := extractMethodDoc(description)
+	description, snippets := extractSnippets(description)
+	description, seeAlso := extractSeeAlso(description)
+	description, stability := parseStability(description, pm.GetOptions(), pluginOptions)
+
 	return &ServiceMethod{
 		Name:              pm.GetName(),
-		Description:       descriptionFromComment(pm.GetComments(), pluginOptions),
+		Description:       description,
 		RequestType:       baseName(pm.GetInputType()),
 		RequestLongType:   strings.TrimPrefix(pm.GetInputType(), "."+pm.GetPackage()+"."),
 		RequestFullType:   strings.TrimPrefix(pm.GetInputType(), "."),
@@ -559,8 +1230,13 @@ func parseServiceMethod(pm *protokit.MethodDescriptor, pluginOptions *PluginOpti
 		ResponseLongType:  strings.TrimPrefix(pm.GetOutputType(), "."+pm.GetPackage()+"."),
 		ResponseFullType:  strings.TrimPrefix(pm.GetOutputType(), "."),
 		ResponseStreaming: pm.GetServerStreaming(),
-		Options: mergeOptions(extractOptions(pm.GetOptions()),
-			extensions.Transform(pm.OptionExtensions)),
+		Operation:         parseOperationInfo(pm.GetOptions(), pm.GetPackage()),
+		Errors:            errors,
+		Doc:               doc,
+		Snippets:          snippets,
+		SeeAlso:           seeAlso,
+		Stability:         stability,
+		Options:           entityOptions(pm.GetOptions(), pm.OptionExtensions, pluginOptions),
 	}
 }
 
@@ -569,8 +1245,8 @@ func baseName(name string) string {
 	return parts[len(parts)-1]
 }
 
-func labelName(lbl descriptor.FieldDescriptorProto_Label, proto3 bool, proto3Opt bool) string {
-	if proto3 && !proto3Opt && lbl != descriptor.FieldDescriptorProto_LABEL_REPEATED {
+func labelName(lbl descriptorpb.FieldDescriptorProto_Label, proto3 bool, proto3Opt bool) string {
+	if proto3 && !proto3Opt && lbl != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
 		return ""
 	}
 
@@ -578,7 +1254,7 @@ func labelName(lbl descriptor.FieldDescriptorProto_Label, proto3 bool, proto3Opt
 }
 
 type typeContainer interface {
-	GetType() descriptor.FieldDescriptorProto_Type
+	GetType() descriptorpb.FieldDescriptorProto_Type
 	GetTypeName() string
 	GetPackage() string
 }
@@ -596,6 +1272,11 @@ func parseType(tc typeContainer) (string, string, string) {
 }
 
 func descriptionFromComment(comment *protokit.Comment, pluginOptions *PluginOptions) string {
+	return trimComment(commentText(comment, pluginOptions), pluginOptions)
+}
+
+// commentText returns the text of comment, without the parts excluded with exclude directives.
+func commentText(comment *protokit.Comment, pluginOptions *PluginOptions) string {
 	if comment == nil {
 		return ""
 	}
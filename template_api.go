@@ -0,0 +1,106 @@
+package gendoc
+
+import (
+	"sort"
+
+	"github.com/pseudomuto/protokit"
+)
+
+// Versions of the template data, selected with the template_api option. New versions may restructure the data in ways
+// that break templates written against older ones, which keep working as long as they select their version.
+const (
+	// TemplateAPIV1 is the original template data: all messages and enums of a file (including nested ones) are listed
+	// in File.Messages and File.Enums, and the fields of proto3 optional fields' synthetic oneofs count as oneof fields.
+	TemplateAPIV1 = "v1"
+	// TemplateAPIV2 nests types within their messages (see Message.Messages and Message.Enums), so that File.Messages
+	// and File.Enums only list top-level types, and groups oneof fields in Message.Oneofs. Proto3 optional fields aren't
+	// oneof fields.
+	TemplateAPIV2 = "v2"
+)
+
+// isTemplateAPI returns whether version is a valid value of the template_api option.
+func isTemplateAPI(version string) bool {
+	switch version {
+	case TemplateAPIV1, TemplateAPIV2:
+		return true
+	}
+
+	return false
+}
+
+// templateAPI returns the version of the template data to build. The built-in templates are written against
+// TemplateAPIV1, so the template_api option only applies to custom templates.
+func templateAPI(pluginOptions *PluginOptions) string {
+	if pluginOptions.TemplateFile == "" || pluginOptions.TemplateAPI == "" {
+		return TemplateAPIV1
+	}
+
+	return pluginOptions.TemplateAPI
+}
+
+// Oneof groups the fields of a message that belong to the same oneof. Only set with TemplateAPIV2.
+type Oneof struct {
+	Name   string          `json:"name"`
+	Fields []*MessageField `json:"fields"`
+
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Option returns the named option.
+func (o Oneof) Option(name string) interface{} { return o.Options[name] }
+
+// parseOneofs returns the oneofs of pm, along with their fields. The synthetic oneofs of proto3 optional fields are
+// skipped. fields are parallel to pm.Fields, with nil for the fields that were excluded.
+func parseOneofs(pm *protokit.Descriptor, fields []*MessageField, pluginOptions *PluginOptions) []*Oneof {
+	oneofs := make([]*Oneof, 0, len(pm.GetOneofDecl()))
+	byIndex := make(map[int32]*Oneof)
+
+	for i, pf := range pm.Fields {
+		if pf.OneofIndex == nil || pf.GetProto3Optional() || fields[i] == nil {
+			continue
+		}
+
+		oneof, ok := byIndex[pf.GetOneofIndex()]
+		if !ok {
+			decl := pm.GetOneofDecl()[pf.GetOneofIndex()]
+			oneof = &Oneof{Name: decl.GetName(), Options: decodeOptions(decl.GetOptions(), pluginOptions)}
+			byIndex[pf.GetOneofIndex()] = oneof
+			oneofs = append(oneofs, oneof)
+		}
+
+		oneof.Fields = append(oneof.Fields, fields[i])
+	}
+
+	return oneofs
+}
+
+// AllMessages returns all messages of the file, including nested ones, ordered by their long name. This is the same as
+// Messages with TemplateAPIV1.
+func (f File) AllMessages() []*Message {
+	messages := make(orderedMessages, 0, len(f.Messages))
+
+	var add func([]*Message)
+	add = func(ms []*Message) {
+		for _, m := range ms {
+			messages = append(messages, m)
+			add(m.Messages)
+		}
+	}
+	add(f.Messages)
+
+	sort.Sort(messages)
+	return messages
+}
+
+// AllEnums returns all enums of the file, including the ones nested in messages, ordered by their long name. This is
+// the same as Enums with TemplateAPIV1.
+func (f File) AllEnums() []*Enum {
+	enums := make(orderedEnums, 0, len(f.Enums))
+	enums = append(enums, f.Enums...)
+	for _, m := range f.AllMessages() {
+		enums = append(enums, m.Enums...)
+	}
+
+	sort.Sort(enums)
+	return enums
+}
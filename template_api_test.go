@@ -0,0 +1,161 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func newTemplateWithAPI(t *testing.T, pbFile string, version string, files ...string) *Template {
+	set, err := utils.LoadDescriptorSet("fixtures", pbFile)
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, files...)
+	return NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{
+		TemplateFile:          "custom.tmpl",
+		TemplateAPI:           version,
+		ExcludeDirectives:     []string{"@exclude"},
+		ExcludeLineDirectives: []string{"@exclude-line"},
+	})
+}
+
+func longNames(messages []*Message) []string {
+	names := make([]string, 0, len(messages))
+	for _, m := range messages {
+		names = append(names, m.LongName)
+	}
+
+	return names
+}
+
+func TestParseOptionsForTemplateAPI(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("custom.tmpl,index.txt")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, TemplateAPIV1, options.TemplateAPI)
+
+	req.Parameter = proto.String("custom.tmpl,index.txt:template_api=v2")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, TemplateAPIV2, options.TemplateAPI)
+}
+
+func TestTemplateAPIV1(t *testing.T) {
+	tmpl := newTemplateWithAPI(t, "fileset.pb", TemplateAPIV1, "Vehicle.proto")
+	require.Equal(t, TemplateAPIV1, tmpl.APIVersion)
+
+	file := tmpl.Files[0]
+	require.Contains(t, longNames(file.Messages), "Vehicle.Engine.Stats")
+	require.Equal(t, longNames(file.Messages), longNames(file.AllMessages()))
+	require.Len(t, file.Enums, 3)
+	require.Len(t, file.AllEnums(), 3)
+
+	vehicle := findMessage("Vehicle", file)
+	require.Empty(t, vehicle.Messages)
+	require.Empty(t, vehicle.Enums)
+	require.Empty(t, vehicle.Oneofs)
+}
+
+func TestTemplateAPIV2NestsTypes(t *testing.T) {
+	v1 := newTemplateWithAPI(t, "fileset.pb", TemplateAPIV1, "Vehicle.proto").Files[0]
+	tmpl := newTemplateWithAPI(t, "fileset.pb", TemplateAPIV2, "Vehicle.proto")
+	require.Equal(t, TemplateAPIV2, tmpl.APIVersion)
+
+	file := tmpl.Files[0]
+	require.Equal(t, []string{"EmptyMessage", "ExcludedMessage", "FindVehicleById", "Manufacturer", "Model", "Vehicle"}, longNames(file.Messages))
+	require.Equal(t, longNames(v1.Messages), longNames(file.AllMessages()))
+
+	require.Len(t, file.Enums, 1)
+	require.Equal(t, "Type", file.Enums[0].LongName)
+	require.Len(t, file.AllEnums(), 3)
+
+	manufacturer := findMessage("Manufacturer", file)
+	require.Len(t, manufacturer.Enums, 1)
+	require.Equal(t, "Manufacturer.Category", manufacturer.Enums[0].LongName)
+
+	vehicle := findMessage("Vehicle", file)
+	require.Equal(t, []string{"Vehicle.Category", "Vehicle.Engine", "Vehicle.PropertiesEntry"},
+		longNames(vehicle.Messages))
+
+	engine := vehicle.Messages[1]
+	require.Equal(t, []string{"Vehicle.Engine.Stats"}, longNames(engine.Messages))
+	require.Len(t, engine.Enums, 1)
+	require.Equal(t, "Vehicle.Engine.FuelType", engine.Enums[0].LongName)
+}
+
+func TestTemplateAPIV2GroupsOneofs(t *testing.T) {
+	file := newTemplateWithAPI(t, "fileset.pb", TemplateAPIV2, "Vehicle.proto").Files[0]
+
+	vehicle := findMessage("Vehicle", file)
+	require.True(t, vehicle.HasOneofs)
+	require.Len(t, vehicle.Oneofs, 2)
+
+	require.Equal(t, "travel", vehicle.Oneofs[0].Name)
+	require.Len(t, vehicle.Oneofs[0].Fields, 2)
+	require.Equal(t, "kilometers", vehicle.Oneofs[0].Fields[0].Name)
+	require.Equal(t, "lightyears", vehicle.Oneofs[0].Fields[1].Name)
+
+	require.Equal(t, "drivers", vehicle.Oneofs[1].Name)
+	require.Equal(t, "human_name", vehicle.Oneofs[1].Fields[0].Name)
+	require.Equal(t, "cat_name", vehicle.Oneofs[1].Fields[1].Name)
+	require.Same(t, findField("cat_name", vehicle), vehicle.Oneofs[1].Fields[1])
+}
+
+func TestTemplateAPIV2SkipsSyntheticOneofs(t *testing.T) {
+	v1 := findMessage("Cookie", newTemplateWithAPI(t, "cookie.pb", TemplateAPIV1, "Cookie.proto").Files[0])
+	require.True(t, v1.HasOneofs)
+	require.True(t, findField("name", v1).IsOneof)
+
+	v2 := findMessage("Cookie", newTemplateWithAPI(t, "cookie.pb", TemplateAPIV2, "Cookie.proto").Files[0])
+	require.False(t, v2.HasOneofs)
+	require.Empty(t, v2.Oneofs)
+
+	field := findField("name", v2)
+	require.False(t, field.IsOneof)
+	require.Empty(t, field.OneofDecl)
+	require.Equal(t, "optional", field.Label)
+}
+
+func TestTemplateAPIOnlyAppliesToCustomTemplates(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Vehicle.proto")
+	tmpl := NewTemplate(protokit.ParseCodeGenRequest(req), &PluginOptions{Type: RenderTypeHTML, TemplateAPI: TemplateAPIV2})
+	require.Equal(t, TemplateAPIV1, tmpl.APIVersion)
+	require.Contains(t, longNames(tmpl.Files[0].Messages), "Vehicle.Engine")
+}
+
+func TestRunPluginWithTemplateAPI(t *testing.T) {
+	dir, err := os.MkdirTemp("", "protoc-gen-doc-template-api")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	templateFile := filepath.Join(dir, "custom.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(
+		`{{.APIVersion}}:{{range .Files}}{{range .Messages}} {{.LongName}}{{end}}{{end}}`), 0644))
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Vehicle.proto")
+	req.Parameter = proto.String(templateFile + ",out.txt:template_api=v2")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Equal(t, "v2: EmptyMessage ExcludedMessage FindVehicleById Manufacturer Model Vehicle", resp.File[0].GetContent())
+
+	req.Parameter = proto.String(templateFile + ",out.txt")
+	resp, err = new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "v1: EmptyMessage ExcludedMessage FindVehicleById Manufacturer Model Vehicle Vehicle.Category")
+}
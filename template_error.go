@@ -0,0 +1,137 @@
+package gendoc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// templateErrorPattern matches the errors of text/template and html/template, which look like
+// `template: NAME:LINE[:COLUMN]: [executing "NAME" at <ACTION>: ]MESSAGE`.
+var templateErrorPattern = regexp.MustCompile(`(?s)^(?:html/)?template: ?(.+?):(\d+)(?::(\d+))?: (?:executing "[^"]*" at <(.*?)>: )?(.*)$`)
+
+// templateErrorContext is the number of lines shown before and after the offending line of a TemplateError.
+const templateErrorContext = 2
+
+// TemplateError is returned when a template fails to parse or execute. It describes where in the template the problem
+// is, along with a snippet of the surrounding lines.
+type TemplateError struct {
+	// The name of the template file. The plugin sets this to the path of the custom template.
+	Name string
+	// The line of the problem, starting at 1.
+	Line int
+	// The column of the problem, starting at 1. Zero when unknown (e.g. for most parse errors).
+	Column int
+	// The action that failed to execute (e.g. `.Name`). Empty for parse errors.
+	Action string
+	// A description of the problem.
+	Message string
+	// The lines surrounding the problem, with the offending line (and column) marked.
+	Snippet string
+	// The error returned by the template package.
+	Err error
+}
+
+// newTemplateError turns an error returned while parsing or executing the template source into a TemplateError. Other
+// errors (e.g. from writing the output) are returned as is.
+func newTemplateError(name, source string, err error) error {
+	match := templateErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+
+	// Problems in the blocks shared with the built-in templates are reported where they are
+	if strings.HasPrefix(match[1], TemplateBlocksPrefix) {
+		name, source = "resources/blocks.tmpl", blocksTemplate
+	}
+
+	line, _ := strconv.Atoi(match[2])
+	column := 0
+	if match[3] != "" {
+		// The template packages count columns from 0
+		column, _ = strconv.Atoi(match[3])
+		column++
+	}
+
+	return &TemplateError{
+		Name:    name,
+		Line:    line,
+		Column:  column,
+		Action:  match[4],
+		Message: match[5],
+		Snippet: templateSnippet(source, line, column),
+		Err:     err,
+	}
+}
+
+func (e *TemplateError) Error() string {
+	name := e.Name
+	if name == "" {
+		name = "template"
+	}
+
+	location := fmt.Sprintf("%s:%d", name, e.Line)
+	if e.Column > 0 {
+		location += fmt.Sprintf(":%d", e.Column)
+	}
+
+	message := e.Message
+	if e.Action != "" {
+		message = fmt.Sprintf("executing <%s>: %s", e.Action, message)
+	}
+
+	if e.Snippet == "" {
+		return location + ": " + message
+	}
+
+	return location + ": " + message + "\n\n" + e.Snippet
+}
+
+// Unwrap returns the error returned by the template package.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// templateSnippet returns the lines of source around line, prefixed with their numbers. The offending line is marked
+// with a `>`, and followed by a caret pointing at column when it's known.
+func templateSnippet(source string, line, column int) string {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first, last := line-templateErrorContext, line+templateErrorContext
+	if first < 1 {
+		first = 1
+	}
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	width := len(strconv.Itoa(last))
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		text := strings.TrimRight(lines[n-1], "\r")
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, text)
+
+		if n == line && column > 0 && column <= len(text)+1 {
+			// Keep tabs so that the caret lines up with the offending column
+			indent := strings.Map(func(r rune) rune {
+				if r == '\t' {
+					return r
+				}
+				return ' '
+			}, text[:column-1])
+
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", indent)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
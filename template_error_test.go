@@ -0,0 +1,56 @@
+package gendoc_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateErrorForExecution(t *testing.T) {
+	template := &Template{Files: []*File{{Name: "Booking.proto"}}}
+	source := "# Docs\n{{range .Files}}\n  {{.Nope}}\n{{end}}\nDone"
+
+	_, err := RenderTemplate(RenderTypeHTML, template, source)
+	require.Error(t, err)
+
+	var templateErr *TemplateError
+	require.True(t, errors.As(err, &templateErr))
+	require.Equal(t, 3, templateErr.Line)
+	require.Equal(t, 5, templateErr.Column)
+	require.Equal(t, ".Nope", templateErr.Action)
+	require.Equal(t, "can't evaluate field Nope in type *gendoc.File", templateErr.Message)
+	require.NotNil(t, errors.Unwrap(err))
+
+	require.Equal(t, `template:3:5: executing <.Nope>: can't evaluate field Nope in type *gendoc.File
+
+  1 | # Docs
+  2 | {{range .Files}}
+> 3 |   {{.Nope}}
+    |     ^
+  4 | {{end}}
+  5 | Done`, err.Error())
+}
+
+func TestTemplateErrorForParsing(t *testing.T) {
+	source := "a\nb\nc\nd\n{{if}}\ne\nf\ng"
+
+	_, err := RenderTemplate(RenderTypeHTML, new(Template), source)
+	require.Error(t, err)
+	require.Equal(t, `template:5: missing value for if
+
+  3 | c
+  4 | d
+> 5 | {{if}}
+  6 | e
+  7 | f`, err.Error())
+}
+
+func TestTemplateErrorWithTabs(t *testing.T) {
+	_, err := RenderTemplate(RenderTypeHTML, new(Template), "\t\t{{index .Files 5}}")
+	require.Error(t, err)
+	require.Equal(t, "template:1:5: executing <index .Files 5>: error calling index: index out of range: 5\n\n"+
+		"> 1 | \t\t{{index .Files 5}}\n"+
+		"    | \t\t  ^", err.Error())
+}
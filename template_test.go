@@ -6,11 +6,12 @@ import (
 
 	. "github.com/daotl/protoc-gen-doc"
 	"github.com/daotl/protoc-gen-doc/extensions"
-	"github.com/golang/protobuf/proto"
-	"github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/pseudomuto/protokit"
 	"github.com/pseudomuto/protokit/utils"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/runtime/protoimpl"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 var (
@@ -50,8 +51,8 @@ func TestMain(m *testing.M) {
 
 func identity(payload interface{}) interface{} { return payload }
 
-var E_ExtendFile = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.FileOptions)(nil),
+var E_ExtendFile = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.FileOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_file",
@@ -59,8 +60,8 @@ var E_ExtendFile = &proto.ExtensionDesc{
 	Filename:      "extend.proto",
 }
 
-var E_ExtendService = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.ServiceOptions)(nil),
+var E_ExtendService = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_service",
@@ -68,8 +69,8 @@ var E_ExtendService = &proto.ExtensionDesc{
 	Filename:      "extend.proto",
 }
 
-var E_ExtendMethod = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.MethodOptions)(nil),
+var E_ExtendMethod = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.MethodOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_method",
@@ -77,8 +78,8 @@ var E_ExtendMethod = &proto.ExtensionDesc{
 	Filename:      "extend.proto",
 }
 
-var E_ExtendEnum = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.EnumOptions)(nil),
+var E_ExtendEnum = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.EnumOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_enum",
@@ -86,8 +87,8 @@ var E_ExtendEnum = &proto.ExtensionDesc{
 	Filename:      "extend.proto",
 }
 
-var E_ExtendEnumValue = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.EnumValueOptions)(nil),
+var E_ExtendEnumValue = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.EnumValueOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_enum_value",
@@ -95,8 +96,8 @@ var E_ExtendEnumValue = &proto.ExtensionDesc{
 	Filename:      "extend.proto",
 }
 
-var E_ExtendMessage = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.MessageOptions)(nil),
+var E_ExtendMessage = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.MessageOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_message",
@@ -104,8 +105,8 @@ var E_ExtendMessage = &proto.ExtensionDesc{
 	Filename:      "extend.proto",
 }
 
-var E_ExtendField = &proto.ExtensionDesc{
-	ExtendedType:  (*descriptor.FieldOptions)(nil),
+var E_ExtendField = &protoimpl.ExtensionInfo{
+	ExtendedType:  (*descriptorpb.FieldOptions)(nil),
 	ExtensionType: (*bool)(nil),
 	Field:         20000,
 	Name:          "com.pseudomuto.protokit.v1.extend_field",
@@ -114,20 +115,14 @@ var E_ExtendField = &proto.ExtensionDesc{
 }
 
 func registerTestExtensions() {
-	proto.RegisterExtension(E_ExtendFile)
-	extensions.SetTransformer(E_ExtendFile.Name, identity)
-	proto.RegisterExtension(E_ExtendService)
-	extensions.SetTransformer(E_ExtendService.Name, identity)
-	proto.RegisterExtension(E_ExtendMethod)
-	extensions.SetTransformer(E_ExtendMethod.Name, identity)
-	proto.RegisterExtension(E_ExtendEnum)
-	extensions.SetTransformer(E_ExtendEnum.Name, identity)
-	proto.RegisterExtension(E_ExtendEnumValue)
-	extensions.SetTransformer(E_ExtendEnumValue.Name, identity)
-	proto.RegisterExtension(E_ExtendMessage)
-	extensions.SetTransformer(E_ExtendMessage.Name, identity)
-	proto.RegisterExtension(E_ExtendField)
-	extensions.SetTransformer(E_ExtendField.Name, identity)
+	for _, xt := range []*protoimpl.ExtensionInfo{
+		E_ExtendFile, E_ExtendService, E_ExtendMethod, E_ExtendEnum, E_ExtendEnumValue, E_ExtendMessage, E_ExtendField,
+	} {
+		if err := protoregistry.GlobalTypes.RegisterExtension(xt); err != nil {
+			panic(err)
+		}
+		extensions.SetTransformer(xt.Name, identity)
+	}
 }
 
 func TestTemplateProperties(t *testing.T) {
@@ -155,8 +150,12 @@ func TestFileEnumProperties(t *testing.T) {
 	require.Len(t, enum.Values, 2)
 
 	expectedValues := []*EnumValue{
-		{Name: "OK", Number: "200", Description: "OK result."},
-		{Name: "BAD_REQUEST", Number: "400", Description: "BAD result."},
+		{Name: "OK", Number: "200", Description: "OK result.",
+			SourceInfo: &SourceInfo{File: "Booking.proto", Line: 35, Col: 5},
+			Descriptor: enum.Descriptor.Values().ByName("OK")},
+		{Name: "BAD_REQUEST", Number: "400", Description: "BAD result.",
+			SourceInfo: &SourceInfo{File: "Booking.proto", Line: 36, Col: 5},
+			Descriptor: enum.Descriptor.Values().ByName("BAD_REQUEST")},
 	}
 
 	for idx, value := range enum.Values {
@@ -562,6 +561,42 @@ func TestBookingDefaultDirectives(t *testing.T) {
 	require.Contains(t, findField("value1", message).Description, "buf:lint:ignore")
 }
 
+func TestFileSource(t *testing.T) {
+	require.Empty(t, bookingFile.Source)
+	require.Nil(t, bookingFile.SourceLines())
+
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	result := protokit.ParseCodeGenRequest(req)
+
+	sourceTemplate := NewTemplate(result, &PluginOptions{IncludeFileSource: true})
+	file := sourceTemplate.Files[0]
+
+	require.Contains(t, file.Source, "syntax = \"proto2\";\n")
+	require.Contains(t, file.Source, "package com.example;\n")
+	require.Contains(t, file.Source, "// Represents the status of a vehicle booking.\nmessage BookingStatus {\n")
+	require.Contains(t, file.Source, "  optional string color_preference = 6 [deprecated = true]; // Color preference of the customer.\n")
+	require.Equal(t, "// Booking related messages.", file.SourceLines()[0])
+}
+
+func TestTemplatePackages(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Vehicle.proto", "nested/Book.proto", "Booking.proto")
+	result := protokit.ParseCodeGenRequest(req)
+
+	packages := NewTemplate(result, new(PluginOptions)).Packages()
+	require.Len(t, packages, 2)
+
+	require.Equal(t, "com.book", packages[0].Name)
+	require.Len(t, packages[0].Files, 1)
+	require.Equal(t, "nested/Book.proto", packages[0].Files[0].Name)
+
+	require.Equal(t, "com.example", packages[1].Name)
+	require.Len(t, packages[1].Files, 2)
+	require.Equal(t, "Booking.proto", packages[1].Files[0].Name)
+	require.Equal(t, "Vehicle.proto", packages[1].Files[1].Name)
+}
+
 func findService(name string, f *File) *Service {
 	for _, s := range f.Services {
 		if s.Name == name {
@@ -621,3 +656,29 @@ func findField(name string, m *Message) *MessageField {
 
 	return nil
 }
+
+func TestTemplatePackageOverviews(t *testing.T) {
+	set, _ := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	req := utils.CreateGenRequest(set, "Vehicle.proto", "nested/Book.proto", "Booking.proto")
+	result := protokit.ParseCodeGenRequest(req)
+
+	// nested/Book.proto has no file comment, so com.book has no overview
+	overviews := NewTemplate(result, new(PluginOptions)).PackageOverviews()
+	require.Len(t, overviews, 1)
+	require.Equal(t, "com.example", overviews[0].Name)
+	require.Equal(t, "Booking related messages.\n\n"+
+		"This file is really just an example. The data model is completely\nfictional.\n\n"+
+		"Messages describing manufacturers / vehicles.", overviews[0].Description)
+}
+
+func TestTemplatePackageDocFile(t *testing.T) {
+	template := &Template{Files: []*File{
+		{Name: "api/booking.proto", Package: "com.example", Description: "Bookings."},
+		{Name: "api/doc.proto", Package: "com.example", Description: "The example API."},
+		{Name: "api/vehicle.proto", Package: "com.example", Description: "Vehicles."},
+	}}
+
+	packages := template.Packages()
+	require.Len(t, packages, 1)
+	require.Equal(t, "The example API.", packages[0].Description)
+}
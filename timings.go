@@ -0,0 +1,149 @@
+package gendoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// TimingsStderr is the value of the timings option that writes the summary to LogOutput (stderr) as a table. Any other
+// value is the name of a JSON file to write the summary to, relative to the output directory.
+const TimingsStderr = "stderr"
+
+// Timings summarizes how long generating docs took (see the timings option). All durations are in milliseconds.
+type Timings struct {
+	// The time taken by the whole run, including parsing.
+	Total float64 `json:"totalMs"`
+	// The time taken to parse the request's descriptors (for all files at once).
+	Parse float64 `json:"parseMs"`
+	// The outputs that were rendered, slowest first.
+	Outputs []*OutputTiming `json:"outputs"`
+	// The files that were documented, slowest first.
+	Files []*FileTiming `json:"files"`
+}
+
+// OutputTiming describes how long generating an output file took.
+type OutputTiming struct {
+	Name string `json:"name"`
+	// The number of files documented in the output.
+	Files int `json:"files"`
+	// The time taken to build the template model, including the time taken for each of its files.
+	Build float64 `json:"buildMs"`
+	// The time taken to render the template.
+	Render float64 `json:"renderMs"`
+	// Whether the output came from the cache (see the cache_dir option), in which case nothing was built or rendered.
+	Cached bool `json:"cached"`
+}
+
+// FileTiming describes how long building the template model for a file took.
+type FileTiming struct {
+	Name string `json:"name"`
+	// The output the file is documented in.
+	Output string  `json:"output"`
+	Build  float64 `json:"buildMs"`
+}
+
+// timingCollector gathers timings while generating docs. It's safe for concurrent use.
+type timingCollector struct {
+	mu      sync.Mutex
+	timings Timings
+}
+
+// addOutput records the timing of an output. Does nothing when timings aren't being collected (i.e. c is nil).
+func (c *timingCollector) addOutput(timing *OutputTiming) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timings.Outputs = append(c.timings.Outputs, timing)
+}
+
+// fileObserver returns a function that records the build times of the files documented in output, for use with
+// newTemplate. Returns nil when timings aren't being collected (i.e. c is nil).
+func (c *timingCollector) fileObserver(output string) func(name string, d time.Duration) {
+	if c == nil {
+		return nil
+	}
+
+	return func(name string, d time.Duration) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.timings.Files = append(c.timings.Files, &FileTiming{Name: name, Output: output, Build: milliseconds(d)})
+	}
+}
+
+// summary returns the collected timings, given the time taken for parsing and the whole run.
+func (c *timingCollector) summary(parse, total time.Duration) *Timings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timings := c.timings
+	timings.Parse = milliseconds(parse)
+	timings.Total = milliseconds(total)
+	if timings.Outputs == nil {
+		timings.Outputs = make([]*OutputTiming, 0)
+	}
+	if timings.Files == nil {
+		timings.Files = make([]*FileTiming, 0)
+	}
+
+	sort.SliceStable(timings.Outputs, func(i, j int) bool {
+		a, b := timings.Outputs[i], timings.Outputs[j]
+		if a.Build+a.Render != b.Build+b.Render {
+			return a.Build+a.Render > b.Build+b.Render
+		}
+		return a.Name < b.Name
+	})
+	sort.SliceStable(timings.Files, func(i, j int) bool {
+		a, b := timings.Files[i], timings.Files[j]
+		if a.Build != b.Build {
+			return a.Build > b.Build
+		}
+		return a.Name < b.Name
+	})
+
+	return &timings
+}
+
+// milliseconds converts d to milliseconds, rounded to microseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Round(time.Microsecond)) / float64(time.Millisecond)
+}
+
+// writeTimings writes a summary of timings to w as tables.
+func writeTimings(w io.Writer, timings *Timings) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "protoc-gen-doc timings: %.3fms in total, %.3fms parsing\n\n", timings.Total, timings.Parse)
+
+	fmt.Fprintln(tw, "OUTPUT\tFILES\tBUILD\tRENDER\t")
+	for _, output := range timings.Outputs {
+		if output.Cached {
+			fmt.Fprintf(tw, "%s\t%d\t(cached)\t\t\n", output.Name, output.Files)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.3fms\t%.3fms\t\n", output.Name, output.Files, output.Build, output.Render)
+	}
+
+	fmt.Fprintln(tw, "\nFILE\tOUTPUT\tBUILD\t")
+	for _, file := range timings.Files {
+		fmt.Fprintf(tw, "%s\t%s\t%.3fms\t\n", file.Name, file.Output, file.Build)
+	}
+
+	return tw.Flush()
+}
+
+// renderTimings renders timings as the content of a JSON file.
+func renderTimings(timings *Timings) (string, error) {
+	data, err := json.MarshalIndent(timings, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
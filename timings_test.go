@@ -0,0 +1,137 @@
+package gendoc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func generateTimings(t *testing.T, parameter string) (*pluginpb.CodeGeneratorResponse, *Timings) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String(parameter)
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	for _, f := range resp.File {
+		if f.GetName() == "timings.json" {
+			timings := new(Timings)
+			require.NoError(t, json.Unmarshal([]byte(f.GetContent()), timings))
+			return resp, timings
+		}
+	}
+
+	require.Fail(t, "timings.json wasn't generated")
+	return nil, nil
+}
+
+func TestParseOptionsForTimings(t *testing.T) {
+	req := new(pluginpb.CodeGeneratorRequest)
+	req.Parameter = proto.String("html,index.html")
+
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Empty(t, options.Timings)
+
+	req.Parameter = proto.String("html,index.html:timings=stderr")
+	options, err = ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, TimingsStderr, options.Timings)
+
+	req.Parameter = proto.String("html,index.html:timings=")
+	_, err = ParseOptions(req)
+	require.Error(t, err)
+}
+
+func TestTimingsFile(t *testing.T) {
+	resp, timings := generateTimings(t, "markdown,docs.md:timings=timings.json")
+	require.Len(t, resp.File, 2)
+	require.Equal(t, "docs.md", resp.File[0].GetName())
+
+	require.True(t, timings.Total >= timings.Parse)
+	require.Len(t, timings.Outputs, 1)
+	require.Equal(t, "docs.md", timings.Outputs[0].Name)
+	require.Equal(t, 3, timings.Outputs[0].Files)
+	require.False(t, timings.Outputs[0].Cached)
+
+	require.Len(t, timings.Files, 3)
+	names := make([]string, 0, len(timings.Files))
+	for i, file := range timings.Files {
+		require.Equal(t, "docs.md", file.Output)
+		if i > 0 {
+			require.True(t, timings.Files[i-1].Build >= file.Build)
+		}
+		names = append(names, file.Name)
+	}
+	require.ElementsMatch(t, []string{"Booking.proto", "Vehicle.proto", "nested/Book.proto"}, names)
+}
+
+func TestTimingsFileForSourceRelative(t *testing.T) {
+	_, timings := generateTimings(t, "markdown,docs.md,source_relative:timings=timings.json")
+	require.Len(t, timings.Outputs, 2)
+	require.Len(t, timings.Files, 3)
+
+	outputs := map[string]int{}
+	for _, output := range timings.Outputs {
+		outputs[output.Name] = output.Files
+	}
+	require.Equal(t, map[string]int{"docs.md": 2, "nested/docs.md": 1}, outputs)
+
+	for _, file := range timings.Files {
+		if file.Name == "nested/Book.proto" {
+			require.Equal(t, "nested/docs.md", file.Output)
+		} else {
+			require.Equal(t, "docs.md", file.Output)
+		}
+	}
+}
+
+func TestTimingsFileForCachedOutput(t *testing.T) {
+	cacheDir, err := os.MkdirTemp("", "protoc-gen-doc-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	parameter := "markdown,docs.md:timings=timings.json,cache_dir=" + cacheDir
+	_, timings := generateTimings(t, parameter)
+	require.False(t, timings.Outputs[0].Cached)
+	require.Len(t, timings.Files, 3)
+
+	_, timings = generateTimings(t, parameter)
+	require.Len(t, timings.Outputs, 1)
+	require.True(t, timings.Outputs[0].Cached)
+	require.Zero(t, timings.Outputs[0].Build)
+	require.Empty(t, timings.Files)
+}
+
+func TestTimingsStderr(t *testing.T) {
+	var buf bytes.Buffer
+	LogOutput = &buf
+	defer func() { LogOutput = os.Stderr }()
+
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String("markdown,docs.md:timings=stderr")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+
+	summary := buf.String()
+	require.Regexp(t, `^protoc-gen-doc timings: [\d.]+ms in total, [\d.]+ms parsing\n`, summary)
+	require.Regexp(t, `OUTPUT\s+FILES\s+BUILD\s+RENDER`, summary)
+	require.Regexp(t, `docs\.md\s+3\s+[\d.]+ms\s+[\d.]+ms`, summary)
+	require.Regexp(t, `FILE\s+OUTPUT\s+BUILD`, summary)
+	require.Regexp(t, `nested/Book\.proto\s+docs\.md\s+[\d.]+ms`, summary)
+}
@@ -0,0 +1,44 @@
+package gendoc
+
+import (
+	"strings"
+)
+
+// TypeDisplay overrides how a type is shown in the field tables, e.g. to show `common.Money` as "Money (see pricing
+// guide)" linking to the guide. It's given for each type, by full name, under the `typeDisplay` key of the meta_file.
+type TypeDisplay struct {
+	// The text shown for the type. Defaults to the name the type is usually shown with.
+	Text string `json:"text,omitempty"`
+	// The URL the type links to, instead of its section. The text isn't linked when it's empty.
+	URL string `json:"url,omitempty"`
+}
+
+// normalizeTypeDisplays returns the displays keyed by full names without a leading dot.
+func normalizeTypeDisplays(displays map[string]*TypeDisplay) map[string]*TypeDisplay {
+	if len(displays) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]*TypeDisplay, len(displays))
+	for name, display := range displays {
+		if display != nil {
+			normalized[strings.TrimPrefix(name, ".")] = display
+		}
+	}
+
+	return normalized
+}
+
+// fieldTypeDisplay returns how fields of the given type are shown, or nil when they're shown as usual.
+func fieldTypeDisplay(fullType, longType string, displays map[string]*TypeDisplay) *TypeDisplay {
+	display, ok := displays[fullType]
+	if !ok {
+		return nil
+	}
+
+	if display.Text == "" {
+		return &TypeDisplay{Text: longType, URL: display.URL}
+	}
+
+	return display
+}
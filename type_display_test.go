@@ -0,0 +1,67 @@
+package gendoc_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTypeDisplayFile writes a meta_file overriding how the types of Booking.proto are shown, returning its name.
+func writeTypeDisplayFile(t *testing.T) string {
+	meta, err := os.CreateTemp("", "meta-*.json")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(meta.Name()) })
+
+	_, err = meta.WriteString(`{"typeDisplay": {` +
+		`".com.example.BookingStatus.StatusCode": {"text": "Status (see guide)", ` +
+		`"url": "https://example.com/status"}, ` +
+		`"int32": {"text": "Count"}}}`)
+	require.NoError(t, err)
+	require.NoError(t, meta.Close())
+
+	return meta.Name()
+}
+
+func TestTypeDisplay(t *testing.T) {
+	req := newBookingRequest(t, "html,index.html:meta_file="+writeTypeDisplayFile(t))
+	options, err := ParseOptions(req)
+	require.NoError(t, err)
+	require.Equal(t, &TypeDisplay{Text: "Status (see guide)", URL: "https://example.com/status"},
+		options.TypeDisplays["com.example.BookingStatus.StatusCode"])
+
+	file := NewTemplate(protokit.ParseCodeGenRequest(req), options).Files[0]
+
+	field := findField("status_code", findMessage("BookingStatus", file))
+	require.Equal(t, &TypeDisplay{Text: "Status (see guide)", URL: "https://example.com/status"}, field.TypeDisplay)
+	require.Equal(t, "BookingStatus.StatusCode", field.LongType)
+
+	// the text defaults to the usual name of the type
+	field = findField("id", findMessage("BookingStatus", file))
+	require.Equal(t, &TypeDisplay{Text: "Count"}, field.TypeDisplay)
+
+	require.Nil(t, findField("description", findMessage("BookingStatus", file)).TypeDisplay)
+}
+
+func TestRenderTypeDisplay(t *testing.T) {
+	meta := writeTypeDisplayFile(t)
+	expected := map[string]string{
+		"html": `<td>status_code</td>` + "\n" + `                    <td><a href="https://example.com/status">` +
+			`Status (see guide)</a></td>`,
+		"markdown": "</a> status_code | [Status (see guide)](https://example.com/status) |",
+		"docbook":  `<entry><link xlink:href="https://example.com/status">Status (see guide)</link></entry>`,
+		"rst":      "`Status (see guide) <https://example.com/status>`__",
+	}
+
+	for format, snippet := range expected {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, format+",docs:meta_file="+meta))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), snippet, format)
+	}
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "markdown,docs:meta_file="+meta))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "</a> id | Count |")
+}
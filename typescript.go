@@ -0,0 +1,264 @@
+package gendoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// typescriptScalars maps scalar value types to the TypeScript types of their proto3 JSON mapping, in which 64-bit
+// integers are strings and bytes are base64 encoded strings.
+var typescriptScalars = map[string]string{
+	"double":   "number",
+	"float":    "number",
+	"int32":    "number",
+	"int64":    "string",
+	"uint32":   "number",
+	"uint64":   "string",
+	"sint32":   "number",
+	"sint64":   "string",
+	"fixed32":  "number",
+	"fixed64":  "string",
+	"sfixed32": "number",
+	"sfixed64": "string",
+	"bool":     "boolean",
+	"string":   "string",
+	"bytes":    "string",
+}
+
+// typescriptWellKnownTypes maps the well-known types with a special JSON mapping to TypeScript types.
+var typescriptWellKnownTypes = map[string]string{
+	"google.protobuf.Any":         `{ "@type": string; [key: string]: unknown }`,
+	"google.protobuf.BoolValue":   "boolean | null",
+	"google.protobuf.BytesValue":  "string | null",
+	"google.protobuf.DoubleValue": "number | null",
+	"google.protobuf.Duration":    "string",
+	"google.protobuf.Empty":       "Record<string, never>",
+	"google.protobuf.FieldMask":   "string",
+	"google.protobuf.FloatValue":  "number | null",
+	"google.protobuf.Int32Value":  "number | null",
+	"google.protobuf.Int64Value":  "string | null",
+	"google.protobuf.ListValue":   "unknown[]",
+	"google.protobuf.NullValue":   "null",
+	"google.protobuf.StringValue": "string | null",
+	"google.protobuf.Struct":      "{ [key: string]: unknown }",
+	"google.protobuf.Timestamp":   "string",
+	"google.protobuf.UInt32Value": "number | null",
+	"google.protobuf.UInt64Value": "string | null",
+	"google.protobuf.Value":       "unknown",
+}
+
+// typescriptRenderer writes TypeScript declarations of the messages and enums, matching their proto3 JSON mapping:
+// an interface per message and a union of string literals per enum, in a namespace per package.
+type typescriptRenderer struct{}
+
+func (r *typescriptRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *typescriptRenderer) ApplyTo(w io.Writer, template *Template) error {
+	types := newTypescriptTypes(template)
+	mapEntries := mapEntryTypes(template)
+
+	var b strings.Builder
+	b.WriteString("// TypeScript declarations of the protocol buffer messages and enums, matching their proto3 JSON " +
+		"mapping.\n")
+
+	for _, pkg := range types.packages {
+		indent := ""
+		b.WriteString("\n")
+		if pkg != "" {
+			fmt.Fprintf(&b, "export declare namespace %s {\n", pkg)
+			indent = "  "
+		}
+
+		first := true
+		separate := func() {
+			if !first {
+				b.WriteString("\n")
+			}
+			first = false
+		}
+
+		for _, file := range types.files[pkg] {
+			for _, enum := range file.AllEnums() {
+				separate()
+				types.writeEnum(&b, indent, enum)
+			}
+
+			for _, message := range file.AllMessages() {
+				if message.Redacted || mapEntries[message.FullName] {
+					continue
+				}
+
+				separate()
+				types.writeMessage(&b, indent, message)
+			}
+		}
+
+		if pkg != "" {
+			b.WriteString("}\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// typescriptTypes names the TypeScript types of a template's messages and enums.
+type typescriptTypes struct {
+	// The packages in the order of their first file, and the files of each package.
+	packages []string
+	files    map[string][]*File
+
+	// The package and TypeScript name of every type, and the messages, keyed by full name.
+	packageOf map[string]string
+	names     map[string]string
+	messages  map[string]*Message
+}
+
+func newTypescriptTypes(template *Template) *typescriptTypes {
+	types := &typescriptTypes{
+		files:     make(map[string][]*File),
+		packageOf: make(map[string]string),
+		names:     make(map[string]string),
+		messages:  make(map[string]*Message),
+	}
+
+	for _, file := range template.Files {
+		if _, ok := types.files[file.Package]; !ok {
+			types.packages = append(types.packages, file.Package)
+		}
+		types.files[file.Package] = append(types.files[file.Package], file)
+
+		for _, message := range file.AllMessages() {
+			types.packageOf[message.FullName] = file.Package
+			types.names[message.FullName] = strings.ReplaceAll(message.LongName, ".", "_")
+			types.messages[message.FullName] = message
+		}
+		for _, enum := range file.AllEnums() {
+			types.packageOf[enum.FullName] = file.Package
+			types.names[enum.FullName] = strings.ReplaceAll(enum.LongName, ".", "_")
+		}
+	}
+
+	return types
+}
+
+// writeEnum writes enum as a union of the names of its values.
+func (t *typescriptTypes) writeEnum(b *strings.Builder, indent string, enum *Enum) {
+	values := make([]string, 0, len(enum.Values))
+	for _, value := range enum.Values {
+		if !value.Redacted {
+			values = append(values, fmt.Sprintf("%q", value.Name))
+		}
+	}
+	if len(values) == 0 {
+		values = append(values, "never")
+	}
+
+	writeJSDoc(b, indent, enum.Description, enum.Option("deprecated") == true)
+	fmt.Fprintf(b, "%sexport type %s = %s;\n", indent, t.names[enum.FullName], strings.Join(values, " | "))
+}
+
+// writeMessage writes message as an interface. All fields are optional, since fields with default values are omitted
+// from the JSON, except proto2 required fields.
+func (t *typescriptTypes) writeMessage(b *strings.Builder, indent string, message *Message) {
+	writeJSDoc(b, indent, message.Description, message.Option("deprecated") == true)
+	fmt.Fprintf(b, "%sexport interface %s {\n", indent, t.names[message.FullName])
+
+	for _, field := range message.Fields {
+		if field.Redacted {
+			continue
+		}
+
+		description := field.Description
+		if field.IsOneof && !strings.HasPrefix(field.OneofDecl, "_") {
+			description = strings.TrimSpace(description + "\n\n" +
+				fmt.Sprintf("Part of oneof `%s`: at most one of its fields is set.", field.OneofDecl))
+		}
+		writeJSDoc(b, indent+"  ", description, field.Option("deprecated") == true)
+
+		name := field.JSONName
+		if name == "" {
+			name = lowerCamelCase(field.Name)
+		}
+		optional := "?"
+		if field.Label == "required" {
+			optional = ""
+		}
+
+		fmt.Fprintf(b, "%s  %s%s: %s;\n", indent, name, optional, t.fieldType(field, t.packageOf[message.FullName]))
+	}
+
+	fmt.Fprintf(b, "%s}\n", indent)
+}
+
+// fieldType returns the TypeScript type of field, naming the types of other packages by their qualified name. Types
+// that are neither documented nor well-known are unknown.
+func (t *typescriptTypes) fieldType(field *MessageField, pkg string) string {
+	if field.IsMap {
+		value := "unknown"
+		if entry, ok := t.messages[field.FullType]; ok {
+			for _, f := range entry.Fields {
+				if f.Name == "value" {
+					value = t.fieldType(f, pkg)
+				}
+			}
+		}
+		return fmt.Sprintf("{ [key: string]: %s }", value)
+	}
+
+	var name string
+	if scalar, ok := typescriptScalars[field.FullType]; ok {
+		name = scalar
+	} else if wkt, ok := typescriptWellKnownTypes[field.FullType]; ok {
+		name = wkt
+	} else if typeName, ok := t.names[field.FullType]; ok {
+		name = typeName
+		if other := t.packageOf[field.FullType]; other != pkg && other != "" {
+			name = other + "." + typeName
+		}
+	} else {
+		name = "unknown"
+	}
+
+	if field.Label == "repeated" {
+		if strings.ContainsAny(name, " |") {
+			return "Array<" + name + ">"
+		}
+		return name + "[]"
+	}
+
+	return name
+}
+
+// writeJSDoc writes text as a JSDoc comment, with a @deprecated tag for deprecated entities.
+func writeJSDoc(b *strings.Builder, indent, text string, deprecated bool) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "*/", `*\/`))
+	if text == "" && !deprecated {
+		return
+	}
+
+	lines := make([]string, 0)
+	if text != "" {
+		lines = append(lines, strings.Split(text, "\n")...)
+	}
+	if deprecated {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "@deprecated")
+	}
+
+	if len(lines) == 1 {
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+		return
+	}
+
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(b, "%s\n", strings.TrimRight(indent+" * "+line, " "))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
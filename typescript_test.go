@@ -0,0 +1,88 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTypeScript(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	output, err := RenderTemplate(RenderTypeTypeScript, NewTemplate(protokit.ParseCodeGenRequest(req),
+		new(PluginOptions)), "")
+	require.NoError(t, err)
+	content := string(output)
+
+	require.Contains(t, content, "\nexport declare namespace com.example {\n")
+	require.Contains(t, content, `
+  /** A flag for the status result. */
+  export type BookingStatus_StatusCode = "OK" | "BAD_REQUEST";
+`)
+	require.Contains(t, content, `
+  export interface Booking {
+    /** ID of booked vehicle. */
+    vehicleId: number;
+`)
+	require.Contains(t, content, `
+    /**
+     * Color preference of the customer.
+     *
+     * @deprecated
+     */
+    colorPreference?: string;
+`)
+	require.Contains(t, content, `
+    /** Part of oneof `+"`travel`"+`: at most one of its fields is set. */
+    lightyears?: string;
+`)
+	require.Contains(t, content, "    rates?: number[];\n")
+	require.Contains(t, content, "    properties?: { [key: string]: string };\n")
+	require.Contains(t, content, "    category?: Vehicle_Category;\n")
+	require.NotContains(t, content, "PropertiesEntry")
+}
+
+func TestRenderTypeScriptAcrossPackages(t *testing.T) {
+	template := &Template{Files: []*File{
+		{
+			Name:    "common.proto",
+			Package: "acme.common",
+			Messages: []*Message{{Name: "Money", LongName: "Money", FullName: "acme.common.Money", Fields: []*MessageField{
+				{Name: "currency_code", JSONName: "currency", FullType: "string"},
+			}}},
+		},
+		{
+			Name: "order.proto",
+			Messages: []*Message{{Name: "Order", LongName: "Order", FullName: "Order", Fields: []*MessageField{
+				{Name: "total", FullType: "acme.common.Money"},
+				{Name: "created_at", FullType: "google.protobuf.Timestamp"},
+				{Name: "tags", FullType: "google.protobuf.StringValue", Label: "repeated"},
+				{Name: "extra", FullType: "other.Unknown", Description: "Not */ a comment end."},
+			}}},
+		},
+	}}
+
+	output, err := RenderTemplate(RenderTypeTypeScript, template, "")
+	require.NoError(t, err)
+	require.Equal(t, `// TypeScript declarations of the protocol buffer messages and enums, matching their proto3 JSON mapping.
+
+export declare namespace acme.common {
+  export interface Money {
+    currency?: string;
+  }
+}
+
+export interface Order {
+  total?: acme.common.Money;
+  createdAt?: string;
+  tags?: Array<string | null>;
+  /** Not *\/ a comment end. */
+  extra?: unknown;
+}
+`, string(output))
+}
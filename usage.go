@@ -0,0 +1,80 @@
+package gendoc
+
+// Usage describes where a message is used: a field of that type, or a method taking it as its request or returning it
+// as its response.
+type Usage struct {
+	// One of field, request or response.
+	Kind string `json:"kind"`
+	// The name of the field or method, qualified by its message or service (e.g. Booking.vehicle_id).
+	LongName string `json:"longName"`
+	// The fully qualified name of the field or method, which the rows of the Markdown template are anchored at.
+	FullName string `json:"fullName"`
+	// The full name of the message or service declaring the field or method, which the HTML templates link to.
+	Anchor string `json:"anchor"`
+}
+
+// addUsages fills in the UsedBy lists of the messages in files. Only usages within files are found. Fields of map
+// entries count as usages by the map field itself.
+func addUsages(files []*File) {
+	messages := make(map[string]*Message)
+	mapFields := make(map[string]*Usage)
+
+	for _, f := range files {
+		for _, m := range f.AllMessages() {
+			messages[m.FullName] = m
+			for _, field := range m.Fields {
+				if field.IsMap {
+					mapFields[field.FullType] = fieldUsage(m, field)
+				}
+			}
+		}
+	}
+
+	for _, f := range files {
+		for _, m := range f.AllMessages() {
+			for _, field := range m.Fields {
+				used, ok := messages[field.FullType]
+				if !ok || field.IsMap || field.Redacted {
+					continue
+				}
+
+				usage := fieldUsage(m, field)
+				if owner, ok := mapFields[m.FullName]; ok {
+					usage = owner
+				}
+
+				used.UsedBy = append(used.UsedBy, usage)
+			}
+		}
+
+		for _, s := range f.Services {
+			for _, method := range s.Methods {
+				if used, ok := messages[method.RequestFullType]; ok {
+					used.UsedBy = append(used.UsedBy, methodUsage("request", s, method))
+				}
+
+				if used, ok := messages[method.ResponseFullType]; ok {
+					used.UsedBy = append(used.UsedBy, methodUsage("response", s, method))
+				}
+			}
+		}
+	}
+}
+
+func fieldUsage(m *Message, field *MessageField) *Usage {
+	return &Usage{
+		Kind:     "field",
+		LongName: m.LongName + "." + field.Name,
+		FullName: m.FullName + "." + field.Name,
+		Anchor:   m.FullName,
+	}
+}
+
+func methodUsage(kind string, s *Service, method *ServiceMethod) *Usage {
+	return &Usage{
+		Kind:     kind,
+		LongName: s.Name + "." + method.Name,
+		FullName: s.FullName + "." + method.Name,
+		Anchor:   s.FullName,
+	}
+}
@@ -0,0 +1,84 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsedBy(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+
+	require.Equal(t, []*Usage{
+		{Kind: "field", LongName: "Vehicle.model", FullName: "com.example.Vehicle.model", Anchor: "com.example.Vehicle"},
+		{
+			Kind:     "response",
+			LongName: "VehicleService.GetModels",
+			FullName: "com.example.VehicleService.GetModels",
+			Anchor:   "com.example.VehicleService",
+		},
+		{
+			Kind:     "request",
+			LongName: "VehicleService.AddModels",
+			FullName: "com.example.VehicleService.AddModels",
+			Anchor:   "com.example.VehicleService",
+		},
+		{
+			Kind:     "response",
+			LongName: "VehicleService.AddModels",
+			FullName: "com.example.VehicleService.AddModels",
+			Anchor:   "com.example.VehicleService",
+		},
+	}, findMessage("Model", template.Files[1]).UsedBy)
+
+	stats := findMessage("Vehicle.Engine.Stats", template.Files[1])
+	require.Equal(t, []*Usage{{
+		Kind:     "field",
+		LongName: "Vehicle.Engine.stats",
+		FullName: "com.example.Vehicle.Engine.stats",
+		Anchor:   "com.example.Vehicle.Engine",
+	}}, stats.UsedBy)
+
+	require.Empty(t, findMessage("Manufacturer", template.Files[1]).UsedBy)
+}
+
+func TestUsedByOnlyWithinTemplate(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Vehicle.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	require.Len(t, findMessage("Model", template.Files[0]).UsedBy, 4)
+
+	req = utils.CreateGenRequest(set, "Booking.proto")
+	template = NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+	require.Len(t, findMessage("BookingStatus", template.Files[0]).UsedBy, 2)
+}
+
+func TestUsedByRendering(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	template := NewTemplate(protokit.ParseCodeGenRequest(req), new(PluginOptions))
+
+	output, err := RenderTemplate(RenderTypeHTML, template, "")
+	require.NoError(t, err)
+	require.Contains(t, string(output), `<p class="used-by">Used by: <a href="#com.example.Booking">Booking.status</a>, `+
+		`<a href="#com.example.BookingService">BookingService.BookVehicle</a> (response)</p>`)
+
+	output, err = RenderTemplate(RenderTypeMarkdown, template, "")
+	require.NoError(t, err)
+	// the usages link to the rows of the fields and methods
+	require.Contains(t, string(output), "Used by: [Booking.status](#com-example-Booking-status), "+
+		"[BookingService.BookVehicle](#com-example-BookingService-BookVehicle) (response)\n")
+	require.Contains(t, string(output), `<a name="com-example-Booking-status"></a> status`)
+	require.Contains(t, string(output), `<a name="com-example-BookingService-BookVehicle"></a> BookVehicle`)
+}
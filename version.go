@@ -1,4 +1,27 @@
 package gendoc
 
+import (
+	"time"
+)
+
 // VERSION is the version of protoc-gen-doc being used.
 const VERSION = "1.5.1"
+
+// SourceDateEpochEnv is the environment variable fixing the generation time shown in the outputs, as a number of
+// seconds since the Unix epoch (see https://reproducible-builds.org/specs/source-date-epoch/).
+const SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+// generatedAt returns the generation time shown in the outputs, formatted as RFC 3339 in UTC: the GeneratedAt option
+// when set and now otherwise. Empty with the reproducible option.
+func generatedAt(pluginOptions *PluginOptions) string {
+	if pluginOptions.Reproducible {
+		return ""
+	}
+
+	t := pluginOptions.GeneratedAt
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
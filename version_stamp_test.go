@@ -0,0 +1,55 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionsForVersionStamp(t *testing.T) {
+	t.Setenv(VersionStampEnv, "")
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:version_stamp=v1.4.0"))
+	require.NoError(t, err)
+	require.Equal(t, "v1.4.0", options.VersionStamp)
+
+	options, err = ParseOptions(newBookingRequest(t, "html,index.html"))
+	require.NoError(t, err)
+	require.Empty(t, options.VersionStamp)
+
+	// the environment variable is used when the option isn't set
+	t.Setenv(VersionStampEnv, "v2.0.0-rc.1")
+	options, err = ParseOptions(newBookingRequest(t, "html,index.html"))
+	require.NoError(t, err)
+	require.Equal(t, "v2.0.0-rc.1", options.VersionStamp)
+
+	options, err = ParseOptions(newBookingRequest(t, "html,index.html:version_stamp=v1.4.0"))
+	require.NoError(t, err)
+	require.Equal(t, "v1.4.0", options.VersionStamp)
+}
+
+func TestRenderVersionStamp(t *testing.T) {
+	t.Setenv(VersionStampEnv, "")
+	expected := map[string]string{
+		"html,index.html": `<footer class="version-stamp">Generated for release v1.4.0</footer>`,
+		"markdown,api.md": "---\nversion: \"v1.4.0\"\n---\n\n# Protocol Documentation",
+		"json,api.json":   `"versionStamp": "v1.4.0"`,
+		"docbook,api.xml": "<edition>v1.4.0</edition>",
+	}
+
+	for parameter, snippet := range expected {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, parameter+":version_stamp=v1.4.0"))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), snippet, parameter)
+	}
+
+	for _, layout := range []string{"minimal", "print", "slate"} {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:version_stamp=v1.4.0,template="+layout))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), `<footer class="version-stamp">`, layout)
+	}
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "markdown,api.md"))
+	require.NoError(t, err)
+	require.NotContains(t, resp.File[0].GetContent(), "version:")
+}
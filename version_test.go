@@ -0,0 +1,77 @@
+package gendoc_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptionsForGeneratedAt(t *testing.T) {
+	t.Setenv(SourceDateEpochEnv, "")
+	options, err := ParseOptions(newBookingRequest(t, "html,index.html:reproducible=true"))
+	require.NoError(t, err)
+	require.True(t, options.Reproducible)
+	require.True(t, options.GeneratedAt.IsZero())
+
+	t.Setenv(SourceDateEpochEnv, "1700000000")
+	options, err = ParseOptions(newBookingRequest(t, "html,index.html"))
+	require.NoError(t, err)
+	require.False(t, options.Reproducible)
+	require.Equal(t, time.Unix(1700000000, 0), options.GeneratedAt)
+
+	t.Setenv(SourceDateEpochEnv, "yesterday")
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html"))
+	require.EqualError(t, err, "Invalid SOURCE_DATE_EPOCH value: yesterday")
+
+	t.Setenv(SourceDateEpochEnv, "")
+	_, err = ParseOptions(newBookingRequest(t, "html,index.html:reproducible=sometimes"))
+	require.Error(t, err)
+}
+
+func TestGeneratedAt(t *testing.T) {
+	req := protokit.ParseCodeGenRequest(newBookingRequest(t, ""))
+
+	meta := NewTemplate(req, &PluginOptions{GeneratedAt: time.Unix(1700000000, 0)}).Meta
+	require.Equal(t, VERSION, meta.GeneratorVersion)
+	require.Equal(t, "2023-11-14T22:13:20Z", meta.GeneratedAt)
+
+	meta = NewTemplate(req, &PluginOptions{GeneratedAt: time.Unix(1700000000, 0), Reproducible: true}).Meta
+	require.Equal(t, VERSION, meta.GeneratorVersion)
+	require.Empty(t, meta.GeneratedAt)
+
+	meta = NewTemplate(req, new(PluginOptions)).Meta
+	generatedAt, err := time.Parse(time.RFC3339, meta.GeneratedAt)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), generatedAt, time.Minute)
+}
+
+func TestRenderGeneratedAt(t *testing.T) {
+	t.Setenv(SourceDateEpochEnv, "1700000000")
+	expected := map[string]string{
+		"html,index.html": `<footer class="generated">Generated by protoc-gen-doc ` + VERSION +
+			`, <time datetime="2023-11-14T22:13:20Z">2023-11-14T22:13:20Z</time></footer>`,
+		"markdown,api.md": "<sub>Generated by protoc-gen-doc " + VERSION + ", 2023-11-14T22:13:20Z</sub>",
+		"json,api.json":   `"generatedAt": "2023-11-14T22:13:20Z"`,
+		"docbook,api.xml": "<pubdate>2023-11-14T22:13:20Z</pubdate>",
+	}
+
+	for parameter, snippet := range expected {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, parameter))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), snippet, parameter)
+	}
+
+	for _, layout := range []string{"minimal", "print", "slate"} {
+		resp, err := new(Plugin).Generate(newBookingRequest(t, "html,index.html:template="+layout))
+		require.NoError(t, err)
+		require.Contains(t, resp.File[0].GetContent(), `<footer class="generated">`, layout)
+	}
+
+	resp, err := new(Plugin).Generate(newBookingRequest(t, "markdown,api.md:reproducible=true"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "<sub>Generated by protoc-gen-doc "+VERSION+"</sub>")
+	require.NotContains(t, resp.File[0].GetContent(), "2023-11-14")
+}
@@ -0,0 +1,155 @@
+package gendoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// WasmRenderPrefix precedes the WebAssembly module of a renderer in place of the format or template of the plugin's
+// parameter, e.g. `wasm:render.wasm,docs.txt`, and the module of a comment hook, e.g.
+// `comment_hook=wasm:spelling.wasm`.
+const WasmRenderPrefix = "wasm:"
+
+// wasmMemoryLimitPages limits the memory of WebAssembly modules to 256 MiB (a page is 64 KiB).
+const wasmMemoryLimitPages = 4096
+
+// wasmTimeout limits how long a single run of a WebAssembly module may take, so a module stuck in a loop can't hang
+// protoc.
+const wasmTimeout = time.Minute
+
+// wasmModule is a WASI command compiled to WebAssembly, e.g. with GOOS=wasip1, TinyGo or Rust's wasm32-wasi target. It
+// runs sandboxed: it can read its standard input and write its standard output and error, but can't access the file
+// system, the network or the environment of the plugin.
+type wasmModule struct {
+	path     string
+	hash     string // of the module's bytes, so a module rebuilt at the same path invalidates cached output
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// loadWasmModule compiles the module at path, which is instantiated each time it runs.
+func loadWasmModule(path string) (*wasmModule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	config := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(wasmMemoryLimitPages).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		runtime.Close(ctx)
+		return nil, err
+	}
+
+	compiled, err := runtime.CompileModule(ctx, data)
+	if err != nil {
+		runtime.Close(ctx)
+		return nil, fmt.Errorf("Invalid WebAssembly module %s: %v", path, err)
+	}
+
+	return &wasmModule{path: path, hash: sha256Hex(string(data)), runtime: runtime, compiled: compiled}, nil
+}
+
+// run runs the module with input on its standard input, writing its standard output to w. env gives the environment
+// variables of the module as KEY=value pairs.
+func (m *wasmModule) run(w io.Writer, input []byte, env ...string) error {
+	var stderr bytes.Buffer
+	// Modules without a name can be instantiated several times at once, e.g. to render pages in parallel
+	config := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(m.path).
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(w).
+		WithStderr(&stderr)
+	for _, variable := range env {
+		keyValue := strings.SplitN(variable, "=", 2)
+		config = config.WithEnv(keyValue[0], keyValue[1])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wasmTimeout)
+	defer cancel()
+
+	mod, err := m.runtime.InstantiateModule(ctx, m.compiled, config)
+	if mod != nil {
+		mod.Close(ctx)
+	}
+	if exitErr, ok := err.(*sys.ExitError); ok && exitErr.ExitCode() == 0 {
+		err = nil
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("WebAssembly module %s timed out after %v", m.path, wasmTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("WebAssembly module %s failed: %v: %s", m.path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// close releases the runtime of the module, which can't run anymore.
+func (m *wasmModule) close() {
+	m.runtime.Close(context.Background())
+}
+
+// closeWasmModules closes the modules of the wasm: renderer and comment hook loaded while parsing options, once
+// generation is done.
+func closeWasmModules(renderer Processor, hook CommentHook) {
+	if r, ok := renderer.(*wasmRenderer); ok {
+		r.module.close()
+	}
+	if h, ok := hook.(*wasmCommentHook); ok {
+		h.module.close()
+	}
+}
+
+// wasmRenderer is the Renderer of the wasm: format: a module reading the template as JSON (the document the json format
+// renders) on its standard input, and writing the output file on its standard output, like the exec: format.
+type wasmRenderer struct {
+	module *wasmModule
+}
+
+func (r *wasmRenderer) Apply(template *Template) ([]byte, error) {
+	return applyBuffered(r, template)
+}
+
+func (r *wasmRenderer) ApplyTo(w io.Writer, template *Template) error {
+	input, err := new(jsonRenderer).Apply(template)
+	if err != nil {
+		return err
+	}
+
+	return r.module.run(w, input, ExecRendererPageEnv+"="+template.Page)
+}
+
+// wasmCommentHook is the CommentHook of the comment_hook option given a WebAssembly module, which reads and writes the
+// comments like a command (see commandCommentHook).
+type wasmCommentHook struct {
+	module *wasmModule
+}
+
+func (h *wasmCommentHook) ProcessComments(comments []*HookComment) error {
+	input, err := json.Marshal(comments)
+	if err != nil {
+		return err
+	}
+
+	var stdout bytes.Buffer
+	if err := h.module.run(&stdout, input); err != nil {
+		return err
+	}
+
+	return readHookOutput(h.module.path, comments, stdout.Bytes())
+}
@@ -0,0 +1,90 @@
+package gendoc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// upperModule is a WASI command copying its standard input to its standard output in upper case (see upper.wat).
+const upperModule = "fixtures/upper.wasm"
+
+func TestParseOptionsForWasmRenderer(t *testing.T) {
+	options, err := ParseOptions(newBookingRequest(t, "wasm:"+upperModule+",docs.txt:locale=de"))
+	require.NoError(t, err)
+	require.NotNil(t, options.Renderer)
+	require.Empty(t, options.TemplateFile)
+	require.Equal(t, "docs.txt", options.OutputFile)
+	require.Equal(t, "de", options.Locale)
+
+	_, err = ParseOptions(newBookingRequest(t, "wasm:fixtures/missing.wasm,docs.txt"))
+	require.Error(t, err)
+
+	_, err = ParseOptions(newBookingRequest(t, "wasm:fixtures/upper.wat,docs.txt"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid WebAssembly module fixtures/upper.wat: ")
+}
+
+func TestWasmRenderer(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	// the pages of each directory are rendered in parallel by instances of the module
+	req := utils.CreateGenRequest(set, "Booking.proto", "Vehicle.proto", "nested/Book.proto")
+	req.Parameter = proto.String("wasm:" + upperModule + ",docs.txt,source_relative")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 2)
+	require.Equal(t, "docs.txt", resp.File[0].GetName())
+	require.Contains(t, resp.File[0].GetContent(), `"NAME": "BOOKING.PROTO"`)
+	require.Equal(t, "nested/docs.txt", resp.File[1].GetName())
+	require.Contains(t, resp.File[1].GetContent(), `"NAME": "NESTED/BOOK.PROTO"`)
+	require.Equal(t, strings.ToUpper(resp.File[1].GetContent()), resp.File[1].GetContent())
+}
+
+func TestWasmCommentHook(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	req.Parameter = proto.String("markdown,docs.md:comment_hook=wasm:" + upperModule)
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 1)
+	require.Contains(t, resp.File[0].GetContent(), "REPRESENTS THE BOOKING OF A VEHICLE.")
+
+	req.Parameter = proto.String("markdown,docs.md:comment_hook=wasm:fixtures/missing.wasm")
+	_, err = new(Plugin).Generate(req)
+	require.Error(t, err)
+}
+
+func TestWasmRendererWithCacheDir(t *testing.T) {
+	module, err := os.ReadFile(upperModule)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "render.wasm")
+	require.NoError(t, os.WriteFile(path, module, 0644))
+
+	cacheDir := t.TempDir()
+	parameter := "wasm:" + path + ",docs.txt:"
+	generateWithCache(t, cacheDir, parameter)
+
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, entries[0].Name()), []byte("cached"), 0644))
+	require.Equal(t, "cached", generateWithCache(t, cacheDir, parameter).File[0].GetContent())
+
+	// rebuilding the module at the same path changes the cache key (here by appending a custom section named "test")
+	rebuilt := append(module, 0x00, 0x06, 0x04, 't', 'e', 's', 't', 0x01)
+	require.NoError(t, os.WriteFile(path, rebuilt, 0644))
+	require.NotEqual(t, "cached", generateWithCache(t, cacheDir, parameter).File[0].GetContent())
+}
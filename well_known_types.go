@@ -0,0 +1,37 @@
+package gendoc
+
+import (
+	"encoding/json"
+)
+
+// WellKnownType describes the JSON representation of one of the well-known types with a special JSON mapping, e.g.
+// google.protobuf.FieldMask, which the built-in templates explain next to the fields of that type.
+type WellKnownType struct {
+	FullType string `json:"fullType"`
+	// The JSON type of the representation: object, array, string, number, boolean, null, or value for any of them.
+	JSONType string `json:"jsonType"`
+	Notes    string `json:"notes"`
+	Example  string `json:"example"`
+}
+
+// wellKnownTypes are the well-known types with a special JSON mapping, keyed by full name.
+var wellKnownTypes = makeWellKnownTypes()
+
+func makeWellKnownTypes() map[string]*WellKnownType {
+	var types []*WellKnownType
+	json.Unmarshal(wellKnownTypesJSON, &types)
+
+	byName := make(map[string]*WellKnownType, len(types))
+	for _, t := range types {
+		byName[t.FullType] = t
+	}
+
+	return byName
+}
+
+// LookupWellKnownType returns the JSON representation of the well-known type with the given full name, or nil when
+// it isn't a well-known type with a special JSON mapping. Templates can call it as wellKnownType, e.g.
+// {{with wellKnownType .FullType}}{{.Notes}}{{end}} on a field.
+func LookupWellKnownType(fullType string) *WellKnownType {
+	return wellKnownTypes[fullType]
+}
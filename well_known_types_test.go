@@ -0,0 +1,61 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func TestLookupWellKnownType(t *testing.T) {
+	for _, name := range []string{"Struct", "Value", "ListValue", "FieldMask", "Duration", "Timestamp", "Int64Value"} {
+		wkt := LookupWellKnownType("google.protobuf." + name)
+		require.NotNil(t, wkt, name)
+		require.Equal(t, "google.protobuf."+name, wkt.FullType)
+		require.NotEmpty(t, wkt.JSONType, name)
+		require.NotEmpty(t, wkt.Notes, name)
+		require.NotEmpty(t, wkt.Example, name)
+	}
+
+	require.Equal(t, "string", LookupWellKnownType("google.protobuf.FieldMask").JSONType)
+	require.Equal(t, `"user.displayName,photo"`, LookupWellKnownType("google.protobuf.FieldMask").Example)
+
+	// messages without a special JSON mapping
+	require.Nil(t, LookupWellKnownType("google.protobuf.FileDescriptorProto"))
+	require.Nil(t, LookupWellKnownType("com.example.Booking"))
+}
+
+func TestRenderWellKnownTypes(t *testing.T) {
+	newRequest := func(parameter string) *pluginpb.CodeGeneratorRequest {
+		return &pluginpb.CodeGeneratorRequest{
+			FileToGenerate: []string{"update.proto"},
+			Parameter:      proto.String(parameter),
+			ProtoFile: []*descriptorpb.FileDescriptorProto{{
+				Name:    proto.String("update.proto"),
+				Package: proto.String("update"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("UpdateRequest"),
+					Field: []*descriptorpb.FieldDescriptorProto{{
+						Name: proto.String("update_mask"), Number: proto.Int32(1),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+						TypeName: proto.String(".google.protobuf.FieldMask"),
+					}},
+				}},
+			}},
+		}
+	}
+
+	resp, err := new(Plugin).Generate(newRequest("html,index.html"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "<br>JSON: <code>string</code>. The field paths in lowerCamelCase, "+
+		"separated by commas. Example: <code>&#34;user.displayName,photo&#34;</code></p>")
+
+	resp, err = new(Plugin).Generate(newRequest("markdown,README.md"))
+	require.NoError(t, err)
+	require.Contains(t, resp.File[0].GetContent(), "|  | <br>JSON: `string`. The field paths in lowerCamelCase, "+
+		"separated by commas. Example: <code>&#34;user.displayName,photo&#34;</code> |")
+}
@@ -0,0 +1,252 @@
+package gendoc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pseudomuto/protokit"
+)
+
+// The names of the pages that GitHub and Gollum wikis show next to and below every page. They're written for the wiki
+// format along with the home page (the output file) and one page per package.
+const (
+	WikiSidebarPage = "_Sidebar"
+	WikiFooterPage  = "_Footer"
+)
+
+// wikiDefaultPage is the name of the page documenting the files without a package.
+const wikiDefaultPage = "default"
+
+// hasWiki returns whether the docs are written as wiki pages.
+func hasWiki(pluginOptions *PluginOptions) bool {
+	return pluginOptions.Type == RenderTypeWiki
+}
+
+// wikiPageName returns the name of the wiki page documenting pkg.
+func wikiPageName(pkg string) string {
+	if pkg == "" {
+		return wikiDefaultPage
+	}
+
+	return pkg
+}
+
+// wikiPageFile returns the path (relative to the output root) the wiki page named page is written to.
+func wikiPageFile(page string) string {
+	return page + ".md"
+}
+
+// outputName returns the path (relative to the output root) of the output file rendered for the group of files
+// keyed by group: a directory, or a wiki page name for the wiki format.
+func outputName(pluginOptions *PluginOptions, group string) string {
+	if hasWiki(pluginOptions) {
+		return wikiPageFile(group)
+	}
+
+	return filepath.Join(group, pluginOptions.OutputFile)
+}
+
+// groupProtosByPackage groups files by the wiki page documenting their package.
+func groupProtosByPackage(fds []*protokit.FileDescriptor) map[string][]*protokit.FileDescriptor {
+	fdsGroup := make(map[string][]*protokit.FileDescriptor)
+	for _, fd := range fds {
+		page := wikiPageName(fd.GetPackage())
+		fdsGroup[page] = append(fdsGroup[page], fd)
+	}
+
+	return fdsGroup
+}
+
+// wikiLink returns a link to the type fullName, shown as text: a Markdown link when the type is documented on the
+// page being rendered (types), a wiki link when it's documented on another page of the wiki, and the plain text
+// otherwise.
+func wikiLink(types map[string]bool, t *Template, fullName, text string) string {
+	fullName = strings.TrimPrefix(fullName, ".")
+	if types[fullName] {
+		return fmt.Sprintf("[%s](#%s)", text, AnchorFilter(fullName))
+	}
+
+	if page, ok := t.WikiPages[fullName]; ok {
+		return fmt.Sprintf("[[%s|%s#%s]]", text, page, AnchorFilter(fullName))
+	}
+
+	return text
+}
+
+// wikiSite describes the pages of a wiki, for the links between them and the pages listing them.
+type wikiSite struct {
+	// The template the home page, sidebar and footer are rendered with. It has no files of its own.
+	template *Template
+	// The name of the home page, which the output file is written to.
+	home string
+	// The documented packages, sorted by page name.
+	packages []*wikiPackage
+	// The page each documented type (and scalar value type) is on, keyed by full name.
+	pages map[string]string
+}
+
+// wikiPackage is a package documented on a page of its own.
+type wikiPackage struct {
+	*Package
+	page     string
+	entities []*IndexEntry
+}
+
+// newWikiSite collects the packages documented on the pages of fdsGroup (see groupProtosByPackage).
+func newWikiSite(fdsGroup map[string][]*protokit.FileDescriptor, pluginOptions *PluginOptions) *wikiSite {
+	name := filepath.Base(pluginOptions.OutputFile)
+	site := &wikiSite{
+		template: NewTemplate(nil, pluginOptions),
+		home:     strings.TrimSuffix(name, filepath.Ext(name)),
+		pages:    make(map[string]string),
+	}
+
+	for _, s := range site.template.Scalars {
+		site.pages[s.ProtoType] = site.home
+	}
+
+	for _, page := range sortedDirectories(fdsGroup) {
+		template := NewTemplate(fdsGroup[page], pluginOptions)
+		for fullName := range template.documentedTypes() {
+			site.pages[fullName] = page
+		}
+
+		pkg := &Package{Name: template.Files[0].Package, Files: template.Files}
+		pkg.Description = packageDescription(pkg.Files)
+		site.packages = append(site.packages, &wikiPackage{Package: pkg, page: page, entities: wikiEntities(pkg)})
+	}
+
+	return site
+}
+
+// wikiEntities returns the messages, enums and services of pkg, in the order they're documented on its page.
+func wikiEntities(pkg *Package) []*IndexEntry {
+	entries := make([]*IndexEntry, 0)
+	for _, f := range pkg.Files {
+		for _, m := range f.Messages {
+			entries = append(entries, &IndexEntry{
+				Name: m.LongName, Kind: "message", FullName: m.FullName, File: f.Name, Anchor: m.FullName,
+			})
+		}
+		for _, e := range f.Enums {
+			entries = append(entries, &IndexEntry{
+				Name: e.LongName, Kind: "enum", FullName: e.FullName, File: f.Name, Anchor: e.FullName,
+			})
+		}
+		for _, s := range f.Services {
+			entries = append(entries, &IndexEntry{
+				Name: s.Name, Kind: "service", FullName: s.FullName, File: f.Name, Anchor: s.FullName,
+			})
+		}
+	}
+
+	return entries
+}
+
+// key returns a string that changes whenever the page of a type changes, for the cache keys of the pages linking to
+// them.
+func (w *wikiSite) key() string {
+	names := make([]string, 0, len(w.pages))
+	for fullName := range w.pages {
+		names = append(names, fullName)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, fullName := range names {
+		b.WriteString(fullName + "=" + w.pages[fullName] + "\n")
+	}
+
+	return b.String()
+}
+
+// title returns the title of the docs.
+func (w *wikiSite) title() string {
+	if w.template.Meta.Title != "" {
+		return w.template.Meta.Title
+	}
+
+	return Translate(w.template.Locale, "Protocol Documentation")
+}
+
+// packageName returns the name pkg is listed with.
+func (w *wikiSite) packageName(pkg *wikiPackage) string {
+	if pkg.Name == "" {
+		return Translate(w.template.Locale, "(default package)")
+	}
+
+	return pkg.Name
+}
+
+// packageLink returns a wiki link to the page of pkg.
+func (w *wikiSite) packageLink(pkg *wikiPackage) string {
+	if name := w.packageName(pkg); name != pkg.page {
+		return fmt.Sprintf("[[%s|%s]]", name, pkg.page)
+	}
+
+	return fmt.Sprintf("[[%s]]", pkg.page)
+}
+
+// renderHome renders the home page, which lists the package pages and documents the scalar value types.
+func (w *wikiSite) renderHome() string {
+	t := w.template
+	var b strings.Builder
+	b.WriteString("# " + w.title() + "\n")
+	if t.Meta.Version != "" {
+		b.WriteString("\n" + Translate(t.Locale, "Version") + " " + t.Meta.Version + "\n")
+	}
+	if t.Meta.Description != "" {
+		b.WriteString("\n" + t.Meta.Description + "\n")
+	}
+
+	b.WriteString("\n## " + Translate(t.Locale, "Package Overview") + "\n\n")
+	for _, pkg := range w.packages {
+		b.WriteString("- " + w.packageLink(pkg))
+		if pkg.Description != "" {
+			// Only the first paragraph, to keep the list short.
+			b.WriteString(": " + string(NoBrFilter(strings.SplitN(pkg.Description, "\n\n", 2)[0])))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n<a name=\"scalar-value-types\"></a>\n\n## " + Translate(t.Locale, "Scalar Value Types") + "\n\n")
+	b.WriteString("| " + Translate(t.Locale, ".proto Type") + " | " + Translate(t.Locale, "Notes") +
+		" | C++ | Java | Python | Go | C# | PHP | Ruby |\n")
+	b.WriteString("| ----------- | ----- | --- | ---- | ------ | -- | -- | --- | ---- |\n")
+	for _, s := range t.Scalars {
+		b.WriteString(fmt.Sprintf("| <a name=\"%s\" /> %s | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			AnchorFilter(s.ProtoType), s.ProtoType, s.Notes, s.CppType, s.JavaType, s.PythonType, s.GoType, s.CSharp,
+			s.PhpType, s.RubyType))
+	}
+
+	return b.String()
+}
+
+// renderSidebar renders the sidebar, which links to the home page and to every documented package and entity.
+func (w *wikiSite) renderSidebar() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("**[[%s|%s]]**\n\n", w.title(), w.home))
+
+	for _, pkg := range w.packages {
+		b.WriteString("- " + w.packageLink(pkg) + "\n")
+		for _, entry := range pkg.entities {
+			b.WriteString(fmt.Sprintf("  - [[%s|%s#%s]]\n", entry.Name, pkg.page, AnchorFilter(entry.Anchor)))
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("- [[%s|%s#scalar-value-types]]\n", Translate(w.template.Locale, "Scalar Value Types"),
+		w.home))
+	return b.String()
+}
+
+// renderFooter renders the footer, which links back to the home page.
+func (w *wikiSite) renderFooter() string {
+	footer := fmt.Sprintf("[[%s|%s]]", w.title(), w.home)
+	if w.template.Meta.Version != "" {
+		footer += " · " + Translate(w.template.Locale, "Version") + " " + w.template.Meta.Version
+	}
+
+	return footer + "\n"
+}
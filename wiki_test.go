@@ -0,0 +1,81 @@
+package gendoc_test
+
+import (
+	"testing"
+
+	. "github.com/daotl/protoc-gen-doc"
+	"github.com/pseudomuto/protokit/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRenderWiki(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto", "nested/Book.proto")
+	req.Parameter = proto.String("wiki,Home.md:title=Shop API,version=1.2.0")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+
+	names := make([]string, len(resp.File))
+	contents := make(map[string]string)
+	for i, f := range resp.File {
+		names[i] = f.GetName()
+		contents[f.GetName()] = f.GetContent()
+	}
+	require.Equal(t, []string{"com.book.md", "com.example.md", "Home.md", "_Sidebar.md", "_Footer.md"}, names)
+
+	book := contents["com.book.md"]
+	require.Contains(t, book, "## nested/Book.proto\n")
+	require.Contains(t, book, "| isbn | [[int64|Home#int64]] |  |  |\n")
+	require.Contains(t, book, "| GetBook | [GetBookRequest](#com-book-GetBookRequest) | [Book](#com-book-Book) |  |\n")
+	require.NotContains(t, book, "com.example.Booking")
+
+	example := contents["com.example.md"]
+	require.Contains(t, example, "<a name=\"com-example-Booking\"></a>\n\n### Booking\n")
+	require.Contains(t, example,
+		"| status | [BookingStatus](#com-example-BookingStatus) | required | Status of the booking. |\n")
+
+	home := contents["Home.md"]
+	require.Contains(t, home, "# Shop API\n\nVersion 1.2.0\n")
+	require.Contains(t, home, "- [[com.book]]\n- [[com.example]]: Booking related messages.\n")
+	require.Contains(t, home, "| <a name=\"int64\" /> int64 |")
+
+	require.Contains(t, contents["_Sidebar.md"],
+		"**[[Shop API|Home]]**\n\n- [[com.book]]\n  - [[Book|com.book#com-book-Book]]\n")
+	require.Contains(t, contents["_Sidebar.md"], "- [[Scalar Value Types|Home#scalar-value-types]]\n")
+	require.Equal(t, "[[Shop API|Home]] · Version 1.2.0\n", contents["_Footer.md"])
+}
+
+func TestRenderWikiLinksBetweenPages(t *testing.T) {
+	resp, err := new(Plugin).Generate(newOperationsRequest(t, "wiki,Home.md"))
+	require.NoError(t, err)
+
+	library := resp.File[0]
+	require.Equal(t, "library.md", library.GetName())
+	require.Contains(t, library.GetContent(), "| GetBook | [Request](#library-Request) | [Book](#library-Book) |  |\n")
+	require.Contains(t, library.GetContent(),
+		"| ExportBook | [Request](#library-Request) | .google.longrunning.Operation |  |\n")
+}
+
+func TestRenderWikiDefaultPackage(t *testing.T) {
+	set, err := utils.LoadDescriptorSet("fixtures", "fileset.pb")
+	require.NoError(t, err)
+
+	req := utils.CreateGenRequest(set, "Booking.proto")
+	for _, pf := range req.ProtoFile {
+		if pf.GetName() == "Booking.proto" {
+			pf.Package = nil
+		}
+	}
+	req.Parameter = proto.String("wiki,Home.md")
+
+	resp, err := new(Plugin).Generate(req)
+	require.NoError(t, err)
+	require.Len(t, resp.File, 4)
+	require.Equal(t, "default.md", resp.File[0].GetName())
+	require.Contains(t, resp.File[0].GetContent(), "[[int32|Home#int32]]")
+	require.Contains(t, resp.File[1].GetContent(), "- [[(default package)|default]]")
+}